@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/dotbitHQ/das-lib/common"
 	"github.com/dotbitHQ/das-lib/core"
+	"github.com/dotbitHQ/das-lib/molecule"
 	"github.com/dotbitHQ/das-lib/witness"
 	"github.com/scorpiotzh/toolib"
 	"strconv"
@@ -46,9 +47,9 @@ func (b *BlockParser) ActionEditRecords(req FuncTransactionHandleReq) (resp Func
 		Outpoint:    common.OutPoint2String(req.TxHash, uint(accBuilder.Index)),
 		AccountId:   accountId,
 	}
-	_, mHex, err := b.dasCore.Daf().ArgsToHex(req.Tx.Outputs[accBuilder.Index].Lock.Args)
+	_, mHex, err := b.an.FromArgs(req.Tx.Outputs[accBuilder.Index].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 
@@ -59,7 +60,7 @@ func (b *BlockParser) ActionEditRecords(req FuncTransactionHandleReq) (resp Func
 		Action:         common.DasActionEditRecords,
 		ServiceType:    dao.ServiceTypeRegister,
 		ChainType:      mHex.ChainType,
-		Address:        mHex.AddressHex,
+		Address:        mHex.Hex,
 		Capacity:       0,
 		Outpoint:       common.OutPoint2String(req.TxHash, uint(accBuilder.Index)),
 		BlockTimestamp: req.BlockTimestamp,
@@ -92,9 +93,9 @@ func (b *BlockParser) ActionEditManager(req FuncTransactionHandleReq) (resp Func
 	}
 	account := accBuilder.Account
 	accountId := common.Bytes2Hex(common.GetAccountIdByAccount(account))
-	ownerHex, managerHex, err := b.dasCore.Daf().ArgsToHex(req.Tx.Outputs[accBuilder.Index].Lock.Args)
+	ownerHex, managerHex, err := b.an.FromArgs(req.Tx.Outputs[accBuilder.Index].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 	transactionInfo := dao.TableTransactionInfo{
@@ -104,7 +105,7 @@ func (b *BlockParser) ActionEditManager(req FuncTransactionHandleReq) (resp Func
 		Action:         common.DasActionEditManager,
 		ServiceType:    dao.ServiceTypeRegister,
 		ChainType:      ownerHex.ChainType,
-		Address:        ownerHex.AddressHex,
+		Address:        ownerHex.Hex,
 		Capacity:       0,
 		Outpoint:       common.OutPoint2String(req.TxHash, uint(accBuilder.Index)),
 		BlockTimestamp: req.BlockTimestamp,
@@ -115,17 +116,17 @@ func (b *BlockParser) ActionEditManager(req FuncTransactionHandleReq) (resp Func
 		Account:            account,
 		AccountId:          accountId,
 		ManagerChainType:   managerHex.ChainType,
-		Manager:            managerHex.AddressHex,
-		ManagerAlgorithmId: managerHex.DasAlgorithmId,
-		ManagerSubAid:      managerHex.DasSubAlgorithmId,
+		Manager:            managerHex.Hex,
+		ManagerAlgorithmId: managerHex.AlgorithmId,
+		ManagerSubAid:      managerHex.SubAlgorithmId,
 	}
 	var cidPk dao.TableCidPk
-	if managerHex.DasAlgorithmId == common.DasAlgorithmIdWebauthn {
-		cidPk.Cid = common.Bytes2Hex(managerHex.AddressPayload[:10])
-		cidPk.Pk = common.Bytes2Hex(managerHex.AddressPayload[10:])
+	if managerHex.AlgorithmId == common.DasAlgorithmIdWebauthn {
+		cidPk.Cid = common.Bytes2Hex(managerHex.Payload()[:10])
+		cidPk.Pk = common.Bytes2Hex(managerHex.Payload()[10:])
 	}
 
-	log.Info("ActionEditManager:", account, managerHex.DasAlgorithmId, managerHex.ChainType, managerHex.AddressHex, transactionInfo.Address)
+	log.Info("ActionEditManager:", account, managerHex.AlgorithmId, managerHex.ChainType, managerHex.Hex, transactionInfo.Address)
 
 	if err := b.dbDao.EditManager(accountInfo, transactionInfo, cidPk); err != nil {
 		log.Error("EditManager err:", err.Error(), toolib.JsonString(transactionInfo))
@@ -189,9 +190,9 @@ func (b *BlockParser) ActionRenewAccount(req FuncTransactionHandleReq) (resp Fun
 		ExpiredAt:   builder.ExpiredAt,
 	}
 
-	ownerHex, _, err := b.dasCore.Daf().ArgsToHex(req.Tx.Outputs[builder.Index].Lock.Args)
+	ownerHex, _, err := b.an.FromArgs(req.Tx.Outputs[builder.Index].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 	transactionInfo := dao.TableTransactionInfo{
@@ -201,7 +202,7 @@ func (b *BlockParser) ActionRenewAccount(req FuncTransactionHandleReq) (resp Fun
 		Action:         common.DasActionRenewAccount,
 		ServiceType:    dao.ServiceTypeRegister,
 		ChainType:      ownerHex.ChainType,
-		Address:        ownerHex.AddressHex,
+		Address:        ownerHex.Hex,
 		Capacity:       renewCapacity,
 		Outpoint:       common.OutPoint2String(req.TxHash, uint(builder.Index)),
 		BlockTimestamp: req.BlockTimestamp,
@@ -272,9 +273,9 @@ func (b *BlockParser) ActionBidExpiredAccountAuction(req FuncTransactionHandleRe
 	}
 	account := builder.Account
 	accountId := common.Bytes2Hex(common.GetAccountIdByAccount(account))
-	oHex, mHex, err := b.dasCore.Daf().ArgsToHex(req.Tx.Outputs[builder.Index].Lock.Args)
+	oHex, mHex, err := b.an.FromArgs(req.Tx.Outputs[builder.Index].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 
@@ -284,9 +285,9 @@ func (b *BlockParser) ActionBidExpiredAccountAuction(req FuncTransactionHandleRe
 		return
 	}
 
-	oldHex, _, err := b.dasCore.Daf().ArgsToHex(res.Transaction.Outputs[req.Tx.Inputs[builder.Index].PreviousOutput.Index].Lock.Args)
+	oldHex, _, err := b.an.FromArgs(res.Transaction.Outputs[req.Tx.Inputs[builder.Index].PreviousOutput.Index].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 	transactionInfos := make([]dao.TableTransactionInfo, 0)
@@ -298,7 +299,7 @@ func (b *BlockParser) ActionBidExpiredAccountAuction(req FuncTransactionHandleRe
 		Action:         common.DasActionBidExpiredAccountAuction,
 		ServiceType:    dao.ServiceTypeRegister,
 		ChainType:      oldHex.ChainType,
-		Address:        oldHex.AddressHex,
+		Address:        oldHex.Hex,
 		Capacity:       res.Transaction.Outputs[req.Tx.Inputs[builder.Index].PreviousOutput.Index].Capacity,
 		Outpoint:       common.OutPoint2String(req.TxHash, uint(builder.Index)),
 		BlockTimestamp: req.BlockTimestamp,
@@ -310,13 +311,13 @@ func (b *BlockParser) ActionBidExpiredAccountAuction(req FuncTransactionHandleRe
 		AccountId:          accountId,
 		Account:            account,
 		OwnerChainType:     oHex.ChainType,
-		Owner:              oHex.AddressHex,
-		OwnerAlgorithmId:   oHex.DasAlgorithmId,
-		OwnerSubAid:        oHex.DasSubAlgorithmId,
+		Owner:              oHex.Hex,
+		OwnerAlgorithmId:   oHex.AlgorithmId,
+		OwnerSubAid:        oHex.SubAlgorithmId,
 		ManagerChainType:   mHex.ChainType,
-		Manager:            mHex.AddressHex,
-		ManagerAlgorithmId: mHex.DasAlgorithmId,
-		ManagerSubAid:      mHex.DasSubAlgorithmId,
+		Manager:            mHex.Hex,
+		ManagerAlgorithmId: mHex.AlgorithmId,
+		ManagerSubAid:      mHex.SubAlgorithmId,
 		ExpiredAt:          builder.ExpiredAt,
 		RegisteredAt:       builder.RegisteredAt,
 		Status:             builder.Status,
@@ -363,9 +364,9 @@ func (b *BlockParser) ActionTransferAccount(req FuncTransactionHandleReq) (resp
 	account := builder.Account
 	accountId := common.Bytes2Hex(common.GetAccountIdByAccount(account))
 
-	oHex, mHex, err := b.dasCore.Daf().ArgsToHex(req.Tx.Outputs[builder.Index].Lock.Args)
+	oHex, mHex, err := b.an.FromArgs(req.Tx.Outputs[builder.Index].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 	oldBuilder, err := witness.AccountCellDataBuilderFromTx(req.Tx, common.DataTypeOld)
@@ -379,9 +380,9 @@ func (b *BlockParser) ActionTransferAccount(req FuncTransactionHandleReq) (resp
 		return
 	}
 
-	oldHex, _, err := b.dasCore.Daf().ArgsToHex(res.Transaction.Outputs[req.Tx.Inputs[oldBuilder.Index].PreviousOutput.Index].Lock.Args)
+	oldHex, _, err := b.an.FromArgs(res.Transaction.Outputs[req.Tx.Inputs[oldBuilder.Index].PreviousOutput.Index].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 	transactionInfo := dao.TableTransactionInfo{
@@ -391,7 +392,7 @@ func (b *BlockParser) ActionTransferAccount(req FuncTransactionHandleReq) (resp
 		Action:         common.DasActionTransferAccount,
 		ServiceType:    dao.ServiceTypeRegister,
 		ChainType:      oldHex.ChainType,
-		Address:        oldHex.AddressHex,
+		Address:        oldHex.Hex,
 		Capacity:       0,
 		Outpoint:       common.OutPoint2String(req.TxHash, uint(builder.Index)),
 		BlockTimestamp: req.BlockTimestamp,
@@ -419,6 +420,7 @@ func (b *BlockParser) ActionTransferAccount(req FuncTransactionHandleReq) (resp
 		var didCellList []dao.TableDidCellInfo
 		var accountIds []string
 		var records []dao.TableRecordsInfo
+		var ledgers []dao.TableOwnershipLedger
 
 		for k, v := range req.TxDidCellMap.Outputs {
 			didCellInfo := dao.TableDidCellInfo{
@@ -431,6 +433,26 @@ func (b *BlockParser) ActionTransferAccount(req FuncTransactionHandleReq) (resp
 				LockCodeHash: v.Lock.CodeHash.Hex(),
 			}
 			didCellList = append(didCellList, didCellInfo)
+			didCellAddr, err := b.an.AnyLock(v.Lock)
+			if err != nil {
+				resp.Err = fmt.Errorf("AnyLock err: %s[%s]", err.Error(), k)
+				return
+			}
+			ledgers = append(ledgers, dao.TableOwnershipLedger{
+				BlockNumber:     req.BlockNumber,
+				BlockTimestamp:  req.BlockTimestamp,
+				TxHash:          req.TxHash,
+				Action:          common.DasActionTransferAccount,
+				AccountId:       builder.AccountId,
+				Account:         builder.Account,
+				Role:            dao.OwnershipRoleOwner,
+				FromAlgorithmId: oldHex.AlgorithmId,
+				FromChainType:   oldHex.ChainType,
+				FromAddress:     oldHex.Hex,
+				ToAlgorithmId:   didCellAddr.AlgorithmId,
+				ToChainType:     didCellAddr.ChainType,
+				ToAddress:       didCellAddr.Hex,
+			})
 
 			_, cellDataNew, err := v.GetDataInfo()
 			if err != nil {
@@ -455,7 +477,7 @@ func (b *BlockParser) ActionTransferAccount(req FuncTransactionHandleReq) (resp
 				}
 			}
 		}
-		if err := b.dbDao.DidCellUpdateListWithAccountCell(transactionInfo, didCellList, accountIds, records, accountInfo); err != nil {
+		if err := b.dbDao.DidCellUpdateListWithAccountCell(transactionInfo, didCellList, accountIds, records, accountInfo, ledgers); err != nil {
 			resp.Err = fmt.Errorf("DidCellUpdateListWithAccountCell err: %s", err.Error())
 			return
 		}
@@ -468,19 +490,19 @@ func (b *BlockParser) ActionTransferAccount(req FuncTransactionHandleReq) (resp
 		AccountId:          accountId,
 		Account:            account,
 		OwnerChainType:     oHex.ChainType,
-		Owner:              oHex.AddressHex,
-		OwnerAlgorithmId:   oHex.DasAlgorithmId,
-		OwnerSubAid:        oHex.DasSubAlgorithmId,
+		Owner:              oHex.Hex,
+		OwnerAlgorithmId:   oHex.AlgorithmId,
+		OwnerSubAid:        oHex.SubAlgorithmId,
 		ManagerChainType:   mHex.ChainType,
-		Manager:            mHex.AddressHex,
-		ManagerAlgorithmId: mHex.DasAlgorithmId,
-		ManagerSubAid:      mHex.DasSubAlgorithmId,
+		Manager:            mHex.Hex,
+		ManagerAlgorithmId: mHex.AlgorithmId,
+		ManagerSubAid:      mHex.SubAlgorithmId,
 	}
 
 	var cidPk dao.TableCidPk
-	if oHex.DasAlgorithmId == common.DasAlgorithmIdWebauthn {
-		cidPk.Cid = common.Bytes2Hex(oHex.AddressPayload[:10])
-		cidPk.Pk = common.Bytes2Hex(oHex.AddressPayload[10:])
+	if oHex.AlgorithmId == common.DasAlgorithmIdWebauthn {
+		cidPk.Cid = common.Bytes2Hex(oHex.Payload()[:10])
+		cidPk.Pk = common.Bytes2Hex(oHex.Payload()[10:])
 	}
 
 	var recordsInfos []dao.TableRecordsInfo
@@ -497,7 +519,7 @@ func (b *BlockParser) ActionTransferAccount(req FuncTransactionHandleReq) (resp
 		})
 	}
 
-	log.Info("ActionTransferAccount:", account, oHex.DasAlgorithmId, oHex.ChainType, oHex.AddressHex, mHex.DasAlgorithmId, mHex.ChainType, mHex.AddressHex, transactionInfo.Address)
+	log.Info("ActionTransferAccount:", account, oHex.AlgorithmId, oHex.ChainType, oHex.Hex, mHex.AlgorithmId, mHex.ChainType, mHex.Hex, transactionInfo.Address)
 
 	if err := b.dbDao.TransferAccount(accountInfo, transactionInfo, recordsInfos, cidPk); err != nil {
 		log.Error("TransferAccount err:", err.Error(), toolib.JsonString(transactionInfo))
@@ -527,9 +549,9 @@ func (b *BlockParser) ActionForceRecoverAccountStatus(req FuncTransactionHandleR
 		resp.Err = fmt.Errorf("AccountCellDataBuilderFromTx err: %s", err.Error())
 		return
 	}
-	oHex, _, err := b.dasCore.Daf().ArgsToHex(req.Tx.Outputs[builder.Index].Lock.Args)
+	oHex, _, err := b.an.FromArgs(req.Tx.Outputs[builder.Index].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 
@@ -546,7 +568,7 @@ func (b *BlockParser) ActionForceRecoverAccountStatus(req FuncTransactionHandleR
 		Action:         common.DasActionForceRecoverAccountStatus,
 		ServiceType:    dao.ServiceTypeRegister,
 		ChainType:      oHex.ChainType,
-		Address:        oHex.AddressHex,
+		Address:        oHex.Hex,
 		Capacity:       req.Tx.OutputsCapacity() - req.Tx.Outputs[0].Capacity,
 		Outpoint:       common.OutPoint2String(req.TxHash, 0),
 		BlockTimestamp: req.BlockTimestamp,
@@ -593,14 +615,14 @@ func (b *BlockParser) ActionRecycleExpiredAccount(req FuncTransactionHandleReq)
 		resp.Err = fmt.Errorf("GetTransaction err: %s", err.Error())
 		return
 	}
-	oHex, _, err := b.dasCore.Daf().ArgsToHex(res.Transaction.Outputs[req.Tx.Inputs[1].PreviousOutput.Index].Lock.Args)
+	oHex, _, err := b.an.FromArgs(res.Transaction.Outputs[req.Tx.Inputs[1].PreviousOutput.Index].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
-	oArgs, err := b.dasCore.Daf().HexToArgs(oHex, oHex)
+	oArgs, err := b.an.ToArgs(oHex, oHex)
 	if err != nil {
-		resp.Err = fmt.Errorf("HexToArgs err: %s", err.Error())
+		resp.Err = fmt.Errorf("ToArgs err: %s", err.Error())
 		return
 	}
 	var oCapacity uint64
@@ -627,13 +649,13 @@ func (b *BlockParser) ActionRecycleExpiredAccount(req FuncTransactionHandleReq)
 		Action:         common.DasActionRecycleExpiredAccount,
 		ServiceType:    dao.ServiceTypeRegister,
 		ChainType:      oHex.ChainType,
-		Address:        oHex.AddressHex,
+		Address:        oHex.Hex,
 		Capacity:       oCapacity,
 		Outpoint:       common.OutPoint2String(req.TxHash, 0),
 		BlockTimestamp: req.BlockTimestamp,
 	}
 
-	log.Info("ActionRecycleExpiredAccount:", builder.Account, oHex.DasAlgorithmId, oHex.ChainType, oHex.AddressHex)
+	log.Info("ActionRecycleExpiredAccount:", builder.Account, oHex.AlgorithmId, oHex.ChainType, oHex.Hex)
 
 	if err = b.dbDao.RecycleExpiredAccount(accountInfo, transactionInfo, builder.AccountId, builder.EnableSubAccount); err != nil {
 		resp.Err = fmt.Errorf("RecycleExpiredAccount err: %s", err.Error())
@@ -658,9 +680,9 @@ func (b *BlockParser) ActionAccountCrossChain(req FuncTransactionHandleReq) (res
 		resp.Err = fmt.Errorf("AccountCellDataBuilderFromTx err: %s", err.Error())
 		return
 	}
-	ownerHex, managerHex, err := b.dasCore.Daf().ArgsToHex(req.Tx.Outputs[0].Lock.Args)
+	ownerHex, managerHex, err := b.an.FromArgs(req.Tx.Outputs[0].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 	var isTrans bool
@@ -681,13 +703,13 @@ func (b *BlockParser) ActionAccountCrossChain(req FuncTransactionHandleReq) (res
 		Outpoint:           common.OutPoint2String(req.TxHash, 0),
 		AccountId:          builder.AccountId,
 		OwnerChainType:     ownerHex.ChainType,
-		Owner:              ownerHex.AddressHex,
-		OwnerAlgorithmId:   ownerHex.DasAlgorithmId,
-		OwnerSubAid:        ownerHex.DasSubAlgorithmId,
+		Owner:              ownerHex.Hex,
+		OwnerAlgorithmId:   ownerHex.AlgorithmId,
+		OwnerSubAid:        ownerHex.SubAlgorithmId,
 		ManagerChainType:   managerHex.ChainType,
-		Manager:            managerHex.AddressHex,
-		ManagerAlgorithmId: managerHex.DasAlgorithmId,
-		ManagerSubAid:      managerHex.DasSubAlgorithmId,
+		Manager:            managerHex.Hex,
+		ManagerAlgorithmId: managerHex.AlgorithmId,
+		ManagerSubAid:      managerHex.SubAlgorithmId,
 		Status:             builder.Status,
 	}
 	transactionInfo := dao.TableTransactionInfo{
@@ -697,13 +719,38 @@ func (b *BlockParser) ActionAccountCrossChain(req FuncTransactionHandleReq) (res
 		Action:         req.Action,
 		ServiceType:    dao.ServiceTypeRegister,
 		ChainType:      ownerHex.ChainType,
-		Address:        ownerHex.AddressHex,
+		Address:        ownerHex.Hex,
 		Capacity:       0,
 		Outpoint:       common.OutPoint2String(req.TxHash, 0),
 		BlockTimestamp: req.BlockTimestamp,
 	}
 
-	if err = b.dbDao.AccountCrossChain(accountInfo, transactionInfo, isTrans); err != nil {
+	crossChainInfo := dao.TableCrossChainInfo{
+		AccountId: builder.AccountId,
+		Account:   builder.Account,
+	}
+	if req.Action == common.DasActionLockAccountForCrossChain {
+		actionDataBuilder, err := witness.ActionDataBuilderFromTx(req.Tx)
+		if err != nil {
+			resp.Err = fmt.Errorf("ActionDataBuilderFromTx err: %s", err.Error())
+			return
+		}
+		crossChainInfo.CoinType, crossChainInfo.ChainId = CrossChainTarget(actionDataBuilder.Params)
+		crossChainInfo.Status = dao.CrossChainStatusLocked
+		crossChainInfo.LockTxHash = req.TxHash
+		crossChainInfo.LockBlockNumber = req.BlockNumber
+		crossChainInfo.LockedAt = req.BlockTimestamp
+	} else {
+		crossChainInfo.Status = dao.CrossChainStatusUnlocked
+		crossChainInfo.UnlockTxHash = req.TxHash
+		crossChainInfo.UnlockBlockNumber = req.BlockNumber
+		crossChainInfo.UnlockedAt = req.BlockTimestamp
+		crossChainInfo.UnlockOwnerAlgorithmId = ownerHex.AlgorithmId
+		crossChainInfo.UnlockOwnerChainType = ownerHex.ChainType
+		crossChainInfo.UnlockOwner = ownerHex.Hex
+	}
+
+	if err = b.dbDao.AccountCrossChain(accountInfo, transactionInfo, isTrans, crossChainInfo); err != nil {
 		log.Error("AccountCrossChain err:", err.Error(), req.TxHash, req.BlockNumber)
 		resp.Err = fmt.Errorf("AccountCrossChain err: %s ", err.Error())
 		return
@@ -712,6 +759,20 @@ func (b *BlockParser) ActionAccountCrossChain(req FuncTransactionHandleReq) (res
 	return
 }
 
+// CrossChainTarget the coin type and chain id of the lock params, empty for the old locks without them.
+// The params of a sub-account lock have the same layout but are not split by das-lib
+func CrossChainTarget(params [][]byte) (coinType, chainId string) {
+	if len(params) == 1 && len(params[0]) == 17 {
+		params = [][]byte{params[0][:8], params[0][8:16], params[0][16:]}
+	}
+	if len(params) < 2 || len(params[0]) != 8 || len(params[1]) != 8 {
+		return
+	}
+	ct, _ := molecule.Bytes2GoU64(params[0])
+	ci, _ := molecule.Bytes2GoU64(params[1])
+	return strconv.FormatUint(ct, 10), strconv.FormatUint(ci, 10)
+}
+
 //func (b *BlockParser) ActionAccountUpgrade(req FuncTransactionHandleReq) (resp FuncTransactionHandleResp) {
 //	if isCV, err := isCurrentVersionTx(req.Tx, common.DasContractNameAccountCellType); err != nil {
 //		resp.Err = fmt.Errorf("isCurrentVersion err: %s", err.Error())
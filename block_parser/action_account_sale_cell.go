@@ -34,9 +34,9 @@ func (b *BlockParser) ActionStartAccountSale(req FuncTransactionHandleReq) (resp
 		resp.Err = fmt.Errorf("AccountCellDataBuilderFromTx err: %s", err.Error())
 		return
 	}
-	ownerHex, managerHex, err := b.dasCore.Daf().ArgsToHex(req.Tx.Outputs[accBuilder.Index].Lock.Args)
+	ownerHex, managerHex, err := b.an.FromArgs(req.Tx.Outputs[accBuilder.Index].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 	accountInfo := dao.TableAccountInfo{
@@ -45,21 +45,28 @@ func (b *BlockParser) ActionStartAccountSale(req FuncTransactionHandleReq) (resp
 		AccountId:          accBuilder.AccountId,
 		Account:            accBuilder.Account,
 		Status:             accBuilder.Status,
-		OwnerAlgorithmId:   ownerHex.DasAlgorithmId,
-		OwnerSubAid:        ownerHex.DasSubAlgorithmId,
+		OwnerAlgorithmId:   ownerHex.AlgorithmId,
+		OwnerSubAid:        ownerHex.SubAlgorithmId,
 		OwnerChainType:     ownerHex.ChainType,
-		Owner:              ownerHex.AddressHex,
-		ManagerAlgorithmId: managerHex.DasAlgorithmId,
-		ManagerSubAid:      managerHex.DasSubAlgorithmId,
+		Owner:              ownerHex.Hex,
+		ManagerAlgorithmId: managerHex.AlgorithmId,
+		ManagerSubAid:      managerHex.SubAlgorithmId,
 		ManagerChainType:   managerHex.ChainType,
-		Manager:            managerHex.AddressHex,
+		Manager:            managerHex.Hex,
+	}
+	tokenInfo, ok, err := timer.GetTokenPriceInfoAt(b.dbDao, timer.TokenIdCkb, req.BlockTimestamp)
+	if err != nil {
+		resp.Err = fmt.Errorf("GetTokenPriceInfoAt err: %s", err.Error())
+		return
+	}
+	if !ok {
+		log.Warn("no fresh price at block time, price_usd left empty:", timer.TokenIdCkb, req.BlockTimestamp, req.TxHash)
 	}
-	tokenInfo := timer.GetTokenPriceInfo(timer.TokenIdCkb)
 	priceUsd := tokenInfo.GetPriceUsd(builder.Price)
 
-	ownerHex, _, err = b.dasCore.Daf().ArgsToHex(req.Tx.Outputs[builder.Index].Lock.Args)
+	ownerHex, _, err = b.an.FromArgs(req.Tx.Outputs[builder.Index].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 
@@ -68,9 +75,9 @@ func (b *BlockParser) ActionStartAccountSale(req FuncTransactionHandleReq) (resp
 		Outpoint:         common.OutPoint2String(req.TxHash, uint(builder.Index)),
 		AccountId:        accountInfo.AccountId,
 		Account:          accountInfo.Account,
-		OwnerAlgorithmId: ownerHex.DasAlgorithmId,
+		OwnerAlgorithmId: ownerHex.AlgorithmId,
 		OwnerChainType:   ownerHex.ChainType,
-		OwnerAddress:     ownerHex.AddressHex,
+		OwnerAddress:     ownerHex.Hex,
 		Description:      builder.Description,
 		StartedAt:        builder.StartedAt * 1e3,
 		PriceCkb:         builder.Price,
@@ -135,12 +142,19 @@ func (b *BlockParser) ActionEditAccountSale(req FuncTransactionHandleReq) (resp
 	}
 
 	accountId := common.Bytes2Hex(common.GetAccountIdByAccount(builder.Account))
-	tokenInfo := timer.GetTokenPriceInfo(timer.TokenIdCkb)
+	tokenInfo, ok, err := timer.GetTokenPriceInfoAt(b.dbDao, timer.TokenIdCkb, req.BlockTimestamp)
+	if err != nil {
+		resp.Err = fmt.Errorf("GetTokenPriceInfoAt err: %s", err.Error())
+		return
+	}
+	if !ok {
+		log.Warn("no fresh price at block time, price_usd left empty:", timer.TokenIdCkb, req.BlockTimestamp, req.TxHash)
+	}
 	priceUsd := tokenInfo.GetPriceUsd(builder.Price)
 
-	oHex, _, err := b.dasCore.Daf().ArgsToHex(req.Tx.Outputs[0].Lock.Args)
+	oHex, _, err := b.an.FromArgs(req.Tx.Outputs[0].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 
@@ -157,9 +171,9 @@ func (b *BlockParser) ActionEditAccountSale(req FuncTransactionHandleReq) (resp
 		ProfitRate:     builder.BuyerInviterProfitRate,
 	}
 
-	ownerHex, _, err := b.dasCore.Daf().ArgsToHex(req.Tx.Outputs[builder.Index].Lock.Args)
+	ownerHex, _, err := b.an.FromArgs(req.Tx.Outputs[builder.Index].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 	tradeHistory := dao.TableTradeHistoryInfo{
@@ -167,9 +181,9 @@ func (b *BlockParser) ActionEditAccountSale(req FuncTransactionHandleReq) (resp
 		Outpoint:         tradeInfo.Outpoint,
 		AccountId:        tradeInfo.AccountId,
 		Account:          tradeInfo.Account,
-		OwnerAlgorithmId: ownerHex.DasAlgorithmId,
+		OwnerAlgorithmId: ownerHex.AlgorithmId,
 		OwnerChainType:   ownerHex.ChainType,
-		OwnerAddress:     ownerHex.AddressHex,
+		OwnerAddress:     ownerHex.Hex,
 		Description:      tradeInfo.Description,
 		StartedAt:        tradeInfo.StartedAt,
 		BlockTimestamp:   tradeInfo.BlockTimestamp,
@@ -186,7 +200,7 @@ func (b *BlockParser) ActionEditAccountSale(req FuncTransactionHandleReq) (resp
 		Action:         common.DasActionEditAccountSale,
 		ServiceType:    dao.ServiceTypeTransaction,
 		ChainType:      oHex.ChainType,
-		Address:        oHex.AddressHex,
+		Address:        oHex.Hex,
 		Capacity:       0,
 		Outpoint:       common.OutPoint2String(req.TxHash, uint(builder.Index)),
 		BlockTimestamp: req.BlockTimestamp,
@@ -226,9 +240,9 @@ func (b *BlockParser) ActionCancelAccountSale(req FuncTransactionHandleReq) (res
 		Status:      builder.Status,
 	}
 
-	ownerHex, _, err := b.dasCore.Daf().ArgsToHex(req.Tx.Outputs[0].Lock.Args)
+	ownerHex, _, err := b.an.FromArgs(req.Tx.Outputs[0].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 	transactionInfo := dao.TableTransactionInfo{
@@ -238,7 +252,7 @@ func (b *BlockParser) ActionCancelAccountSale(req FuncTransactionHandleReq) (res
 		Action:         common.DasActionCancelAccountSale,
 		ServiceType:    dao.ServiceTypeTransaction,
 		ChainType:      ownerHex.ChainType,
-		Address:        ownerHex.AddressHex,
+		Address:        ownerHex.Hex,
 		Capacity:       req.Tx.Outputs[1].Capacity,
 		Outpoint:       common.OutPoint2String(req.TxHash, 1),
 		BlockTimestamp: req.BlockTimestamp,
@@ -314,9 +328,9 @@ func (b *BlockParser) ActionBuyAccount(req FuncTransactionHandleReq) (resp FuncT
 		return
 	}
 
-	ownerHex, managerHex, err := b.dasCore.Daf().ArgsToHex(req.Tx.Outputs[0].Lock.Args)
+	ownerHex, managerHex, err := b.an.FromArgs(req.Tx.Outputs[0].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 	accountInfo := dao.TableAccountInfo{
@@ -325,12 +339,12 @@ func (b *BlockParser) ActionBuyAccount(req FuncTransactionHandleReq) (resp FuncT
 		AccountId:          accountId,
 		Account:            account,
 		OwnerChainType:     ownerHex.ChainType,
-		Owner:              ownerHex.AddressHex,
-		OwnerAlgorithmId:   ownerHex.DasAlgorithmId,
+		Owner:              ownerHex.Hex,
+		OwnerAlgorithmId:   ownerHex.AlgorithmId,
 		ManagerChainType:   managerHex.ChainType,
-		Manager:            managerHex.AddressHex,
-		ManagerAlgorithmId: managerHex.DasAlgorithmId,
-		ManagerSubAid:      managerHex.DasSubAlgorithmId,
+		Manager:            managerHex.Hex,
+		ManagerAlgorithmId: managerHex.AlgorithmId,
+		ManagerSubAid:      managerHex.SubAlgorithmId,
 		Status:             accBuilder.Status,
 	}
 	transactionInfoBuy := dao.TableTransactionInfo{
@@ -340,14 +354,14 @@ func (b *BlockParser) ActionBuyAccount(req FuncTransactionHandleReq) (resp FuncT
 		Action:         common.DasActionBuyAccount,
 		ServiceType:    dao.ServiceTypeTransaction,
 		ChainType:      ownerHex.ChainType,
-		Address:        ownerHex.AddressHex,
+		Address:        ownerHex.Hex,
 		Capacity:       builder.Price,
 		Outpoint:       common.OutPoint2String(req.TxHash, 0),
 		BlockTimestamp: req.BlockTimestamp,
 	}
-	ownerHex, _, err = b.dasCore.Daf().ArgsToHex(res.Transaction.Outputs[req.Tx.Inputs[1].PreviousOutput.Index].Lock.Args)
+	ownerHex, _, err = b.an.FromArgs(res.Transaction.Outputs[req.Tx.Inputs[1].PreviousOutput.Index].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 	transactionInfoSale := dao.TableTransactionInfo{
@@ -357,28 +371,36 @@ func (b *BlockParser) ActionBuyAccount(req FuncTransactionHandleReq) (resp FuncT
 		Action:         dao.DasActionSaleAccount,
 		ServiceType:    dao.ServiceTypeTransaction,
 		ChainType:      ownerHex.ChainType,
-		Address:        ownerHex.AddressHex,
+		Address:        ownerHex.Hex,
 		Outpoint:       common.OutPoint2String(req.TxHash, 1),
 		BlockTimestamp: req.BlockTimestamp,
 	}
 	for i := 1; i < len(req.Tx.Outputs); i++ {
-		ownerHex, _, err = b.dasCore.Daf().ScriptToHex(req.Tx.Outputs[i].Lock)
+		ownerHex, _, err = b.an.FromScript(req.Tx.Outputs[i].Lock)
 		if err != nil {
-			resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+			resp.Err = fmt.Errorf("FromScript err: %s", err.Error())
 			return
 		}
-		if transactionInfoSale.ChainType == ownerHex.ChainType && strings.EqualFold(transactionInfoSale.Address, ownerHex.AddressHex) {
+		if transactionInfoSale.ChainType == ownerHex.ChainType && strings.EqualFold(transactionInfoSale.Address, ownerHex.Hex) {
 			transactionInfoSale.Capacity = req.Tx.Outputs[i].Capacity
 			break
 		}
 	}
-	tokenInfo := timer.GetTokenPriceInfo(timer.TokenIdCkb)
+	tokenInfo, ok, err := timer.GetTokenPriceInfoAt(b.dbDao, timer.TokenIdCkb, req.BlockTimestamp)
+	if err != nil {
+		resp.Err = fmt.Errorf("GetTokenPriceInfoAt err: %s", err.Error())
+		return
+	}
+	if !ok {
+		log.Warn("no fresh price at block time, price_usd left empty:", timer.TokenIdCkb, req.BlockTimestamp, req.TxHash)
+	}
 	tradeDealInfo := dao.TableTradeDealInfo{
 		BlockNumber:    req.BlockNumber,
 		Outpoint:       transactionInfoBuy.Outpoint,
 		AccountId:      accountId,
 		Account:        account,
 		DealType:       dao.DealTypeSale,
+		AccountLength:  common.GetAccountLength(account),
 		SellChainType:  transactionInfoSale.ChainType,
 		SellAddress:    transactionInfoSale.Address,
 		BuyChainType:   transactionInfoBuy.ChainType,
@@ -445,13 +467,13 @@ func (b *BlockParser) getRebateInfoList(salePrice decimal.Decimal, account strin
 		channelScript = &tmp
 	}
 
-	inviterHex, _, err := b.dasCore.Daf().ScriptToHex(molecule.MoleculeScript2CkbScript(inviterScript))
+	inviterHex, _, err := b.an.FromScript(molecule.MoleculeScript2CkbScript(inviterScript))
 	if err != nil {
-		return list, fmt.Errorf("ScriptToHex err: %s", err.Error())
+		return list, fmt.Errorf("FromScript err: %s", err.Error())
 	}
-	channelHex, _, err := b.dasCore.Daf().ScriptToHex(molecule.MoleculeScript2CkbScript(channelScript))
+	channelHex, _, err := b.an.FromScript(molecule.MoleculeScript2CkbScript(channelScript))
 	if err != nil {
-		return list, fmt.Errorf("ScriptToHex err: %s", err.Error())
+		return list, fmt.Errorf("FromScript err: %s", err.Error())
 	}
 	inviteeId := common.Bytes2Hex(common.GetAccountIdByAccount(account))
 	list = append(list, dao.TableRebateInfo{
@@ -468,7 +490,7 @@ func (b *BlockParser) getRebateInfoList(salePrice decimal.Decimal, account strin
 		InviterArgs:      inviterArgs,
 		InviterAccount:   "",
 		InviterChainType: inviterHex.ChainType,
-		InviterAddress:   inviterHex.AddressHex,
+		InviterAddress:   inviterHex.Hex,
 		BlockTimestamp:   req.BlockTimestamp,
 	})
 
@@ -486,7 +508,7 @@ func (b *BlockParser) getRebateInfoList(salePrice decimal.Decimal, account strin
 		InviterArgs:      common.Bytes2Hex(channelScript.Args().RawData()),
 		InviterAccount:   "",
 		InviterChainType: channelHex.ChainType,
-		InviterAddress:   channelHex.AddressHex,
+		InviterAddress:   channelHex.Hex,
 		BlockTimestamp:   req.BlockTimestamp,
 	})
 	return list, nil
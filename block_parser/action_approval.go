@@ -1,11 +1,9 @@
 package block_parser
 
 import (
-	"das_database/config"
 	"das_database/dao"
 	"fmt"
 	"github.com/dotbitHQ/das-lib/common"
-	"github.com/dotbitHQ/das-lib/core"
 	"github.com/dotbitHQ/das-lib/witness"
 	"gorm.io/gorm"
 )
@@ -39,21 +37,20 @@ func (b *BlockParser) DasActionCreateApproval(req FuncTransactionHandleReq) (res
 	}
 
 	transfer := accBuilder.AccountApproval.Params.Transfer
-	dasf := core.DasAddressFormat{DasNetType: config.Cfg.Server.Net}
-	toHex, _, err := dasf.ScriptToHex(transfer.ToLock)
+	toHex, _, err := b.an.FromScript(transfer.ToLock)
 	if err != nil {
-		resp.Err = fmt.Errorf("ScriptToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromScript err: %s", err.Error())
 		return
 	}
-	toNormal, err := dasf.HexToNormal(toHex)
+	toNormal, err := b.an.ToNormal(toHex)
 	if err != nil {
-		resp.Err = fmt.Errorf("HexToNormal err: %s", err.Error())
+		resp.Err = fmt.Errorf("ToNormal err: %s", err.Error())
 		return
 	}
 
-	platformHex, _, err := dasf.ScriptToHex(transfer.PlatformLock)
+	platformHex, _, err := b.an.FromScript(transfer.PlatformLock)
 	if err != nil {
-		resp.Err = fmt.Errorf("ScriptToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromScript err: %s", err.Error())
 		return
 	}
 
@@ -63,11 +60,11 @@ func (b *BlockParser) DasActionCreateApproval(req FuncTransactionHandleReq) (res
 		Outpoint:         outpoint,
 		Account:          accBuilder.Account,
 		AccountID:        accBuilder.AccountId,
-		Platform:         platformHex.AddressHex,
+		Platform:         platformHex.Hex,
 		OwnerAlgorithmID: accountInfo.OwnerAlgorithmId,
 		Owner:            accountInfo.Owner,
-		ToAlgorithmID:    toHex.DasAlgorithmId,
-		To:               toNormal.AddressNormal,
+		ToAlgorithmID:    toHex.AlgorithmId,
+		To:               toNormal,
 		ProtectedUntil:   transfer.ProtectedUntil,
 		SealedUntil:      transfer.SealedUntil,
 		MaxDelayCount:    transfer.DelayCountRemain,
@@ -217,9 +214,9 @@ func (b *BlockParser) DasActionFulfillApproval(req FuncTransactionHandleReq) (re
 	approval := accBuilder.AccountApproval
 	switch approval.Action {
 	case witness.AccountApprovalActionTransfer:
-		owner, manager, err := b.dasCore.Daf().ScriptToHex(approval.Params.Transfer.ToLock)
+		owner, manager, err := b.an.FromScript(approval.Params.Transfer.ToLock)
 		if err != nil {
-			resp.Err = fmt.Errorf("ScriptToHex err: %s", err.Error())
+			resp.Err = fmt.Errorf("FromScript err: %s", err.Error())
 			return
 		}
 
@@ -234,20 +231,33 @@ func (b *BlockParser) DasActionFulfillApproval(req FuncTransactionHandleReq) (re
 		}
 
 		resp.Err = b.dbDao.Transaction(func(tx *gorm.DB) error {
+			ownership, err := dao.GetOwnership(tx, []string{accBuilder.AccountId})
+			if err != nil {
+				return err
+			}
 			if err := tx.Model(&dao.TableAccountInfo{}).Where("account_id=?", accBuilder.AccountId).Updates(map[string]interface{}{
 				"outpoint":             common.OutPoint2String(req.TxHash, 0),
 				"block_number":         req.BlockNumber,
 				"status":               dao.AccountStatusNormal,
-				"owner":                owner.AddressHex,
+				"owner":                owner.Hex,
 				"owner_chain_type":     owner.ChainType,
-				"owner_algorithm_id":   owner.DasAlgorithmId,
-				"manager":              manager.AddressHex,
+				"owner_algorithm_id":   owner.AlgorithmId,
+				"manager":              manager.Hex,
 				"manager_chain_type":   manager.ChainType,
-				"manager_algorithm_id": manager.DasAlgorithmId,
+				"manager_algorithm_id": manager.AlgorithmId,
 			}).Error; err != nil {
 				return err
 			}
-			if err := tx.Where("account_id = ?", accBuilder.AccountId).Delete(&dao.TableRecordsInfo{}).Error; err != nil {
+			txInfo := dao.TableTransactionInfo{
+				BlockNumber:    req.BlockNumber,
+				Action:         common.DasActionFulfillApproval,
+				Outpoint:       common.OutPoint2String(req.TxHash, 0),
+				BlockTimestamp: req.BlockTimestamp,
+			}
+			if err := dao.ReplaceRecords(tx, []string{accBuilder.AccountId}, nil, txInfo); err != nil {
+				return err
+			}
+			if err := dao.CreateOwnershipLedger(tx, ownership, txInfo); err != nil {
 				return err
 			}
 			return tx.Model(&dao.ApprovalInfo{}).Where("id=?", approvalInfo.ID).Updates(map[string]interface{}{
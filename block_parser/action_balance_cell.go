@@ -34,9 +34,9 @@ func (b *BlockParser) ActionBalanceCells(req FuncTransactionHandleReq) (resp Fun
 		if req.Action == dao.DasActionTransferBalance && v.Type != nil && v.Type.CodeHash.Hex() != dasBalance.ContractTypeId.Hex() {
 			continue
 		}
-		oldHex, _, err := b.dasCore.Daf().ArgsToHex(v.Lock.Args)
+		oldHex, _, err := b.an.FromArgs(v.Lock.Args)
 		if err != nil {
-			resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+			resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 			return
 		}
 		transactionInfos = append(transactionInfos, dao.TableTransactionInfo{
@@ -44,7 +44,7 @@ func (b *BlockParser) ActionBalanceCells(req FuncTransactionHandleReq) (resp Fun
 			Action:         req.Action,
 			ServiceType:    serviceType,
 			ChainType:      oldHex.ChainType,
-			Address:        oldHex.AddressHex,
+			Address:        oldHex.Hex,
 			Capacity:       v.Capacity,
 			Outpoint:       common.OutPoint2String(req.TxHash, uint(i)),
 			BlockTimestamp: req.BlockTimestamp,
@@ -93,9 +93,9 @@ func (b *BlockParser) ActionBalanceCell(req FuncTransactionHandleReq) (resp Func
 		return
 	}
 
-	oHex, _, err := b.dasCore.Daf().ArgsToHex(output.Lock.Args)
+	oHex, _, err := b.an.FromArgs(output.Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 	tx := dao.TableTransactionInfo{
@@ -103,7 +103,7 @@ func (b *BlockParser) ActionBalanceCell(req FuncTransactionHandleReq) (resp Func
 		Action:         req.Action,
 		ServiceType:    serviceType,
 		ChainType:      oHex.ChainType,
-		Address:        oHex.AddressHex,
+		Address:        oHex.Hex,
 		Capacity:       req.Tx.Outputs[0].Capacity,
 		Outpoint:       common.OutPoint2String(req.TxHash, 0),
 		BlockTimestamp: req.BlockTimestamp,
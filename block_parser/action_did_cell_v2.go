@@ -3,54 +3,194 @@ package block_parser
 import (
 	"bytes"
 	"das_database/dao"
+	"das_database/normalize"
 	"fmt"
 	"github.com/dotbitHQ/das-lib/common"
 	"github.com/dotbitHQ/das-lib/witness"
+	"sort"
 	"strconv"
+	"strings"
 )
 
+func (b *BlockParser) DidCellActionUpgrade(req FuncTransactionHandleReq) (resp FuncTransactionHandleResp) {
+	log.Info("DidCellActionUpgrade:", req.BlockNumber, req.TxHash, req.Action)
+	resp.Err = b.didCellActionHandle(req)
+	return
+}
+
 func (b *BlockParser) DidCellActionUpdate(req FuncTransactionHandleReq) (resp FuncTransactionHandleResp) {
 	log.Info("DidCellActionUpdate:", req.BlockNumber, req.TxHash, req.Action)
+	resp.Err = b.didCellActionHandle(req)
+	return
+}
 
-	if len(req.TxDidCellMap.Inputs) != len(req.TxDidCellMap.Outputs) {
-		resp.Err = fmt.Errorf("len(req.TxDidCellMap.Inputs)!=len(req.TxDidCellMap.Outputs)")
-		return
-	}
+func (b *BlockParser) DidCellActionRecycle(req FuncTransactionHandleReq) (resp FuncTransactionHandleResp) {
+	log.Info("DidCellActionRecycle:", req.BlockNumber, req.TxHash, req.Action)
+	resp.Err = b.didCellActionHandle(req)
+	return
+}
+
+func (b *BlockParser) didCellActionHandle(req FuncTransactionHandleReq) error {
 	txDidEntityWitness, err := witness.GetDidEntityFromTx(req.Tx)
 	if err != nil {
-		resp.Err = fmt.Errorf("witness.GetDidEntityFromTx err: %s", err.Error())
-		return
+		return fmt.Errorf("witness.GetDidEntityFromTx err: %s", err.Error())
 	}
+	res, err := getDidCellChanges(req, b.an, txDidEntityWitness)
+	if err != nil {
+		return fmt.Errorf("getDidCellChanges err: %s", err.Error())
+	}
+	var accountInfos []dao.TableAccountInfo
+	if req.Action == common.DidCellActionUpgrade {
+		if accountInfos, err = b.didCellUpgradeAccounts(req, res.ledgers); err != nil {
+			return fmt.Errorf("didCellUpgradeAccounts err: %s", err.Error())
+		}
+	}
+	if err := b.dbDao.DidCellUpdateList(res.oldOutpointList, res.list, res.records, accountInfos, res.txList, res.ledgers); err != nil {
+		return fmt.Errorf("DidCellUpdateList err: %s", err.Error())
+	}
+	return nil
+}
 
-	var oldOutpointList []string
-	var list []dao.TableDidCellInfo
-	var accountIds []string
-	var records []dao.TableRecordsInfo
-	var txList []dao.TableTransactionInfo
-
-	for k, v := range req.TxDidCellMap.Inputs {
-		_, cellDataOld, err := v.GetDataInfo()
-		if err != nil {
-			resp.Err = fmt.Errorf("GetDataInfo old err: %s[%s]", err.Error(), k)
-			return
+// didCellUpgradeAccounts the account cells of the tx are left with the upgraded status,
+// and the owner of an upgraded account moves from the owner of its account cell to the did cell
+func (b *BlockParser) didCellUpgradeAccounts(req FuncTransactionHandleReq, ledgers []dao.TableOwnershipLedger) ([]dao.TableAccountInfo, error) {
+	builderMap, err := witness.AccountCellDataBuilderMapFromTx(req.Tx, common.DataTypeNew)
+	if err != nil {
+		return nil, fmt.Errorf("AccountCellDataBuilderMapFromTx err: %s", err.Error())
+	}
+	var accountInfos []dao.TableAccountInfo
+	for _, v := range builderMap {
+		if v.Status != common.AccountStatusOnUpgrade {
+			continue
 		}
-		n, ok := req.TxDidCellMap.Outputs[k]
-		if !ok {
-			resp.Err = fmt.Errorf("TxDidCellMap diff err: %s[%s]", err.Error(), k)
-			return
+		accountInfos = append(accountInfos, dao.TableAccountInfo{
+			BlockNumber: req.BlockNumber,
+			Outpoint:    common.OutPoint2String(req.TxHash, uint(v.Index)),
+			AccountId:   v.AccountId,
+			Status:      v.Status,
+		})
+	}
+	for i, v := range ledgers {
+		if v.Action != common.DidCellActionUpgrade {
+			continue
 		}
-		_, cellDataNew, err := n.GetDataInfo()
+		accountInfo, err := b.dbDao.GetAccountInfoByAccountId(v.AccountId)
 		if err != nil {
-			resp.Err = fmt.Errorf("GetDataInfo new err: %s[%s]", err.Error(), k)
-			return
+			return nil, fmt.Errorf("GetAccountInfoByAccountId err: %s", err.Error())
+		}
+		if accountInfo.Id == 0 {
+			continue
+		}
+		ledgers[i].FromAlgorithmId = accountInfo.OwnerAlgorithmId
+		ledgers[i].FromChainType = accountInfo.OwnerChainType
+		ledgers[i].FromAddress = accountInfo.Owner
+	}
+	return accountInfos, nil
+}
+
+type didCellChanges struct {
+	oldOutpointList []string
+	list            []dao.TableDidCellInfo
+	records         []dao.DidCellRecords
+	txList          []dao.TableTransactionInfo
+	ledgers         []dao.TableOwnershipLedger
+}
+
+// getDidCellChanges pairs the did cells of the inputs and outputs by type args.
+// A cell only in the outputs is upgraded, a cell only in the inputs is recycled,
+// and a cell in both is checked for owner, expiry and records changes
+func getDidCellChanges(req FuncTransactionHandleReq, an *normalize.AddressNormalizer, txDidEntityWitness witness.TxDidEntityWitness) (res didCellChanges, err error) {
+	var keys []string
+	for k := range req.TxDidCellMap.Inputs {
+		keys = append(keys, k)
+	}
+	for k := range req.TxDidCellMap.Outputs {
+		if _, ok := req.TxDidCellMap.Inputs[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	txInfo := func(accountId, account string, action common.DidCellAction, addr string, index uint64) dao.TableTransactionInfo {
+		return dao.TableTransactionInfo{
+			BlockNumber:    req.BlockNumber,
+			AccountId:      accountId,
+			Account:        account,
+			Action:         action,
+			ServiceType:    dao.ServiceTypeRegister,
+			ChainType:      common.ChainTypeAnyLock,
+			Address:        addr,
+			Capacity:       0,
+			Outpoint:       common.OutPoint2String(req.TxHash, uint(index)),
+			BlockTimestamp: req.BlockTimestamp,
+		}
+	}
+	ledger := func(accountId, account string, action common.DidCellAction, from, to string) dao.TableOwnershipLedger {
+		l := dao.TableOwnershipLedger{
+			BlockNumber:    req.BlockNumber,
+			BlockTimestamp: req.BlockTimestamp,
+			TxHash:         req.TxHash,
+			Action:         action,
+			AccountId:      accountId,
+			Account:        account,
+			Role:           dao.OwnershipRoleOwner,
+			FromAddress:    from,
+			ToAddress:      to,
+		}
+		if from != "" {
+			l.FromAlgorithmId, l.FromChainType = common.DasAlgorithmIdAnyLock, common.ChainTypeAnyLock
+		}
+		if to != "" {
+			l.ToAlgorithmId, l.ToChainType = common.DasAlgorithmIdAnyLock, common.ChainTypeAnyLock
+		}
+		return l
+	}
+
+	for _, k := range keys {
+		v, hasOld := req.TxDidCellMap.Inputs[k]
+		n, hasNew := req.TxDidCellMap.Outputs[k]
+
+		var addrOld, addrNew string
+		var cellDataOld, cellDataNew *witness.DidCellDataLV
+		if hasOld {
+			if _, cellDataOld, err = v.GetDataInfo(); err != nil {
+				return res, fmt.Errorf("GetDataInfo old err: %s[%s]", err.Error(), k)
+			}
+			lockOld, err := an.AnyLock(v.Lock)
+			if err != nil {
+				return res, fmt.Errorf("AnyLock old err: %s[%s]", err.Error(), k)
+			}
+			addrOld = lockOld.Hex
+			res.oldOutpointList = append(res.oldOutpointList, common.OutPointStruct2String(v.OutPoint))
+		}
+		if hasNew {
+			if _, cellDataNew, err = n.GetDataInfo(); err != nil {
+				return res, fmt.Errorf("GetDataInfo new err: %s[%s]", err.Error(), k)
+			}
+			lockNew, err := an.AnyLock(n.Lock)
+			if err != nil {
+				return res, fmt.Errorf("AnyLock new err: %s[%s]", err.Error(), k)
+			}
+			addrNew = lockNew.Hex
+		}
+
+		account := ""
+		if hasOld {
+			account = cellDataOld.Account
+		} else {
+			account = cellDataNew.Account
 		}
-		account := cellDataOld.Account
 		accountId := common.Bytes2Hex(common.GetAccountIdByAccount(account))
 
-		oldOutpoint := common.OutPointStruct2String(v.OutPoint)
-		oldOutpointList = append(oldOutpointList, oldOutpoint)
+		if !hasNew {
+			recycleTx := txInfo(accountId, account, common.DidCellActionRecycle, addrOld, v.Index)
+			res.records = append(res.records, dao.DidCellRecords{AccountId: accountId, TxInfo: recycleTx})
+			res.txList = append(res.txList, recycleTx)
+			res.ledgers = append(res.ledgers, ledger(accountId, account, common.DidCellActionRecycle, addrOld, ""))
+			continue
+		}
 
-		tmp := dao.TableDidCellInfo{
+		res.list = append(res.list, dao.TableDidCellInfo{
 			BlockNumber:  req.BlockNumber,
 			Outpoint:     common.OutPointStruct2String(n.OutPoint),
 			AccountId:    accountId,
@@ -58,124 +198,61 @@ func (b *BlockParser) DidCellActionUpdate(req FuncTransactionHandleReq) (resp Fu
 			Args:         common.Bytes2Hex(n.Lock.Args),
 			LockCodeHash: n.Lock.CodeHash.Hex(),
 			ExpiredAt:    cellDataNew.ExpireAt,
+		})
+
+		var recordsTx *dao.TableTransactionInfo
+		if !hasOld {
+			upgradeTx := txInfo(accountId, account, common.DidCellActionUpgrade, addrNew, n.Index)
+			recordsTx = &upgradeTx
+			res.txList = append(res.txList, upgradeTx)
+			res.ledgers = append(res.ledgers, ledger(accountId, account, common.DidCellActionUpgrade, "", addrNew))
+		} else {
+			if !v.Lock.Equals(n.Lock) {
+				res.txList = append(res.txList, txInfo(accountId, account, common.DidCellActionEditOwner, addrOld, v.Index))
+				res.ledgers = append(res.ledgers, ledger(accountId, account, common.DidCellActionEditOwner, addrOld, addrNew))
+			}
+			if cellDataOld.ExpireAt != cellDataNew.ExpireAt {
+				res.txList = append(res.txList, txInfo(accountId, account, common.DidCellActionRenew, addrOld, v.Index))
+			}
+			if !bytes.Equal(cellDataOld.WitnessHash, cellDataNew.WitnessHash) {
+				editTx := txInfo(accountId, account, common.DidCellActionEditRecords, addrOld, v.Index)
+				recordsTx = &editTx
+				res.txList = append(res.txList, editTx)
+			}
 		}
-		list = append(list, tmp)
-		addrOld, err := v.GetLockAddress(b.dasCore.NetType())
-		if err != nil {
-			resp.Err = fmt.Errorf("GetLockAddress err: %s", err.Error())
-			return
-		}
-		if !v.Lock.Equals(n.Lock) {
-			txList = append(txList, dao.TableTransactionInfo{
-				BlockNumber:    req.BlockNumber,
-				AccountId:      accountId,
-				Account:        account,
-				Action:         common.DidCellActionEditOwner,
-				ServiceType:    dao.ServiceTypeRegister,
-				ChainType:      common.ChainTypeAnyLock,
-				Address:        addrOld,
-				Capacity:       0,
-				Outpoint:       common.OutPoint2String(req.TxHash, uint(v.Index)),
-				BlockTimestamp: req.BlockTimestamp,
-			})
+		if recordsTx == nil {
+			continue
 		}
-		if cellDataOld.ExpireAt != cellDataNew.ExpireAt {
-			txList = append(txList, dao.TableTransactionInfo{
-				BlockNumber:    req.BlockNumber,
-				AccountId:      accountId,
-				Account:        account,
-				Action:         common.DidCellActionRenew,
-				ServiceType:    dao.ServiceTypeRegister,
-				ChainType:      common.ChainTypeAnyLock,
-				Address:        addrOld,
-				Capacity:       0,
-				Outpoint:       common.OutPoint2String(req.TxHash, uint(v.Index)),
-				BlockTimestamp: req.BlockTimestamp,
-			})
+		// without the witness of the new cell its records are unknown, the indexed ones are kept
+		w, ok := txDidEntityWitness.Outputs[n.Index]
+		if !ok || w.DidCellWitnessDataV0 == nil {
+			log.Warn("getDidCellChanges: no witness of the did cell, records kept:", req.TxHash, account)
+			continue
 		}
-
-		if bytes.Compare(cellDataOld.WitnessHash, cellDataNew.WitnessHash) != 0 {
-			txList = append(txList, dao.TableTransactionInfo{
-				BlockNumber:    req.BlockNumber,
-				AccountId:      accountId,
-				Account:        account,
-				Action:         common.DidCellActionEditRecords,
-				ServiceType:    dao.ServiceTypeRegister,
-				ChainType:      common.ChainTypeAnyLock,
-				Address:        addrOld,
-				Capacity:       0,
-				Outpoint:       common.OutPoint2String(req.TxHash, uint(v.Index)),
-				BlockTimestamp: req.BlockTimestamp,
+		parentAccountId := didCellParentAccountId(account)
+		records := dao.DidCellRecords{AccountId: accountId, TxInfo: *recordsTx}
+		for _, r := range w.DidCellWitnessDataV0.Records {
+			records.Records = append(records.Records, dao.TableRecordsInfo{
+				AccountId:       accountId,
+				ParentAccountId: parentAccountId,
+				Account:         account,
+				Key:             r.Key,
+				Type:            r.Type,
+				Label:           r.Label,
+				Value:           r.Value,
+				Ttl:             strconv.FormatUint(uint64(r.TTL), 10),
 			})
-
-			accountIds = append(accountIds, accountId)
-			if w, yes := txDidEntityWitness.Outputs[n.Index]; yes {
-				for _, r := range w.DidCellWitnessDataV0.Records {
-					records = append(records, dao.TableRecordsInfo{
-						AccountId:       accountId,
-						ParentAccountId: "",
-						Account:         account,
-						Key:             r.Key,
-						Type:            r.Type,
-						Label:           r.Label,
-						Value:           r.Value,
-						Ttl:             strconv.FormatUint(uint64(r.TTL), 10),
-					})
-				}
-			}
 		}
-	}
-
-	if err := b.dbDao.DidCellUpdateList(oldOutpointList, list, accountIds, records, txList); err != nil {
-		resp.Err = fmt.Errorf("DidCellUpdateList err: %s", err.Error())
-		return
+		res.records = append(res.records, records)
 	}
 	return
 }
 
-func (b *BlockParser) DidCellActionRecycle(req FuncTransactionHandleReq) (resp FuncTransactionHandleResp) {
-	log.Info("DidCellActionRecycle:", req.BlockNumber, req.TxHash, req.Action)
-
-	var oldOutpointList []string
-	var accountIds []string
-	var txList []dao.TableTransactionInfo
-	for k, v := range req.TxDidCellMap.Inputs {
-		oldOutpoint := common.OutPointStruct2String(v.OutPoint)
-		oldOutpointList = append(oldOutpointList, oldOutpoint)
-
-		_, cellData, err := v.GetDataInfo()
-		if err != nil {
-			resp.Err = fmt.Errorf("GetDataInfo err: %s[%s]", err.Error(), k)
-			return
-		}
-		account := cellData.Account
-		accountId := common.Bytes2Hex(common.GetAccountIdByAccount(account))
-		accountIds = append(accountIds, accountId)
-
-		anyLockAddr, err := v.GetLockAddress(b.dasCore.NetType())
-		if err != nil {
-			resp.Err = fmt.Errorf("GetLockAddress err: %s[%s]", err.Error(), k)
-			return
-		}
-
-		txInfo := dao.TableTransactionInfo{
-			BlockNumber:    req.BlockNumber,
-			AccountId:      accountId,
-			Account:        account,
-			Action:         common.DidCellActionRecycle,
-			ServiceType:    dao.ServiceTypeRegister,
-			ChainType:      common.ChainTypeAnyLock,
-			Address:        anyLockAddr,
-			Capacity:       0,
-			Outpoint:       common.OutPoint2String(req.TxHash, uint(v.Index)),
-			BlockTimestamp: req.BlockTimestamp,
-		}
-		txList = append(txList, txInfo)
+// didCellParentAccountId the parent account id of a sub-account, empty for a top level account
+func didCellParentAccountId(account string) string {
+	if strings.Count(account, ".") < 2 {
+		return ""
 	}
-
-	if err := b.dbDao.DidCellRecycleList(oldOutpointList, accountIds, txList); err != nil {
-		resp.Err = fmt.Errorf("DidCellRecycleList err: %s", err.Error())
-		return
-	}
-	return
+	parent := account[strings.Index(account, ".")+1:]
+	return common.Bytes2Hex(common.GetAccountIdByAccount(parent))
 }
@@ -43,9 +43,9 @@ func (b *BlockParser) ActionConsolidateIncome(req FuncTransactionHandleReq) (res
 
 	for i, v := range req.Tx.Outputs {
 		if dasContract.IsSameTypeId(v.Lock.CodeHash) {
-			ownerHex, _, err := b.dasCore.Daf().ArgsToHex(v.Lock.Args)
+			ownerHex, _, err := b.an.FromArgs(v.Lock.Args)
 			if err != nil {
-				resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+				resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 				return
 			}
 			transactionInfos = append(transactionInfos, dao.TableTransactionInfo{
@@ -53,7 +53,7 @@ func (b *BlockParser) ActionConsolidateIncome(req FuncTransactionHandleReq) (res
 				Action:         common.DasActionConsolidateIncome,
 				ServiceType:    dao.ServiceTypeTransaction,
 				ChainType:      ownerHex.ChainType,
-				Address:        ownerHex.AddressHex,
+				Address:        ownerHex.Hex,
 				Capacity:       v.Capacity,
 				Outpoint:       common.OutPoint2String(req.TxHash, uint(i)),
 				BlockTimestamp: req.BlockTimestamp,
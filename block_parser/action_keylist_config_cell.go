@@ -21,22 +21,26 @@ func (b *BlockParser) ActionCreateDeviceKeyList(req FuncTransactionHandleReq) (r
 	log.Info("ActionCreateDeviceKeyList:", req.BlockNumber, req.TxHash)
 
 	builder, err := witness.WebAuthnKeyListDataBuilderFromTx(req.Tx, common.DataTypeNew)
+	if err != nil {
+		resp.Err = fmt.Errorf("WebAuthnKeyListDataBuilderFromTx err: %s", err.Error())
+		return
+	}
 	//add cidpk
-	var cidPk []dao.TableCidPk
 	keyList := witness.ConvertToWebauthnKeyList(builder.DeviceKeyListCellData.Keys())
 	if len(keyList) == 0 {
-		resp.Err = fmt.Errorf("ConvertToWebauthnKeyList err: %s", err.Error())
+		resp.Err = fmt.Errorf("ConvertToWebauthnKeyList err: key list is empty")
 		return
 	}
-	//var cidPk []dao.TableCidPk
-	cidPk = append([]dao.TableCidPk{}, dao.TableCidPk{
+	outpoint := common.OutPoint2String(req.TxHash, uint(builder.Index))
+	cidPk := dao.TableCidPk{
 		Cid:             keyList[0].Cid,
 		Pk:              keyList[0].PubKey,
 		EnableAuthorize: dao.EnableAuthorizeOn,
-		Outpoint:        common.OutPoint2String(req.TxHash, uint(builder.Index)),
-	})
-	if err := b.dbDao.InsertCidPk(cidPk); err != nil {
-		resp.Err = fmt.Errorf("InsertCidPk err: %s", err.Error())
+		Outpoint:        outpoint,
+	}
+	history := deviceKeyHistory(req, outpoint, cidPk.Cid, cidPk.Pk, keyList)
+	if err := b.dbDao.CreateDeviceKeyList(cidPk, history); err != nil {
+		resp.Err = fmt.Errorf("CreateDeviceKeyList err: %s", err.Error())
 		return
 	}
 	return
@@ -59,15 +63,15 @@ func (b *BlockParser) ActionUpdateDeviceKeyList(req FuncTransactionHandleReq) (r
 		return
 	}
 	log.Info("args: ", common.Bytes2Hex(req.Tx.Outputs[0].Lock.Args))
-	ownerHex, _, err := b.dasCore.Daf().ArgsToHex(req.Tx.Outputs[0].Lock.Args)
+	ownerHex, _, err := b.an.FromArgs(req.Tx.Outputs[0].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 	var masterCidPk1 dao.TableCidPk
 
-	masterCidPk1.Cid = common.Bytes2Hex(ownerHex.AddressPayload[:10])
-	masterCidPk1.Pk = common.Bytes2Hex(ownerHex.AddressPayload[10:])
+	masterCidPk1.Cid = common.Bytes2Hex(ownerHex.Payload()[:10])
+	masterCidPk1.Pk = common.Bytes2Hex(ownerHex.Payload()[10:])
 	masterCidPk1.Outpoint = common.OutPoint2String(req.TxHash, 0)
 
 	webauthnSignLv, err := witness.GetWebAuthnSignByWitnessArgs(req.Tx.Witnesses[0])
@@ -122,9 +126,29 @@ func (b *BlockParser) ActionUpdateDeviceKeyList(req FuncTransactionHandleReq) (r
 			Outpoint:       common.OutPoint2String(req.TxHash, 0),
 		})
 	}
-	if err = b.dbDao.UpdateAuthorizeByMaster(authorize, masterCidPk1, slaveCidPksSign, slaveCidPks); err != nil {
+	history := deviceKeyHistory(req, masterCidPk1.Outpoint, masterCidPk1.Cid, masterCidPk1.Pk, keyList)
+	if err = b.dbDao.UpdateAuthorizeByMaster(authorize, masterCidPk1, slaveCidPksSign, slaveCidPks, history); err != nil {
 		resp.Err = fmt.Errorf("UpdateAuthorizeByMaster err: %s", err.Error())
 		return
 	}
 	return
 }
+
+// deviceKeyHistory the keys of the key list cell at outpoint
+func deviceKeyHistory(req FuncTransactionHandleReq, outpoint, masterCid, masterPk string, keyList []witness.WebauthnKey) (list []dao.TableAuthorizeHistory) {
+	for _, v := range keyList {
+		list = append(list, dao.TableAuthorizeHistory{
+			BlockNumber:    req.BlockNumber,
+			BlockTimestamp: req.BlockTimestamp,
+			Outpoint:       outpoint,
+			Action:         req.Action,
+			MasterCid:      masterCid,
+			MasterPk:       masterPk,
+			SlaveAlgId:     common.DasAlgorithmId(v.MinAlgId),
+			SlaveSubAlgId:  common.DasAlgorithmId(v.SubAlgId),
+			SlaveCid:       v.Cid,
+			SlavePk:        v.PubKey,
+		})
+	}
+	return
+}
@@ -29,23 +29,30 @@ func (b *BlockParser) ActionMakeOffer(req FuncTransactionHandleReq) (resp FuncTr
 		resp.Err = fmt.Errorf("OfferCellDataBuilderFromTx err: %s", err.Error())
 		return
 	}
-	ownerHex, _, err := b.dasCore.Daf().ArgsToHex(req.Tx.Outputs[builder.Index].Lock.Args)
+	ownerHex, _, err := b.an.FromArgs(req.Tx.Outputs[builder.Index].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 
 	accountId := common.Bytes2Hex(common.GetAccountIdByAccount(builder.Account))
-	tokenInfo := timer.GetTokenPriceInfo(timer.TokenIdCkb)
+	tokenInfo, ok, err := timer.GetTokenPriceInfoAt(b.dbDao, timer.TokenIdCkb, req.BlockTimestamp)
+	if err != nil {
+		resp.Err = fmt.Errorf("GetTokenPriceInfoAt err: %s", err.Error())
+		return
+	}
+	if !ok {
+		log.Warn("no fresh price at block time, price_usd left empty:", timer.TokenIdCkb, req.BlockTimestamp, req.TxHash)
+	}
 	priceUsd := tokenInfo.GetPriceUsd(builder.Price)
 	offerInfo := dao.TableOfferInfo{
 		BlockNumber:    req.BlockNumber,
 		Outpoint:       common.OutPoint2String(req.TxHash, uint(builder.Index)),
 		AccountId:      accountId,
 		Account:        builder.Account,
-		AlgorithmId:    ownerHex.DasAlgorithmId,
+		AlgorithmId:    ownerHex.AlgorithmId,
 		ChainType:      ownerHex.ChainType,
-		Address:        ownerHex.AddressHex,
+		Address:        ownerHex.Hex,
 		BlockTimestamp: req.BlockTimestamp,
 		Price:          builder.Price,
 		PriceUsd:       priceUsd,
@@ -60,7 +67,7 @@ func (b *BlockParser) ActionMakeOffer(req FuncTransactionHandleReq) (resp FuncTr
 		Action:         common.DasActionMakeOffer,
 		ServiceType:    dao.ServiceTypeTransaction,
 		ChainType:      ownerHex.ChainType,
-		Address:        ownerHex.AddressHex,
+		Address:        ownerHex.Hex,
 		Capacity:       req.Tx.Outputs[builder.Index].Capacity,
 		Outpoint:       common.OutPoint2String(req.TxHash, uint(builder.Index)),
 		BlockTimestamp: req.BlockTimestamp,
@@ -97,14 +104,21 @@ func (b *BlockParser) ActionEditOffer(req FuncTransactionHandleReq) (resp FuncTr
 		resp.Err = fmt.Errorf("OfferCellDataBuilderFromTx err: %s", err.Error())
 		return
 	}
-	ownerHex, _, err := b.dasCore.Daf().ArgsToHex(req.Tx.Outputs[builder.Index].Lock.Args)
+	ownerHex, _, err := b.an.FromArgs(req.Tx.Outputs[builder.Index].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 
 	accountId := common.Bytes2Hex(common.GetAccountIdByAccount(builder.Account))
-	tokenInfo := timer.GetTokenPriceInfo(timer.TokenIdCkb)
+	tokenInfo, ok, err := timer.GetTokenPriceInfoAt(b.dbDao, timer.TokenIdCkb, req.BlockTimestamp)
+	if err != nil {
+		resp.Err = fmt.Errorf("GetTokenPriceInfoAt err: %s", err.Error())
+		return
+	}
+	if !ok {
+		log.Warn("no fresh price at block time, price_usd left empty:", timer.TokenIdCkb, req.BlockTimestamp, req.TxHash)
+	}
 	priceUsd := tokenInfo.GetPriceUsd(builder.Price)
 	offerInfo := dao.TableOfferInfo{
 		BlockNumber:    req.BlockNumber,
@@ -122,7 +136,7 @@ func (b *BlockParser) ActionEditOffer(req FuncTransactionHandleReq) (resp FuncTr
 		Account:        builder.Account,
 		ServiceType:    dao.ServiceTypeTransaction,
 		ChainType:      ownerHex.ChainType,
-		Address:        ownerHex.AddressHex,
+		Address:        ownerHex.Hex,
 		Outpoint:       common.OutPoint2String(req.TxHash, uint(builder.Index)),
 		BlockTimestamp: req.BlockTimestamp,
 	}
@@ -175,9 +189,9 @@ func (b *BlockParser) ActionCancelOffer(req FuncTransactionHandleReq) (resp Func
 		account = oldBuilderMap[common.OutPoint2String(req.TxHash, 0)].Account
 	}
 	accountId := common.Bytes2Hex(common.GetAccountIdByAccount(account))
-	ownerHex, _, err := b.dasCore.Daf().ArgsToHex(res.Transaction.Outputs[req.Tx.Inputs[0].PreviousOutput.Index].Lock.Args)
+	ownerHex, _, err := b.an.FromArgs(res.Transaction.Outputs[req.Tx.Inputs[0].PreviousOutput.Index].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 	transactionInfo := dao.TableTransactionInfo{
@@ -187,7 +201,7 @@ func (b *BlockParser) ActionCancelOffer(req FuncTransactionHandleReq) (resp Func
 		Action:         common.DasActionCancelOffer,
 		ServiceType:    dao.ServiceTypeTransaction,
 		ChainType:      ownerHex.ChainType,
-		Address:        ownerHex.AddressHex,
+		Address:        ownerHex.Hex,
 		Capacity:       req.Tx.OutputsCapacity(),
 		Outpoint:       common.OutPoint2String(req.TxHash, 0),
 		BlockTimestamp: req.BlockTimestamp,
@@ -269,9 +283,9 @@ func (b *BlockParser) ActionAcceptOffer(req FuncTransactionHandleReq) (resp Func
 		return
 	}
 
-	ownerHex, managerHex, err := b.dasCore.Daf().ArgsToHex(req.Tx.Outputs[buyerBuilder.Index].Lock.Args)
+	ownerHex, managerHex, err := b.an.FromArgs(req.Tx.Outputs[buyerBuilder.Index].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 	accountInfo := dao.TableAccountInfo{
@@ -280,13 +294,13 @@ func (b *BlockParser) ActionAcceptOffer(req FuncTransactionHandleReq) (resp Func
 		AccountId:          buyerBuilder.AccountId,
 		Account:            buyerBuilder.Account,
 		OwnerChainType:     ownerHex.ChainType,
-		Owner:              ownerHex.AddressHex,
-		OwnerAlgorithmId:   ownerHex.DasAlgorithmId,
-		OwnerSubAid:        ownerHex.DasSubAlgorithmId,
+		Owner:              ownerHex.Hex,
+		OwnerAlgorithmId:   ownerHex.AlgorithmId,
+		OwnerSubAid:        ownerHex.SubAlgorithmId,
 		ManagerChainType:   managerHex.ChainType,
-		Manager:            managerHex.AddressHex,
-		ManagerAlgorithmId: managerHex.DasAlgorithmId,
-		ManagerSubAid:      managerHex.DasSubAlgorithmId,
+		Manager:            managerHex.Hex,
+		ManagerAlgorithmId: managerHex.AlgorithmId,
+		ManagerSubAid:      managerHex.SubAlgorithmId,
 		Status:             buyerBuilder.Status,
 	}
 	transactionInfoBuy := dao.TableTransactionInfo{
@@ -296,14 +310,14 @@ func (b *BlockParser) ActionAcceptOffer(req FuncTransactionHandleReq) (resp Func
 		Action:         dao.DasActionOfferAccepted,
 		ServiceType:    dao.ServiceTypeTransaction,
 		ChainType:      ownerHex.ChainType,
-		Address:        ownerHex.AddressHex,
+		Address:        ownerHex.Hex,
 		Capacity:       0,
 		Outpoint:       common.OutPoint2String(req.TxHash, 0),
 		BlockTimestamp: req.BlockTimestamp,
 	}
-	ownerHex, _, err = b.dasCore.Daf().ArgsToHex(resAccount.Transaction.Outputs[req.Tx.Inputs[1].PreviousOutput.Index].Lock.Args)
+	ownerHex, _, err = b.an.FromArgs(resAccount.Transaction.Outputs[req.Tx.Inputs[1].PreviousOutput.Index].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 	transactionInfoSale := dao.TableTransactionInfo{
@@ -313,28 +327,36 @@ func (b *BlockParser) ActionAcceptOffer(req FuncTransactionHandleReq) (resp Func
 		Action:         common.DasActionAcceptOffer,
 		ServiceType:    dao.ServiceTypeTransaction,
 		ChainType:      ownerHex.ChainType,
-		Address:        ownerHex.AddressHex,
+		Address:        ownerHex.Hex,
 		Outpoint:       common.OutPoint2String(req.TxHash, 1),
 		BlockTimestamp: req.BlockTimestamp,
 	}
 	for i := 1; i < len(req.Tx.Outputs); i++ {
-		ownerHex, _, err = b.dasCore.Daf().ScriptToHex(req.Tx.Outputs[i].Lock)
+		ownerHex, _, err = b.an.FromScript(req.Tx.Outputs[i].Lock)
 		if err != nil {
-			resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+			resp.Err = fmt.Errorf("FromScript err: %s", err.Error())
 			return
 		}
-		if transactionInfoSale.ChainType == ownerHex.ChainType && strings.EqualFold(transactionInfoSale.Address, ownerHex.AddressHex) {
+		if transactionInfoSale.ChainType == ownerHex.ChainType && strings.EqualFold(transactionInfoSale.Address, ownerHex.Hex) {
 			transactionInfoSale.Capacity = req.Tx.Outputs[i].Capacity
 			break
 		}
 	}
-	tokenInfo := timer.GetTokenPriceInfo(timer.TokenIdCkb)
+	tokenInfo, ok, err := timer.GetTokenPriceInfoAt(b.dbDao, timer.TokenIdCkb, req.BlockTimestamp)
+	if err != nil {
+		resp.Err = fmt.Errorf("GetTokenPriceInfoAt err: %s", err.Error())
+		return
+	}
+	if !ok {
+		log.Warn("no fresh price at block time, price_usd left empty:", timer.TokenIdCkb, req.BlockTimestamp, req.TxHash)
+	}
 	tradeDealInfo := dao.TableTradeDealInfo{
 		BlockNumber:    req.BlockNumber,
 		Outpoint:       transactionInfoSale.Outpoint,
 		AccountId:      buyerBuilder.AccountId,
 		Account:        buyerBuilder.Account,
 		DealType:       dao.DealTypeOffer,
+		AccountLength:  common.GetAccountLength(buyerBuilder.Account),
 		SellChainType:  transactionInfoSale.ChainType,
 		SellAddress:    transactionInfoSale.Address,
 		BuyChainType:   transactionInfoBuy.ChainType,
@@ -394,9 +416,9 @@ func (b *BlockParser) getOfferRebateInfoList(salePrice decimal.Decimal, account
 		tmp := molecule.ScriptDefault()
 		channelScript = &tmp
 	}
-	inviterHex, _, err := b.dasCore.Daf().ScriptToHex(molecule.MoleculeScript2CkbScript(inviterScript))
+	inviterHex, _, err := b.an.FromScript(molecule.MoleculeScript2CkbScript(inviterScript))
 	if err != nil {
-		return list, fmt.Errorf("ScriptToHex err: %s", err.Error())
+		return list, fmt.Errorf("FromScript err: %s", err.Error())
 	}
 	inviteeId := common.Bytes2Hex(common.GetAccountIdByAccount(account))
 	list = append(list, dao.TableRebateInfo{
@@ -413,12 +435,12 @@ func (b *BlockParser) getOfferRebateInfoList(salePrice decimal.Decimal, account
 		InviterArgs:      common.Bytes2Hex(inviterScript.Args().RawData()),
 		InviterAccount:   "",
 		InviterChainType: inviterHex.ChainType,
-		InviterAddress:   inviterHex.AddressHex,
+		InviterAddress:   inviterHex.Hex,
 		BlockTimestamp:   req.BlockTimestamp,
 	})
-	channelHex, _, err := b.dasCore.Daf().ScriptToHex(molecule.MoleculeScript2CkbScript(channelScript))
+	channelHex, _, err := b.an.FromScript(molecule.MoleculeScript2CkbScript(channelScript))
 	if err != nil {
-		return list, fmt.Errorf("ScriptToHex err: %s", err.Error())
+		return list, fmt.Errorf("FromScript err: %s", err.Error())
 	}
 	list = append(list, dao.TableRebateInfo{
 		BlockNumber:      req.BlockNumber,
@@ -434,7 +456,7 @@ func (b *BlockParser) getOfferRebateInfoList(salePrice decimal.Decimal, account
 		InviterArgs:      common.Bytes2Hex(channelScript.Args().RawData()),
 		InviterAccount:   "",
 		InviterChainType: channelHex.ChainType,
-		InviterAddress:   channelHex.AddressHex,
+		InviterAddress:   channelHex.Hex,
 		BlockTimestamp:   req.BlockTimestamp,
 	})
 	return list, nil
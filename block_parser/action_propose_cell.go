@@ -125,15 +125,15 @@ func (b *BlockParser) ActionConfirmProposal(req FuncTransactionHandleReq) (resp
 
 	for _, v := range accMap {
 
-		ownerHex, managerHex, err := b.dasCore.Daf().ArgsToHex(req.Tx.Outputs[v.Index].Lock.Args)
+		ownerHex, managerHex, err := b.an.FromArgs(req.Tx.Outputs[v.Index].Lock.Args)
 		if err != nil {
-			resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+			resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 			return
 		}
-		if ownerHex.DasAlgorithmId == common.DasAlgorithmIdWebauthn {
+		if ownerHex.AlgorithmId == common.DasAlgorithmIdWebauthn {
 			cidPks = append(cidPks, dao.TableCidPk{
-				Cid: common.Bytes2Hex(ownerHex.AddressPayload[:10]),
-				Pk:  common.Bytes2Hex(ownerHex.AddressPayload[10:]),
+				Cid: common.Bytes2Hex(ownerHex.Payload()[:10]),
+				Pk:  common.Bytes2Hex(ownerHex.Payload()[10:]),
 			})
 		}
 
@@ -145,13 +145,13 @@ func (b *BlockParser) ActionConfirmProposal(req FuncTransactionHandleReq) (resp
 			AccountId:           v.AccountId,
 			Account:             v.Account,
 			OwnerChainType:      ownerHex.ChainType,
-			Owner:               ownerHex.AddressHex,
-			OwnerAlgorithmId:    ownerHex.DasAlgorithmId,
-			OwnerSubAid:         ownerHex.DasSubAlgorithmId,
+			Owner:               ownerHex.Hex,
+			OwnerAlgorithmId:    ownerHex.AlgorithmId,
+			OwnerSubAid:         ownerHex.SubAlgorithmId,
 			ManagerChainType:    managerHex.ChainType,
-			Manager:             managerHex.AddressHex,
-			ManagerAlgorithmId:  managerHex.DasAlgorithmId,
-			ManagerSubAid:       managerHex.DasSubAlgorithmId,
+			Manager:             managerHex.Hex,
+			ManagerAlgorithmId:  managerHex.AlgorithmId,
+			ManagerSubAid:       managerHex.SubAlgorithmId,
 			Status:              v.Status,
 			RegisteredAt:        v.RegisteredAt,
 			ExpiredAt:           v.ExpiredAt,
@@ -173,16 +173,16 @@ func (b *BlockParser) ActionConfirmProposal(req FuncTransactionHandleReq) (resp
 				Action:         common.DasActionConfirmProposal,
 				ServiceType:    dao.ServiceTypeRegister,
 				ChainType:      ownerHex.ChainType,
-				Address:        ownerHex.AddressHex,
+				Address:        ownerHex.Hex,
 				Capacity:       req.Tx.Outputs[v.Index].Capacity,
 				Outpoint:       common.OutPoint2String(req.TxHash, uint(v.Index)),
 				BlockTimestamp: req.BlockTimestamp,
 			})
 
 			argsStr := preAcc.OwnerLockArgs
-			inviteeHex, _, err := b.dasCore.Daf().ArgsToHex(common.Hex2Bytes(argsStr))
+			inviteeHex, _, err := b.an.FromArgs(common.Hex2Bytes(argsStr))
 			if err != nil {
-				resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+				resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 				return
 			}
 			inviterId := preAcc.InviterId
@@ -200,9 +200,9 @@ func (b *BlockParser) ActionConfirmProposal(req FuncTransactionHandleReq) (resp
 				tmp := molecule.ScriptDefault()
 				inviterLock = &tmp
 			}
-			inviterHex, _, err := b.dasCore.Daf().ScriptToHex(molecule.MoleculeScript2CkbScript(inviterLock))
+			inviterHex, _, err := b.an.FromScript(molecule.MoleculeScript2CkbScript(inviterLock))
 			if err != nil {
-				resp.Err = fmt.Errorf("ScriptToHex err: %s", err.Error())
+				resp.Err = fmt.Errorf("FromScript err: %s", err.Error())
 				return
 			}
 			inviteeId := common.Bytes2Hex(common.GetAccountIdByAccount(preAcc.Account))
@@ -212,7 +212,7 @@ func (b *BlockParser) ActionConfirmProposal(req FuncTransactionHandleReq) (resp
 				InviteeId:        inviteeId,
 				InviteeAccount:   preAcc.Account,
 				InviteeChainType: inviteeHex.ChainType,
-				InviteeAddress:   inviteeHex.AddressHex,
+				InviteeAddress:   inviteeHex.Hex,
 				RewardType:       dao.RewardTypeInviter,
 				Reward:           capacity.Div(decimal.NewFromInt(common.PercentRateBase)).Mul(decimal.NewFromInt(int64(profitRateInviter))).BigInt().Uint64(),
 				Action:           common.DasActionConfirmProposal,
@@ -220,7 +220,7 @@ func (b *BlockParser) ActionConfirmProposal(req FuncTransactionHandleReq) (resp
 				InviterArgs:      common.Bytes2Hex(inviterLock.Args().RawData()),
 				InviterId:        inviterId,
 				InviterChainType: inviterHex.ChainType,
-				InviterAddress:   inviterHex.AddressHex,
+				InviterAddress:   inviterHex.Hex,
 				BlockTimestamp:   req.BlockTimestamp,
 			})
 
@@ -230,9 +230,9 @@ func (b *BlockParser) ActionConfirmProposal(req FuncTransactionHandleReq) (resp
 				tmp := molecule.ScriptDefault()
 				channelLock = &tmp
 			}
-			channelHex, _, err := b.dasCore.Daf().ScriptToHex(molecule.MoleculeScript2CkbScript(channelLock))
+			channelHex, _, err := b.an.FromScript(molecule.MoleculeScript2CkbScript(channelLock))
 			if err != nil {
-				resp.Err = fmt.Errorf("ScriptToHex err: %s", err.Error())
+				resp.Err = fmt.Errorf("FromScript err: %s", err.Error())
 				return
 			}
 			rebateInfos = append(rebateInfos, dao.TableRebateInfo{
@@ -241,14 +241,14 @@ func (b *BlockParser) ActionConfirmProposal(req FuncTransactionHandleReq) (resp
 				InviteeId:        inviteeId,
 				InviteeAccount:   preAcc.Account,
 				InviteeChainType: inviteeHex.ChainType,
-				InviteeAddress:   inviteeHex.AddressHex,
+				InviteeAddress:   inviteeHex.Hex,
 				RewardType:       dao.RewardTypeChannel,
 				Reward:           capacity.Div(decimal.NewFromInt(common.PercentRateBase)).Mul(decimal.NewFromInt(int64(profitRateChannel))).BigInt().Uint64(),
 				Action:           common.DasActionConfirmProposal,
 				ServiceType:      dao.ServiceTypeRegister,
 				InviterArgs:      common.Bytes2Hex(channelLock.Args().RawData()),
 				InviterChainType: channelHex.ChainType,
-				InviterAddress:   channelHex.AddressHex,
+				InviterAddress:   channelHex.Hex,
 				BlockTimestamp:   req.BlockTimestamp,
 			})
 
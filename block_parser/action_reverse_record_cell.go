@@ -17,9 +17,9 @@ func (b *BlockParser) ActionDeclareReverseRecord(req FuncTransactionHandleReq) (
 	log.Info("ActionDeclareReverseRecord:", req.BlockNumber, req.TxHash)
 
 	account := string(req.Tx.OutputsData[0])
-	oHex, _, err := b.dasCore.Daf().ArgsToHex(req.Tx.Outputs[0].Lock.Args)
+	oHex, _, err := b.an.FromArgs(req.Tx.Outputs[0].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 
@@ -28,9 +28,9 @@ func (b *BlockParser) ActionDeclareReverseRecord(req FuncTransactionHandleReq) (
 		BlockNumber:    req.BlockNumber,
 		BlockTimestamp: req.BlockTimestamp,
 		Outpoint:       common.OutPoint2String(req.TxHash, 0),
-		AlgorithmId:    oHex.DasAlgorithmId,
+		AlgorithmId:    oHex.AlgorithmId,
 		ChainType:      oHex.ChainType,
-		Address:        oHex.AddressHex,
+		Address:        oHex.Hex,
 		Account:        account,
 		AccountId:      accountId,
 		Capacity:       req.Tx.Outputs[0].Capacity,
@@ -43,7 +43,7 @@ func (b *BlockParser) ActionDeclareReverseRecord(req FuncTransactionHandleReq) (
 		Action:         common.DasActionDeclareReverseRecord,
 		ServiceType:    dao.ServiceTypeRegister,
 		ChainType:      oHex.ChainType,
-		Address:        oHex.AddressHex,
+		Address:        oHex.Hex,
 		Capacity:       req.Tx.Outputs[0].Capacity,
 		Outpoint:       common.OutPoint2String(req.TxHash, 0),
 		BlockTimestamp: req.BlockTimestamp,
@@ -70,9 +70,9 @@ func (b *BlockParser) ActionRedeclareReverseRecord(req FuncTransactionHandleReq)
 	lastOutpoint := common.OutPointStruct2String(req.Tx.Inputs[0].PreviousOutput)
 	account := string(req.Tx.OutputsData[0])
 
-	ownerHex, _, err := b.dasCore.Daf().ArgsToHex(req.Tx.Outputs[0].Lock.Args)
+	ownerHex, _, err := b.an.FromArgs(req.Tx.Outputs[0].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 
@@ -81,9 +81,9 @@ func (b *BlockParser) ActionRedeclareReverseRecord(req FuncTransactionHandleReq)
 		BlockNumber:    req.BlockNumber,
 		BlockTimestamp: req.BlockTimestamp,
 		Outpoint:       common.OutPoint2String(req.TxHash, 0),
-		AlgorithmId:    ownerHex.DasAlgorithmId,
+		AlgorithmId:    ownerHex.AlgorithmId,
 		ChainType:      ownerHex.ChainType,
-		Address:        ownerHex.AddressHex,
+		Address:        ownerHex.Hex,
 		AccountId:      accountId,
 		Account:        account,
 		Capacity:       req.Tx.Outputs[0].Capacity,
@@ -96,7 +96,7 @@ func (b *BlockParser) ActionRedeclareReverseRecord(req FuncTransactionHandleReq)
 		Action:         common.DasActionRedeclareReverseRecord,
 		ServiceType:    dao.ServiceTypeRegister,
 		ChainType:      ownerHex.ChainType,
-		Address:        ownerHex.AddressHex,
+		Address:        ownerHex.Hex,
 		Capacity:       req.Tx.Outputs[0].Capacity,
 		Outpoint:       common.OutPoint2String(req.TxHash, 0),
 		BlockTimestamp: req.BlockTimestamp,
@@ -130,9 +130,9 @@ func (b *BlockParser) ActionRetractReverseRecord(req FuncTransactionHandleReq) (
 		listOutpoint = append(listOutpoint, common.OutPointStruct2String(v.PreviousOutput))
 	}
 
-	ownerHex, _, err := b.dasCore.Daf().ArgsToHex(res.Transaction.Outputs[req.Tx.Inputs[0].PreviousOutput.Index].Lock.Args)
+	ownerHex, _, err := b.an.FromArgs(res.Transaction.Outputs[req.Tx.Inputs[0].PreviousOutput.Index].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 	txInfo := dao.TableTransactionInfo{
@@ -142,7 +142,7 @@ func (b *BlockParser) ActionRetractReverseRecord(req FuncTransactionHandleReq) (
 		Action:         common.DasActionRetractReverseRecord,
 		ServiceType:    dao.ServiceTypeRegister,
 		ChainType:      ownerHex.ChainType,
-		Address:        ownerHex.AddressHex,
+		Address:        ownerHex.Hex,
 		Capacity:       req.Tx.OutputsCapacity(),
 		Outpoint:       common.OutPoint2String(req.TxHash, 0),
 		BlockTimestamp: req.BlockTimestamp,
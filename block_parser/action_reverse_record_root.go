@@ -38,7 +38,7 @@ func (b *BlockParser) ActionReverseRecordRoot(req FuncTransactionHandleReq) (res
 			return
 		}
 		algorithmId := common.DasAlgorithmId(v.SignType)
-		address := common.FormatAddressPayload(v.Address, algorithmId)
+		address := b.an.FromPayload(v.Address, algorithmId).ReverseHex()
 
 		outpoint := common.OutPoint2String(req.TxHash, uint(idx))
 		smtRecord := &dao.ReverseSmtInfo{
@@ -66,7 +66,7 @@ func (b *BlockParser) ActionReverseRecordRoot(req FuncTransactionHandleReq) (res
 			outpoint := common.OutPoint2String(req.TxHash, uint(idx))
 			accountId := common.Bytes2Hex(common.GetAccountIdByAccount(v.NextAccount))
 			algorithmId := common.DasAlgorithmId(v.SignType)
-			address := common.FormatAddressPayload(v.Address, algorithmId)
+			address := b.an.FromPayload(v.Address, algorithmId).ReverseHex()
 			p2shP2wpkh, err := v.GetP2SHP2WPKH(b.dasCore.NetType())
 			if err != nil {
 				log.Error("GetP2SHP2WPKH err: %s", err.Error())
@@ -1,8 +1,10 @@
 package block_parser
 
 import (
+	"bytes"
 	"das_database/config"
 	"das_database/dao"
+	"encoding/json"
 	"fmt"
 	"github.com/dotbitHQ/das-lib/common"
 	"github.com/dotbitHQ/das-lib/core"
@@ -31,9 +33,9 @@ func (b *BlockParser) ActionEnableSubAccount(req FuncTransactionHandleReq) (resp
 		resp.Err = fmt.Errorf("AccountCellDataBuilderFromTx err: %s", err.Error())
 		return
 	}
-	ownerHex, _, err := b.dasCore.Daf().ArgsToHex(req.Tx.Outputs[builder.Index].Lock.Args)
+	ownerHex, _, err := b.an.FromArgs(req.Tx.Outputs[builder.Index].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 
@@ -51,17 +53,17 @@ func (b *BlockParser) ActionEnableSubAccount(req FuncTransactionHandleReq) (resp
 		Action:         common.DasActionEnableSubAccount,
 		ServiceType:    dao.ServiceTypeRegister,
 		ChainType:      ownerHex.ChainType,
-		Address:        ownerHex.AddressHex,
+		Address:        ownerHex.Hex,
 		Capacity:       req.Tx.Outputs[1].Capacity,
 		Outpoint:       common.OutPoint2String(req.TxHash, 1),
 		BlockTimestamp: req.BlockTimestamp,
 	}
-	feeOwner, _, err := b.dasCore.Daf().ScriptToHex(req.Tx.Outputs[len(req.Tx.Outputs)-1].Lock)
+	feeOwner, _, err := b.an.FromScript(req.Tx.Outputs[len(req.Tx.Outputs)-1].Lock)
 	if err != nil {
-		resp.Err = fmt.Errorf("ScriptToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromScript err: %s", err.Error())
 		return
 	}
-	if feeOwner.AddressHex != ownerHex.AddressHex {
+	if feeOwner.Hex != ownerHex.Hex {
 		transactionInfo.Capacity = 0
 	}
 
@@ -183,9 +185,9 @@ func (b *BlockParser) actionUpdateSubAccountForRecycle(req FuncTransactionHandle
 			LeafDataHash: common.Bytes2Hex(value),
 		}
 		smtInfos = append(smtInfos, smtInfo)
-		oHex, _, err := b.dasCore.Daf().ScriptToHex(builder.SubAccountData.Lock)
+		oHex, _, err := b.an.FromScript(builder.SubAccountData.Lock)
 		if err != nil {
-			return fmt.Errorf("ScriptToHex err: %s", err.Error())
+			return fmt.Errorf("FromScript err: %s", err.Error())
 		}
 		outpointTx := common.OutPoint2String(req.TxHash, indexTx)
 		txInfo := dao.TableTransactionInfo{
@@ -195,7 +197,7 @@ func (b *BlockParser) actionUpdateSubAccountForRecycle(req FuncTransactionHandle
 			Action:         common.DasActionRecycleExpiredAccount,
 			ServiceType:    dao.ServiceTypeRegister,
 			ChainType:      oHex.ChainType,
-			Address:        oHex.AddressHex,
+			Address:        oHex.Hex,
 			Capacity:       0,
 			Outpoint:       outpointTx,
 			BlockTimestamp: req.BlockTimestamp,
@@ -237,9 +239,9 @@ func (b *BlockParser) actionUpdateSubAccountForCreate(req FuncTransactionHandleR
 	var parentAccount string
 
 	for _, v := range createBuilderMap {
-		ownerHex, managerHex, err := b.dasCore.Daf().ArgsToHex(v.SubAccountData.Lock.Args)
+		ownerHex, managerHex, err := b.an.FromArgs(v.SubAccountData.Lock.Args)
 		if err != nil {
-			return fmt.Errorf("ArgsToHex err: %s", err.Error())
+			return fmt.Errorf("FromArgs err: %s", err.Error())
 		}
 		accountInfos = append(accountInfos, dao.TableAccountInfo{
 			BlockNumber:          req.BlockNumber,
@@ -248,13 +250,13 @@ func (b *BlockParser) actionUpdateSubAccountForCreate(req FuncTransactionHandleR
 			ParentAccountId:      parentAccountId,
 			Account:              v.Account,
 			OwnerChainType:       ownerHex.ChainType,
-			Owner:                ownerHex.AddressHex,
-			OwnerAlgorithmId:     ownerHex.DasAlgorithmId,
-			OwnerSubAid:          ownerHex.DasSubAlgorithmId,
+			Owner:                ownerHex.Hex,
+			OwnerAlgorithmId:     ownerHex.AlgorithmId,
+			OwnerSubAid:          ownerHex.SubAlgorithmId,
 			ManagerChainType:     managerHex.ChainType,
-			Manager:              managerHex.AddressHex,
-			ManagerAlgorithmId:   managerHex.DasAlgorithmId,
-			ManagerSubAid:        managerHex.DasSubAlgorithmId,
+			Manager:              managerHex.Hex,
+			ManagerAlgorithmId:   managerHex.AlgorithmId,
+			ManagerSubAid:        managerHex.SubAlgorithmId,
 			Status:               v.SubAccountData.Status,
 			EnableSubAccount:     v.SubAccountData.EnableSubAccount,
 			RenewSubAccountPrice: v.SubAccountData.RenewSubAccountPrice,
@@ -294,9 +296,9 @@ func (b *BlockParser) actionUpdateSubAccountForCreate(req FuncTransactionHandleR
 		}
 	}
 
-	ownerHex, _, err := b.dasCore.Daf().ScriptToHex(req.Tx.Outputs[len(req.Tx.Outputs)-1].Lock)
+	ownerHex, _, err := b.an.FromScript(req.Tx.Outputs[len(req.Tx.Outputs)-1].Lock)
 	if err != nil {
-		return fmt.Errorf("ArgsToHex err: %s", err.Error())
+		return fmt.Errorf("FromScript err: %s", err.Error())
 	}
 
 	if manualMintYears > 0 {
@@ -309,25 +311,18 @@ func (b *BlockParser) actionUpdateSubAccountForCreate(req FuncTransactionHandleR
 		Action:         common.DasActionCreateSubAccount,
 		ServiceType:    dao.ServiceTypeRegister,
 		ChainType:      ownerHex.ChainType,
-		Address:        ownerHex.AddressHex,
+		Address:        ownerHex.Hex,
 		Capacity:       manualCapacity,
 		Outpoint:       subAccountCellOutpoint,
 		BlockTimestamp: req.BlockTimestamp,
 	}
 
 	if err := b.dbDao.Transaction(func(tx *gorm.DB) error {
-		if len(subAccountIds) > 0 {
-			if err := tx.Where("account_id IN(?)", subAccountIds).
-				Delete(&dao.TableRecordsInfo{}).Error; err != nil {
-				return err
-			}
-		}
-		if len(records) > 0 {
-			if err := tx.Create(&records).Error; err != nil {
-				return err
-			}
+		if err := dao.ReplaceRecords(tx, subAccountIds, records, transactionInfo); err != nil {
+			return err
 		}
 		if len(accountInfos) > 0 {
+			dao.FillAccountSearchFields(accountInfos)
 			if err := tx.Clauses(clause.Insert{
 				Modifier: "IGNORE",
 			}).Create(&accountInfos).Error; err != nil {
@@ -447,9 +442,9 @@ func (b *BlockParser) actionUpdateSubAccountForRenew(req FuncTransactionHandleRe
 		})
 	}
 
-	ownerHex, _, err := b.dasCore.Daf().ScriptToHex(req.Tx.Outputs[len(req.Tx.Outputs)-1].Lock)
+	ownerHex, _, err := b.an.FromScript(req.Tx.Outputs[len(req.Tx.Outputs)-1].Lock)
 	if err != nil {
-		return fmt.Errorf("ArgsToHex err: %s", err.Error())
+		return fmt.Errorf("FromScript err: %s", err.Error())
 	}
 	if manualRenewYears > 0 {
 		manualCapacity = config.PriceToCKB(renewSubAccountPrice, quote, manualRenewYears)
@@ -462,7 +457,7 @@ func (b *BlockParser) actionUpdateSubAccountForRenew(req FuncTransactionHandleRe
 		Action:         common.DasActionRenewSubAccount,
 		ServiceType:    dao.ServiceTypeRegister,
 		ChainType:      ownerHex.ChainType,
-		Address:        ownerHex.AddressHex,
+		Address:        ownerHex.Hex,
 		Capacity:       manualCapacity,
 		Outpoint:       subAccountCellOutpoint,
 		BlockTimestamp: req.BlockTimestamp,
@@ -532,9 +527,9 @@ func (b *BlockParser) actionUpdateSubAccountForEdit(req FuncTransactionHandleReq
 
 	var index uint
 	for _, builder := range editBuilderMap {
-		ownerHex, _, err := b.dasCore.Daf().ArgsToHex(builder.SubAccountData.Lock.Args)
+		ownerHex, _, err := b.an.FromArgs(builder.SubAccountData.Lock.Args)
 		if err != nil {
-			return fmt.Errorf("ArgsToHex err: %s", err.Error())
+			return fmt.Errorf("FromArgs err: %s", err.Error())
 		}
 		outpoint := common.OutPoint2String(req.TxHash, 0)
 		accountInfo := dao.TableAccountInfo{
@@ -561,7 +556,7 @@ func (b *BlockParser) actionUpdateSubAccountForEdit(req FuncTransactionHandleReq
 			Action:         common.DasActionEditSubAccount,
 			ServiceType:    dao.ServiceTypeRegister,
 			ChainType:      ownerHex.ChainType,
-			Address:        ownerHex.AddressHex,
+			Address:        ownerHex.Hex,
 			Capacity:       0,
 			Outpoint:       common.OutPoint2String(outpoint, index),
 			BlockTimestamp: req.BlockTimestamp,
@@ -570,30 +565,30 @@ func (b *BlockParser) actionUpdateSubAccountForEdit(req FuncTransactionHandleReq
 
 		switch builder.EditKey {
 		case common.EditKeyOwner:
-			oHex, mHex, err := b.dasCore.Daf().ArgsToHex(builder.EditLockArgs)
+			oHex, mHex, err := b.an.FromArgs(builder.EditLockArgs)
 			if err != nil {
-				return fmt.Errorf("ArgsToHex err: %s", err.Error())
+				return fmt.Errorf("FromArgs err: %s", err.Error())
 			}
-			accountInfo.OwnerAlgorithmId = oHex.DasAlgorithmId
-			accountInfo.ManagerSubAid = oHex.DasSubAlgorithmId
+			accountInfo.OwnerAlgorithmId = oHex.AlgorithmId
+			accountInfo.ManagerSubAid = oHex.SubAlgorithmId
 			accountInfo.OwnerChainType = oHex.ChainType
-			accountInfo.Owner = oHex.AddressHex
-			accountInfo.ManagerAlgorithmId = mHex.DasAlgorithmId
-			accountInfo.ManagerSubAid = mHex.DasSubAlgorithmId
+			accountInfo.Owner = oHex.Hex
+			accountInfo.ManagerAlgorithmId = mHex.AlgorithmId
+			accountInfo.ManagerSubAid = mHex.SubAlgorithmId
 			accountInfo.ManagerChainType = mHex.ChainType
-			accountInfo.Manager = mHex.AddressHex
+			accountInfo.Manager = mHex.Hex
 			if err = b.dbDao.EditOwnerSubAccount(accountInfo, smtInfo, transactionInfo); err != nil {
 				return fmt.Errorf("EditOwnerSubAccount err: %s", err.Error())
 			}
 		case common.EditKeyManager:
-			_, mHex, err := b.dasCore.Daf().ArgsToHex(builder.EditLockArgs)
+			_, mHex, err := b.an.FromArgs(builder.EditLockArgs)
 			if err != nil {
-				return fmt.Errorf("ArgsToHex err: %s", err.Error())
+				return fmt.Errorf("FromArgs err: %s", err.Error())
 			}
-			accountInfo.ManagerAlgorithmId = mHex.DasAlgorithmId
-			accountInfo.ManagerSubAid = mHex.DasSubAlgorithmId
+			accountInfo.ManagerAlgorithmId = mHex.AlgorithmId
+			accountInfo.ManagerSubAid = mHex.SubAlgorithmId
 			accountInfo.ManagerChainType = mHex.ChainType
-			accountInfo.Manager = mHex.AddressHex
+			accountInfo.Manager = mHex.Hex
 			if err = b.dbDao.EditManagerSubAccount(accountInfo, smtInfo, transactionInfo); err != nil {
 				return fmt.Errorf("EditManagerSubAccount err: %s", err.Error())
 			}
@@ -653,9 +648,9 @@ func (b *BlockParser) actionUpdateSubAccountForApproval(req FuncTransactionHandl
 			LeafDataHash: common.Bytes2Hex(value),
 		})
 
-		oHex, _, err := b.dasCore.Daf().ScriptToHex(v.CurrentSubAccountData.Lock)
+		oHex, _, err := b.an.FromScript(v.CurrentSubAccountData.Lock)
 		if err != nil {
-			return fmt.Errorf("ScriptToHex err: %s", err.Error())
+			return fmt.Errorf("FromScript err: %s", err.Error())
 		}
 		outpointTx := common.OutPoint2String(req.TxHash, indexTx)
 		txInfo := dao.TableTransactionInfo{
@@ -665,7 +660,7 @@ func (b *BlockParser) actionUpdateSubAccountForApproval(req FuncTransactionHandl
 			Action:         v.Action,
 			ServiceType:    dao.ServiceTypeSubAccount,
 			ChainType:      oHex.ChainType,
-			Address:        oHex.AddressHex,
+			Address:        oHex.Hex,
 			Outpoint:       outpointTx,
 			BlockTimestamp: req.BlockTimestamp,
 		}
@@ -690,16 +685,15 @@ func (b *BlockParser) actionUpdateSubAccountForApproval(req FuncTransactionHandl
 		case common.SubActionCreateApproval:
 			accountInfo["status"] = uint8(dao.AccountStatusApproval)
 			transfer := v.CurrentSubAccountData.AccountApproval.Params.Transfer
-			dasf := core.DasAddressFormat{DasNetType: config.Cfg.Server.Net}
-			toHex, _, err := dasf.ScriptToHex(transfer.ToLock)
+			toHex, _, err := b.an.FromScript(transfer.ToLock)
 			if err != nil {
 				return err
 			}
-			toNormal, err := dasf.HexToNormal(toHex)
+			toNormal, err := b.an.ToNormal(toHex)
 			if err != nil {
 				return err
 			}
-			platformHex, _, err := dasf.ScriptToHex(transfer.PlatformLock)
+			platformHex, _, err := b.an.FromScript(transfer.PlatformLock)
 			if err != nil {
 				return err
 			}
@@ -712,11 +706,11 @@ func (b *BlockParser) actionUpdateSubAccountForApproval(req FuncTransactionHandl
 			approval.Outpoint = outpoint
 			approval.Account = v.CurrentSubAccountData.Account()
 			approval.AccountID = v.CurrentSubAccountData.AccountId
-			approval.Platform = platformHex.AddressHex
+			approval.Platform = platformHex.Hex
 			approval.OwnerAlgorithmID = accInfo.OwnerAlgorithmId
 			approval.Owner = accInfo.Owner
-			approval.ToAlgorithmID = toHex.DasAlgorithmId
-			approval.To = toNormal.AddressNormal
+			approval.ToAlgorithmID = toHex.AlgorithmId
+			approval.To = toNormal
 			approval.ProtectedUntil = transfer.ProtectedUntil
 			approval.SealedUntil = transfer.SealedUntil
 			approval.MaxDelayCount = transfer.DelayCountRemain
@@ -764,17 +758,17 @@ func (b *BlockParser) actionUpdateSubAccountForApproval(req FuncTransactionHandl
 				approval.RefOutpoint = refOutpoint
 				approval.Outpoint = outpoint
 
-				owner, manager, err := b.dasCore.Daf().ScriptToHex(chainApproval.Params.Transfer.ToLock)
+				owner, manager, err := b.an.FromScript(chainApproval.Params.Transfer.ToLock)
 				if err != nil {
 					return err
 				}
 				accountInfo["status"] = uint8(dao.AccountStatusNormal)
-				accountInfo["owner"] = owner.AddressHex
+				accountInfo["owner"] = owner.Hex
 				accountInfo["owner_chain_type"] = owner.ChainType
-				accountInfo["owner_algorithm_id"] = owner.DasAlgorithmId
-				accountInfo["manager"] = manager.AddressHex
+				accountInfo["owner_algorithm_id"] = owner.AlgorithmId
+				accountInfo["manager"] = manager.Hex
 				accountInfo["manager_chain_type"] = manager.ChainType
-				accountInfo["manager_algorithm_id"] = manager.DasAlgorithmId
+				accountInfo["manager_algorithm_id"] = manager.AlgorithmId
 			}
 		default:
 			return fmt.Errorf("unknown sub action: %s", v.Action)
@@ -794,7 +788,7 @@ func (b *BlockParser) actionUpdateSubAccountForApproval(req FuncTransactionHandl
 				return err
 			}
 			if action == common.SubActionFullfillApproval {
-				if err := tx.Where("account_id = ?", accId).Delete(&dao.TableRecordsInfo{}).Error; err != nil {
+				if err := dao.ReplaceRecords(tx, []string{accId.(string)}, nil, txs[idx]); err != nil {
 					return err
 				}
 			}
@@ -892,9 +886,9 @@ func (b *BlockParser) ActionCreateSubAccount(req FuncTransactionHandleReq) (resp
 	var capacity uint64
 	var parentAccount string
 	for _, v := range builderMap {
-		ownerHex, managerHex, err := b.dasCore.Daf().ArgsToHex(v.SubAccountData.Lock.Args)
+		ownerHex, managerHex, err := b.an.FromArgs(v.SubAccountData.Lock.Args)
 		if err != nil {
-			resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+			resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 			return
 		}
 
@@ -905,13 +899,13 @@ func (b *BlockParser) ActionCreateSubAccount(req FuncTransactionHandleReq) (resp
 			ParentAccountId:      parentAccountId,
 			Account:              v.Account,
 			OwnerChainType:       ownerHex.ChainType,
-			Owner:                ownerHex.AddressHex,
-			OwnerAlgorithmId:     ownerHex.DasAlgorithmId,
-			OwnerSubAid:          ownerHex.DasSubAlgorithmId,
+			Owner:                ownerHex.Hex,
+			OwnerAlgorithmId:     ownerHex.AlgorithmId,
+			OwnerSubAid:          ownerHex.SubAlgorithmId,
 			ManagerChainType:     managerHex.ChainType,
-			Manager:              managerHex.AddressHex,
-			ManagerAlgorithmId:   managerHex.DasAlgorithmId,
-			ManagerSubAid:        managerHex.DasSubAlgorithmId,
+			Manager:              managerHex.Hex,
+			ManagerAlgorithmId:   managerHex.AlgorithmId,
+			ManagerSubAid:        managerHex.SubAlgorithmId,
 			Status:               v.SubAccountData.Status,
 			EnableSubAccount:     v.SubAccountData.EnableSubAccount,
 			RenewSubAccountPrice: v.SubAccountData.RenewSubAccountPrice,
@@ -937,9 +931,9 @@ func (b *BlockParser) ActionCreateSubAccount(req FuncTransactionHandleReq) (resp
 		capacity += (v.SubAccountData.ExpiredAt - v.SubAccountData.RegisteredAt) / uint64(common.OneYearSec) * newPrice
 	}
 
-	ownerHex, _, err := b.dasCore.Daf().ScriptToHex(req.Tx.Outputs[len(req.Tx.Outputs)-1].Lock)
+	ownerHex, _, err := b.an.FromScript(req.Tx.Outputs[len(req.Tx.Outputs)-1].Lock)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromScript err: %s", err.Error())
 		return
 	}
 
@@ -950,7 +944,7 @@ func (b *BlockParser) ActionCreateSubAccount(req FuncTransactionHandleReq) (resp
 		Action:         common.DasActionCreateSubAccount,
 		ServiceType:    dao.ServiceTypeRegister,
 		ChainType:      ownerHex.ChainType,
-		Address:        ownerHex.AddressHex,
+		Address:        ownerHex.Hex,
 		Capacity:       capacity,
 		Outpoint:       subAccountCellOutpoint,
 		BlockTimestamp: req.BlockTimestamp,
@@ -990,7 +984,116 @@ func (b *BlockParser) ActionEditSubAccount(req FuncTransactionHandleReq) (resp F
 }
 
 func (b *BlockParser) ActionSubAccountCrossChain(req FuncTransactionHandleReq) (resp FuncTransactionHandleResp) {
+	if isCV, err := isCurrentVersionTx(req.Tx, common.DASContractNameSubAccountCellType); err != nil {
+		resp.Err = fmt.Errorf("isCurrentVersion err: %s", err.Error())
+		return
+	} else if !isCV {
+		log.Warn("not current version sub account cross chain tx")
+		return
+	}
 	log.Info("ActionSubAccountCrossChain:", req.BlockNumber, req.TxHash, req.Action)
+
+	var subAccountNewBuilder witness.SubAccountNewBuilder
+	builderMap, err := subAccountNewBuilder.SubAccountNewMapFromTx(req.Tx)
+	if err != nil {
+		resp.Err = fmt.Errorf("SubAccountBuilderMapFromTx err: %s", err.Error())
+		return
+	}
+
+	status := dao.AccountStatusOnLock
+	var coinType, chainId string
+	if req.Action == common.DasActionUnlockSubAccountForCrossChain {
+		status = dao.AccountStatusNormal
+	} else {
+		actionDataBuilder, err := witness.ActionDataBuilderFromTx(req.Tx)
+		if err != nil {
+			resp.Err = fmt.Errorf("ActionDataBuilderFromTx err: %s", err.Error())
+			return
+		}
+		coinType, chainId = CrossChainTarget(actionDataBuilder.Params)
+	}
+	outpoint := common.OutPoint2String(req.TxHash, 0)
+	var index uint
+	for _, builder := range builderMap {
+		if builder.CurrentSubAccountData == nil {
+			resp.Err = fmt.Errorf("CurrentSubAccountData is nil: %s", builder.Account)
+			return
+		}
+		ownerHex, managerHex, err := b.an.FromArgs(builder.CurrentSubAccountData.Lock.Args)
+		if err != nil {
+			resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
+			return
+		}
+		// unlocked to another owner
+		isTrans := req.Action == common.DasActionUnlockSubAccountForCrossChain &&
+			!bytes.Equal(builder.SubAccountData.Lock.Args, builder.CurrentSubAccountData.Lock.Args)
+
+		accountInfo := dao.TableAccountInfo{
+			BlockNumber:        req.BlockNumber,
+			Outpoint:           outpoint,
+			AccountId:          builder.SubAccountData.AccountId,
+			OwnerChainType:     ownerHex.ChainType,
+			Owner:              ownerHex.Hex,
+			OwnerAlgorithmId:   ownerHex.AlgorithmId,
+			OwnerSubAid:        ownerHex.SubAlgorithmId,
+			ManagerChainType:   managerHex.ChainType,
+			Manager:            managerHex.Hex,
+			ManagerAlgorithmId: managerHex.AlgorithmId,
+			ManagerSubAid:      managerHex.SubAlgorithmId,
+			Status:             uint8(status),
+			Nonce:              builder.CurrentSubAccountData.Nonce,
+		}
+		value, err := builder.CurrentSubAccountData.ToH256()
+		if err != nil {
+			resp.Err = fmt.Errorf("CurrentSubAccountData.ToH256() err: %s", err.Error())
+			return
+		}
+		smtInfo := dao.TableSmtInfo{
+			BlockNumber:  req.BlockNumber,
+			Outpoint:     outpoint,
+			AccountId:    builder.SubAccountData.AccountId,
+			LeafDataHash: common.Bytes2Hex(value),
+		}
+		transactionInfo := dao.TableTransactionInfo{
+			BlockNumber:    req.BlockNumber,
+			AccountId:      builder.SubAccountData.AccountId,
+			Account:        builder.Account,
+			Action:         req.Action,
+			ServiceType:    dao.ServiceTypeRegister,
+			ChainType:      ownerHex.ChainType,
+			Address:        ownerHex.Hex,
+			Capacity:       0,
+			Outpoint:       common.OutPoint2String(outpoint, index),
+			BlockTimestamp: req.BlockTimestamp,
+		}
+		index++
+
+		crossChainInfo := dao.TableCrossChainInfo{
+			AccountId:       builder.SubAccountData.AccountId,
+			Account:         builder.Account,
+			ParentAccountId: didCellParentAccountId(builder.Account),
+		}
+		if req.Action == common.DasActionLockSubAccountForCrossChain {
+			crossChainInfo.CoinType, crossChainInfo.ChainId = coinType, chainId
+			crossChainInfo.Status = dao.CrossChainStatusLocked
+			crossChainInfo.LockTxHash = req.TxHash
+			crossChainInfo.LockBlockNumber = req.BlockNumber
+			crossChainInfo.LockedAt = req.BlockTimestamp
+		} else {
+			crossChainInfo.Status = dao.CrossChainStatusUnlocked
+			crossChainInfo.UnlockTxHash = req.TxHash
+			crossChainInfo.UnlockBlockNumber = req.BlockNumber
+			crossChainInfo.UnlockedAt = req.BlockTimestamp
+			crossChainInfo.UnlockOwnerAlgorithmId = ownerHex.AlgorithmId
+			crossChainInfo.UnlockOwnerChainType = ownerHex.ChainType
+			crossChainInfo.UnlockOwner = ownerHex.Hex
+		}
+
+		if err := b.dbDao.SubAccountCrossChain(accountInfo, smtInfo, transactionInfo, isTrans, crossChainInfo); err != nil {
+			resp.Err = fmt.Errorf("SubAccountCrossChain err: %s", err.Error())
+			return
+		}
+	}
 	return
 }
 
@@ -1010,9 +1113,9 @@ func (b *BlockParser) ActionConfigSubAccountCreatingScript(req FuncTransactionHa
 		return
 	}
 	accountCellOutpoint := common.OutPoint2String(req.TxHash, uint(builder.Index))
-	ownerHex, _, err := b.dasCore.Daf().ArgsToHex(req.Tx.Outputs[builder.Index].Lock.Args)
+	ownerHex, _, err := b.an.FromArgs(req.Tx.Outputs[builder.Index].Lock.Args)
 	if err != nil {
-		resp.Err = fmt.Errorf("ArgsToHex err: %s", err.Error())
+		resp.Err = fmt.Errorf("FromArgs err: %s", err.Error())
 		return
 	}
 
@@ -1030,7 +1133,7 @@ func (b *BlockParser) ActionConfigSubAccountCreatingScript(req FuncTransactionHa
 		Action:         common.DasActionConfigSubAccountCustomScript,
 		ServiceType:    dao.ServiceTypeRegister,
 		ChainType:      ownerHex.ChainType,
-		Address:        ownerHex.AddressHex,
+		Address:        ownerHex.Hex,
 		Capacity:       0,
 		Outpoint:       common.OutPoint2String(req.TxHash, 0),
 		BlockTimestamp: req.BlockTimestamp,
@@ -1060,9 +1163,9 @@ func (b *BlockParser) ActionCollectSubAccountProfit(req FuncTransactionHandleReq
 
 	var txs []dao.TableTransactionInfo
 	if len(req.Tx.Outputs) >= 2 {
-		ownerHex, _, err := b.dasCore.Daf().ScriptToHex(req.Tx.Outputs[1].Lock)
+		ownerHex, _, err := b.an.FromScript(req.Tx.Outputs[1].Lock)
 		if err != nil {
-			resp.Err = fmt.Errorf("ScriptToHex err: %s", err.Error())
+			resp.Err = fmt.Errorf("FromScript err: %s", err.Error())
 			return
 		}
 		txs = append(txs, dao.TableTransactionInfo{
@@ -1072,16 +1175,16 @@ func (b *BlockParser) ActionCollectSubAccountProfit(req FuncTransactionHandleReq
 			Action:         req.Action,
 			ServiceType:    dao.ServiceTypeRegister,
 			ChainType:      ownerHex.ChainType,
-			Address:        ownerHex.AddressHex,
+			Address:        ownerHex.Hex,
 			Capacity:       req.Tx.Outputs[1].Capacity,
 			Outpoint:       common.OutPoint2String(req.TxHash, 1),
 			BlockTimestamp: req.BlockTimestamp,
 		})
 	}
 	if len(req.Tx.Outputs) >= 3 {
-		ownerHex, _, err := b.dasCore.Daf().ScriptToHex(req.Tx.Outputs[2].Lock)
+		ownerHex, _, err := b.an.FromScript(req.Tx.Outputs[2].Lock)
 		if err != nil {
-			resp.Err = fmt.Errorf("ScriptToHex err: %s", err.Error())
+			resp.Err = fmt.Errorf("FromScript err: %s", err.Error())
 			return
 		}
 		txs = append(txs, dao.TableTransactionInfo{
@@ -1091,7 +1194,7 @@ func (b *BlockParser) ActionCollectSubAccountProfit(req FuncTransactionHandleReq
 			Action:         req.Action,
 			ServiceType:    dao.ServiceTypeRegister,
 			ChainType:      ownerHex.ChainType,
-			Address:        ownerHex.AddressHex,
+			Address:        ownerHex.Hex,
 			Capacity:       req.Tx.Outputs[2].Capacity,
 			Outpoint:       common.OutPoint2String(req.TxHash, 2),
 			BlockTimestamp: req.BlockTimestamp,
@@ -1242,6 +1345,11 @@ func (b *BlockParser) ActionConfigSubAccount(req FuncTransactionHandleReq) (resp
 		}).Error; err != nil {
 			return err
 		}
+
+		ruleMap := SubAccountRuleMap(req, index, accountInfo.Account)
+		if err := dao.ReplaceSubAccountRules(tx, parentAccountId, ruleMap); err != nil {
+			return err
+		}
 		return tx.Model(&dao.TableAccountInfo{}).Where("account_id=?", parentAccountId).Updates(map[string]interface{}{
 			"outpoint": common.OutPoint2String(req.TxHash, 0),
 		}).Error
@@ -1252,6 +1360,167 @@ func (b *BlockParser) ActionConfigSubAccount(req FuncTransactionHandleReq) (resp
 	return
 }
 
+// SubAccountRuleMap the price and preserved rules a config_sub_account tx sets, the sub-account cell is output index.
+// A rule type is left out when the tx keeps its rules as they were, or its witness can not be decoded:
+// the error is logged and the indexed rules are kept, a bad witness must not halt the sync
+func SubAccountRuleMap(req FuncTransactionHandleReq, index int, parentAccount string) map[dao.SubAccountRuleType][]dao.TableSubAccountRule {
+	parentAccountId := common.Bytes2Hex(req.Tx.Outputs[index].Type.Args)
+	subAccountCellData := witness.ConvertSubAccountCellOutputData(req.Tx.OutputsData[index])
+	ruleMap := make(map[dao.SubAccountRuleType][]dao.TableSubAccountRule)
+	for _, v := range []struct {
+		ruleType  dao.SubAccountRuleType
+		action    common.ActionDataType
+		rulesHash []byte
+	}{
+		{dao.SubAccountRuleTypePrice, common.ActionDataTypeSubAccountPriceRules, subAccountCellData.PriceRulesHash},
+		{dao.SubAccountRuleTypePreserved, common.ActionDataTypeSubAccountPreservedRules, subAccountCellData.PreservedRulesHash},
+	} {
+		list, ok, err := subAccountRuleList(req, parentAccount, parentAccountId, v.ruleType, v.action, v.rulesHash)
+		if err != nil {
+			log.Error("subAccountRuleList err:", err.Error(), req.TxHash, parentAccount)
+			continue
+		} else if !ok {
+			continue
+		}
+		ruleMap[v.ruleType] = list
+	}
+	return ruleMap
+}
+
+// subAccountRuleList decodes the rules of the type from the witnesses of the tx. ok is false when there is
+// no such witness while the sub-account cell still refers to rules of the type, the indexed rules are kept then
+func subAccountRuleList(req FuncTransactionHandleReq, parentAccount, parentAccountId string, ruleType dao.SubAccountRuleType, action common.ActionDataType, rulesHash []byte) ([]dao.TableSubAccountRule, bool, error) {
+	entity := witness.NewSubAccountRuleEntity(parentAccount)
+	if err := entity.ParseFromTx(req.Tx, action); err != nil {
+		return nil, false, fmt.Errorf("ParseFromTx %s err: %s", ruleType, err.Error())
+	}
+	if len(entity.Rules) == 0 && bytes.Count(rulesHash, []byte{0}) != len(rulesHash) {
+		return nil, false, nil
+	}
+	list := make([]dao.TableSubAccountRule, 0, len(entity.Rules))
+	for _, v := range entity.Rules {
+		ast, err := json.Marshal(v.Ast)
+		if err != nil {
+			return nil, false, fmt.Errorf("json.Marshal err: %s", err.Error())
+		}
+		lengthMin, lengthMax := subAccountRuleLength(&v.Ast)
+		list = append(list, dao.TableSubAccountRule{
+			ParentAccountId: parentAccountId,
+			ParentAccount:   parentAccount,
+			RuleType:        ruleType,
+			Idx:             v.Index,
+			Name:            v.Name,
+			Note:            v.Note,
+			Price:           uint64(v.Price),
+			Status:          v.Status,
+			LengthMin:       lengthMin,
+			LengthMax:       lengthMax,
+			Condition:       subAccountRuleCondition(&v.Ast),
+			Ast:             string(ast),
+			TxHash:          req.TxHash,
+			BlockNumber:     req.BlockNumber,
+			BlockTimestamp:  req.BlockTimestamp,
+		})
+	}
+	return list, true, nil
+}
+
+// subAccountRuleLength the account_length range the condition requires, only comparisons at the top
+// or joined by "and" are taken into account, 0 means no bound
+func subAccountRuleLength(e *witness.AstExpression) (lengthMin, lengthMax uint32) {
+	if e.Type != witness.Operator {
+		return
+	}
+	switch e.Symbol {
+	case witness.And:
+		for _, v := range e.Expressions {
+			lo, hi := subAccountRuleLength(v)
+			if lo > lengthMin {
+				lengthMin = lo
+			}
+			if hi > 0 && (lengthMax == 0 || hi < lengthMax) {
+				lengthMax = hi
+			}
+		}
+		return
+	case witness.Gt, witness.Gte, witness.Lt, witness.Lte, witness.Equ:
+	default:
+		return
+	}
+	if len(e.Expressions) != 2 {
+		return
+	}
+	symbol, variable, value := e.Symbol, e.Expressions[0], e.Expressions[1]
+	if variable.Type == witness.Value {
+		variable, value = value, variable
+		switch symbol {
+		case witness.Gt:
+			symbol = witness.Lt
+		case witness.Gte:
+			symbol = witness.Lte
+		case witness.Lt:
+			symbol = witness.Gt
+		case witness.Lte:
+			symbol = witness.Gte
+		}
+	}
+	if variable.Type != witness.Variable || witness.VariableName(variable.Name) != witness.AccountLength || value.Type != witness.Value {
+		return
+	}
+	n := uint32(value.GetNumberValue(""))
+	switch symbol {
+	case witness.Gt:
+		lengthMin = n + 1
+	case witness.Gte:
+		lengthMin = n
+	case witness.Lt:
+		if n > 1 {
+			lengthMax = n - 1
+		}
+	case witness.Lte:
+		lengthMax = n
+	case witness.Equ:
+		lengthMin, lengthMax = n, n
+	}
+	return
+}
+
+// subAccountRuleCondition a readable form of the condition, e.g. account_length >= 4 and only_include_charset(account_chars, "en"),
+// long lists are cut short
+func subAccountRuleCondition(e *witness.AstExpression) string {
+	switch e.Type {
+	case witness.Operator:
+		parts := make([]string, 0, len(e.Expressions))
+		for _, v := range e.Expressions {
+			part := subAccountRuleCondition(v)
+			if v.Type == witness.Operator && (v.Symbol == witness.And || v.Symbol == witness.Or || e.Symbol == witness.Not) {
+				part = "(" + part + ")"
+			}
+			parts = append(parts, part)
+		}
+		if e.Symbol == witness.Not {
+			return "not " + strings.Join(parts, "")
+		}
+		return strings.Join(parts, " "+string(e.Symbol)+" ")
+	case witness.Function:
+		args := make([]string, 0, len(e.Arguments))
+		for _, v := range e.Arguments {
+			args = append(args, subAccountRuleCondition(v))
+		}
+		return e.Name + "(" + strings.Join(args, ", ") + ")"
+	case witness.Variable:
+		return e.Name
+	case witness.Value:
+		if list, ok := e.Value.([]string); ok && len(list) > 5 {
+			bys, _ := json.Marshal(list[:5])
+			return fmt.Sprintf("%s...(%d)", strings.TrimSuffix(string(bys), "]"), len(list))
+		}
+		bys, _ := json.Marshal(e.Value)
+		return string(bys)
+	}
+	return ""
+}
+
 func (b *BlockParser) getOutpoint(req FuncTransactionHandleReq, dasContractName common.DasContractName) (string, string, error) {
 	// get ref outpoint
 	contractSub, err := core.GetDasContractInfo(dasContractName)
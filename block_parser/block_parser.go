@@ -4,6 +4,7 @@ import (
 	"context"
 	"das_database/config"
 	"das_database/dao"
+	"das_database/normalize"
 	"das_database/notify"
 	"fmt"
 	"github.com/dotbitHQ/das-lib/common"
@@ -22,6 +23,7 @@ var IsLatestBlockNumber bool
 
 type BlockParser struct {
 	dasCore              *core.DasCore
+	an                   *normalize.AddressNormalizer
 	mapTransactionHandle map[common.DasAction]FuncTransactionHandle
 	currentBlockNumber   uint64
 	dbDao                *dao.DbDao
@@ -49,6 +51,7 @@ type ParamsBlockParser struct {
 func NewBlockParser(p ParamsBlockParser) (*BlockParser, error) {
 	bp := BlockParser{
 		dasCore:            p.DasCore,
+		an:                 normalize.NewAddressNormalizer(p.DasCore.NetType()),
 		currentBlockNumber: p.CurrentBlockNumber,
 		dbDao:              p.DbDao,
 		concurrencyNum:     p.ConcurrencyNum,
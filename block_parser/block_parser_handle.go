@@ -58,8 +58,8 @@ func (b *BlockParser) registerTransactionHandle() {
 	b.mapTransactionHandle[common.DasActionCreateSubAccount] = b.ActionCreateSubAccount
 	b.mapTransactionHandle[common.DasActionEditSubAccount] = b.ActionEditSubAccount
 	b.mapTransactionHandle[common.DasActionUpdateSubAccount] = b.ActionUpdateSubAccount
-	//b.mapTransactionHandle[common.DasActionLockSubAccountForCrossChain] = b.ActionSubAccountCrossChain
-	//b.mapTransactionHandle[common.DasActionUnlockSubAccountForCrossChain] = b.ActionSubAccountCrossChain
+	b.mapTransactionHandle[common.DasActionLockSubAccountForCrossChain] = b.ActionSubAccountCrossChain
+	b.mapTransactionHandle[common.DasActionUnlockSubAccountForCrossChain] = b.ActionSubAccountCrossChain
 	b.mapTransactionHandle[common.DasActionConfigSubAccountCustomScript] = b.ActionConfigSubAccountCreatingScript
 	b.mapTransactionHandle[common.DasActionCollectSubAccountProfit] = b.ActionCollectSubAccountProfit
 	b.mapTransactionHandle[common.DasActionCollectSubAccountChannelProfit] = b.ActionCollectSubAccountChannelProfit2
@@ -75,12 +75,9 @@ func (b *BlockParser) registerTransactionHandle() {
 
 	//b.mapTransactionHandle[common.DasActionAccountCellUpgrade] = b.ActionAccountUpgrade // upgrade account cell
 	//did cell
+	b.mapTransactionHandle[common.DidCellActionUpgrade] = b.DidCellActionUpgrade
 	b.mapTransactionHandle[common.DidCellActionUpdate] = b.DidCellActionUpdate
 	b.mapTransactionHandle[common.DidCellActionRecycle] = b.DidCellActionRecycle
-
-	//b.mapTransactionHandle[common.DidCellActionEditRecords] = b.ActionEditDidCellRecords // edit did cell record
-	//b.mapTransactionHandle[common.DidCellActionEditOwner] = b.ActionEditDidCellOwner     // edit did cell owner
-	//b.mapTransactionHandle[common.DidCellActionRecycle] = b.ActionDidCellRecycle         //  did cell recycle
 }
 
 func isCurrentVersionTx(tx *types.Transaction, name common.DasContractName) (bool, error) {
@@ -1,11 +1,14 @@
 package block_parser
 
 import (
+	"bytes"
 	"context"
 	"das_database/config"
+	"das_database/normalize"
 	"fmt"
 	"github.com/dotbitHQ/das-lib/common"
 	"github.com/dotbitHQ/das-lib/core"
+	"github.com/dotbitHQ/das-lib/molecule"
 	"github.com/dotbitHQ/das-lib/witness"
 	"github.com/nervosnetwork/ckb-sdk-go/rpc"
 	"github.com/nervosnetwork/ckb-sdk-go/types"
@@ -168,3 +171,175 @@ func getNewDasCoreTestnet2() (*core.DasCore, error) {
 	}
 	return dc, nil
 }
+
+func TestSubAccountRuleLength(t *testing.T) {
+	length := func(symbol witness.SymbolType, n uint32) *witness.AstExpression {
+		return &witness.AstExpression{
+			Type:   witness.Operator,
+			Symbol: symbol,
+			Expressions: witness.AstExpressions{
+				{Type: witness.Variable, Name: string(witness.AccountLength)},
+				{Type: witness.Value, ValueType: witness.Uint8, Value: n},
+			},
+		}
+	}
+	charset := &witness.AstExpression{
+		Type: witness.Function,
+		Name: string(witness.FunctionOnlyIncludeCharset),
+		Arguments: witness.AstExpressions{
+			{Type: witness.Variable, Name: string(witness.AccountChars)},
+			{Type: witness.Value, ValueType: witness.Charset, Value: 2},
+		},
+	}
+	cases := []struct {
+		ast      *witness.AstExpression
+		min, max uint32
+	}{
+		{length(witness.Equ, 3), 3, 3},
+		{length(witness.Gt, 4), 5, 0},
+		{&witness.AstExpression{Type: witness.Operator, Symbol: witness.And, Expressions: witness.AstExpressions{length(witness.Gte, 4), length(witness.Lt, 8), charset}}, 4, 7},
+		{&witness.AstExpression{Type: witness.Operator, Symbol: witness.Or, Expressions: witness.AstExpressions{length(witness.Equ, 1), length(witness.Equ, 2)}}, 0, 0},
+		{charset, 0, 0},
+	}
+	for i, v := range cases {
+		min, max := subAccountRuleLength(v.ast)
+		if min != v.min || max != v.max {
+			t.Fatal(i, subAccountRuleCondition(v.ast), min, max)
+		}
+	}
+	cond := subAccountRuleCondition(cases[2].ast)
+	if cond != "account_length >= 4 and account_length < 8 and only_include_charset(account_chars, 2)" {
+		t.Fatal(cond)
+	}
+}
+
+func TestGetDidCellChanges(t *testing.T) {
+	lock := func(b byte) *types.Script {
+		return &types.Script{
+			CodeHash: types.HexToHash("0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8"),
+			HashType: types.HashTypeType,
+			Args:     bytes.Repeat([]byte{b}, 20),
+		}
+	}
+	cell := func(index uint64, l *types.Script, account string, expireAt uint64, witnessHash byte) core.DidCellInfo {
+		content, _ := (&witness.DidCellDataLV{
+			Flag:        witness.DidCellDataLVFlag,
+			Version:     witness.DidCellDataLVVersion,
+			WitnessHash: bytes.Repeat([]byte{witnessHash}, 20),
+			ExpireAt:    expireAt,
+			Account:     account,
+		}).ObjToBys()
+		data, _ := (&witness.SporeData{
+			ContentType: []byte{},
+			Content:     content,
+			ClusterId:   witness.GetClusterId(common.DasNetTypeTestnet2),
+		}).ObjToBys()
+		return core.DidCellInfo{
+			Index:       index,
+			OutPoint:    &types.OutPoint{TxHash: types.HexToHash(fmt.Sprintf("0x%064x", index+1)), Index: uint(index)},
+			Lock:        l,
+			OutputsData: data,
+		}
+	}
+	txHash := "0x00000000000000000000000000000000000000000000000000000000000000ff"
+	req := FuncTransactionHandleReq{
+		TxHash:         txHash,
+		BlockNumber:    100,
+		BlockTimestamp: 1700000000000,
+		TxDidCellMap: core.TxDidCellMap{
+			Inputs: map[string]core.DidCellInfo{
+				"0x01": cell(0, lock(1), "owner.bit", 10, 1),
+				"0x02": cell(1, lock(2), "records.bit", 10, 1),
+				"0x03": cell(2, lock(3), "recycle.bit", 10, 1),
+				"0x05": cell(3, lock(6), "nowitness.bit", 10, 1),
+			},
+			Outputs: map[string]core.DidCellInfo{
+				"0x01": cell(0, lock(4), "owner.bit", 20, 1),
+				"0x02": cell(1, lock(2), "records.bit", 10, 2),
+				"0x04": cell(2, lock(5), "sub.parent.bit", 30, 1),
+				"0x05": cell(3, lock(6), "nowitness.bit", 10, 3),
+			},
+		},
+	}
+	records := func(key string) witness.DidEntity {
+		return witness.DidEntity{DidCellWitnessDataV0: &witness.DidCellWitnessDataV0{
+			Records: []witness.Record{{Key: key, Type: "profile", Label: "", Value: "v", TTL: 300}},
+		}}
+	}
+	txDidEntityWitness := witness.TxDidEntityWitness{Outputs: map[uint64]witness.DidEntity{
+		1: records("twitter"),
+		2: records("github"),
+	}}
+
+	res, err := getDidCellChanges(req, normalize.NewAddressNormalizer(common.DasNetTypeTestnet2), txDidEntityWitness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.oldOutpointList) != 4 || len(res.list) != 4 {
+		t.Fatal(res.oldOutpointList, res.list)
+	}
+	var actions []string
+	for _, v := range res.txList {
+		actions = append(actions, v.Account+":"+v.Action)
+	}
+	want := "owner.bit:did_edit_owner,owner.bit:did_renew,records.bit:did_edit_records,recycle.bit:did_recycle,sub.parent.bit:did_upgrade,nowitness.bit:did_edit_records"
+	if strings.Join(actions, ",") != want {
+		t.Fatal(actions)
+	}
+	if len(res.ledgers) != 3 || res.ledgers[0].Action != common.DidCellActionEditOwner || res.ledgers[0].FromAddress == res.ledgers[0].ToAddress ||
+		res.ledgers[0].ToAlgorithmId != common.DasAlgorithmIdAnyLock || res.ledgers[1].ToAddress != "" || res.ledgers[2].FromAddress != "" {
+		t.Fatal(res.ledgers)
+	}
+	// the records of nowitness.bit are kept, its new cell has no witness
+	if len(res.records) != 3 {
+		t.Fatal(res.records)
+	}
+	edited, recycled, upgraded := res.records[0], res.records[1], res.records[2]
+	if len(edited.Records) != 1 || edited.Records[0].Key != "twitter" || edited.Records[0].ParentAccountId != "" ||
+		edited.TxInfo.Action != common.DidCellActionEditRecords {
+		t.Fatal(edited)
+	}
+	if len(recycled.Records) != 0 || recycled.TxInfo.Action != common.DidCellActionRecycle {
+		t.Fatal(recycled)
+	}
+	if len(upgraded.Records) != 1 || upgraded.Records[0].Key != "github" ||
+		upgraded.Records[0].ParentAccountId != common.Bytes2Hex(common.GetAccountIdByAccount("parent.bit")) ||
+		upgraded.TxInfo.Action != common.DidCellActionUpgrade {
+		t.Fatal(upgraded)
+	}
+	for _, v := range res.list {
+		if v.Account == "owner.bit" && (v.ExpiredAt != 20 || v.Args != common.Bytes2Hex(lock(4).Args)) {
+			t.Fatal(v)
+		}
+	}
+}
+
+func TestCrossChainTarget(t *testing.T) {
+	coinType, chainId := CrossChainTarget([][]byte{
+		molecule.GoU64ToBytes(60), molecule.GoU64ToBytes(1), {0},
+	})
+	if coinType != "60" || chainId != "1" {
+		t.Fatal(coinType, chainId)
+	}
+	// sub-account locks keep the params in one piece
+	raw := append(append(molecule.GoU64ToBytes(195), molecule.GoU64ToBytes(728126428)...), 0)
+	if coinType, chainId = CrossChainTarget([][]byte{raw}); coinType != "195" || chainId != "728126428" {
+		t.Fatal(coinType, chainId)
+	}
+	if coinType, chainId = CrossChainTarget([][]byte{{0}}); coinType != "" || chainId != "" {
+		t.Fatal(coinType, chainId)
+	}
+}
+
+func TestDeviceKeyHistory(t *testing.T) {
+	req := FuncTransactionHandleReq{BlockNumber: 10, BlockTimestamp: 1000, Action: common.DasActionUpdateKeyList}
+	keyList := []witness.WebauthnKey{
+		{MinAlgId: 8, SubAlgId: 7, Cid: "0x01", PubKey: "0x02"},
+		{MinAlgId: 8, SubAlgId: 7, Cid: "0x03", PubKey: "0x04"},
+	}
+	list := deviceKeyHistory(req, "0xaa-0", "0x01", "0x02", keyList)
+	if len(list) != 2 || list[1].MasterCid != "0x01" || list[1].SlaveCid != "0x03" || list[1].Outpoint != "0xaa-0" ||
+		list[0].SlaveAlgId != common.DasAlgorithmIdWebauthn || list[0].Action != common.DasActionUpdateKeyList {
+		t.Fatal(list)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"das_database/config"
+	"das_database/dao"
+	"das_database/normalize"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/urfave/cli/v2"
+)
+
+func runAddressBackfill(ctx *cli.Context) error {
+	if err := config.InitCfg(ctx.String("config")); err != nil {
+		return err
+	}
+	dryRun := ctx.Bool("dry-run")
+
+	cfgMysql := config.Cfg.DB.Mysql
+	db, err := http_api.NewGormDB(cfgMysql.Addr, cfgMysql.User, cfgMysql.Password, cfgMysql.DbName, cfgMysql.MaxOpenConn, cfgMysql.MaxIdleConn)
+	if err != nil {
+		return fmt.Errorf("NewGormDataBase err:%s", err.Error())
+	}
+	dbDao, err := dao.Initialize(db)
+	if err != nil {
+		return fmt.Errorf("Initialize err:%s ", err.Error())
+	}
+	an := normalize.NewAddressNormalizer(config.Cfg.Server.Net)
+
+	for _, c := range dao.AddressColumns {
+		res, err := backfillAddress(dbDao, an, c, dryRun)
+		if err != nil {
+			return fmt.Errorf("backfillAddress err: %s[%s]", err.Error(), c)
+		}
+		if res.rows > 0 || res.skipped > 0 || len(res.conflicts) > 0 {
+			log.Info("address backfill:", c, res.rows, res.skipped, len(res.conflicts), dryRun)
+		}
+		for _, v := range res.conflicts {
+			log.Warn("address backfill conflict:", c, v.Id, v.Address)
+		}
+	}
+	log.Info("address backfill ok")
+	return nil
+}
+
+type addressBackfillResult struct {
+	rows      int
+	skipped   int
+	conflicts []dao.AddressRow
+}
+
+// backfillAddress rewrites the addresses of the column in the canonical form. The ckb addresses which can not
+// be parsed or belong to another net are skipped, the rows whose new address would duplicate a unique key are
+// left as they are and reported as conflicts
+func backfillAddress(dbDao *dao.DbDao, an *normalize.AddressNormalizer, c dao.AddressColumn, dryRun bool) (res addressBackfillResult, err error) {
+	afterId, limit := uint64(0), 1000
+	for {
+		list, err := dbDao.GetAddressList(c, afterId, limit)
+		if err != nil {
+			return res, fmt.Errorf("GetAddressList err: %s", err.Error())
+		}
+		var rows []dao.AddressRow
+		for _, v := range list {
+			addr, err := an.Canonical(normalize.Address{
+				ChainType:   v.ChainType,
+				AlgorithmId: v.AlgorithmId,
+				Hex:         v.Address,
+			})
+			if err != nil {
+				log.Warn("Canonical err:", err.Error(), c, v.Id, v.Address)
+				res.skipped++
+				continue
+			}
+			if addr.Hex != v.Address {
+				v.Address = addr.Hex
+				rows = append(rows, v)
+			}
+		}
+		if dryRun {
+			res.rows += len(rows)
+		} else {
+			conflicts, err := dbDao.UpdateAddressList(c, rows)
+			if err != nil {
+				return res, fmt.Errorf("UpdateAddressList err: %s", err.Error())
+			}
+			res.rows += len(rows) - len(conflicts)
+			res.conflicts = append(res.conflicts, conflicts...)
+		}
+		if len(list) < limit {
+			return res, nil
+		}
+		afterId = list[len(list)-1].Id
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"das_database/block_parser"
+	"das_database/config"
+	"das_database/dao"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/witness"
+	"github.com/nervosnetwork/ckb-sdk-go/rpc"
+	"github.com/nervosnetwork/ckb-sdk-go/types"
+	"github.com/urfave/cli/v2"
+	"strings"
+)
+
+// runCrossChainBackfill replays the locks and unlocks of t_transaction_info into t_cross_chain_info, in the order they
+// were parsed. The owners come from the permission snapshots, the target chains from the lock txs
+func runCrossChainBackfill(ctx *cli.Context) error {
+	dbDao, err := newBackfillDao(ctx)
+	if err != nil {
+		return err
+	}
+	dryRun := ctx.Bool("dry-run")
+	client, err := rpc.Dial(config.Cfg.Chain.CkbUrl)
+	if err != nil {
+		return fmt.Errorf("rpc.Dial err: %s", err.Error())
+	}
+
+	afterId, limit, locks, unlocks := uint64(0), 100, 0, 0
+	for {
+		list, err := dbDao.GetCrossChainTransactionList(afterId, limit)
+		if err != nil {
+			return fmt.Errorf("GetCrossChainTransactionList err: %s", err.Error())
+		}
+		for _, v := range list {
+			txHash, _ := common.String2OutPoint(v.Outpoint)
+			info := dao.TableCrossChainInfo{
+				AccountId: v.AccountId,
+				Account:   v.Account,
+			}
+			if v.Action == common.DasActionLockSubAccountForCrossChain || v.Action == common.DasActionUnlockSubAccountForCrossChain {
+				info.ParentAccountId = subAccountParentId(v.Account)
+			}
+			before := make(map[string]dao.TableAccountInfo)
+			if v.Action == common.DasActionLockAccountForCrossChain || v.Action == common.DasActionLockSubAccountForCrossChain {
+				// the owner before the lock
+				if v.BlockNumber > 0 {
+					snapshot, err := dbDao.GetSnapshotPermissionsInfo(v.AccountId, v.BlockNumber-1)
+					if err != nil {
+						return fmt.Errorf("GetSnapshotPermissionsInfo err: %s", err.Error())
+					}
+					if snapshot.Id > 0 {
+						before[v.AccountId] = dao.TableAccountInfo{
+							OwnerAlgorithmId: snapshot.OwnerAlgorithmId,
+							OwnerChainType:   snapshot.OwnerAlgorithmId.ToChainType(),
+							Owner:            snapshot.Owner,
+						}
+					}
+				}
+				txRes, err := client.GetTransaction(context.Background(), types.HexToHash(txHash))
+				if err != nil {
+					return fmt.Errorf("GetTransaction err: %s[%s]", err.Error(), txHash)
+				}
+				if txRes != nil && txRes.Transaction != nil {
+					if actionDataBuilder, err := witness.ActionDataBuilderFromTx(txRes.Transaction); err == nil {
+						info.CoinType, info.ChainId = block_parser.CrossChainTarget(actionDataBuilder.Params)
+					}
+				}
+				info.Status = dao.CrossChainStatusLocked
+				info.LockTxHash = txHash
+				info.LockBlockNumber = v.BlockNumber
+				info.LockedAt = v.BlockTimestamp
+				locks++
+			} else {
+				snapshot, err := dbDao.GetSnapshotPermissionsInfo(v.AccountId, v.BlockNumber)
+				if err != nil {
+					return fmt.Errorf("GetSnapshotPermissionsInfo err: %s", err.Error())
+				}
+				info.Status = dao.CrossChainStatusUnlocked
+				info.UnlockTxHash = txHash
+				info.UnlockBlockNumber = v.BlockNumber
+				info.UnlockedAt = v.BlockTimestamp
+				info.UnlockOwnerAlgorithmId = snapshot.OwnerAlgorithmId
+				info.UnlockOwnerChainType = v.ChainType
+				info.UnlockOwner = v.Address
+				unlocks++
+			}
+			if !dryRun {
+				if err := dbDao.SaveCrossChainInfo(before, info); err != nil {
+					return fmt.Errorf("SaveCrossChainInfo err: %s[%s]", err.Error(), txHash)
+				}
+			}
+		}
+		if len(list) < limit {
+			break
+		}
+		afterId = list[len(list)-1].Id
+	}
+	log.Info("cross chain backfill ok:", locks, unlocks, dryRun)
+	return nil
+}
+
+// subAccountParentId the parent account id of a sub-account
+func subAccountParentId(account string) string {
+	i := strings.Index(account, ".")
+	if i == -1 {
+		return ""
+	}
+	return common.Bytes2Hex(common.GetAccountIdByAccount(account[i+1:]))
+}
@@ -5,6 +5,7 @@ import (
 	"das_database/block_parser"
 	"das_database/config"
 	"das_database/dao"
+	"das_database/grpc_server"
 	"das_database/http_server"
 	"das_database/prometheus"
 	"das_database/snapshot"
@@ -39,6 +40,92 @@ func main() {
 			},
 		},
 		Action: runServer,
+		Commands: []*cli.Command{
+			{
+				Name:  "rebate-statement",
+				Usage: "Write the monthly rebate statements of the inviters as csv files",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "config",
+						Aliases: []string{"c"},
+						Usage:   "Load configuration from `FILE`",
+					},
+					&cli.StringFlag{
+						Name:  "month",
+						Usage: "`YYYY-MM` in UTC, the last month by default",
+					},
+					&cli.StringFlag{
+						Name:  "dir",
+						Usage: "Write into `DIR`, rebate_statement.dir of the config by default",
+					},
+				},
+				Action: runRebateStatement,
+			},
+			{
+				Name:  "address-backfill",
+				Usage: "Rewrite the addresses kept by older versions in the canonical form",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "config",
+						Aliases: []string{"c"},
+						Usage:   "Load configuration from `FILE`",
+					},
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Only count the rows to rewrite",
+					},
+				},
+				Action: runAddressBackfill,
+			},
+			{
+				Name:  "trade-deal-backfill",
+				Usage: "Record the account length of the past deals and rebuild the daily trade rollups",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "config",
+						Aliases: []string{"c"},
+						Usage:   "Load configuration from `FILE`",
+					},
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Only count the deals without account length",
+					},
+				},
+				Action: runTradeDealBackfill,
+			},
+			{
+				Name:  "sub-account-rule-backfill",
+				Usage: "Decode the sub-account rules of the config txs recorded in t_rule_config again",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "config",
+						Aliases: []string{"c"},
+						Usage:   "Load configuration from `FILE`",
+					},
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Only decode the rules without writing them",
+					},
+				},
+				Action: runSubAccountRuleBackfill,
+			},
+			{
+				Name:  "cross-chain-backfill",
+				Usage: "Replay the past locks and unlocks of t_transaction_info into t_cross_chain_info",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "config",
+						Aliases: []string{"c"},
+						Usage:   "Load configuration from `FILE`",
+					},
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Only count the locks and unlocks",
+					},
+				},
+				Action: runCrossChainBackfill,
+			},
+		},
 	}
 
 	if err := app.Run(os.Args); err != nil {
@@ -134,8 +221,16 @@ func runServer(ctx *cli.Context) error {
 		Wg:      &wgServer,
 		DasCore: dc,
 	}
-	parserTimer.RunUpdateTokenPrice()
+	if err := parserTimer.RunUpdateTokenPrice(); err != nil {
+		return fmt.Errorf("RunUpdateTokenPrice err: %s", err.Error())
+	}
 	parserTimer.RunFixCharset()
+	parserTimer.RunFixAccountSearch()
+	parserTimer.RunExpiryReminder()
+	parserTimer.RunRebateStatement()
+	parserTimer.RunAutoMintReconcile()
+	parserTimer.RunApprovalNotice()
+	parserTimer.RunCrossChainReport()
 	log.Info("parser timer ok")
 
 	// snapshot
@@ -160,17 +255,31 @@ func runServer(ctx *cli.Context) error {
 
 	// http server
 	hs, err := http_server.Initialize(http_server.HttpServerParams{
-		Address: config.Cfg.Server.HttpServerAddr,
+		Address:         config.Cfg.Server.HttpServerAddr,
+		InternalAddress: config.Cfg.Server.HttpServerInternalAddr,
+		DbDao:           dbDao,
+		Ctx:             ctxServer,
+		DasCore:         dc,
+		Bp:              bp,
+		Red:             red,
+	})
+	if err != nil {
+		return fmt.Errorf("http server Initialize err:%s", err.Error())
+	}
+	hs.Run()
+
+	// grpc server
+	gs := grpc_server.Initialize(grpc_server.GrpcServerParams{
+		Address: config.Cfg.Server.GrpcServerAddr,
 		DbDao:   dbDao,
 		Ctx:     ctxServer,
 		DasCore: dc,
 		Bp:      bp,
 		Red:     red,
 	})
-	if err != nil {
-		return fmt.Errorf("http server Initialize err:%s", err.Error())
+	if err := gs.Run(); err != nil {
+		return fmt.Errorf("grpc server Run err: %s", err.Error())
 	}
-	hs.Run()
 
 	// quit monitor
 	toolib.ExitMonitoring(func(sig os.Signal) {
@@ -180,6 +289,7 @@ func runServer(ctx *cli.Context) error {
 			_ = watcher.Close()
 		}
 		hs.Shutdown()
+		gs.Shutdown()
 		cancel()
 		wgServer.Wait()
 		exit <- struct{}{}
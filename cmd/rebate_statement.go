@@ -0,0 +1,58 @@
+package main
+
+import (
+	"das_database/config"
+	"das_database/dao"
+	"das_database/timer"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/urfave/cli/v2"
+	"time"
+)
+
+func runRebateStatement(ctx *cli.Context) error {
+	if err := config.InitCfg(ctx.String("config")); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+	if m := ctx.String("month"); m != "" {
+		var err error
+		if month, err = time.ParseInLocation("2006-01", m, time.UTC); err != nil {
+			return fmt.Errorf("month invalid: %s", err.Error())
+		}
+	}
+	dir := ctx.String("dir")
+	if dir == "" {
+		dir = config.Cfg.RebateStatement.Dir
+	}
+	if dir == "" {
+		return fmt.Errorf("dir is empty")
+	}
+
+	cfgMysql := config.Cfg.DB.Mysql
+	db, err := http_api.NewGormDB(cfgMysql.Addr, cfgMysql.User, cfgMysql.Password, cfgMysql.DbName, cfgMysql.MaxOpenConn, cfgMysql.MaxIdleConn)
+	if err != nil {
+		return fmt.Errorf("NewGormDataBase err:%s", err.Error())
+	}
+	dbDao, err := dao.Initialize(db)
+	if err != nil {
+		return fmt.Errorf("Initialize err:%s ", err.Error())
+	}
+
+	latestTs, err := dbDao.GetLatestTxTimestamp()
+	if err != nil {
+		return fmt.Errorf("GetLatestTxTimestamp err: %s", err.Error())
+	}
+	if latestTs < uint64(month.AddDate(0, 1, 0).UnixMilli()) {
+		log.Warn("the parser is not past the end of the month yet, the statement may be incomplete:", month.Format("2006-01"))
+	}
+
+	monthDir, err := timer.WriteRebateStatement(dbDao, month, dir)
+	if err != nil {
+		return fmt.Errorf("WriteRebateStatement err: %s", err.Error())
+	}
+	log.Info("rebate statement ok:", monthDir)
+	return nil
+}
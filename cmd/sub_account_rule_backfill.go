@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"das_database/block_parser"
+	"das_database/config"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/nervosnetwork/ckb-sdk-go/rpc"
+	"github.com/nervosnetwork/ckb-sdk-go/types"
+	"github.com/urfave/cli/v2"
+)
+
+// runSubAccountRuleBackfill decodes the rules of the config tx recorded for each parent account in t_rule_config
+// again, for the rules kept before t_sub_account_rule existed or skipped for a bad witness
+func runSubAccountRuleBackfill(ctx *cli.Context) error {
+	dbDao, err := newBackfillDao(ctx)
+	if err != nil {
+		return err
+	}
+	dryRun := ctx.Bool("dry-run")
+	client, err := rpc.Dial(config.Cfg.Chain.CkbUrl)
+	if err != nil {
+		return fmt.Errorf("rpc.Dial err: %s", err.Error())
+	}
+
+	afterId, limit, accounts, rules := int64(0), 100, 0, 0
+	for {
+		list, err := dbDao.GetRuleConfigList(afterId, limit)
+		if err != nil {
+			return fmt.Errorf("GetRuleConfigList err: %s", err.Error())
+		}
+		for _, v := range list {
+			txRes, err := client.GetTransaction(context.Background(), types.HexToHash(v.TxHash))
+			if err != nil {
+				return fmt.Errorf("GetTransaction err: %s[%s]", err.Error(), v.TxHash)
+			}
+			if txRes == nil || txRes.Transaction == nil {
+				log.Warn("sub account rule backfill, tx not found:", v.TxHash, v.Account)
+				continue
+			}
+			// the sub-account cell is the output typed with the parent account id
+			index := -1
+			for i, o := range txRes.Transaction.Outputs {
+				if o.Type != nil && bytes.Equal(o.Type.Args, common.Hex2Bytes(v.AccountId)) {
+					index = i
+					break
+				}
+			}
+			if index < 0 {
+				log.Warn("sub account rule backfill, no sub-account cell:", v.TxHash, v.Account)
+				continue
+			}
+			ruleMap := block_parser.SubAccountRuleMap(block_parser.FuncTransactionHandleReq{
+				Tx:             txRes.Transaction,
+				TxHash:         v.TxHash,
+				BlockNumber:    v.BlockNumber,
+				BlockTimestamp: v.BlockTimestamp,
+			}, index, v.Account)
+			for _, r := range ruleMap {
+				rules += len(r)
+			}
+			if !dryRun {
+				if err := dbDao.ReplaceSubAccountRuleList(v.AccountId, ruleMap); err != nil {
+					return fmt.Errorf("ReplaceSubAccountRuleList err: %s[%s]", err.Error(), v.Account)
+				}
+			}
+			accounts++
+		}
+		if len(list) < limit {
+			break
+		}
+		afterId = list[len(list)-1].Id
+	}
+	log.Info("sub account rule backfill ok:", accounts, rules, dryRun)
+	return nil
+}
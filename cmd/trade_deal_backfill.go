@@ -0,0 +1,68 @@
+package main
+
+import (
+	"das_database/config"
+	"das_database/dao"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/urfave/cli/v2"
+)
+
+func newBackfillDao(ctx *cli.Context) (*dao.DbDao, error) {
+	if err := config.InitCfg(ctx.String("config")); err != nil {
+		return nil, err
+	}
+	cfgMysql := config.Cfg.DB.Mysql
+	db, err := http_api.NewGormDB(cfgMysql.Addr, cfgMysql.User, cfgMysql.Password, cfgMysql.DbName, cfgMysql.MaxOpenConn, cfgMysql.MaxIdleConn)
+	if err != nil {
+		return nil, fmt.Errorf("NewGormDataBase err:%s", err.Error())
+	}
+	dbDao, err := dao.Initialize(db)
+	if err != nil {
+		return nil, fmt.Errorf("Initialize err:%s ", err.Error())
+	}
+	return dbDao, nil
+}
+
+// runTradeDealBackfill records the account length of the deals kept before it existed,
+// then rebuilds the daily rollups of the whole history
+func runTradeDealBackfill(ctx *cli.Context) error {
+	dbDao, err := newBackfillDao(ctx)
+	if err != nil {
+		return err
+	}
+	dryRun := ctx.Bool("dry-run")
+
+	afterId, limit, rows := uint64(0), 1000, 0
+	for {
+		list, err := dbDao.GetTradeDealNoLengthList(afterId, limit)
+		if err != nil {
+			return fmt.Errorf("GetTradeDealNoLengthList err: %s", err.Error())
+		}
+		for i := range list {
+			list[i].AccountLength = common.GetAccountLength(list[i].Account)
+		}
+		if !dryRun {
+			if err := dbDao.UpdateTradeDealAccountLength(list); err != nil {
+				return fmt.Errorf("UpdateTradeDealAccountLength err: %s", err.Error())
+			}
+		}
+		rows += len(list)
+		if len(list) < limit {
+			break
+		}
+		afterId = list[len(list)-1].Id
+	}
+	log.Info("trade deal backfill account length:", rows, dryRun)
+	if dryRun {
+		return nil
+	}
+
+	days, err := dbDao.RebuildTradeDealDaily()
+	if err != nil {
+		return fmt.Errorf("RebuildTradeDealDaily err: %s", err.Error())
+	}
+	log.Info("trade deal backfill ok, days:", days)
+	return nil
+}
@@ -40,12 +40,15 @@ func AddCfgFileWatcher(configFilePath string) (*fsnotify.Watcher, error) {
 
 type CfgServer struct {
 	Server struct {
-		Name                  string            `json:"name" yaml:"name"`
-		Net                   common.DasNetType `json:"net" yaml:"net"`
-		HttpServerAddr        string            `json:"http_server_addr" yaml:"http_server_addr"`
-		FixCharset            bool              `json:"fix_charset" yaml:"fix_charset"`
-		NotExit               bool              `json:"not_exit" yaml:"not_exit"`
-		PrometheusPushGateway string            `json:"prometheus_push_gateway" yaml:"prometheus_push_gateway"`
+		Name                   string            `json:"name" yaml:"name"`
+		Net                    common.DasNetType `json:"net" yaml:"net"`
+		HttpServerAddr         string            `json:"http_server_addr" yaml:"http_server_addr"`
+		HttpServerInternalAddr string            `json:"http_server_internal_addr" yaml:"http_server_internal_addr"`
+		InternalApiKey         string            `json:"internal_api_key" yaml:"internal_api_key"`
+		GrpcServerAddr         string            `json:"grpc_server_addr" yaml:"grpc_server_addr"`
+		FixCharset             bool              `json:"fix_charset" yaml:"fix_charset"`
+		NotExit                bool              `json:"not_exit" yaml:"not_exit"`
+		PrometheusPushGateway  string            `json:"prometheus_push_gateway" yaml:"prometheus_push_gateway"`
 	} `json:"server" yaml:"server"`
 	Notice struct {
 		WebhookLarkErr string `json:"webhook_lark_err" yaml:"webhook_lark_err"`
@@ -75,6 +78,60 @@ type CfgServer struct {
 			DbNum    int    `json:"db_num" yaml:"db_num"`
 		} `json:"redis" yaml:"redis"`
 	} `json:"cache" yaml:"cache"`
+	ExpiryReminder struct {
+		Open        bool     `json:"open" yaml:"open"`
+		Days        []uint64 `json:"days" yaml:"days"`
+		Webhook     string   `json:"webhook" yaml:"webhook"`
+		LarkWebhook string   `json:"lark_webhook" yaml:"lark_webhook"`
+	} `json:"expiry_reminder" yaml:"expiry_reminder"`
+	RebateStatement struct {
+		Open bool   `json:"open" yaml:"open"`
+		Dir  string `json:"dir" yaml:"dir"`
+	} `json:"rebate_statement" yaml:"rebate_statement"`
+	ApprovalNotice struct {
+		Open        bool   `json:"open" yaml:"open"`
+		Webhook     string `json:"webhook" yaml:"webhook"`
+		LarkWebhook string `json:"lark_webhook" yaml:"lark_webhook"`
+	} `json:"approval_notice" yaml:"approval_notice"`
+	CrossChain struct {
+		StuckDays   uint64 `json:"stuck_days" yaml:"stuck_days"`
+		Report      bool   `json:"report" yaml:"report"`
+		LarkWebhook string `json:"lark_webhook" yaml:"lark_webhook"`
+	} `json:"cross_chain" yaml:"cross_chain"`
+	TokenList []TokenCfg `json:"token_list" yaml:"token_list"`
+	Price     struct {
+		StaleSeconds int64                     `json:"stale_seconds" yaml:"stale_seconds"`
+		Sources      map[string]PriceSourceCfg `json:"sources" yaml:"sources"`
+		Tokens       map[string]PriceTokenCfg  `json:"tokens" yaml:"tokens"`
+	} `json:"price" yaml:"price"`
+}
+
+type TokenCfg struct {
+	TokenId     string          `json:"token_id" yaml:"token_id"`
+	ChainType   int             `json:"chain_type" yaml:"chain_type"`
+	CoinType    common.CoinType `json:"coin_type" yaml:"coin_type"`
+	GeckoId     string          `json:"gecko_id" yaml:"gecko_id"`
+	Name        string          `json:"name" yaml:"name"`
+	Symbol      string          `json:"symbol" yaml:"symbol"`
+	Decimals    int32           `json:"decimals" yaml:"decimals"`
+	Logo        string          `json:"logo" yaml:"logo"`
+	Price       string          `json:"price" yaml:"price"` // initial price of a new token
+	DisplayName string          `json:"display_name" yaml:"display_name"`
+	Icon        string          `json:"icon" yaml:"icon"`
+}
+
+type PriceSourceCfg struct {
+	Url     string `json:"url" yaml:"url"`
+	ApiKey  string `json:"api_key" yaml:"api_key"`
+	Timeout int    `json:"timeout" yaml:"timeout"`
+}
+
+// PriceTokenCfg the price sources of a token, keyed by token_id
+type PriceTokenCfg struct {
+	Aggregate   string            `json:"aggregate" yaml:"aggregate"` // priority or median
+	Sources     []string          `json:"sources" yaml:"sources"`     // in priority order
+	Symbols     map[string]string `json:"symbols" yaml:"symbols"`     // source name -> symbol on that source
+	StaticPrice string            `json:"static_price" yaml:"static_price"`
 }
 
 type DbMysql struct {
@@ -2,13 +2,9 @@ package dao
 
 import (
 	"fmt"
-	"github.com/dotbitHQ/das-lib/common"
 	"github.com/dotbitHQ/das-lib/http_api/logger"
-	"github.com/shopspring/decimal"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
-	"time"
 )
 
 var log = logger.NewLogger("dao", logger.LevelDebug)
@@ -76,235 +72,24 @@ func Initialize(db *gorm.DB) (*DbDao, error) {
 		&TableAuthorize{},
 		&ApprovalInfo{},
 		&TableDidCellInfo{},
+		&TableTradeDealDaily{},
+		&TableTradeDealPriceDaily{},
+		&TableOfferBookStat{},
+		&TableTokenPriceHistory{},
+		&TableRecordsHistory{},
+		&TableOwnershipLedger{},
+		&TableSubAccountAutoMintReconcile{},
+		&TableSubAccountRule{},
+		&TableApprovalEvent{},
+		&TableCrossChainInfo{},
+		&TableAuthorizeHistory{},
 	); err != nil {
 		return nil, err
 	}
 
-	if len(tokenList) > 0 {
-		if err := db.Transaction(func(tx *gorm.DB) error {
-			if err := tx.Clauses(clause.Insert{
-				Modifier: "IGNORE",
-			}).Create(&tokenList).Error; err != nil {
-				return err
-			}
-			for i := range tokenList {
-				if err := tx.Model(TableTokenPriceInfo{}).
-					Where("token_id=?", tokenList[i].TokenId).
-					Updates(map[string]interface{}{
-						"chain_type":   tokenList[i].ChainType,
-						"name":         tokenList[i].Name,
-						"symbol":       tokenList[i].Symbol,
-						"decimals":     tokenList[i].Decimals,
-						"logo":         tokenList[i].Logo,
-						"coin_type":    tokenList[i].CoinType,
-						"display_name": tokenList[i].DisplayName,
-						"icon":         tokenList[i].Icon,
-					}).Error; err != nil {
-					return err
-				}
-			}
-			return nil
-		}); err != nil {
-			return nil, err
-		}
-	}
-
-	if err := db.Where("token_id='polygon_matic'").
-		Delete(&TableTokenPriceInfo{}).Error; err != nil {
-		log.Error("del polygon_matic err: %s", err.Error())
-	}
-
 	return &DbDao{db: db}, nil
 }
 
-var tokenList = []TableTokenPriceInfo{
-	{
-		TokenId:       "ckb_ckb",
-		ChainType:     0,
-		CoinType:      common.CoinTypeCKB,
-		GeckoId:       "nervos-network",
-		Name:          "Nervos Network",
-		Symbol:        "CKB",
-		Decimals:      8,
-		Logo:          "https://app.did.id/images/components/portal-wallet.svg",
-		LastUpdatedAt: time.Now().Unix(),
-		DisplayName:   ".bit Balance",
-		Icon:          "dotbit-balance",
-	},
-	{
-		TokenId:       "eth_eth",
-		ChainType:     1,
-		CoinType:      common.CoinTypeEth,
-		GeckoId:       "ethereum",
-		Name:          "Ethereum",
-		Symbol:        "ETH",
-		Decimals:      18,
-		Logo:          "https://app.did.id/images/components/ethereum.svg",
-		LastUpdatedAt: time.Now().Unix(),
-		DisplayName:   "Ethereum",
-		Icon:          "ethereum",
-	},
-	{
-		TokenId:       "btc_btc",
-		ChainType:     2,
-		CoinType:      "0",
-		GeckoId:       "bitcoin",
-		Name:          "Bitcoin",
-		Symbol:        "BTC",
-		Decimals:      8,
-		Logo:          "https://app.did.id/images/components/bitcoin.svg",
-		LastUpdatedAt: time.Now().Unix(),
-		DisplayName:   "Bitcoin",
-		Icon:          "",
-	},
-	{
-		TokenId:       "tron_trx",
-		ChainType:     3,
-		CoinType:      common.CoinTypeTrx,
-		GeckoId:       "tron",
-		Name:          "TRON",
-		Symbol:        "TRX",
-		Decimals:      6,
-		Logo:          "https://app.did.id/images/components/tron.svg",
-		LastUpdatedAt: time.Now().Unix(),
-		DisplayName:   "TRON",
-		Icon:          "tron",
-	},
-	{
-		TokenId:       "bsc_bnb",
-		ChainType:     1,
-		CoinType:      common.CoinTypeBSC,
-		GeckoId:       "binancecoin",
-		Name:          "Binance",
-		Symbol:        "BNB",
-		Decimals:      18,
-		Logo:          "https://app.did.id/images/components/binance-smart-chain.svg",
-		LastUpdatedAt: time.Now().Unix(),
-		DisplayName:   "Binance",
-		Icon:          "binance-smart-chain",
-	},
-	//{
-	//	TokenId:       "polygon_matic",
-	//	ChainType:     1,
-	//	CoinType:      common.CoinTypeMatic,
-	//	GeckoId:       "matic-network",
-	//	Name:          "Polygon",
-	//	Symbol:        "MATIC",
-	//	Decimals:      18,
-	//	Logo:          "https://app.did.id/images/components/polygon.svg",
-	//	LastUpdatedAt: time.Now().Unix(),
-	//	DisplayName:   "Polygon",
-	//	Icon:          "polygon",
-	//},
-	{
-		TokenId:       "doge_doge",
-		ChainType:     7,
-		CoinType:      common.CoinTypeDogeCoin,
-		GeckoId:       "doge_doge",
-		Name:          "Dogecoin",
-		Symbol:        "doge",
-		Decimals:      8,
-		Logo:          "https://app.did.id/images/components/doge.svg",
-		LastUpdatedAt: time.Now().Unix(),
-		DisplayName:   "Dogecoin",
-		Icon:          "dogecoin",
-	},
-	{
-		TokenId:       "eth_erc20_usdt",
-		ChainType:     1,
-		CoinType:      common.CoinTypeEth,
-		GeckoId:       "eth_erc20_usdt",
-		Name:          "ERC20-USDT",
-		Symbol:        "ERC20-USDT",
-		Decimals:      6,
-		Logo:          "",
-		Price:         decimal.NewFromInt(1),
-		LastUpdatedAt: time.Now().Unix(),
-		DisplayName:   "Ethereum",
-		Icon:          "ethereum",
-	},
-	{
-		TokenId:       "bsc_bep20_usdt",
-		ChainType:     1,
-		CoinType:      common.CoinTypeBSC,
-		GeckoId:       "bsc_bep20_usdt",
-		Name:          "BEP20-USDT",
-		Symbol:        "BEP20-USDT",
-		Decimals:      6,
-		Logo:          "",
-		Price:         decimal.NewFromInt(1),
-		LastUpdatedAt: time.Now().Unix(),
-		DisplayName:   "Binance",
-		Icon:          "binance-smart-chain",
-	},
-	{
-		TokenId:       "tron_trc20_usdt",
-		ChainType:     3,
-		CoinType:      common.CoinTypeTrx,
-		GeckoId:       "tron_trc20_usdt",
-		Name:          "TRC20-USDT",
-		Symbol:        "TRC20-USDT",
-		Decimals:      6,
-		Logo:          "",
-		Price:         decimal.NewFromInt(1),
-		LastUpdatedAt: time.Now().Unix(),
-		DisplayName:   "TRON",
-		Icon:          "tron",
-	},
-	{
-		TokenId:       "stripe_usd",
-		ChainType:     99,
-		GeckoId:       "stripe_usd",
-		Name:          "USD",
-		Symbol:        "USD",
-		Decimals:      2,
-		Logo:          "",
-		Price:         decimal.NewFromInt(1),
-		LastUpdatedAt: time.Now().Unix(),
-		DisplayName:   "by Stripe",
-		Icon:          "stripe",
-	},
-	{
-		TokenId:       "did_point",
-		ChainType:     98,
-		CoinType:      common.CoinTypeCKB,
-		GeckoId:       "did_point",
-		Name:          "DIDCredits",
-		Symbol:        "Credits",
-		Decimals:      6,
-		Logo:          "",
-		Price:         decimal.NewFromInt(1),
-		LastUpdatedAt: time.Now().Unix(),
-		DisplayName:   "DIDCredits",
-		Icon:          "didpoint",
-	},
-	{
-		TokenId:       "ckb_ccc",
-		ChainType:     0,
-		CoinType:      common.CoinTypeCKB,
-		GeckoId:       "ckb_ccc",
-		Name:          "Nervos Network",
-		Symbol:        "CKB",
-		Decimals:      8,
-		LastUpdatedAt: time.Now().Unix(),
-		DisplayName:   "CKB",
-		Icon:          "ckbccc",
-	},
-	{
-		TokenId:       "polygon_pol",
-		ChainType:     1,
-		CoinType:      common.CoinTypeMatic,
-		GeckoId:       "polygon_pol",
-		Name:          "Polygon",
-		Symbol:        "POL",
-		Decimals:      18,
-		Logo:          "https://app.did.id/images/components/polygon.svg",
-		LastUpdatedAt: time.Now().Unix(),
-		DisplayName:   "Polygon",
-		Icon:          "polygon",
-	},
-}
-
 func (d *DbDao) Transaction(fn func(tx *gorm.DB) error) error {
 	return d.db.Transaction(fn)
 }
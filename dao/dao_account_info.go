@@ -4,6 +4,7 @@ import (
 	"github.com/dotbitHQ/das-lib/common"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"strings"
 	"time"
 )
 
@@ -13,7 +14,7 @@ type TableAccountInfo struct {
 	Outpoint             string                   `json:"outpoint" gorm:"column:outpoint;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'Hash-Index'"`
 	AccountId            string                   `json:"account_id" gorm:"column:account_id;uniqueIndex:uk_account_id;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'hash of account'"`
 	ParentAccountId      string                   `json:"parent_account_id" gorm:"column:parent_account_id;index:k_parent_account_id;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
-	Account              string                   `json:"account" gorm:"column:account;index:account;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	Account              string                   `json:"account" gorm:"column:account;index:account;index:ft_account,class:FULLTEXT,option:WITH PARSER ngram;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
 	OwnerChainType       common.ChainType         `json:"owner_chain_type" gorm:"column:owner_chain_type;index:k_oct_o;type:smallint(6) NOT NULL DEFAULT '0' COMMENT ''"`
 	Owner                string                   `json:"owner" gorm:"column:owner;index:k_oct_o;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'owner address'"`
 	OwnerAlgorithmId     common.DasAlgorithmId    `json:"owner_algorithm_id" gorm:"column:owner_algorithm_id;type:smallint(6) NOT NULL DEFAULT '0' COMMENT ''"`
@@ -29,7 +30,9 @@ type TableAccountInfo struct {
 	RegisteredAt         uint64                   `json:"registered_at" gorm:"column:registered_at; index:k_registered_at; type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
 	ExpiredAt            uint64                   `json:"expired_at" gorm:"column:expired_at;index:k_expired_at;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
 	ConfirmProposalHash  string                   `json:"confirm_proposal_hash" gorm:"column:confirm_proposal_hash;index:k_confirm_proposal_hash;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
-	CharsetNum           uint64                   `json:"charset_num" gorm:"column:charset_num; index:k_charset_num; type: bigint(20) unsigned NOT NULL DEFAULT '0'; "`
+	CharsetNum           uint64                   `json:"charset_num" gorm:"column:charset_num; index:k_charset_num; index:k_al_cn,priority:2; type: bigint(20) unsigned NOT NULL DEFAULT '0'; "`
+	AccountLength        uint8                    `json:"account_length" gorm:"column:account_length;index:k_al_cn,priority:1;type:smallint(6) NOT NULL DEFAULT '0' COMMENT 'characters of the first label'"`
+	ReverseAccount       string                   `json:"reverse_account" gorm:"column:reverse_account;index:k_reverse_account;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'for suffix search'"`
 	CreatedAt            time.Time                `json:"created_at" gorm:"column:created_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT ''"`
 	UpdatedAt            time.Time                `json:"updated_at" gorm:"column:updated_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT ''"`
 }
@@ -58,6 +61,10 @@ func (t *TableAccountInfo) TableName() string {
 
 func (d *DbDao) EditManager(accountInfo TableAccountInfo, transactionInfo TableTransactionInfo, cidPk TableCidPk) error {
 	return d.db.Transaction(func(tx *gorm.DB) error {
+		ownership, err := GetOwnership(tx, []string{accountInfo.AccountId})
+		if err != nil {
+			return err
+		}
 		if err := tx.Select("block_number", "outpoint", "manager_chain_type", "manager", "manager_algorithm_id").
 			Where("account_id = ?", accountInfo.AccountId).Updates(accountInfo).Error; err != nil {
 			return err
@@ -80,12 +87,19 @@ func (d *DbDao) EditManager(accountInfo TableAccountInfo, transactionInfo TableT
 			}
 		}
 
+		if err := CreateOwnershipLedger(tx, ownership, transactionInfo); err != nil {
+			return err
+		}
 		return nil
 	})
 }
 
 func (d *DbDao) TransferAccount(accountInfo TableAccountInfo, transactionInfo TableTransactionInfo, recordsInfos []TableRecordsInfo, cidPk TableCidPk) error {
 	return d.db.Transaction(func(tx *gorm.DB) error {
+		ownership, err := GetOwnership(tx, []string{accountInfo.AccountId})
+		if err != nil {
+			return err
+		}
 		if err := tx.Select("block_number", "outpoint", "owner_chain_type", "owner", "owner_algorithm_id", "manager_chain_type", "manager", "manager_algorithm_id").
 			Where("account_id = ?", accountInfo.AccountId).
 			Updates(accountInfo).Error; err != nil {
@@ -101,15 +115,9 @@ func (d *DbDao) TransferAccount(accountInfo TableAccountInfo, transactionInfo Ta
 			return err
 		}
 
-		if err := tx.Where("account_id = ?", accountInfo.AccountId).Delete(&TableRecordsInfo{}).Error; err != nil {
+		if err := ReplaceRecords(tx, []string{accountInfo.AccountId}, recordsInfos, transactionInfo); err != nil {
 			return err
 		}
-
-		if len(recordsInfos) > 0 {
-			if err := tx.Create(&recordsInfos).Error; err != nil {
-				return err
-			}
-		}
 		if cidPk.Cid != "" {
 			if err := d.db.Clauses(clause.OnConflict{
 				DoUpdates: clause.AssignmentColumns([]string{}),
@@ -117,6 +125,9 @@ func (d *DbDao) TransferAccount(accountInfo TableAccountInfo, transactionInfo Ta
 				return err
 			}
 		}
+		if err := CreateOwnershipLedger(tx, ownership, transactionInfo); err != nil {
+			return err
+		}
 		return nil
 	})
 }
@@ -137,14 +148,9 @@ func (d *DbDao) TransferAccountToDid(accountInfo TableAccountInfo, didCellInfo T
 			return err
 		}
 
-		if err := tx.Where("account_id = ?", didCellInfo.AccountId).Delete(&TableRecordsInfo{}).Error; err != nil {
+		if err := ReplaceRecords(tx, []string{didCellInfo.AccountId}, recordsInfos, transactionInfo); err != nil {
 			return err
 		}
-		if len(recordsInfos) > 0 {
-			if err := tx.Create(&recordsInfos).Error; err != nil {
-				return err
-			}
-		}
 
 		if err := tx.Clauses(clause.OnConflict{
 			DoUpdates: clause.AssignmentColumns([]string{
@@ -172,6 +178,7 @@ func (d *DbDao) ConfirmProposal(incomeCellInfos []TableIncomeCellInfo, accountIn
 		}
 
 		if len(accountInfos) > 0 {
+			FillAccountSearchFields(accountInfos)
 			if err := tx.Clauses(clause.OnConflict{
 				DoUpdates: clause.AssignmentColumns([]string{
 					"block_number", "outpoint",
@@ -208,13 +215,25 @@ func (d *DbDao) ConfirmProposal(incomeCellInfos []TableIncomeCellInfo, accountIn
 		}
 
 		if len(recordAccountIds) > 0 {
-			if err := tx.Where("account_id IN(?)", recordAccountIds).Delete(&TableRecordsInfo{}).Error; err != nil {
-				return err
+			// the history of each account is kept with its own transaction
+			recordsMap := make(map[string][]TableRecordsInfo)
+			for _, v := range records {
+				recordsMap[v.AccountId] = append(recordsMap[v.AccountId], v)
 			}
-		}
-		if len(records) > 0 {
-			if err := tx.Create(&records).Error; err != nil {
-				return err
+			for _, accountId := range recordAccountIds {
+				var txInfo TableTransactionInfo
+				if len(transactionInfos) > 0 {
+					txInfo = transactionInfos[0]
+				}
+				for _, v := range transactionInfos {
+					if v.AccountId == accountId {
+						txInfo = v
+						break
+					}
+				}
+				if err := ReplaceRecords(tx, []string{accountId}, recordsMap[accountId], txInfo); err != nil {
+					return err
+				}
 			}
 		}
 
@@ -287,6 +306,10 @@ func (d *DbDao) GetAccountInfoByParentAccountId(parentAccountId string) (account
 
 func (d *DbDao) BidExpiredAccountAuction(accountInfo TableAccountInfo, recordsInfos []TableRecordsInfo, transactionInfos []TableTransactionInfo) error {
 	return d.db.Transaction(func(tx *gorm.DB) error {
+		ownership, err := GetOwnership(tx, []string{accountInfo.AccountId})
+		if err != nil {
+			return err
+		}
 		//update account_info
 		if err := tx.Select("status", "expired_at", "registered_at", "block_number", "outpoint", "owner_chain_type", "owner", "owner_algorithm_id", "owner_sub_aid", "manager_chain_type", "manager", "manager_algorithm_id", "manager_sub_aid").
 			Where("account_id = ?", accountInfo.AccountId).
@@ -303,15 +326,11 @@ func (d *DbDao) BidExpiredAccountAuction(accountInfo TableAccountInfo, recordsIn
 			return err
 		}
 
-		//delete record
-		if err := tx.Where("account_id=?", accountInfo.AccountId).Delete(&TableRecordsInfo{}).Error; err != nil {
+		if err := ReplaceRecords(tx, []string{accountInfo.AccountId}, recordsInfos, transactionInfos[0]); err != nil {
 			return err
 		}
-		//default record
-		if len(recordsInfos) > 0 {
-			if err := tx.Create(&recordsInfos).Error; err != nil {
-				return err
-			}
+		if err := CreateOwnershipLedger(tx, ownership, transactionInfos[0]); err != nil {
+			return err
 		}
 		return nil
 	})
@@ -319,6 +338,10 @@ func (d *DbDao) BidExpiredAccountAuction(accountInfo TableAccountInfo, recordsIn
 
 func (d *DbDao) RecycleExpiredAccount(accountInfo TableAccountInfo, transactionInfo TableTransactionInfo, accountId string, enableSubAccount uint8) error {
 	return d.db.Transaction(func(tx *gorm.DB) error {
+		ownership, err := GetOwnership(tx, []string{accountId})
+		if err != nil {
+			return err
+		}
 		if err := tx.Select("block_number", "outpoint").
 			Where("account_id=?", accountInfo.AccountId).
 			Updates(accountInfo).Error; err != nil {
@@ -338,7 +361,7 @@ func (d *DbDao) RecycleExpiredAccount(accountInfo TableAccountInfo, transactionI
 			return err
 		}
 
-		if err := tx.Where("account_id=?", accountId).Delete(&TableRecordsInfo{}).Error; err != nil {
+		if err := ReplaceRecords(tx, []string{accountId}, nil, transactionInfo); err != nil {
 			return err
 		}
 
@@ -347,7 +370,12 @@ func (d *DbDao) RecycleExpiredAccount(accountInfo TableAccountInfo, transactionI
 				return err
 			}
 
-			if err := tx.Where("parent_account_id=?", accountId).Delete(&TableRecordsInfo{}).Error; err != nil {
+			var subAccountIds []string
+			if err := tx.Model(&TableRecordsInfo{}).Where("parent_account_id=?", accountId).
+				Distinct().Pluck("account_id", &subAccountIds).Error; err != nil {
+				return err
+			}
+			if err := ReplaceRecords(tx, subAccountIds, nil, transactionInfo); err != nil {
 				return err
 			}
 
@@ -356,12 +384,19 @@ func (d *DbDao) RecycleExpiredAccount(accountInfo TableAccountInfo, transactionI
 			}
 		}
 
+		if err := CreateOwnershipLedger(tx, ownership, transactionInfo); err != nil {
+			return err
+		}
 		return nil
 	})
 }
 
-func (d *DbDao) AccountCrossChain(accountInfo TableAccountInfo, transactionInfo TableTransactionInfo, isTrans bool) error {
+func (d *DbDao) AccountCrossChain(accountInfo TableAccountInfo, transactionInfo TableTransactionInfo, isTrans bool, crossChainInfo TableCrossChainInfo) error {
 	return d.db.Transaction(func(tx *gorm.DB) error {
+		before, err := GetOwnership(tx, []string{accountInfo.AccountId})
+		if err != nil {
+			return err
+		}
 		if err := tx.Select("block_number", "outpoint",
 			"owner_chain_type", "owner", "owner_algorithm_id", "manager_chain_type", "manager", "manager_algorithm_id", "status").
 			Where("account_id = ?", accountInfo.AccountId).
@@ -379,11 +414,15 @@ func (d *DbDao) AccountCrossChain(accountInfo TableAccountInfo, transactionInfo
 		}
 
 		if isTrans {
-			if err := tx.Where("account_id = ?", accountInfo.AccountId).Delete(&TableRecordsInfo{}).Error; err != nil {
+			if err := ReplaceRecords(tx, []string{accountInfo.AccountId}, nil, transactionInfo); err != nil {
 				return err
 			}
 		}
 
+		if err := saveCrossChainInfo(tx, before, crossChainInfo); err != nil {
+			return err
+		}
+
 		return nil
 	})
 }
@@ -404,15 +443,9 @@ func (d *DbDao) AccountUpgrade(accountInfo TableAccountInfo, didCellInfo TableDi
 		}).Create(&transactionInfo).Error; err != nil {
 			return err
 		}
-		if err := tx.Where("account_id = ?", didCellInfo.AccountId).Delete(&TableRecordsInfo{}).Error; err != nil {
+		if err := ReplaceRecords(tx, []string{didCellInfo.AccountId}, recordsInfos, transactionInfo); err != nil {
 			return err
 		}
-
-		if len(recordsInfos) > 0 {
-			if err := tx.Create(&recordsInfos).Error; err != nil {
-				return err
-			}
-		}
 		if err := tx.Clauses(clause.OnConflict{
 			DoUpdates: clause.AssignmentColumns([]string{
 				"args", "account", "expired_at",
@@ -458,7 +491,340 @@ func (d *DbDao) GetAccountInfoByAccountId(accountId string) (info TableAccountIn
 	return
 }
 
+func (d *DbDao) GetAccountNameList(accountIds []string) (list []TableAccountInfo, err error) {
+	if len(accountIds) == 0 {
+		return
+	}
+	err = d.db.Select("account_id,account").Where("account_id IN ?", accountIds).Find(&list).Error
+	return
+}
+
 func (d *DbDao) UpdateAccountInfo(accountId string, accInfo map[string]interface{}) (err error) {
 	err = d.db.Model(&TableAccountInfo{}).Where("account_id=?", accountId).Updates(accInfo).Error
 	return
 }
+
+// ExpiryFilter ExpiredAt range is [ExpiredFrom, ExpiredTo), owner and parent account are optional
+type ExpiryFilter struct {
+	OwnerChainType  common.ChainType
+	Owner           string
+	DidCellHolder   *DidCellHolder
+	ParentAccountId string
+	ExpiredFrom     uint64
+	ExpiredTo       uint64
+}
+
+// accountExpiredAt the expiry of an account, upgraded accounts expire with their did cell.
+// It needs the join of joinDidCellExpiry
+const accountExpiredAt = "IFNULL(t_did_cell_info.expired_at,t_account_info.expired_at)"
+
+// joinDidCellExpiry joins the did cells of the upgraded accounts, the columns of the query must be qualified
+func (d *DbDao) joinDidCellExpiry() *gorm.DB {
+	return d.db.Model(&TableAccountInfo{}).
+		Joins("LEFT JOIN t_did_cell_info ON t_did_cell_info.account_id=t_account_info.account_id AND t_account_info.status=?", AccountStatusOnUpgrade)
+}
+
+// fillDidCellExpiredAt sets the expiry of the upgraded accounts in the list to the one of their did cells
+func (d *DbDao) fillDidCellExpiredAt(list []TableAccountInfo) error {
+	var accountIds []string
+	for _, v := range list {
+		if AccountStatus(v.Status) == AccountStatusOnUpgrade {
+			accountIds = append(accountIds, v.AccountId)
+		}
+	}
+	if len(accountIds) == 0 {
+		return nil
+	}
+	didCells, err := d.GetDidCellListByAccountIds(accountIds)
+	if err != nil {
+		return err
+	}
+	expiredAtMap := make(map[string]uint64)
+	for _, v := range didCells {
+		expiredAtMap[v.AccountId] = v.ExpiredAt
+	}
+	for i, v := range list {
+		if expiredAt, ok := expiredAtMap[v.AccountId]; ok && AccountStatus(v.Status) == AccountStatusOnUpgrade {
+			list[i].ExpiredAt = expiredAt
+		}
+	}
+	return nil
+}
+
+func (d *DbDao) expiryQuery(f ExpiryFilter) *gorm.DB {
+	db := d.joinDidCellExpiry().
+		Where(accountExpiredAt+">=? AND "+accountExpiredAt+"<? AND t_account_info.status!=?", f.ExpiredFrom, f.ExpiredTo, AccountStatusRecycle)
+	if f.Owner != "" || f.DidCellHolder != nil {
+		db = d.whereAccountHolder(db, f.OwnerChainType, f.Owner, f.DidCellHolder)
+	}
+	if f.ParentAccountId != "" {
+		db = db.Where("t_account_info.parent_account_id=?", f.ParentAccountId)
+	}
+	return db
+}
+
+// GetAccountListByExpiry the expiry of an upgraded account is the one of its did cell, the cursor key is the expiry
+func (d *DbDao) GetAccountListByExpiry(f ExpiryFilter, cursor *Cursor, limit, offset int) (list []TableAccountInfo, err error) {
+	err = afterCursor(d.expiryQuery(f), cursor, accountExpiredAt, "t_account_info.id", false).
+		Select("t_account_info.*").
+		Order(accountExpiredAt + ",t_account_info.id").Limit(limit).Offset(offset).Find(&list).Error
+	if err != nil {
+		return
+	}
+	err = d.fillDidCellExpiredAt(list)
+	return
+}
+
+func (d *DbDao) GetAccountCountByExpiry(f ExpiryFilter) (count int64, err error) {
+	err = d.expiryQuery(f).Count(&count).Error
+	return
+}
+
+// AccountSearchFields account_length counts the characters of the first label (sub-account name for sub-accounts),
+// reverse_account turns suffix matching into an index prefix scan
+func AccountSearchFields(account string) (accountLength uint8, reverseAccount string) {
+	label := strings.TrimSuffix(account, common.DasAccountSuffix)
+	if i := strings.Index(label, "."); i != -1 {
+		label = label[:i]
+	}
+	return common.GetAccountLength(label), reverseString(account)
+}
+
+func reverseString(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r)
+}
+
+func FillAccountSearchFields(list []TableAccountInfo) {
+	for i := range list {
+		list[i].AccountLength, list[i].ReverseAccount = AccountSearchFields(list[i].Account)
+	}
+}
+
+func (d *DbDao) GetNeedFixSearchAccountList() (list []TableAccountInfo, err error) {
+	err = d.db.Select("id", "account_id", "account").
+		Where("reverse_account='' AND account!=''").Limit(500).Find(&list).Error
+	return
+}
+
+func (d *DbDao) UpdateAccountSearchFields(list []TableAccountInfo) error {
+	if len(list) == 0 {
+		return nil
+	}
+	return d.db.Transaction(func(tx *gorm.DB) error {
+		for _, v := range list {
+			accountLength, reverseAccount := AccountSearchFields(v.Account)
+			if err := tx.Model(TableAccountInfo{}).
+				Where("account_id=?", v.AccountId).
+				Updates(map[string]interface{}{
+					"account_length":  accountLength,
+					"reverse_account": reverseAccount,
+				}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+const (
+	AccountMatchPrefix   = "prefix"
+	AccountMatchSuffix   = "suffix"
+	AccountMatchContains = "contains"
+
+	AccountLevelTop = "top"
+	AccountLevelSub = "sub"
+)
+
+// AccountSearchFilter Keyword is matched against the account without the .bit suffix,
+// zero values are ignored, RegisteredAt range is [RegisteredFrom, RegisteredTo)
+type AccountSearchFilter struct {
+	Keyword         string
+	Match           string
+	LengthMin       uint8
+	LengthMax       uint8
+	CharsetNum      uint64
+	Status          []AccountStatus
+	RegisteredFrom  uint64
+	RegisteredTo    uint64
+	Level           string
+	ParentAccountId string
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func (d *DbDao) accountSearchQuery(f AccountSearchFilter) *gorm.DB {
+	db := d.db.Model(&TableAccountInfo{})
+	if len(f.Status) > 0 {
+		db = db.Where("status IN ?", f.Status)
+	} else {
+		db = db.Where("status!=?", AccountStatusRecycle)
+	}
+	if f.Keyword != "" {
+		keyword := strings.TrimSuffix(f.Keyword, common.DasAccountSuffix)
+		switch f.Match {
+		case AccountMatchSuffix:
+			db = db.Where("reverse_account LIKE ?", likeEscaper.Replace(reverseString(keyword+common.DasAccountSuffix))+"%")
+		case AccountMatchContains:
+			// the ngram full-text index narrows the rows, LIKE drops the false positives
+			db = db.Where("MATCH(account) AGAINST(? IN BOOLEAN MODE) AND account LIKE ?",
+				`"`+strings.ReplaceAll(keyword, `"`, "")+`"`, "%"+likeEscaper.Replace(keyword)+"%")
+		default:
+			db = db.Where("account LIKE ?", likeEscaper.Replace(keyword)+"%")
+		}
+	}
+	if f.LengthMin > 0 {
+		db = db.Where("account_length>=?", f.LengthMin)
+	}
+	if f.LengthMax > 0 {
+		db = db.Where("account_length<=?", f.LengthMax)
+	}
+	if f.CharsetNum > 0 {
+		db = db.Where("charset_num=?", f.CharsetNum)
+	}
+	if f.RegisteredFrom > 0 {
+		db = db.Where("registered_at>=?", f.RegisteredFrom)
+	}
+	if f.RegisteredTo > 0 {
+		db = db.Where("registered_at<?", f.RegisteredTo)
+	}
+	switch {
+	case f.ParentAccountId != "":
+		db = db.Where("parent_account_id=?", f.ParentAccountId)
+	case f.Level == AccountLevelTop:
+		db = db.Where("parent_account_id=''")
+	case f.Level == AccountLevelSub:
+		db = db.Where("parent_account_id!=''")
+	}
+	return db
+}
+
+func (d *DbDao) SearchAccountList(f AccountSearchFilter, cursor *Cursor, limit, offset int) (list []TableAccountInfo, err error) {
+	err = afterCursor(d.accountSearchQuery(f), cursor, "", "id", false).
+		Order("id").Limit(limit).Offset(offset).Find(&list).Error
+	return
+}
+
+func (d *DbDao) CountAccountList(f AccountSearchFilter) (count int64, err error) {
+	err = d.accountSearchQuery(f).Count(&count).Error
+	return
+}
+
+const (
+	SubAccountExpiryActive   = "active"
+	SubAccountExpiryExpiring = "expiring"
+	SubAccountExpiryExpired  = "expired"
+)
+
+// SubAccountFilter the sub-accounts of a parent account, owner, status and expiry are optional.
+// Expiry is relative to Now, expiring ones expire within [Now, ExpiringTo)
+type SubAccountFilter struct {
+	ParentAccountId string
+	OwnerChainType  common.ChainType
+	Owner           string
+	DidCellHolder   *DidCellHolder
+	Status          []AccountStatus
+	Expiry          string
+	Now             uint64
+	ExpiringTo      uint64
+}
+
+func (d *DbDao) subAccountQuery(f SubAccountFilter) *gorm.DB {
+	db := d.joinDidCellExpiry().Where("t_account_info.parent_account_id=?", f.ParentAccountId)
+	if f.Owner != "" || f.DidCellHolder != nil {
+		db = d.whereAccountHolder(db, f.OwnerChainType, f.Owner, f.DidCellHolder)
+	}
+	if len(f.Status) > 0 {
+		db = db.Where("t_account_info.status IN ?", f.Status)
+	} else {
+		db = db.Where("t_account_info.status!=?", AccountStatusRecycle)
+	}
+	switch f.Expiry {
+	case SubAccountExpiryActive:
+		db = db.Where(accountExpiredAt+">=?", f.Now)
+	case SubAccountExpiryExpiring:
+		db = db.Where(accountExpiredAt+">=? AND "+accountExpiredAt+"<?", f.Now, f.ExpiringTo)
+	case SubAccountExpiryExpired:
+		db = db.Where(accountExpiredAt+"<?", f.Now)
+	}
+	return db
+}
+
+func (d *DbDao) GetSubAccountList(f SubAccountFilter, cursor *Cursor, limit, offset int) (list []TableAccountInfo, err error) {
+	err = afterCursor(d.subAccountQuery(f), cursor, "t_account_info.registered_at", "t_account_info.id", true).
+		Select("t_account_info.*").
+		Order("t_account_info.registered_at DESC,t_account_info.id DESC").Limit(limit).Offset(offset).Find(&list).Error
+	if err != nil {
+		return
+	}
+	err = d.fillDidCellExpiredAt(list)
+	return
+}
+
+func (d *DbDao) GetSubAccountCount(f SubAccountFilter) (count int64, err error) {
+	err = d.subAccountQuery(f).Count(&count).Error
+	return
+}
+
+type SubAccountStats struct {
+	Total    int64 `json:"total" gorm:"column:total"`
+	Active   int64 `json:"active" gorm:"column:active"`
+	Expiring int64 `json:"expiring" gorm:"column:expiring"`
+	Expired  int64 `json:"expired" gorm:"column:expired"`
+}
+
+// GetSubAccountStats expiring ones are also counted as active
+func (d *DbDao) GetSubAccountStats(parentAccountId string, now, expiringTo uint64) (stats SubAccountStats, err error) {
+	err = d.subAccountQuery(SubAccountFilter{ParentAccountId: parentAccountId}).
+		Select("COUNT(*) AS total,"+
+			"IFNULL(SUM("+accountExpiredAt+">=?),0) AS active,"+
+			"IFNULL(SUM("+accountExpiredAt+">=? AND "+accountExpiredAt+"<?),0) AS expiring,"+
+			"IFNULL(SUM("+accountExpiredAt+"<?),0) AS expired", now, now, expiringTo, now).
+		Take(&stats).Error
+	return
+}
+
+type SubAccountMintedDaily struct {
+	Day   int64 `json:"day" gorm:"column:day"` // days since 1970-01-01 UTC
+	Count int64 `json:"count" gorm:"column:count"`
+}
+
+// GetSubAccountMintedDaily the sub-accounts registered each day, recycled ones are deleted from t_account_info and not counted
+func (d *DbDao) GetSubAccountMintedDaily(parentAccountId string) (list []SubAccountMintedDaily, err error) {
+	err = d.db.Model(&TableAccountInfo{}).Where("parent_account_id=?", parentAccountId).
+		Select("registered_at DIV 86400 AS day,COUNT(*) AS count").
+		Group("day").Order("day").
+		Find(&list).Error
+	return
+}
+
+func (d *DbDao) GetAccountListByAccountIds(accountIds []string) (list []TableAccountInfo, err error) {
+	if len(accountIds) == 0 {
+		return
+	}
+	err = d.db.Where("account_id IN ?", accountIds).Find(&list).Error
+	return
+}
+
+// GetSubAccountListByParentIds the sub-accounts of each parent by account, limit of each parent after offset
+func (d *DbDao) GetSubAccountListByParentIds(parentAccountIds []string, limit, offset int) (list []TableAccountInfo, err error) {
+	err = d.findPerKey(&TableAccountInfo{}, &list, "parent_account_id", "account", parentAccountIds, limit, offset)
+	return
+}
+
+type SubAccountCount struct {
+	ParentAccountId string `json:"parent_account_id" gorm:"column:parent_account_id"`
+	Count           int64  `json:"count" gorm:"column:count"`
+}
+
+func (d *DbDao) GetSubAccountCountByParentIds(parentAccountIds []string) (list []SubAccountCount, err error) {
+	if len(parentAccountIds) == 0 {
+		return
+	}
+	err = d.db.Model(&TableAccountInfo{}).Select("parent_account_id,COUNT(*) AS count").
+		Where("parent_account_id IN ?", parentAccountIds).Group("parent_account_id").Find(&list).Error
+	return
+}
@@ -0,0 +1,108 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+// AddressColumn an address column with the columns of its algorithm id and chain type, empty when the table has none
+type AddressColumn struct {
+	Table       string
+	AlgorithmId string
+	ChainType   string
+	Address     string
+}
+
+// AddressColumns the owner, manager and other address columns kept in the canonical form, the recipient of
+// an approval is kept as a normal address and the reverse records keep the payloads as formatted by das-lib
+var AddressColumns = []AddressColumn{
+	{Table: TableNameAccountInfo, AlgorithmId: "owner_algorithm_id", ChainType: "owner_chain_type", Address: "owner"},
+	{Table: TableNameAccountInfo, AlgorithmId: "manager_algorithm_id", ChainType: "manager_chain_type", Address: "manager"},
+	{Table: TableNameSnapshotPermissionsInfo, AlgorithmId: "owner_algorithm_id", Address: "owner"},
+	{Table: TableNameSnapshotPermissionsInfo, AlgorithmId: "manager_algorithm_id", Address: "manager"},
+	{Table: TableNameSnapshotRegisterInfo, AlgorithmId: "owner_algorithm_id", Address: "owner"},
+	{Table: TableNameTradeInfo, AlgorithmId: "owner_algorithm_id", ChainType: "owner_chain_type", Address: "owner_address"},
+	{Table: TableNameTradeHistoryInfo, AlgorithmId: "owner_algorithm_id", ChainType: "owner_chain_type", Address: "owner_address"},
+	{Table: TableNameTradeDealInfo, ChainType: "sell_chain_type", Address: "sell_address"},
+	{Table: TableNameTradeDealInfo, ChainType: "buy_chain_type", Address: "buy_address"},
+	{Table: TableNameOfferInfo, AlgorithmId: "algorithm_id", ChainType: "chain_type", Address: "address"},
+	{Table: TableNameApprovalInfo, AlgorithmId: "owner_algorithm_id", Address: "owner"},
+	{Table: TableNameOwnershipLedger, AlgorithmId: "from_algorithm_id", ChainType: "from_chain_type", Address: "from_address"},
+	{Table: TableNameOwnershipLedger, AlgorithmId: "to_algorithm_id", ChainType: "to_chain_type", Address: "to_address"},
+	{Table: TableNameCrossChainInfo, AlgorithmId: "owner_algorithm_id", ChainType: "owner_chain_type", Address: "owner"},
+	{Table: TableNameCrossChainInfo, AlgorithmId: "unlock_owner_algorithm_id", ChainType: "unlock_owner_chain_type", Address: "unlock_owner"},
+	{Table: TableNameRebateInfo, ChainType: "invitee_chain_type", Address: "invitee_address"},
+	{Table: TableNameRebateInfo, ChainType: "inviter_chain_type", Address: "inviter_address"},
+	{Table: TableNameTransactionInfo, ChainType: "chain_type", Address: "address"},
+}
+
+func (c AddressColumn) String() string {
+	return c.Table + "." + c.Address
+}
+
+// AddressRow an address of the column with its algorithm id and chain type,
+// the one missing from the table is derived from the other
+type AddressRow struct {
+	Id          uint64                `gorm:"column:id"`
+	Address     string                `gorm:"column:address"`
+	AlgorithmId common.DasAlgorithmId `gorm:"column:algorithm_id"`
+	ChainType   common.ChainType      `gorm:"column:chain_type"`
+}
+
+// GetAddressList the addresses of the column after the id
+func (d *DbDao) GetAddressList(c AddressColumn, afterId uint64, limit int) (list []AddressRow, err error) {
+	algorithmId, chainType := "0", "0"
+	if c.AlgorithmId != "" {
+		algorithmId = fmt.Sprintf("`%s`", c.AlgorithmId)
+	}
+	if c.ChainType != "" {
+		chainType = fmt.Sprintf("`%s`", c.ChainType)
+	}
+	err = d.db.Table(c.Table).
+		Select(fmt.Sprintf("id,`%s` AS address,%s AS algorithm_id,%s AS chain_type", c.Address, algorithmId, chainType)).
+		Where("id>?", afterId).Where(fmt.Sprintf("`%s`!=''", c.Address)).
+		Order("id").Limit(limit).Find(&list).Error
+	if err != nil {
+		return
+	}
+	for i, v := range list {
+		if c.AlgorithmId == "" {
+			list[i].AlgorithmId = v.ChainType.ToDasAlgorithmId(false)
+		}
+		if c.ChainType == "" {
+			list[i].ChainType = v.AlgorithmId.ToChainType()
+		}
+	}
+	return
+}
+
+// UpdateAddressList sets the addresses of the column by id. A row whose new address would duplicate a unique key
+// is left as it is and returned in conflicts, the other rows are updated
+func (d *DbDao) UpdateAddressList(c AddressColumn, list []AddressRow) (conflicts []AddressRow, err error) {
+	if len(list) == 0 {
+		return
+	}
+	err = d.db.Transaction(func(tx *gorm.DB) error {
+		for _, v := range list {
+			if err := tx.Table(c.Table).Where("id=?", v.Id).Update(c.Address, v.Address).Error; err != nil {
+				if !isDuplicateKey(err) {
+					return err
+				}
+				conflicts = append(conflicts, v)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func isDuplicateKey(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
@@ -0,0 +1,67 @@
+package dao
+
+import (
+	"fmt"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type ApprovalEvent string
+
+const (
+	ApprovalEventUnprotected ApprovalEvent = "unprotected" // protected_until is reached, the owner can revoke the approval
+	ApprovalEventFulfillable ApprovalEvent = "fulfillable" // sealed_until is reached, the approval can be fulfilled
+)
+
+// TableApprovalEvent a lifecycle event of a pending approval, EventAt is the protected_until or sealed_until
+// it was emitted for, a delayed approval gets a new fulfillable event with the new sealed_until
+type TableApprovalEvent struct {
+	Id         uint64        `json:"id" gorm:"column:id;primaryKey;type:bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT ''"`
+	ApprovalId uint64        `json:"approval_id" gorm:"column:approval_id;uniqueIndex:uk_ai_e_ea,priority:1;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT 'id of t_approval_info'"`
+	AccountId  string        `json:"account_id" gorm:"column:account_id;index:k_account_id;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	Account    string        `json:"account" gorm:"column:account;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	Event      ApprovalEvent `json:"event" gorm:"column:event;uniqueIndex:uk_ai_e_ea,priority:2;type:varchar(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'unprotected, fulfillable'"`
+	EventAt    uint64        `json:"event_at" gorm:"column:event_at;uniqueIndex:uk_ai_e_ea,priority:3;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT 'protected_until or sealed_until, in seconds'"`
+	CreatedAt  time.Time     `json:"created_at" gorm:"column:created_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT ''"`
+	UpdatedAt  time.Time     `json:"updated_at" gorm:"column:updated_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT ''"`
+}
+
+const (
+	TableNameApprovalEvent = "t_approval_event"
+)
+
+func (t *TableApprovalEvent) TableName() string {
+	return TableNameApprovalEvent
+}
+
+var approvalEventColumns = map[ApprovalEvent]string{
+	ApprovalEventUnprotected: "protected_until",
+	ApprovalEventFulfillable: "sealed_until",
+}
+
+// GetApprovalDueList the pending approvals which reached the time of the event and have no such event yet
+func (d *DbDao) GetApprovalDueList(event ApprovalEvent, now uint64, limit int) (list []ApprovalInfo, err error) {
+	column, ok := approvalEventColumns[event]
+	if !ok {
+		return nil, fmt.Errorf("unknown approval event: %s", event)
+	}
+	err = d.db.Where("status=? AND "+column+">0 AND "+column+"<=?", ApprovalStatusEnable, now).
+		Where("NOT EXISTS (SELECT 1 FROM "+TableNameApprovalEvent+" e WHERE e.approval_id=t_approval_info.id AND e.event=? AND e.event_at=t_approval_info."+column+")", event).
+		Order("id").Limit(limit).Find(&list).Error
+	return
+}
+
+func (d *DbDao) CreateApprovalEvents(list []TableApprovalEvent) error {
+	if len(list) == 0 {
+		return nil
+	}
+	return d.db.Clauses(clause.Insert{Modifier: "IGNORE"}).Create(&list).Error
+}
+
+func (d *DbDao) GetApprovalEventList(approvalIds []uint64) (list []TableApprovalEvent, err error) {
+	if len(approvalIds) == 0 {
+		return
+	}
+	err = d.db.Where("approval_id IN ?", approvalIds).Order("id").Find(&list).Error
+	return
+}
@@ -23,7 +23,7 @@ type ApprovalInfo struct {
 	Account          string                `gorm:"column:account;NOT NULL"`
 	AccountID        string                `gorm:"column:account_id;index:idx_account_id;NOT NULL"`
 	ParentAccountID  string                `gorm:"column:parent_account_id;index:idx_parent_account_id;NOT NULL"`
-	Platform         string                `gorm:"column:platform;NOT NULL"` // platform address
+	Platform         string                `gorm:"column:platform;index:idx_platform;NOT NULL"` // platform address
 	OwnerAlgorithmID common.DasAlgorithmId `gorm:"column:owner_algorithm_id;default:0;NOT NULL"`
 	Owner            string                `gorm:"column:owner;index:idx_owner;NOT NULL"` // owner address
 	ToAlgorithmID    common.DasAlgorithmId `gorm:"column:to_algorithm_id;default:0;NOT NULL"`
@@ -37,8 +37,12 @@ type ApprovalInfo struct {
 	UpdatedAt        time.Time             `gorm:"column:updated_at;type:timestamp;default:CURRENT_TIMESTAMP;NOT NULL" json:"updated_at"`
 }
 
+const (
+	TableNameApprovalInfo = "t_approval_info"
+)
+
 func (m *ApprovalInfo) TableName() string {
-	return "t_approval_info"
+	return TableNameApprovalInfo
 }
 
 func (d *DbDao) CreateAccountApproval(info ApprovalInfo) (err error) {
@@ -69,3 +73,43 @@ func (d *DbDao) GetPendingApprovalByAccIdAndPlatform(accountId, platform string)
 	}
 	return
 }
+
+// ApprovalFilter all fields are optional, Owner and Platform are hex addresses, To is a normal address
+type ApprovalFilter struct {
+	AccountId string
+	Owner     string
+	Platform  string
+	To        string
+	Status    []ApprovalStatus
+}
+
+func (d *DbDao) approvalQuery(f ApprovalFilter) *gorm.DB {
+	db := d.db.Model(&ApprovalInfo{})
+	if f.AccountId != "" {
+		db = db.Where("account_id=?", f.AccountId)
+	}
+	if f.Owner != "" {
+		db = db.Where("owner=?", f.Owner)
+	}
+	if f.Platform != "" {
+		db = db.Where("platform=?", f.Platform)
+	}
+	if f.To != "" {
+		db = db.Where("`to`=?", f.To)
+	}
+	if len(f.Status) > 0 {
+		db = db.Where("status IN ?", f.Status)
+	}
+	return db
+}
+
+func (d *DbDao) GetApprovalList(f ApprovalFilter, cursor *Cursor, limit, offset int) (list []ApprovalInfo, err error) {
+	err = afterCursor(d.approvalQuery(f), cursor, "", "id", true).
+		Order("id DESC").Limit(limit).Offset(offset).Find(&list).Error
+	return
+}
+
+func (d *DbDao) GetApprovalCount(f ApprovalFilter) (count int64, err error) {
+	err = d.approvalQuery(f).Count(&count).Error
+	return
+}
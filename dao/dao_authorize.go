@@ -15,7 +15,7 @@ type TableAuthorize struct {
 	MasterPk       string                `json:"master_pk" gorm:"column:master_pk; type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL"`
 	SlaveAlgId     common.DasAlgorithmId `json:"slave_alg_id" gorm:"column:slave_alg_id; type:tinyint DEFAULT NULL"`
 	SlaveSubAlgId  common.DasAlgorithmId `json:"slave_sub_alg_id" gorm:"column:slave_sub_alg_id; type:tinyint DEFAULT NULL"`
-	SlaveCid       string                `json:"slave_cid" gorm:"column:slave_cid; uniqueIndex:uk_mastercid_slavecid;  type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL"`
+	SlaveCid       string                `json:"slave_cid" gorm:"column:slave_cid; uniqueIndex:uk_mastercid_slavecid; index:k_slave_cid; type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL"`
 	SlavePk        string                `json:"slave_pk" gorm:"column:slave_pk; type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL"`
 	Outpoint       string                `json:"outpoint" gorm:"column:outpoint;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL"`
 	CreatedAt      time.Time             `json:"created_at" gorm:"column:created_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT ''""`
@@ -30,7 +30,7 @@ func (t *TableAuthorize) TableName() string {
 	return TableNameAuthorize
 }
 
-func (d *DbDao) UpdateAuthorizeByMaster(authorize []TableAuthorize, masterCidPks, slaveCidPksSign TableCidPk, slaveCidPks []TableCidPk) error {
+func (d *DbDao) UpdateAuthorizeByMaster(authorize []TableAuthorize, masterCidPks, slaveCidPksSign TableCidPk, slaveCidPks []TableCidPk, history []TableAuthorizeHistory) error {
 	return d.db.Transaction(func(tx *gorm.DB) error {
 		if err := tx.Where("master_cid = ? and master_pk = ? ", authorize[0].MasterCid, authorize[0].MasterPk).Delete(&TableAuthorize{}).Error; err != nil {
 			return err
@@ -67,6 +67,22 @@ func (d *DbDao) UpdateAuthorizeByMaster(authorize []TableAuthorize, masterCidPks
 			}
 		}
 
+		if err := createAuthorizeHistory(tx, history); err != nil {
+			return err
+		}
+
 		return nil
 	})
 }
+
+// GetAuthorizeByMasterCid the keys of the key list of the master, the master itself is one of them
+func (d *DbDao) GetAuthorizeByMasterCid(masterCid string) (list []TableAuthorize, err error) {
+	err = d.db.Where("master_cid=?", masterCid).Order("id").Find(&list).Error
+	return
+}
+
+// GetAuthorizeBySlaveCid the masters which have the key in their key lists
+func (d *DbDao) GetAuthorizeBySlaveCid(slaveCid string) (list []TableAuthorize, err error) {
+	err = d.db.Where("slave_cid=? AND master_cid!=?", slaveCid, slaveCid).Order("id").Find(&list).Error
+	return
+}
@@ -0,0 +1,83 @@
+package dao
+
+import (
+	"github.com/dotbitHQ/das-lib/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// TableAuthorizeHistory the keys of every key list cell of a master key, one row per key and outpoint,
+// t_authorize only keeps the keys of the live cell
+type TableAuthorizeHistory struct {
+	Id             uint64                `json:"id" gorm:"column:id;primaryKey;type:bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT ''"`
+	BlockNumber    uint64                `json:"block_number" gorm:"column:block_number;index:k_block_number;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
+	BlockTimestamp uint64                `json:"block_timestamp" gorm:"column:block_timestamp;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
+	Outpoint       string                `json:"outpoint" gorm:"column:outpoint;uniqueIndex:uk_o_sc,priority:1;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'outpoint of the key list cell'"`
+	Action         common.DasAction      `json:"action" gorm:"column:action;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	MasterCid      string                `json:"master_cid" gorm:"column:master_cid;index:k_master_cid;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	MasterPk       string                `json:"master_pk" gorm:"column:master_pk;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	SlaveAlgId     common.DasAlgorithmId `json:"slave_alg_id" gorm:"column:slave_alg_id;type:smallint(6) NOT NULL DEFAULT '0' COMMENT ''"`
+	SlaveSubAlgId  common.DasAlgorithmId `json:"slave_sub_alg_id" gorm:"column:slave_sub_alg_id;type:smallint(6) NOT NULL DEFAULT '0' COMMENT ''"`
+	SlaveCid       string                `json:"slave_cid" gorm:"column:slave_cid;uniqueIndex:uk_o_sc,priority:2;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	SlavePk        string                `json:"slave_pk" gorm:"column:slave_pk;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	CreatedAt      time.Time             `json:"created_at" gorm:"column:created_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT ''"`
+	UpdatedAt      time.Time             `json:"updated_at" gorm:"column:updated_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT ''"`
+}
+
+const (
+	TableNameAuthorizeHistory = "t_authorize_history"
+)
+
+func (t *TableAuthorizeHistory) TableName() string {
+	return TableNameAuthorizeHistory
+}
+
+// AuthorizeHistoryOutpoint a key list cell of the master, Id is the smallest id of its keys
+type AuthorizeHistoryOutpoint struct {
+	Id             uint64           `json:"id" gorm:"column:id"`
+	Outpoint       string           `json:"outpoint" gorm:"column:outpoint"`
+	Action         common.DasAction `json:"action" gorm:"column:action"`
+	BlockNumber    uint64           `json:"block_number" gorm:"column:block_number"`
+	BlockTimestamp uint64           `json:"block_timestamp" gorm:"column:block_timestamp"`
+}
+
+func createAuthorizeHistory(tx *gorm.DB, list []TableAuthorizeHistory) error {
+	if len(list) == 0 {
+		return nil
+	}
+	return tx.Clauses(clause.Insert{
+		Modifier: "IGNORE",
+	}).Create(&list).Error
+}
+
+func (d *DbDao) authorizeHistoryQuery(masterCid, outpoint string) *gorm.DB {
+	db := d.db.Model(&TableAuthorizeHistory{}).Where("master_cid=?", masterCid)
+	if outpoint != "" {
+		db = db.Where("outpoint=?", outpoint)
+	}
+	return db
+}
+
+// GetAuthorizeHistoryOutpoints the key list cells of the master, the latest first
+func (d *DbDao) GetAuthorizeHistoryOutpoints(masterCid, outpoint string, cursor *Cursor, limit, offset int) (list []AuthorizeHistoryOutpoint, err error) {
+	err = afterCursor(d.authorizeHistoryQuery(masterCid, outpoint), cursor, "", "id", true).
+		Select("MIN(id) AS id,outpoint,action,block_number,block_timestamp").
+		Group("outpoint,action,block_number,block_timestamp").
+		Order("id DESC").
+		Limit(limit).Offset(offset).Find(&list).Error
+	return
+}
+
+func (d *DbDao) GetAuthorizeHistoryOutpointCount(masterCid, outpoint string) (count int64, err error) {
+	err = d.authorizeHistoryQuery(masterCid, outpoint).Distinct("outpoint").Count(&count).Error
+	return
+}
+
+func (d *DbDao) GetAuthorizeHistoryByOutpoints(outpoints []string) (list []TableAuthorizeHistory, err error) {
+	if len(outpoints) == 0 {
+		return
+	}
+	err = d.db.Where("outpoint IN(?)", outpoints).Order("id").Find(&list).Error
+	return
+}
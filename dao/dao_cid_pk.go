@@ -1,7 +1,7 @@
 package dao
 
 import (
-	"fmt"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 	"time"
 )
@@ -32,22 +32,21 @@ func (t *TableCidPk) TableName() string {
 	return TableNameCidPk
 }
 
-//insert cid pk
-func (d *DbDao) InsertCidPk(data []TableCidPk) (err error) {
-	if len(data) == 0 {
-		return fmt.Errorf("data is empty")
-	}
-	if err := d.db.Clauses(clause.OnConflict{
-		DoUpdates: clause.AssignmentColumns([]string{
-			"enable_authorize", "outpoint",
-		}),
-	}).Create(&data).Error; err != nil {
-		return err
-	}
-	return
-}
-
 func (d *DbDao) GetCidPk(cid1 string) (cidpk TableCidPk, err error) {
 	err = d.db.Where("`cid`= ? ", cid1).Find(&cidpk).Error
 	return
 }
+
+// CreateDeviceKeyList the master key of a new key list cell with the keys of the cell kept in t_authorize_history
+func (d *DbDao) CreateDeviceKeyList(cidPk TableCidPk, history []TableAuthorizeHistory) error {
+	return d.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{
+			DoUpdates: clause.AssignmentColumns([]string{
+				"enable_authorize", "outpoint",
+			}),
+		}).Create(&cidPk).Error; err != nil {
+			return err
+		}
+		return createAuthorizeHistory(tx, history)
+	})
+}
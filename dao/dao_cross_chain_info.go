@@ -0,0 +1,223 @@
+package dao
+
+import (
+	"github.com/dotbitHQ/das-lib/common"
+	"gorm.io/gorm"
+	"time"
+)
+
+type CrossChainStatus uint8
+
+const (
+	CrossChainStatusLocked   CrossChainStatus = 1
+	CrossChainStatusUnlocked CrossChainStatus = 2
+)
+
+// TableCrossChainInfo one bridge of an account, created by the lock and closed by the unlock.
+// The owner is the one before the lock, the account is held by the black hole address in between.
+// An unlock without an indexed lock is kept with an empty lock_tx_hash
+type TableCrossChainInfo struct {
+	Id                     uint64                `json:"id" gorm:"column:id;primaryKey;type:bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT ''"`
+	AccountId              string                `json:"account_id" gorm:"column:account_id;uniqueIndex:uk_ai_lth_uth,priority:1;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'hash of account'"`
+	Account                string                `json:"account" gorm:"column:account;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	ParentAccountId        string                `json:"parent_account_id" gorm:"column:parent_account_id;index:k_parent_account_id;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	Status                 CrossChainStatus      `json:"status" gorm:"column:status;index:k_s_la,priority:1;type:smallint(6) NOT NULL DEFAULT '0' COMMENT '1: locked 2: unlocked'"`
+	OwnerAlgorithmId       common.DasAlgorithmId `json:"owner_algorithm_id" gorm:"column:owner_algorithm_id;type:smallint(6) NOT NULL DEFAULT '0' COMMENT ''"`
+	OwnerChainType         common.ChainType      `json:"owner_chain_type" gorm:"column:owner_chain_type;index:k_oct_o,priority:1;type:smallint(6) NOT NULL DEFAULT '0' COMMENT ''"`
+	Owner                  string                `json:"owner" gorm:"column:owner;index:k_oct_o,priority:2;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'owner before the lock'"`
+	CoinType               string                `json:"coin_type" gorm:"column:coin_type;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'target chain of the lock'"`
+	ChainId                string                `json:"chain_id" gorm:"column:chain_id;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	LockTxHash             string                `json:"lock_tx_hash" gorm:"column:lock_tx_hash;uniqueIndex:uk_ai_lth_uth,priority:2;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	LockBlockNumber        uint64                `json:"lock_block_number" gorm:"column:lock_block_number;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
+	LockedAt               uint64                `json:"locked_at" gorm:"column:locked_at;index:k_s_la,priority:2;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT 'block_timestamp of the lock'"`
+	UnlockTxHash           string                `json:"unlock_tx_hash" gorm:"column:unlock_tx_hash;uniqueIndex:uk_ai_lth_uth,priority:3;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	UnlockBlockNumber      uint64                `json:"unlock_block_number" gorm:"column:unlock_block_number;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
+	UnlockedAt             uint64                `json:"unlocked_at" gorm:"column:unlocked_at;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT 'block_timestamp of the unlock'"`
+	UnlockOwnerAlgorithmId common.DasAlgorithmId `json:"unlock_owner_algorithm_id" gorm:"column:unlock_owner_algorithm_id;type:smallint(6) NOT NULL DEFAULT '0' COMMENT ''"`
+	UnlockOwnerChainType   common.ChainType      `json:"unlock_owner_chain_type" gorm:"column:unlock_owner_chain_type;type:smallint(6) NOT NULL DEFAULT '0' COMMENT ''"`
+	UnlockOwner            string                `json:"unlock_owner" gorm:"column:unlock_owner;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'owner after the unlock'"`
+	CreatedAt              time.Time             `json:"created_at" gorm:"column:created_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT ''"`
+	UpdatedAt              time.Time             `json:"updated_at" gorm:"column:updated_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT ''"`
+}
+
+const (
+	TableNameCrossChainInfo = "t_cross_chain_info"
+)
+
+func (t *TableCrossChainInfo) TableName() string {
+	return TableNameCrossChainInfo
+}
+
+// saveCrossChainInfo opens a bridge for a lock with the owner in before, or closes the open bridge of the account
+// locked before the unlock. Both are idempotent so that a block parsed again does not add rows, a lock is looked up
+// by its tx hash since its row no longer matches the unique key once it is unlocked
+func saveCrossChainInfo(tx *gorm.DB, before map[string]TableAccountInfo, info TableCrossChainInfo) error {
+	if info.Status == CrossChainStatusLocked {
+		var done int64
+		if err := tx.Model(&TableCrossChainInfo{}).
+			Where("account_id=? AND lock_tx_hash=?", info.AccountId, info.LockTxHash).
+			Count(&done).Error; err != nil {
+			return err
+		} else if done > 0 {
+			return nil
+		}
+		if old, ok := before[info.AccountId]; ok {
+			info.OwnerAlgorithmId = old.OwnerAlgorithmId
+			info.OwnerChainType = old.OwnerChainType
+			info.Owner = old.Owner
+		}
+		return tx.Create(&info).Error
+	}
+
+	var done int64
+	if err := tx.Model(&TableCrossChainInfo{}).
+		Where("account_id=? AND unlock_tx_hash=?", info.AccountId, info.UnlockTxHash).
+		Count(&done).Error; err != nil {
+		return err
+	} else if done > 0 {
+		return nil
+	}
+	var open TableCrossChainInfo
+	if err := tx.Where("account_id=? AND status=? AND lock_block_number<=?", info.AccountId, CrossChainStatusLocked, info.UnlockBlockNumber).
+		Order("lock_block_number DESC,id DESC").Limit(1).Find(&open).Error; err != nil {
+		return err
+	}
+	if open.Id == 0 {
+		return tx.Create(&info).Error
+	}
+	return tx.Model(&TableCrossChainInfo{}).Where("id=?", open.Id).Updates(map[string]interface{}{
+		"status":                    CrossChainStatusUnlocked,
+		"unlock_tx_hash":            info.UnlockTxHash,
+		"unlock_block_number":       info.UnlockBlockNumber,
+		"unlocked_at":               info.UnlockedAt,
+		"unlock_owner_algorithm_id": info.UnlockOwnerAlgorithmId,
+		"unlock_owner_chain_type":   info.UnlockOwnerChainType,
+		"unlock_owner":              info.UnlockOwner,
+	}).Error
+}
+
+// CrossChainActions the actions of t_transaction_info the bridges are made of
+var CrossChainActions = []string{
+	common.DasActionLockAccountForCrossChain, common.DasActionUnlockAccountForCrossChain,
+	common.DasActionLockSubAccountForCrossChain, common.DasActionUnlockSubAccountForCrossChain,
+}
+
+// GetCrossChainTransactionList the locks and unlocks after the id, in id order
+func (d *DbDao) GetCrossChainTransactionList(afterId uint64, limit int) (list []TableTransactionInfo, err error) {
+	err = d.db.Where("id>? AND action IN ?", afterId, CrossChainActions).
+		Order("id").Limit(limit).Find(&list).Error
+	return
+}
+
+// SaveCrossChainInfo saveCrossChainInfo out of the parser, for the backfill of the past locks and unlocks
+func (d *DbDao) SaveCrossChainInfo(before map[string]TableAccountInfo, info TableCrossChainInfo) error {
+	return d.db.Transaction(func(tx *gorm.DB) error {
+		return saveCrossChainInfo(tx, before, info)
+	})
+}
+
+// CrossChainFilter the owner is the one before the lock, LockedBefore is a block_timestamp
+type CrossChainFilter struct {
+	ParentAccountId string
+	OwnerChainType  common.ChainType
+	Owner           string
+	LockedBefore    uint64
+}
+
+func (d *DbDao) crossChainLockedQuery(f CrossChainFilter) *gorm.DB {
+	db := d.db.Model(&TableCrossChainInfo{}).Where("status=?", CrossChainStatusLocked)
+	if f.ParentAccountId != "" {
+		db = db.Where("parent_account_id=?", f.ParentAccountId)
+	}
+	if f.Owner != "" {
+		db = db.Where("owner_chain_type=? AND owner=?", f.OwnerChainType, f.Owner)
+	}
+	if f.LockedBefore > 0 {
+		db = db.Where("locked_at<?", f.LockedBefore)
+	}
+	return db
+}
+
+// GetCrossChainLockedList the accounts currently bridged, the longest locked first
+func (d *DbDao) GetCrossChainLockedList(f CrossChainFilter, cursor *Cursor, limit, offset int) (list []TableCrossChainInfo, err error) {
+	err = afterCursor(d.crossChainLockedQuery(f), cursor, "locked_at", "id", false).
+		Order("locked_at,id").
+		Limit(limit).Offset(offset).Find(&list).Error
+	return
+}
+
+func (d *DbDao) GetCrossChainLockedCount(f CrossChainFilter) (count int64, err error) {
+	err = d.crossChainLockedQuery(f).Count(&count).Error
+	return
+}
+
+// GetCrossChainOrphanUnlockList unlocks without an indexed lock
+func (d *DbDao) GetCrossChainOrphanUnlockList(limit int) (list []TableCrossChainInfo, err error) {
+	err = d.db.Where("status=? AND lock_tx_hash=''", CrossChainStatusUnlocked).
+		Order("unlock_block_number DESC").Limit(limit).Find(&list).Error
+	return
+}
+
+// GetCrossChainDoubleLockList the open bridges of the accounts which are locked more than once without an unlock in between
+func (d *DbDao) GetCrossChainDoubleLockList(limit int) (list []TableCrossChainInfo, err error) {
+	sub := d.db.Model(&TableCrossChainInfo{}).Select("account_id").
+		Where("status=?", CrossChainStatusLocked).Group("account_id").Having("COUNT(*)>1")
+	err = d.db.Where("status=? AND account_id IN(?)", CrossChainStatusLocked, sub).
+		Order("account_id,id").Limit(limit).Find(&list).Error
+	return
+}
+
+// GetCrossChainStatusMismatchList the open bridges whose account is no longer on lock in t_account_info
+func (d *DbDao) GetCrossChainStatusMismatchList(limit int) (list []TableCrossChainInfo, err error) {
+	err = d.db.Table(TableNameCrossChainInfo+" c").Select("c.*").
+		Joins("LEFT JOIN "+TableNameAccountInfo+" a ON a.account_id=c.account_id").
+		Where("c.status=? AND (a.account_id IS NULL OR a.status!=?)", CrossChainStatusLocked, AccountStatusOnLock).
+		Order("c.locked_at").Limit(limit).Find(&list).Error
+	return
+}
+
+// GetAccountOnLockUnregisteredList the accounts on lock in t_account_info without an open bridge,
+// locked before the registry was indexed or missed by the parser
+func (d *DbDao) GetAccountOnLockUnregisteredList(limit int) (list []TableAccountInfo, err error) {
+	sub := d.db.Model(&TableCrossChainInfo{}).Select("account_id").Where("status=?", CrossChainStatusLocked)
+	err = d.db.Where("status=? AND account_id NOT IN(?)", AccountStatusOnLock, sub).
+		Order("block_number").Limit(limit).Find(&list).Error
+	return
+}
+
+// CrossChainReport compares the locks with the unlocks and with the status in t_account_info, each list is limited
+type CrossChainReport struct {
+	StuckCount     int64
+	Stuck          []TableCrossChainInfo
+	OrphanUnlocks  []TableCrossChainInfo
+	DoubleLocks    []TableCrossChainInfo
+	StatusMismatch []TableCrossChainInfo
+	Unregistered   []TableAccountInfo
+}
+
+func (r CrossChainReport) IsEmpty() bool {
+	return r.StuckCount == 0 && len(r.OrphanUnlocks) == 0 && len(r.DoubleLocks) == 0 &&
+		len(r.StatusMismatch) == 0 && len(r.Unregistered) == 0
+}
+
+// GetCrossChainReport the bridges locked before stuckBefore, a block_timestamp, are stuck
+func (d *DbDao) GetCrossChainReport(stuckBefore uint64, limit int) (report CrossChainReport, err error) {
+	f := CrossChainFilter{LockedBefore: stuckBefore}
+	if report.StuckCount, err = d.GetCrossChainLockedCount(f); err != nil {
+		return
+	}
+	if report.Stuck, err = d.GetCrossChainLockedList(f, nil, limit, 0); err != nil {
+		return
+	}
+	if report.OrphanUnlocks, err = d.GetCrossChainOrphanUnlockList(limit); err != nil {
+		return
+	}
+	if report.DoubleLocks, err = d.GetCrossChainDoubleLockList(limit); err != nil {
+		return
+	}
+	if report.StatusMismatch, err = d.GetCrossChainStatusMismatchList(limit); err != nil {
+		return
+	}
+	report.Unregistered, err = d.GetAccountOnLockUnregisteredList(limit)
+	return
+}
@@ -0,0 +1,71 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+)
+
+// Cursor is the sort key of the last row of a page, the next page starts right after it.
+// Key holds numeric sort columns (block_number, expired_at, price ...), KeyStr string ones, Id breaks the ties
+type Cursor struct {
+	Key    uint64 `json:"k,omitempty"`
+	KeyStr string `json:"s,omitempty"`
+	Id     uint64 `json:"i,omitempty"`
+}
+
+var ErrCursorInvalid = errors.New("cursor invalid")
+
+// afterCursor keeps the rows after the cursor for a query ordered by (keyColumn, idColumn) with a numeric keyColumn,
+// either column may be empty when the order has a single column. A cursor with a string key is rejected
+func afterCursor(db *gorm.DB, cursor *Cursor, keyColumn, idColumn string, desc bool) *gorm.DB {
+	if cursor == nil {
+		return db
+	}
+	if cursor.KeyStr != "" {
+		db = db.Session(&gorm.Session{})
+		_ = db.AddError(ErrCursorInvalid)
+		return db
+	}
+	return cursorWhere(db, cursor.Key, cursor.Id, keyColumn, idColumn, desc)
+}
+
+// afterStrCursor is afterCursor for a string keyColumn, the cursor must carry a string key
+func afterStrCursor(db *gorm.DB, cursor *Cursor, keyColumn, idColumn string, desc bool) *gorm.DB {
+	if cursor == nil {
+		return db
+	}
+	if cursor.KeyStr == "" || cursor.Key != 0 {
+		db = db.Session(&gorm.Session{})
+		_ = db.AddError(ErrCursorInvalid)
+		return db
+	}
+	return cursorWhere(db, cursor.KeyStr, cursor.Id, keyColumn, idColumn, desc)
+}
+
+func cursorWhere(db *gorm.DB, key interface{}, id uint64, keyColumn, idColumn string, desc bool) *gorm.DB {
+	op := ">"
+	if desc {
+		op = "<"
+	}
+	switch {
+	case keyColumn == "":
+		return db.Where(fmt.Sprintf("%s%s?", idColumn, op), id)
+	case idColumn == "":
+		return db.Where(fmt.Sprintf("%s%s?", keyColumn, op), key)
+	default:
+		return db.Where(fmt.Sprintf("(%s%s? OR (%s=? AND %s%s?))", keyColumn, op, keyColumn, idColumn, op), key, key, id)
+	}
+}
+
+// findPerKey the rows of each key in the order, limit rows of a key after its first offset rows,
+// so the nested lists of many keys are loaded with one query
+func (d *DbDao) findPerKey(model, dest interface{}, keyColumn, order string, keys []string, limit, offset int) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	sub := d.db.Model(model).Select(fmt.Sprintf("*,ROW_NUMBER() OVER (PARTITION BY %s ORDER BY %s) AS rn", keyColumn, order)).
+		Where(fmt.Sprintf("%s IN ?", keyColumn), keys)
+	return d.db.Table("(?) AS t", sub).Where("rn>? AND rn<=?", offset, offset+limit).
+		Order(keyColumn + ",rn").Find(dest).Error
+}
@@ -57,3 +57,8 @@ func (d *DbDao) UpdateCustomScript(cs TableCustomScriptInfo, accountCellOutpoint
 		return nil
 	})
 }
+
+func (d *DbDao) GetCustomScriptInfo(accountId string) (info TableCustomScriptInfo, err error) {
+	err = d.db.Where("account_id=?", accountId).Limit(1).Find(&info).Error
+	return
+}
@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"github.com/dotbitHQ/das-lib/common"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 	"time"
@@ -14,10 +15,10 @@ type TableDidCellInfo struct {
 	Id           uint64    `json:"id" gorm:"column:id;primaryKey;type:bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT ''"`
 	BlockNumber  uint64    `json:"block_number" gorm:"column:block_number;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
 	Outpoint     string    `json:"outpoint" gorm:"column:outpoint;uniqueIndex:uk_op;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' "`
-	AccountId    string    `json:"account_id" gorm:"column:account_id;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'hash of account'"`
+	AccountId    string    `json:"account_id" gorm:"column:account_id;index:k_account_id;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'hash of account'"`
 	Account      string    `json:"account" gorm:"column:account;index:account;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
-	Args         string    `json:"args" gorm:"column:args;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' "`
-	LockCodeHash string    `json:"lock_code_hash" gorm:"column:lock_code_hash;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' "`
+	Args         string    `json:"args" gorm:"column:args;index:k_lch_args,priority:2;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' "`
+	LockCodeHash string    `json:"lock_code_hash" gorm:"column:lock_code_hash;index:k_lch_args,priority:1;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' "`
 	ExpiredAt    uint64    `json:"expired_at" gorm:"column:expired_at;index:k_expired_at;type:bigint(20) unsigned NOT NULL DEFAULT '0' "`
 	CreatedAt    time.Time `json:"created_at" gorm:"column:created_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP "`
 	UpdatedAt    time.Time `json:"updated_at" gorm:"column:updated_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP "`
@@ -27,78 +28,7 @@ func (t *TableDidCellInfo) TableName() string {
 	return TableNameDidCellInfo
 }
 
-func (d *DbDao) CreateDidCellRecordsInfos(outpoint string, didCellInfo TableDidCellInfo, recordsInfos []TableRecordsInfo, txInfo TableTransactionInfo) error {
-	return d.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Where("account_id = ?", didCellInfo.AccountId).Delete(&TableRecordsInfo{}).Error; err != nil {
-			return err
-		}
-
-		if len(recordsInfos) > 0 {
-			if err := tx.Create(&recordsInfos).Error; err != nil {
-				return err
-			}
-		}
-
-		if err := tx.Select("outpoint", "block_number").
-			Where("outpoint = ?", outpoint).
-			Updates(didCellInfo).Error; err != nil {
-			return err
-		}
-
-		if err := tx.Clauses(clause.Insert{
-			Modifier: "IGNORE",
-		}).Create(&txInfo).Error; err != nil {
-			return err
-		}
-		return nil
-	})
-}
-
-func (d *DbDao) EditDidCellOwner(outpoint string, didCellInfo TableDidCellInfo, txInfo TableTransactionInfo, recordsInfos []TableRecordsInfo) error {
-	return d.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Select("outpoint", "block_number", "args", "lock_code_hash").
-			Where("outpoint = ?", outpoint).
-			Updates(didCellInfo).Error; err != nil {
-			return err
-		}
-		if err := tx.Clauses(clause.Insert{
-			Modifier: "IGNORE",
-		}).Create(&txInfo).Error; err != nil {
-			return err
-		}
-
-		if err := tx.Where("account_id = ?", didCellInfo.AccountId).
-			Delete(&TableRecordsInfo{}).Error; err != nil {
-			return err
-		}
-		if len(recordsInfos) > 0 {
-			if err := tx.Create(&recordsInfos).Error; err != nil {
-				return err
-			}
-		}
-
-		return nil
-	})
-}
-
-func (d *DbDao) DidCellRecycle(outpoint, accountId string, txInfo TableTransactionInfo) error {
-	return d.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Where("account_id=?", accountId).Delete(&TableRecordsInfo{}).Error; err != nil {
-			return err
-		}
-		if err := tx.Where("outpoint = ? ", outpoint).Delete(&TableDidCellInfo{}).Error; err != nil {
-			return err
-		}
-		if err := tx.Clauses(clause.Insert{
-			Modifier: "IGNORE",
-		}).Create(&txInfo).Error; err != nil {
-			return err
-		}
-		return nil
-	})
-}
-
-func (d *DbDao) DidCellUpdateListWithAccountCell(transactionInfo TableTransactionInfo, didCellList []TableDidCellInfo, accountIds []string, records []TableRecordsInfo, accountInfo TableAccountInfo) error {
+func (d *DbDao) DidCellUpdateListWithAccountCell(transactionInfo TableTransactionInfo, didCellList []TableDidCellInfo, accountIds []string, records []TableRecordsInfo, accountInfo TableAccountInfo, ledgers []TableOwnershipLedger) error {
 	return d.db.Transaction(func(tx *gorm.DB) error {
 		if err := tx.Select("block_number", "outpoint", "status").
 			Where("account_id = ?", accountInfo.AccountId).
@@ -113,17 +43,8 @@ func (d *DbDao) DidCellUpdateListWithAccountCell(transactionInfo TableTransactio
 				return err
 			}
 		}
-		if len(accountIds) > 0 {
-			if err := tx.Where("account_id IN(?)", accountIds).
-				Delete(&TableRecordsInfo{}).Error; err != nil {
-				return err
-			}
-		}
-
-		if len(records) > 0 {
-			if err := tx.Create(&records).Error; err != nil {
-				return err
-			}
+		if err := ReplaceRecords(tx, accountIds, records, transactionInfo); err != nil {
+			return err
 		}
 
 		if err := tx.Clauses(clause.Insert{
@@ -132,11 +53,23 @@ func (d *DbDao) DidCellUpdateListWithAccountCell(transactionInfo TableTransactio
 			return err
 		}
 
+		if err := createOwnershipLedgerList(tx, ledgers); err != nil {
+			return err
+		}
+
 		return nil
 	})
 }
 
-func (d *DbDao) DidCellUpdateList(oldOutpointList []string, list []TableDidCellInfo, accountIds []string, records []TableRecordsInfo, listTx []TableTransactionInfo) error {
+// DidCellRecords the records of a did cell replaced by a tx, TxInfo goes into the records history
+type DidCellRecords struct {
+	AccountId string
+	Records   []TableRecordsInfo
+	TxInfo    TableTransactionInfo
+}
+
+// DidCellUpdateList replaces the did cells of the old outpoints by the new list, the status of accountInfos is updated
+func (d *DbDao) DidCellUpdateList(oldOutpointList []string, list []TableDidCellInfo, recordsList []DidCellRecords, accountInfos []TableAccountInfo, listTx []TableTransactionInfo, ledgers []TableOwnershipLedger) error {
 	return d.db.Transaction(func(tx *gorm.DB) error {
 		if len(oldOutpointList) > 0 {
 			if err := tx.Where("outpoint IN(?) ", oldOutpointList).
@@ -151,15 +84,15 @@ func (d *DbDao) DidCellUpdateList(oldOutpointList []string, list []TableDidCellI
 				return err
 			}
 		}
-		if len(accountIds) > 0 {
-			if err := tx.Where("account_id IN(?)", accountIds).
-				Delete(&TableRecordsInfo{}).Error; err != nil {
+		for _, v := range recordsList {
+			if err := ReplaceRecords(tx, []string{v.AccountId}, v.Records, v.TxInfo); err != nil {
 				return err
 			}
 		}
-
-		if len(records) > 0 {
-			if err := tx.Create(&records).Error; err != nil {
+		for _, v := range accountInfos {
+			if err := tx.Select("block_number", "outpoint", "status").
+				Where("account_id = ?", v.AccountId).
+				Updates(v).Error; err != nil {
 				return err
 			}
 		}
@@ -171,32 +104,66 @@ func (d *DbDao) DidCellUpdateList(oldOutpointList []string, list []TableDidCellI
 			}
 		}
 
+		if err := createOwnershipLedgerList(tx, ledgers); err != nil {
+			return err
+		}
+
 		return nil
 	})
 }
 
-func (d *DbDao) DidCellRecycleList(oldOutpointList []string, accountIds []string, listTx []TableTransactionInfo) error {
-	return d.db.Transaction(func(tx *gorm.DB) error {
-		if len(accountIds) > 0 {
-			if err := tx.Where("account_id IN(?)", accountIds).
-				Delete(&TableRecordsInfo{}).Error; err != nil {
-				return err
-			}
-		}
-		if len(oldOutpointList) > 0 {
-			if err := tx.Where("outpoint IN(?) ", oldOutpointList).
-				Delete(&TableDidCellInfo{}).Error; err != nil {
-				return err
-			}
-		}
-		if len(listTx) > 0 {
-			if err := tx.Clauses(clause.Insert{
-				Modifier: "IGNORE",
-			}).Create(&listTx).Error; err != nil {
-				return err
-			}
+// DidCellHolder the lock of the did cells held by an address, the hash type is not kept since did cell locks are type scripts
+type DidCellHolder struct {
+	LockCodeHash string
+	Args         string
+}
+
+func (d *DbDao) GetDidCellByAccountId(accountId string) (info TableDidCellInfo, err error) {
+	err = d.db.Where("account_id=?", accountId).Order("id DESC").Limit(1).Find(&info).Error
+	return
+}
+
+func (d *DbDao) GetDidCellListByAccountIds(accountIds []string) (list []TableDidCellInfo, err error) {
+	if len(accountIds) == 0 {
+		return
+	}
+	err = d.db.Where("account_id IN ?", accountIds).Find(&list).Error
+	return
+}
+
+// GetUpgradedDidCellList the did cells of the accounts of the list which are upgraded to did cells
+func (d *DbDao) GetUpgradedDidCellList(list []TableAccountInfo) ([]TableDidCellInfo, error) {
+	var accountIds []string
+	for _, v := range list {
+		if AccountStatus(v.Status) == AccountStatusOnUpgrade {
+			accountIds = append(accountIds, v.AccountId)
 		}
+	}
+	return d.GetDidCellListByAccountIds(accountIds)
+}
 
-		return nil
-	})
+func (d *DbDao) didCellHolderQuery(holder DidCellHolder) *gorm.DB {
+	return d.db.Model(&TableDidCellInfo{}).Where("lock_code_hash=? AND args=?", holder.LockCodeHash, holder.Args)
+}
+
+func (d *DbDao) GetDidCellListByHolder(holder DidCellHolder, cursor *Cursor, limit, offset int) (list []TableDidCellInfo, err error) {
+	err = afterCursor(d.didCellHolderQuery(holder), cursor, "", "id", false).
+		Order("id").Limit(limit).Offset(offset).Find(&list).Error
+	return
+}
+
+func (d *DbDao) GetDidCellCountByHolder(holder DidCellHolder) (count int64, err error) {
+	err = d.didCellHolderQuery(holder).Count(&count).Error
+	return
+}
+
+// whereAccountHolder matches the accounts held by the address. The owner of an upgraded account in t_account_info
+// is the one before the upgrade, so these accounts are matched by the holder of the did cell instead
+func (d *DbDao) whereAccountHolder(db *gorm.DB, ownerChainType common.ChainType, owner string, holder *DidCellHolder) *gorm.DB {
+	if holder == nil {
+		return db.Where("t_account_info.owner_chain_type=? AND t_account_info.owner=? AND t_account_info.status!=?", ownerChainType, owner, AccountStatusOnUpgrade)
+	}
+	return db.Where("((t_account_info.owner_chain_type=? AND t_account_info.owner=? AND t_account_info.status!=?) OR t_account_info.account_id IN (?))",
+		ownerChainType, owner, AccountStatusOnUpgrade,
+		d.didCellHolderQuery(*holder).Select("account_id"))
 }
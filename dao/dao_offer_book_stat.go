@@ -0,0 +1,50 @@
+package dao
+
+import (
+	"fmt"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"time"
+)
+
+type TableOfferBookStat struct {
+	Id          uint64          `json:"id" gorm:"column:id;primaryKey;type:bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT ''"`
+	AccountId   string          `json:"account_id" gorm:"column:account_id;uniqueIndex:uk_account_id;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'hash of account'"`
+	Account     string          `json:"account" gorm:"column:account;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	OfferCount  uint64          `json:"offer_count" gorm:"column:offer_count;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
+	TopPrice    uint64          `json:"top_price" gorm:"column:top_price;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
+	TopPriceUsd decimal.Decimal `json:"top_price_usd" gorm:"column:top_price_usd;type:decimal(50, 8) NOT NULL DEFAULT '0.00000000' COMMENT ''"`
+	TotalPrice  uint64          `json:"total_price" gorm:"column:total_price;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
+	BlockNumber uint64          `json:"block_number" gorm:"column:block_number;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT 'latest offer block'"`
+	CreatedAt   time.Time       `json:"created_at" gorm:"column:created_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT ''"`
+	UpdatedAt   time.Time       `json:"updated_at" gorm:"column:updated_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT ''"`
+}
+
+const (
+	TableNameOfferBookStat = "t_offer_book_stat"
+)
+
+func (t *TableOfferBookStat) TableName() string {
+	return TableNameOfferBookStat
+}
+
+// refreshOfferBookStat rebuilds the stat rows of the given accounts from the open offers in t_offer_info
+func refreshOfferBookStat(tx *gorm.DB, accountIds []string) error {
+	if len(accountIds) == 0 {
+		return nil
+	}
+	if err := tx.Where("account_id IN ?", accountIds).Delete(&TableOfferBookStat{}).Error; err != nil {
+		return err
+	}
+	// the usd price is the one of the offer with the top price, the earliest one of a tie
+	sql := fmt.Sprintf(`INSERT INTO %s(account_id,account,offer_count,top_price,top_price_usd,total_price,block_number)
+SELECT o.account_id,MAX(o.account),COUNT(*),MAX(IF(o.rn=1,o.price,0)),MAX(IF(o.rn=1,o.price_usd,0)),SUM(o.price),MAX(o.block_number)
+FROM (SELECT *,ROW_NUMBER() OVER (PARTITION BY account_id ORDER BY price DESC,id) AS rn
+FROM %s WHERE account_id IN ?) o GROUP BY o.account_id`, TableNameOfferBookStat, TableNameOfferInfo)
+	return tx.Exec(sql, accountIds).Error
+}
+
+func (d *DbDao) GetOfferBookStat(accountId string) (stat TableOfferBookStat, err error) {
+	err = d.db.Where("account_id=?", accountId).Limit(1).Find(&stat).Error
+	return
+}
@@ -45,6 +45,9 @@ func (d *DbDao) MakeOffer(offerInfo TableOfferInfo, transactionInfo TableTransac
 		}).Create(&offerInfo).Error; err != nil {
 			return err
 		}
+		if err := refreshOfferBookStat(tx, []string{offerInfo.AccountId}); err != nil {
+			return err
+		}
 
 		if err := tx.Clauses(clause.OnConflict{
 			DoUpdates: clause.AssignmentColumns([]string{
@@ -67,6 +70,9 @@ func (d *DbDao) EditOffer(oldOutpoint string, offerInfo TableOfferInfo, transact
 			Where("outpoint = ?", oldOutpoint).Updates(offerInfo).Error; err != nil {
 			return err
 		}
+		if err := refreshOfferBookStat(tx, []string{offerInfo.AccountId}); err != nil {
+			return err
+		}
 
 		if err := tx.Clauses(clause.OnConflict{
 			DoUpdates: clause.AssignmentColumns([]string{
@@ -83,9 +89,17 @@ func (d *DbDao) EditOffer(oldOutpoint string, offerInfo TableOfferInfo, transact
 
 func (d *DbDao) CancelOffer(oldOutpoints []string, transactionInfo TableTransactionInfo) error {
 	return d.db.Transaction(func(tx *gorm.DB) error {
+		var accountIds []string
+		if err := tx.Model(&TableOfferInfo{}).Where("outpoint IN ?", oldOutpoints).
+			Distinct().Pluck("account_id", &accountIds).Error; err != nil {
+			return err
+		}
 		if err := tx.Where("outpoint IN ?", oldOutpoints).Delete(&TableOfferInfo{}).Error; err != nil {
 			return err
 		}
+		if err := refreshOfferBookStat(tx, accountIds); err != nil {
+			return err
+		}
 
 		if err := tx.Clauses(clause.OnConflict{
 			DoUpdates: clause.AssignmentColumns([]string{
@@ -102,6 +116,10 @@ func (d *DbDao) CancelOffer(oldOutpoints []string, transactionInfo TableTransact
 
 func (d *DbDao) AcceptOffer(incomeCellInfos []TableIncomeCellInfo, accountInfo TableAccountInfo, offerOutpoint string, tradeDealInfo TableTradeDealInfo, transactionInfoBuy, transactionInfoSale TableTransactionInfo, rebateInfos []TableRebateInfo, recordsInfos []TableRecordsInfo) error {
 	return d.db.Transaction(func(tx *gorm.DB) error {
+		ownership, err := GetOwnership(tx, []string{accountInfo.AccountId})
+		if err != nil {
+			return err
+		}
 		if len(incomeCellInfos) > 0 {
 			if err := tx.Clauses(clause.OnConflict{
 				DoUpdates: clause.AssignmentColumns([]string{
@@ -120,15 +138,21 @@ func (d *DbDao) AcceptOffer(incomeCellInfos []TableIncomeCellInfo, accountInfo T
 		if err := tx.Where("outpoint = ?", offerOutpoint).Delete(&TableOfferInfo{}).Error; err != nil {
 			return err
 		}
+		if err := refreshOfferBookStat(tx, []string{tradeDealInfo.AccountId}); err != nil {
+			return err
+		}
 
 		if err := tx.Clauses(clause.OnConflict{
 			DoUpdates: clause.AssignmentColumns([]string{
 				"account_id", "account", "deal_type", "sell_chain_type", "sell_address",
-				"buy_chain_type", "buy_address", "price_ckb", "price_usd",
+				"buy_chain_type", "buy_address", "price_ckb", "price_usd", "account_length",
 			}),
 		}).Create(&tradeDealInfo).Error; err != nil {
 			return err
 		}
+		if err := refreshTradeDealDaily(tx, tradeDealInfo); err != nil {
+			return err
+		}
 
 		if err := tx.Clauses(clause.OnConflict{
 			DoUpdates: clause.AssignmentColumns([]string{
@@ -160,16 +184,30 @@ func (d *DbDao) AcceptOffer(incomeCellInfos []TableIncomeCellInfo, accountInfo T
 			}
 		}
 
-		if err := tx.Where("account_id = ?", accountInfo.AccountId).Delete(&TableRecordsInfo{}).Error; err != nil {
+		if err := ReplaceRecords(tx, []string{accountInfo.AccountId}, recordsInfos, transactionInfoBuy); err != nil {
 			return err
 		}
 
-		if len(recordsInfos) > 0 {
-			if err := tx.Create(&recordsInfos).Error; err != nil {
-				return err
-			}
+		if err := CreateOwnershipLedger(tx, ownership, transactionInfoBuy); err != nil {
+			return err
 		}
-
 		return nil
 	})
 }
+
+func (d *DbDao) GetOfferListByAccountId(accountId string, cursor *Cursor, limit, offset int) (list []TableOfferInfo, err error) {
+	err = afterCursor(d.db.Where("account_id=?", accountId), cursor, "price", "id", true).
+		Order("price DESC,id DESC").Limit(limit).Offset(offset).Find(&list).Error
+	return
+}
+
+func (d *DbDao) GetOfferCountByAccountId(accountId string) (count int64, err error) {
+	err = d.db.Model(&TableOfferInfo{}).Where("account_id=?", accountId).Count(&count).Error
+	return
+}
+
+// GetOfferListByAccountIds the offers of each account by price, limit of each account after offset
+func (d *DbDao) GetOfferListByAccountIds(accountIds []string, limit, offset int) (list []TableOfferInfo, err error) {
+	err = d.findPerKey(&TableOfferInfo{}, &list, "account_id", "price DESC,id DESC", accountIds, limit, offset)
+	return
+}
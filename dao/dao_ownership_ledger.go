@@ -0,0 +1,143 @@
+package dao
+
+import (
+	"github.com/dotbitHQ/das-lib/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type TableOwnershipLedger struct {
+	Id              uint64                `json:"id" gorm:"column:id;primaryKey;type:bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT ''"`
+	BlockNumber     uint64                `json:"block_number" gorm:"column:block_number;index:k_ai_bn,priority:2;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
+	BlockTimestamp  uint64                `json:"block_timestamp" gorm:"column:block_timestamp;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
+	TxHash          string                `json:"tx_hash" gorm:"column:tx_hash;uniqueIndex:uk_th_ai_r,priority:1;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	Action          string                `json:"action" gorm:"column:action;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	AccountId       string                `json:"account_id" gorm:"column:account_id;index:k_ai_bn,priority:1;uniqueIndex:uk_th_ai_r,priority:2;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'hash of account'"`
+	Account         string                `json:"account" gorm:"column:account;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	Role            string                `json:"role" gorm:"column:role;uniqueIndex:uk_th_ai_r,priority:3;type:varchar(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'owner or manager'"`
+	FromAlgorithmId common.DasAlgorithmId `json:"from_algorithm_id" gorm:"column:from_algorithm_id;type:smallint(6) NOT NULL DEFAULT '0' COMMENT ''"`
+	FromChainType   common.ChainType      `json:"from_chain_type" gorm:"column:from_chain_type;index:k_fct_fa;type:smallint(6) NOT NULL DEFAULT '0' COMMENT ''"`
+	FromAddress     string                `json:"from_address" gorm:"column:from_address;index:k_fct_fa;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'empty when created'"`
+	ToAlgorithmId   common.DasAlgorithmId `json:"to_algorithm_id" gorm:"column:to_algorithm_id;type:smallint(6) NOT NULL DEFAULT '0' COMMENT ''"`
+	ToChainType     common.ChainType      `json:"to_chain_type" gorm:"column:to_chain_type;index:k_tct_ta;type:smallint(6) NOT NULL DEFAULT '0' COMMENT ''"`
+	ToAddress       string                `json:"to_address" gorm:"column:to_address;index:k_tct_ta;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'empty when recycled'"`
+	CreatedAt       time.Time             `json:"created_at" gorm:"column:created_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT ''"`
+	UpdatedAt       time.Time             `json:"updated_at" gorm:"column:updated_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT ''"`
+}
+
+const (
+	TableNameOwnershipLedger = "t_ownership_ledger"
+
+	OwnershipRoleOwner   = "owner"
+	OwnershipRoleManager = "manager"
+)
+
+func (t *TableOwnershipLedger) TableName() string {
+	return TableNameOwnershipLedger
+}
+
+// GetOwnership reads the current owner and manager of the accounts, call it before updating t_account_info
+// and hand the result to CreateOwnershipLedger afterwards
+func GetOwnership(tx *gorm.DB, accountIds []string) (map[string]TableAccountInfo, error) {
+	res := make(map[string]TableAccountInfo)
+	if len(accountIds) == 0 {
+		return res, nil
+	}
+	var list []TableAccountInfo
+	if err := tx.Select("account_id", "account",
+		"owner_algorithm_id", "owner_chain_type", "owner",
+		"manager_algorithm_id", "manager_chain_type", "manager").
+		Where("account_id IN ?", accountIds).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	for _, v := range list {
+		res[v.AccountId] = v
+	}
+	return res, nil
+}
+
+// CreateOwnershipLedger compares the accounts with their state in before and writes the owner and manager changes,
+// accounts deleted in the meantime are recorded as moved to an empty address
+func CreateOwnershipLedger(tx *gorm.DB, before map[string]TableAccountInfo, transactionInfo TableTransactionInfo) error {
+	if len(before) == 0 {
+		return nil
+	}
+	var accountIds []string
+	for k := range before {
+		accountIds = append(accountIds, k)
+	}
+	after, err := GetOwnership(tx, accountIds)
+	if err != nil {
+		return err
+	}
+
+	txHash, _ := common.String2OutPoint(transactionInfo.Outpoint)
+	var list []TableOwnershipLedger
+	for accountId, old := range before {
+		for _, v := range ownershipDiff(old, after[accountId]) {
+			v.AccountId = accountId
+			v.Account = old.Account
+			v.BlockNumber = transactionInfo.BlockNumber
+			v.BlockTimestamp = transactionInfo.BlockTimestamp
+			v.TxHash = txHash
+			v.Action = transactionInfo.Action
+			list = append(list, v)
+		}
+	}
+	return createOwnershipLedgerList(tx, list)
+}
+
+func ownershipDiff(before, after TableAccountInfo) (list []TableOwnershipLedger) {
+	if before.OwnerChainType != after.OwnerChainType || before.Owner != after.Owner {
+		list = append(list, TableOwnershipLedger{
+			Role:            OwnershipRoleOwner,
+			FromAlgorithmId: before.OwnerAlgorithmId,
+			FromChainType:   before.OwnerChainType,
+			FromAddress:     before.Owner,
+			ToAlgorithmId:   after.OwnerAlgorithmId,
+			ToChainType:     after.OwnerChainType,
+			ToAddress:       after.Owner,
+		})
+	}
+	if before.ManagerChainType != after.ManagerChainType || before.Manager != after.Manager {
+		list = append(list, TableOwnershipLedger{
+			Role:            OwnershipRoleManager,
+			FromAlgorithmId: before.ManagerAlgorithmId,
+			FromChainType:   before.ManagerChainType,
+			FromAddress:     before.Manager,
+			ToAlgorithmId:   after.ManagerAlgorithmId,
+			ToChainType:     after.ManagerChainType,
+			ToAddress:       after.Manager,
+		})
+	}
+	return
+}
+
+func createOwnershipLedgerList(tx *gorm.DB, list []TableOwnershipLedger) error {
+	if len(list) == 0 {
+		return nil
+	}
+	return tx.Clauses(clause.Insert{
+		Modifier: "IGNORE",
+	}).Create(&list).Error
+}
+
+func (d *DbDao) GetOwnershipLedgerList(accountId, role string, cursor *Cursor, limit, offset int) (list []TableOwnershipLedger, err error) {
+	db := d.db.Where("account_id=?", accountId)
+	if role != "" {
+		db = db.Where("role=?", role)
+	}
+	err = afterCursor(db, cursor, "block_number", "id", true).
+		Order("block_number DESC,id DESC").Limit(limit).Offset(offset).Find(&list).Error
+	return
+}
+
+func (d *DbDao) GetOwnershipLedgerCount(accountId, role string) (count int64, err error) {
+	db := d.db.Model(&TableOwnershipLedger{}).Where("account_id=?", accountId)
+	if role != "" {
+		db = db.Where("role=?", role)
+	}
+	err = db.Count(&count).Error
+	return
+}
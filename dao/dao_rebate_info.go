@@ -2,6 +2,7 @@ package dao
 
 import (
 	"github.com/dotbitHQ/das-lib/common"
+	"gorm.io/gorm"
 	"time"
 )
 
@@ -22,7 +23,7 @@ type TableRebateInfo struct {
 	InviterAccount   string           `json:"inviter_account" gorm:"column:inviter_account;index:k_inviter_account;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'inviter account'"`
 	InviterChainType common.ChainType `json:"inviter_chain_type" gorm:"column:inviter_chain_type;index:k_irct_ia;type:smallint(6) NOT NULL DEFAULT '0' COMMENT ''"`
 	InviterAddress   string           `json:"inviter_address" gorm:"column:inviter_address;index:k_irct_ia;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'address of inviter'"`
-	BlockTimestamp   uint64           `json:"block_timestamp" gorm:"column:block_timestamp;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
+	BlockTimestamp   uint64           `json:"block_timestamp" gorm:"column:block_timestamp;index:k_block_timestamp;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
 	CreatedAt        time.Time        `json:"created_at" gorm:"column:created_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT ''"`
 	UpdatedAt        time.Time        `json:"updated_at" gorm:"column:updated_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT ''"`
 }
@@ -36,3 +37,88 @@ const (
 func (t *TableRebateInfo) TableName() string {
 	return TableNameRebateInfo
 }
+
+// RebateFilter the inviter is either an address or an account, the account is only known for register rebates.
+// RewardType -1 for both inviter and channel rewards, the time range is [StartAt, EndAt) of block_timestamp
+type RebateFilter struct {
+	InviterChainType common.ChainType
+	InviterAddress   string
+	InviterId        string
+	RewardType       int
+	ServiceType      int
+	StartAt          uint64
+	EndAt            uint64
+}
+
+type RebateSum struct {
+	RewardType  int    `json:"reward_type" gorm:"column:reward_type"`
+	ServiceType int    `json:"service_type" gorm:"column:service_type"`
+	Count       int64  `json:"count" gorm:"column:count"`
+	Reward      uint64 `json:"reward" gorm:"column:reward"`
+}
+
+type RebateInviterSum struct {
+	InviterChainType common.ChainType `json:"inviter_chain_type" gorm:"column:inviter_chain_type"`
+	InviterAddress   string           `json:"inviter_address" gorm:"column:inviter_address"`
+	Count            int64            `json:"count" gorm:"column:count"`
+	Reward           uint64           `json:"reward" gorm:"column:reward"`
+}
+
+func (d *DbDao) rebateQuery(f RebateFilter) *gorm.DB {
+	db := d.db.Model(&TableRebateInfo{})
+	if f.InviterAddress != "" {
+		db = db.Where("inviter_chain_type=? AND inviter_address=?", f.InviterChainType, f.InviterAddress)
+	}
+	if f.InviterId != "" {
+		db = db.Where("inviter_id=?", f.InviterId)
+	}
+	if f.RewardType >= 0 {
+		db = db.Where("reward_type=?", f.RewardType)
+	}
+	if f.ServiceType > 0 {
+		db = db.Where("service_type=?", f.ServiceType)
+	}
+	if f.StartAt > 0 {
+		db = db.Where("block_timestamp>=?", f.StartAt)
+	}
+	if f.EndAt > 0 {
+		db = db.Where("block_timestamp<?", f.EndAt)
+	}
+	return db
+}
+
+func (d *DbDao) GetRebateList(f RebateFilter, cursor *Cursor, limit, offset int) (list []TableRebateInfo, err error) {
+	err = afterCursor(d.rebateQuery(f), cursor, "block_number", "id", true).
+		Order("block_number DESC,id DESC").
+		Limit(limit).Offset(offset).Find(&list).Error
+	return
+}
+
+func (d *DbDao) GetRebateCount(f RebateFilter) (count int64, err error) {
+	err = d.rebateQuery(f).Count(&count).Error
+	return
+}
+
+// GetRebateSum the rewards by reward type and service type
+func (d *DbDao) GetRebateSum(f RebateFilter) (list []RebateSum, err error) {
+	err = d.rebateQuery(f).
+		Select("reward_type,service_type,COUNT(*) AS count,SUM(reward) AS reward").
+		Group("reward_type,service_type").
+		Order("reward_type,service_type").
+		Find(&list).Error
+	return
+}
+
+// GetRebateInviterSum the rewards by inviter, ordered by reward for the leaderboard
+func (d *DbDao) GetRebateInviterSum(f RebateFilter, limit int) (list []RebateInviterSum, err error) {
+	db := d.rebateQuery(f).
+		Select("inviter_chain_type,inviter_address,COUNT(*) AS count,SUM(reward) AS reward").
+		Where("inviter_address!=''").
+		Group("inviter_chain_type,inviter_address").
+		Order("reward DESC,inviter_address")
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+	err = db.Find(&list).Error
+	return
+}
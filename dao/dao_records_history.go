@@ -0,0 +1,178 @@
+package dao
+
+import (
+	"github.com/dotbitHQ/das-lib/common"
+	"gorm.io/gorm"
+	"sort"
+	"time"
+)
+
+type TableRecordsHistory struct {
+	Id             uint64    `json:"id" gorm:"column:id;primaryKey;type:bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT ''"`
+	BlockNumber    uint64    `json:"block_number" gorm:"column:block_number;index:k_ai_bn,priority:2;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
+	BlockTimestamp uint64    `json:"block_timestamp" gorm:"column:block_timestamp;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
+	TxHash         string    `json:"tx_hash" gorm:"column:tx_hash;index:k_tx_hash;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	Action         string    `json:"action" gorm:"column:action;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	AccountId      string    `json:"account_id" gorm:"column:account_id;index:k_ai_bn,priority:1;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'hash of account'"`
+	Account        string    `json:"account" gorm:"column:account;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	Type           string    `json:"type" gorm:"column:type;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT ''"`
+	Key            string    `json:"key" gorm:"column:key;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT ''"`
+	Label          string    `json:"label" gorm:"column:label;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT ''"`
+	ValueBefore    string    `json:"value_before" gorm:"column:value_before;type:varchar(1024) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'empty when added'"`
+	ValueAfter     string    `json:"value_after" gorm:"column:value_after;type:varchar(1024) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'empty when removed'"`
+	CreatedAt      time.Time `json:"created_at" gorm:"column:created_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT ''"`
+	UpdatedAt      time.Time `json:"updated_at" gorm:"column:updated_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT ''"`
+}
+
+const (
+	TableNameRecordsHistory = "t_records_history"
+)
+
+func (t *TableRecordsHistory) TableName() string {
+	return TableNameRecordsHistory
+}
+
+// RecordIdentity records of an account are identified by type, key and label
+func RecordIdentity(recordType, key, label string) string {
+	return recordType + "\x00" + key + "\x00" + label
+}
+
+// ReplaceRecords replaces all records of the accounts and appends the per-key changes to t_records_history,
+// re-parsing the same transaction changes nothing so no history is written twice.
+// Every write of t_records_info goes through it
+func ReplaceRecords(tx *gorm.DB, accountIds []string, recordsInfos []TableRecordsInfo, transactionInfo TableTransactionInfo) error {
+	if len(accountIds) == 0 {
+		return nil
+	}
+	var oldRecords []TableRecordsInfo
+	if err := tx.Where("account_id IN ?", accountIds).Find(&oldRecords).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("account_id IN ?", accountIds).Delete(&TableRecordsInfo{}).Error; err != nil {
+		return err
+	}
+	if len(recordsInfos) > 0 {
+		if err := tx.Create(&recordsInfos).Error; err != nil {
+			return err
+		}
+	}
+
+	txHash, _ := common.String2OutPoint(transactionInfo.Outpoint)
+	historyList := DiffRecords(oldRecords, recordsInfos)
+	for i := range historyList {
+		historyList[i].BlockNumber = transactionInfo.BlockNumber
+		historyList[i].BlockTimestamp = transactionInfo.BlockTimestamp
+		historyList[i].TxHash = txHash
+		historyList[i].Action = transactionInfo.Action
+	}
+	if len(historyList) == 0 {
+		return nil
+	}
+	return tx.Create(&historyList).Error
+}
+
+// DiffRecords the changes from the old records to the new ones. Records with the same identity are diffed as
+// multisets of their values, a removed and an added value of the same identity are paired as a change
+func DiffRecords(oldRecords, newRecords []TableRecordsInfo) (list []TableRecordsHistory) {
+	type recordState struct {
+		record        TableRecordsInfo
+		before, after []string
+	}
+	states := make(map[string]*recordState)
+	var order []string
+	get := func(r TableRecordsInfo) *recordState {
+		id := r.AccountId + "\x00" + RecordIdentity(r.Type, r.Key, r.Label)
+		s, ok := states[id]
+		if !ok {
+			s = &recordState{record: r}
+			states[id] = s
+			order = append(order, id)
+		}
+		return s
+	}
+	for _, v := range oldRecords {
+		s := get(v)
+		s.before = append(s.before, v.Value)
+	}
+	for _, v := range newRecords {
+		s := get(v)
+		s.after = append(s.after, v.Value)
+	}
+	sort.Strings(order)
+	for _, id := range order {
+		s := states[id]
+		removed, added := subtractValues(s.before, s.after), subtractValues(s.after, s.before)
+		for i := 0; i < len(removed) || i < len(added); i++ {
+			h := TableRecordsHistory{
+				AccountId: s.record.AccountId,
+				Account:   s.record.Account,
+				Type:      s.record.Type,
+				Key:       s.record.Key,
+				Label:     s.record.Label,
+			}
+			if i < len(removed) {
+				h.ValueBefore = removed[i]
+			}
+			if i < len(added) {
+				h.ValueAfter = added[i]
+			}
+			list = append(list, h)
+		}
+	}
+	return
+}
+
+// subtractValues the values of a which are not matched one to one by the values of b, sorted
+func subtractValues(a, b []string) (res []string) {
+	count := make(map[string]int)
+	for _, v := range b {
+		count[v]++
+	}
+	for _, v := range a {
+		if count[v] > 0 {
+			count[v]--
+			continue
+		}
+		res = append(res, v)
+	}
+	sort.Strings(res)
+	return
+}
+
+func (d *DbDao) GetRecordsHistoryList(accountId string, cursor *Cursor, limit, offset int) (list []TableRecordsHistory, err error) {
+	err = afterCursor(d.db.Where("account_id=?", accountId), cursor, "block_number", "id", true).
+		Order("block_number DESC,id DESC").Limit(limit).Offset(offset).Find(&list).Error
+	return
+}
+
+func (d *DbDao) GetRecordsHistoryCount(accountId string) (count int64, err error) {
+	err = d.db.Model(&TableRecordsHistory{}).Where("account_id=?", accountId).Count(&count).Error
+	return
+}
+
+func (d *DbDao) GetAllRecordsHistory(accountId string) (list []TableRecordsHistory, err error) {
+	err = d.db.Where("account_id=?", accountId).Order("block_number,id").Find(&list).Error
+	return
+}
+
+// RecordsAtBlock rebuilds the records of an account as they were after blockNumber,
+// history is in ascending order, the changes after the block are undone from current
+func RecordsAtBlock(history []TableRecordsHistory, current []TableRecordsInfo, blockNumber uint64) []TableRecordsInfo {
+	res := make([]TableRecordsInfo, len(current))
+	copy(res, current)
+	for i := len(history) - 1; i >= 0 && history[i].BlockNumber > blockNumber; i-- {
+		v := history[i]
+		if v.ValueAfter != "" {
+			for j, r := range res {
+				if r.Type == v.Type && r.Key == v.Key && r.Label == v.Label && r.Value == v.ValueAfter {
+					res = append(res[:j], res[j+1:]...)
+					break
+				}
+			}
+		}
+		if v.ValueBefore != "" {
+			res = append(res, TableRecordsInfo{AccountId: v.AccountId, Account: v.Account, Type: v.Type, Key: v.Key, Label: v.Label, Value: v.ValueBefore})
+		}
+	}
+	return res
+}
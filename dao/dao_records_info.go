@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"fmt"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 	"time"
@@ -36,16 +37,10 @@ func (d *DbDao) CreateRecordsInfos(accountInfo TableAccountInfo, recordsInfos []
 			return err
 		}
 
-		if err := tx.Where("account_id = ?", accountInfo.AccountId).Delete(&TableRecordsInfo{}).Error; err != nil {
+		if err := ReplaceRecords(tx, []string{accountInfo.AccountId}, recordsInfos, transactionInfo); err != nil {
 			return err
 		}
 
-		if len(recordsInfos) > 0 {
-			if err := tx.Create(&recordsInfos).Error; err != nil {
-				return err
-			}
-		}
-
 		if err := tx.Clauses(clause.OnConflict{
 			DoUpdates: clause.AssignmentColumns([]string{
 				"account_id", "account", "service_type",
@@ -58,3 +53,59 @@ func (d *DbDao) CreateRecordsInfos(accountInfo TableAccountInfo, recordsInfos []
 		return nil
 	})
 }
+
+type RecordsSearchFilter struct {
+	Value        string
+	Prefix       bool
+	Key          string
+	Type         string
+	ExpiredAfter uint64
+}
+
+type RecordsSearchResult struct {
+	TableRecordsInfo
+	ExpiredAt uint64 `json:"expired_at" gorm:"column:expired_at"`
+}
+
+// recordsSearchQuery drops the records of recycled accounts and of accounts expired before ExpiredAfter
+func (d *DbDao) recordsSearchQuery(f RecordsSearchFilter) *gorm.DB {
+	db := d.db.Table(TableNameRecordsInfo+" r").
+		Joins(fmt.Sprintf("JOIN %s a ON a.account_id=r.account_id", TableNameAccountInfo)).
+		Where("a.status!=? AND a.expired_at>?", AccountStatusRecycle, f.ExpiredAfter)
+	if f.Prefix {
+		db = db.Where("r.value LIKE ?", likeEscaper.Replace(f.Value)+"%")
+	} else {
+		db = db.Where("r.value=?", f.Value)
+	}
+	if f.Key != "" {
+		db = db.Where("r.`key`=?", f.Key)
+	}
+	if f.Type != "" {
+		db = db.Where("r.`type`=?", f.Type)
+	}
+	return db
+}
+
+func (d *DbDao) SearchRecordsByValue(f RecordsSearchFilter, cursor *Cursor, limit, offset int) (list []RecordsSearchResult, err error) {
+	err = afterCursor(d.recordsSearchQuery(f), cursor, "", "r.id", false).Select("r.*,a.expired_at").
+		Order("r.id").Limit(limit).Offset(offset).Find(&list).Error
+	return
+}
+
+func (d *DbDao) CountRecordsByValue(f RecordsSearchFilter) (count int64, err error) {
+	err = d.recordsSearchQuery(f).Count(&count).Error
+	return
+}
+
+func (d *DbDao) GetRecordsByAccountId(accountId string) (list []TableRecordsInfo, err error) {
+	err = d.db.Where("account_id=?", accountId).Find(&list).Error
+	return
+}
+
+func (d *DbDao) GetRecordsByAccountIds(accountIds []string) (list []TableRecordsInfo, err error) {
+	if len(accountIds) == 0 {
+		return
+	}
+	err = d.db.Where("account_id IN ?", accountIds).Order("account_id,id").Find(&list).Error
+	return
+}
@@ -107,3 +107,14 @@ func (d *DbDao) RetractReverseRecord(listOutpoint []string, txInfo TableTransact
 		return nil
 	})
 }
+
+// GetReverseListByAccountIds the addresses which set each account as their reverse record, the latest first
+func (d *DbDao) GetReverseListByAccountIds(accountIds []string, limit, offset int) (list []TableReverseInfo, err error) {
+	err = d.findPerKey(&TableReverseInfo{}, &list, "account_id", "id DESC", accountIds, limit, offset)
+	return
+}
+
+func (d *DbDao) GetReverseListByAddress(chainType common.ChainType, address string) (list []TableReverseInfo, err error) {
+	err = d.db.Where("chain_type=? AND address=?", chainType, address).Order("id DESC").Find(&list).Error
+	return
+}
@@ -18,3 +18,14 @@ type RuleConfig struct {
 func (m *RuleConfig) TableName() string {
 	return "t_rule_config"
 }
+
+func (d *DbDao) GetRuleConfig(accountId string) (info RuleConfig, err error) {
+	err = d.db.Where("account_id=?", accountId).Limit(1).Find(&info).Error
+	return
+}
+
+// GetRuleConfigList the latest config tx of each parent account after the id, in id order
+func (d *DbDao) GetRuleConfigList(afterId int64, limit int) (list []RuleConfig, err error) {
+	err = d.db.Where("id>?", afterId).Order("id").Limit(limit).Find(&list).Error
+	return
+}
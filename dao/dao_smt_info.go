@@ -28,13 +28,11 @@ func (t *TableSmtInfo) TableName() string {
 
 func (d *DbDao) CreateSubAccount(subAccountIds []string, accountInfos []TableAccountInfo, smtInfos []TableSmtInfo, transactionInfo TableTransactionInfo, parentAccountInfo TableAccountInfo) error {
 	return d.db.Transaction(func(tx *gorm.DB) error {
-		if len(subAccountIds) > 0 {
-			if err := tx.Where(" account_id IN(?) ", subAccountIds).
-				Delete(&TableRecordsInfo{}).Error; err != nil {
-				return err
-			}
+		if err := ReplaceRecords(tx, subAccountIds, nil, transactionInfo); err != nil {
+			return err
 		}
 		if len(accountInfos) > 0 {
+			FillAccountSearchFields(accountInfos)
 			if err := tx.Clauses(clause.OnConflict{
 				DoUpdates: clause.AssignmentColumns([]string{
 					"block_number", "outpoint",
@@ -81,13 +79,11 @@ func (d *DbDao) CreateSubAccount(subAccountIds []string, accountInfos []TableAcc
 
 func (d *DbDao) UpdateSubAccountForCreate(subAccountIds []string, accountInfos []TableAccountInfo, smtInfos []TableSmtInfo, transactionInfo TableTransactionInfo) error {
 	return d.db.Transaction(func(tx *gorm.DB) error {
-		if len(subAccountIds) > 0 {
-			if err := tx.Where("account_id IN(?)", subAccountIds).
-				Delete(&TableRecordsInfo{}).Error; err != nil {
-				return err
-			}
+		if err := ReplaceRecords(tx, subAccountIds, nil, transactionInfo); err != nil {
+			return err
 		}
 		if len(accountInfos) > 0 {
+			FillAccountSearchFields(accountInfos)
 			if err := tx.Clauses(clause.Insert{
 				Modifier: "IGNORE",
 			}).Create(&accountInfos).Error; err != nil {
@@ -115,6 +111,10 @@ func (d *DbDao) UpdateSubAccountForCreate(subAccountIds []string, accountInfos [
 
 func (d *DbDao) EditOwnerSubAccount(accountInfo TableAccountInfo, smtInfo TableSmtInfo, transactionInfo TableTransactionInfo) error {
 	return d.db.Transaction(func(tx *gorm.DB) error {
+		ownership, err := GetOwnership(tx, []string{accountInfo.AccountId})
+		if err != nil {
+			return err
+		}
 		if err := tx.Select("block_number", "outpoint",
 			"owner_chain_type", "owner", "owner_algorithm_id",
 			"manager_chain_type", "manager", "manager_algorithm_id", "nonce").
@@ -135,15 +135,22 @@ func (d *DbDao) EditOwnerSubAccount(accountInfo TableAccountInfo, smtInfo TableS
 			return err
 		}
 
-		if err := tx.Where("account_id = ?", accountInfo.AccountId).Delete(&TableRecordsInfo{}).Error; err != nil {
+		if err := ReplaceRecords(tx, []string{accountInfo.AccountId}, nil, transactionInfo); err != nil {
 			return err
 		}
 
+		if err := CreateOwnershipLedger(tx, ownership, transactionInfo); err != nil {
+			return err
+		}
 		return nil
 	})
 }
 func (d *DbDao) EditManagerSubAccount(accountInfo TableAccountInfo, smtInfo TableSmtInfo, transactionInfo TableTransactionInfo) error {
 	return d.db.Transaction(func(tx *gorm.DB) error {
+		ownership, err := GetOwnership(tx, []string{accountInfo.AccountId})
+		if err != nil {
+			return err
+		}
 		if err := tx.Select("block_number", "outpoint",
 			"manager_chain_type", "manager", "manager_algorithm_id", "nonce").
 			Where("account_id = ?", accountInfo.AccountId).
@@ -163,6 +170,9 @@ func (d *DbDao) EditManagerSubAccount(accountInfo TableAccountInfo, smtInfo Tabl
 			return err
 		}
 
+		if err := CreateOwnershipLedger(tx, ownership, transactionInfo); err != nil {
+			return err
+		}
 		return nil
 	})
 }
@@ -186,16 +196,55 @@ func (d *DbDao) EditRecordsSubAccount(accountInfo TableAccountInfo, smtInfo Tabl
 			return err
 		}
 
-		if err := tx.Where("account_id = ?", accountInfo.AccountId).Delete(&TableRecordsInfo{}).Error; err != nil {
+		if err := ReplaceRecords(tx, []string{accountInfo.AccountId}, recordsInfos, transactionInfo); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// SubAccountCrossChain the lock moves the sub-account to the black hole address with status on lock,
+// the records are cleared when it is unlocked to another owner, the bridge is kept in t_cross_chain_info
+func (d *DbDao) SubAccountCrossChain(accountInfo TableAccountInfo, smtInfo TableSmtInfo, transactionInfo TableTransactionInfo, isTrans bool, crossChainInfo TableCrossChainInfo) error {
+	return d.db.Transaction(func(tx *gorm.DB) error {
+		ownership, err := GetOwnership(tx, []string{accountInfo.AccountId})
+		if err != nil {
+			return err
+		}
+		if err := tx.Select("block_number", "outpoint",
+			"owner_chain_type", "owner", "owner_algorithm_id", "owner_sub_aid",
+			"manager_chain_type", "manager", "manager_algorithm_id", "manager_sub_aid", "status", "nonce").
+			Where("account_id = ?", accountInfo.AccountId).
+			Updates(accountInfo).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Select("block_number", "outpoint", "leaf_data_hash").
+			Where("account_id = ?", accountInfo.AccountId).
+			Updates(&smtInfo).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.Insert{
+			Modifier: "IGNORE",
+		}).Create(&transactionInfo).Error; err != nil {
 			return err
 		}
 
-		if len(recordsInfos) > 0 {
-			if err := tx.Create(&recordsInfos).Error; err != nil {
+		if isTrans {
+			if err := ReplaceRecords(tx, []string{accountInfo.AccountId}, nil, transactionInfo); err != nil {
 				return err
 			}
 		}
 
+		if err := CreateOwnershipLedger(tx, ownership, transactionInfo); err != nil {
+			return err
+		}
+
+		if err := saveCrossChainInfo(tx, ownership, crossChainInfo); err != nil {
+			return err
+		}
 		return nil
 	})
 }
@@ -237,6 +286,7 @@ func (d *DbDao) RenewSubAccount(accountInfos []TableAccountInfo, smtInfos []Tabl
 	})
 }
 
+// RecycleSubAccount txs has the transaction of each recycled sub-account
 func (d *DbDao) RecycleSubAccount(subAccIds []string, smtInfos []TableSmtInfo, txs []TableTransactionInfo) error {
 	if len(subAccIds) == 0 && len(smtInfos) == 0 {
 		return nil
@@ -246,9 +296,10 @@ func (d *DbDao) RecycleSubAccount(subAccIds []string, smtInfos []TableSmtInfo, t
 			Delete(&TableAccountInfo{}).Error; err != nil {
 			return err
 		}
-		if err := tx.Where("account_id IN(?)", subAccIds).
-			Delete(&TableRecordsInfo{}).Error; err != nil {
-			return err
+		for _, v := range txs {
+			if err := ReplaceRecords(tx, []string{v.AccountId}, nil, v); err != nil {
+				return err
+			}
 		}
 		for i, v := range smtInfos {
 			if err := tx.Select("block_number", "outpoint", "leaf_data_hash").
@@ -266,6 +317,7 @@ func (d *DbDao) RecycleSubAccount(subAccIds []string, smtInfos []TableSmtInfo, t
 	})
 }
 
+// ApprovalSubAccount transactionInfos has the transaction of each account, in the same order
 func (d *DbDao) ApprovalSubAccount(accountInfos []map[string]interface{}, smtInfos []TableSmtInfo, transactionInfos []TableTransactionInfo) error {
 	return d.db.Transaction(func(tx *gorm.DB) error {
 		for idx := range accountInfos {
@@ -278,7 +330,7 @@ func (d *DbDao) ApprovalSubAccount(accountInfos []map[string]interface{}, smtInf
 				return err
 			}
 			if action == common.SubActionFullfillApproval {
-				if err := tx.Where("account_id = ?", accId).Delete(&TableRecordsInfo{}).Error; err != nil {
+				if err := ReplaceRecords(tx, []string{accId.(string)}, nil, transactionInfos[idx]); err != nil {
 					return err
 				}
 			}
@@ -16,6 +16,7 @@ type TableSnapshotPermissionsInfo struct {
 	AccountId          string                   `json:"account_id" gorm:"column:account_id; uniqueIndex:uk_account_id; type:varchar(255) NOT NULL DEFAULT '' COMMENT '';"`
 	Hash               string                   `json:"hash" gorm:"column:hash; uniqueIndex:uk_account_id; index:k_hash; type:varchar(255) NOT NULL DEFAULT '' COMMENT '';"`
 	Account            string                   `json:"account" gorm:"column:account; type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '';"`
+	AccountLength      uint8                    `json:"account_length" gorm:"column:account_length; type:smallint(6) NOT NULL DEFAULT '0' COMMENT 'characters of the first label';"`
 	BlockTimestamp     uint64                   `json:"block_timestamp" gorm:"column:block_timestamp; type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT '';"`
 	Owner              string                   `json:"owner" gorm:"column:owner; index:k_owner; type:varchar(255) NOT NULL DEFAULT '' COMMENT '';"`
 	Manager            string                   `json:"manager" gorm:"column:manager; index:k_manager; type:varchar(255) NOT NULL DEFAULT '' COMMENT '';"`
@@ -56,6 +57,7 @@ func (d *DbDao) CreateSnapshotPermissions(list []TableSnapshotPermissionsInfo) e
 	for i, v := range list {
 		accountIds = append(accountIds, v.AccountId)
 		mapNewPermissions[v.AccountId] = &list[i]
+		list[i].AccountLength, _ = AccountSearchFields(v.Account)
 		if v.Status == AccountStatusRecycle {
 			list[i].OwnerBlockNumber = v.BlockNumber
 			list[i].ManagerBlockNumber = v.BlockNumber
@@ -159,19 +161,22 @@ func (d *DbDao) GetRecycleInfo(accountId string, startBlockNumber, endBlockNumbe
 	return
 }
 
-func (d *DbDao) GetSnapshotAddressAccounts(addressHex string, roleType RoleType, blockNumber uint64, limit, offset int) (list []TableSnapshotPermissionsInfo, err error) {
+// GetSnapshotAddressAccounts is ordered by account, the cursor key is the account of the last row.
+// An address may be kept in more than one form, the did cells are kept by ckb address
+func (d *DbDao) GetSnapshotAddressAccounts(addressHexes []string, roleType RoleType, blockNumber uint64, cursor *Cursor, limit, offset int) (list []TableSnapshotPermissionsInfo, err error) {
+	db := afterStrCursor(d.db, cursor, "account", "", false)
 	switch roleType {
 	case RoleTypeOwner:
-		err = d.db.Select("account_id,account").
-			Where("owner=? AND block_number<=? AND (owner_block_number=0 OR owner_block_number>?)",
-				addressHex, blockNumber, blockNumber).
+		err = db.Select("account_id,account").
+			Where("owner IN(?) AND block_number<=? AND (owner_block_number=0 OR owner_block_number>?)",
+				addressHexes, blockNumber, blockNumber).
 			Group("account_id,account").
 			Order("account").
 			Limit(limit).Offset(offset).Find(&list).Error
 	case RoleTypeManager:
-		err = d.db.Select("account_id,account").
-			Where("manager=? AND block_number<=? AND (manager_block_number=0 OR manager_block_number>?)",
-				addressHex, blockNumber, blockNumber).
+		err = db.Select("account_id,account").
+			Where("manager IN(?) AND block_number<=? AND (manager_block_number=0 OR manager_block_number>?)",
+				addressHexes, blockNumber, blockNumber).
 			Group("account_id,account").
 			Order("account").
 			Limit(limit).Offset(offset).Find(&list).Error
@@ -180,30 +185,69 @@ func (d *DbDao) GetSnapshotAddressAccounts(addressHex string, roleType RoleType,
 	return
 }
 
-func (d *DbDao) GetSnapshotAddressAccountsTotal(addressHex string, roleType RoleType, blockNumber uint64) (count int64, err error) {
+func (d *DbDao) GetSnapshotAddressAccountsTotal(addressHexes []string, roleType RoleType, blockNumber uint64) (count int64, err error) {
 	switch roleType {
 	case RoleTypeOwner:
 		err = d.db.Model(&TableSnapshotPermissionsInfo{}).
-			Where("owner=? AND block_number<=? AND (owner_block_number=0 OR owner_block_number>?)",
-				addressHex, blockNumber, blockNumber).
+			Where("owner IN(?) AND block_number<=? AND (owner_block_number=0 OR owner_block_number>?)",
+				addressHexes, blockNumber, blockNumber).
 			Group("account_id,account").Count(&count).Error
 	case RoleTypeManager:
 		err = d.db.Model(&TableSnapshotPermissionsInfo{}).
-			Where("manager=? AND block_number<=? AND (manager_block_number=0 OR manager_block_number>?)",
-				addressHex, blockNumber, blockNumber).
+			Where("manager IN(?) AND block_number<=? AND (manager_block_number=0 OR manager_block_number>?)",
+				addressHexes, blockNumber, blockNumber).
 			Group("account_id,account").Count(&count).Error
 	}
 
 	return
 }
 
-func (d *DbDao) GetSnapshotDidList(addressHex string, blockNumber, accLen uint64) (list []TableSnapshotPermissionsInfo, err error) {
-	sql := fmt.Sprintf(" SELECT account_id,account FROM %s ", TableNameSnapshotPermissionsInfo)
-	sql += " WHERE parent_account_id='' AND owner=? AND block_number<=? AND (owner_block_number=0 OR owner_block_number>?) "
+func (d *DbDao) snapshotDidListQuery(addressHexes []string, blockNumber uint64, accLen uint8) *gorm.DB {
+	db := d.db.Model(&TableSnapshotPermissionsInfo{}).
+		Where("parent_account_id='' AND owner IN(?) AND block_number<=? AND (owner_block_number=0 OR owner_block_number>?)",
+			addressHexes, blockNumber, blockNumber)
 	if accLen > 0 {
-		sql += fmt.Sprintf(" AND LENGTH(account)=%d ", accLen+4)
+		db = db.Where("account_length=?", accLen)
 	}
-	sql += " GROUP BY account_id,account "
-	err = d.db.Raw(sql, addressHex, blockNumber, blockNumber).Find(&list).Error
+	return db
+}
+
+// GetSnapshotDidList is ordered by account, the cursor key is the account of the last row, limit 0 loads the whole list
+func (d *DbDao) GetSnapshotDidList(addressHexes []string, blockNumber uint64, accLen uint8, cursor *Cursor, limit, offset int) (list []TableSnapshotPermissionsInfo, err error) {
+	db := afterStrCursor(d.snapshotDidListQuery(addressHexes, blockNumber, accLen), cursor, "account", "", false).
+		Select("account_id,account").Group("account_id,account").Order("account")
+	if limit > 0 {
+		db = db.Limit(limit).Offset(offset)
+	}
+	err = db.Find(&list).Error
+	return
+}
+
+func (d *DbDao) GetSnapshotDidListTotal(addressHexes []string, blockNumber uint64, accLen uint8) (count int64, err error) {
+	err = d.snapshotDidListQuery(addressHexes, blockNumber, accLen).
+		Distinct("account_id").Count(&count).Error
 	return
 }
+
+func (d *DbDao) GetNeedFixSnapshotLengthList() (list []TableSnapshotPermissionsInfo, err error) {
+	err = d.db.Select("id", "account").
+		Where("account_length=0 AND account!=''").Limit(500).Find(&list).Error
+	return
+}
+
+func (d *DbDao) UpdateSnapshotAccountLength(list []TableSnapshotPermissionsInfo) error {
+	if len(list) == 0 {
+		return nil
+	}
+	return d.db.Transaction(func(tx *gorm.DB) error {
+		for _, v := range list {
+			accountLength, _ := AccountSearchFields(v.Account)
+			if err := tx.Model(TableSnapshotPermissionsInfo{}).
+				Where("id=?", v.Id).
+				Update("account_length", accountLength).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
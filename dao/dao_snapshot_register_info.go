@@ -39,9 +39,10 @@ func (d *DbDao) CreateSnapshotRegister(list []TableSnapshotRegisterInfo) error {
 	}).Create(&list).Error
 }
 
-func (d *DbDao) GetRegisterHistory(limit, offset int) (list []TableSnapshotRegisterInfo, err error) {
-	err = d.db.Select("account,owner,registered_at").Where("parent_account_id=''").
-		Order("block_number").
-		Limit(limit).Offset(offset).Find(&list).Error
+func (d *DbDao) GetRegisterHistory(cursor *Cursor, limit int) (list []TableSnapshotRegisterInfo, err error) {
+	db := d.db.Select("id,block_number,account,owner,registered_at").Where("parent_account_id=''")
+	err = afterCursor(db, cursor, "block_number", "id", false).
+		Order("block_number,id").
+		Limit(limit).Find(&list).Error
 	return
 }
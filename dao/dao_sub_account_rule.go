@@ -0,0 +1,74 @@
+package dao
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type SubAccountRuleType string
+
+const (
+	SubAccountRuleTypePrice     SubAccountRuleType = "price"
+	SubAccountRuleTypePreserved SubAccountRuleType = "preserved"
+)
+
+// TableSubAccountRule a price or preserved rule of a parent account, decoded from the witnesses of config_sub_account
+type TableSubAccountRule struct {
+	Id              uint64             `json:"id" gorm:"column:id;primaryKey;type:bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT ''"`
+	ParentAccountId string             `json:"parent_account_id" gorm:"column:parent_account_id;uniqueIndex:uk_pai_rt_idx,priority:1;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	ParentAccount   string             `json:"parent_account" gorm:"column:parent_account;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	RuleType        SubAccountRuleType `json:"rule_type" gorm:"column:rule_type;uniqueIndex:uk_pai_rt_idx,priority:2;type:varchar(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'price, preserved'"`
+	Idx             uint32             `json:"idx" gorm:"column:idx;uniqueIndex:uk_pai_rt_idx,priority:3;type:int(11) unsigned NOT NULL DEFAULT '0' COMMENT 'index of the rule, rules are matched in this order'"`
+	Name            string             `json:"name" gorm:"column:name;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	Note            string             `json:"note" gorm:"column:note;type:varchar(1024) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	Price           uint64             `json:"price" gorm:"column:price;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT 'usd per year, 10^6'"`
+	Status          uint8              `json:"status" gorm:"column:status;type:smallint(6) NOT NULL DEFAULT '0' COMMENT '0: off 1: on'"`
+	LengthMin       uint32             `json:"length_min" gorm:"column:length_min;type:int(11) unsigned NOT NULL DEFAULT '0' COMMENT 'account_length lower bound of the condition, 0: none'"`
+	LengthMax       uint32             `json:"length_max" gorm:"column:length_max;type:int(11) unsigned NOT NULL DEFAULT '0' COMMENT 'account_length upper bound of the condition, 0: none'"`
+	Condition       string             `json:"condition" gorm:"column:condition;type:text CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT 'readable condition'"`
+	Ast             string             `json:"ast" gorm:"column:ast;type:mediumtext CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT 'json of the ast expression'"`
+	TxHash          string             `json:"tx_hash" gorm:"column:tx_hash;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	BlockNumber     uint64             `json:"block_number" gorm:"column:block_number;index:k_block_number;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
+	BlockTimestamp  uint64             `json:"block_timestamp" gorm:"column:block_timestamp;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
+	CreatedAt       time.Time          `json:"created_at" gorm:"column:created_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT ''"`
+	UpdatedAt       time.Time          `json:"updated_at" gorm:"column:updated_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT ''"`
+}
+
+const (
+	TableNameSubAccountRule = "t_sub_account_rule"
+)
+
+func (t *TableSubAccountRule) TableName() string {
+	return TableNameSubAccountRule
+}
+
+// GetSubAccountRuleList the rules of the parent account in matching order, all types when ruleType is empty
+func (d *DbDao) GetSubAccountRuleList(parentAccountId string, ruleType SubAccountRuleType) (list []TableSubAccountRule, err error) {
+	db := d.db.Where("parent_account_id=?", parentAccountId)
+	if ruleType != "" {
+		db = db.Where("rule_type=?", ruleType)
+	}
+	err = db.Order("rule_type,idx").Find(&list).Error
+	return
+}
+
+// ReplaceSubAccountRules replaces the rules of each type in ruleMap, the types left out are kept
+func ReplaceSubAccountRules(tx *gorm.DB, parentAccountId string, ruleMap map[SubAccountRuleType][]TableSubAccountRule) error {
+	for ruleType, list := range ruleMap {
+		if err := tx.Where("parent_account_id=? AND rule_type=?", parentAccountId, ruleType).Delete(&TableSubAccountRule{}).Error; err != nil {
+			return err
+		}
+		if len(list) > 0 {
+			if err := tx.Create(&list).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *DbDao) ReplaceSubAccountRuleList(parentAccountId string, ruleMap map[SubAccountRuleType][]TableSubAccountRule) error {
+	return d.db.Transaction(func(tx *gorm.DB) error {
+		return ReplaceSubAccountRules(tx, parentAccountId, ruleMap)
+	})
+}
@@ -0,0 +1,77 @@
+package dao
+
+import (
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type AutoMintReconcileStatus int
+
+const (
+	AutoMintReconcileStatusOk        AutoMintReconcileStatus = 0
+	AutoMintReconcileStatusMismatch  AutoMintReconcileStatus = 1 // the recorded expenditure differs from the capacity withdrawn on chain
+	AutoMintReconcileStatusOverdrawn AutoMintReconcileStatus = 2 // more withdrawn on chain than the income so far
+	AutoMintReconcileStatusNotFound  AutoMintReconcileStatus = 3 // no such output on chain
+)
+
+// TableSubAccountAutoMintReconcile one check of a collected profit against the chain and the running balance
+type TableSubAccountAutoMintReconcile struct {
+	Id                uint64                  `json:"id" gorm:"column:id;primaryKey;type:bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT ''"`
+	StatementId       uint64                  `json:"statement_id" gorm:"column:statement_id;uniqueIndex:uk_statement_id;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT 'id of t_sub_account_auto_mint_statement'"`
+	BlockNumber       uint64                  `json:"block_number" gorm:"column:block_number;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT 'block of the withdrawal tx on chain'"`
+	TxHash            string                  `json:"tx_hash" gorm:"column:tx_hash;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	WitnessIndex      int                     `json:"witness_index" gorm:"column:witness_index;type:int(11) NOT NULL DEFAULT '0' COMMENT 'output index of the withdrawal'"`
+	ParentAccountId   string                  `json:"parent_account_id" gorm:"column:parent_account_id;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	ServiceProviderId string                  `json:"service_provider_id" gorm:"column:service_provider_id;index:k_spi_s,priority:1;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	Recorded          decimal.Decimal         `json:"recorded" gorm:"column:recorded;type:decimal(60,2) NOT NULL DEFAULT '0' COMMENT 'expenditure in the statement'"`
+	Withdrawn         decimal.Decimal         `json:"withdrawn" gorm:"column:withdrawn;type:decimal(60,2) NOT NULL DEFAULT '0' COMMENT 'capacity of the output on chain'"`
+	Balance           decimal.Decimal         `json:"balance" gorm:"column:balance;type:decimal(60,2) NOT NULL DEFAULT '0' COMMENT 'income so far minus the withdrawn amounts, after the withdrawal'"`
+	Status            AutoMintReconcileStatus `json:"status" gorm:"column:status;index:k_spi_s,priority:2;index:k_status;type:smallint(6) NOT NULL DEFAULT '0' COMMENT '0: ok 1: mismatch 2: overdrawn 3: not found'"`
+	CreatedAt         time.Time               `json:"created_at" gorm:"column:created_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT ''"`
+	UpdatedAt         time.Time               `json:"updated_at" gorm:"column:updated_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT ''"`
+}
+
+const (
+	TableNameSubAccountAutoMintReconcile = "t_sub_account_auto_mint_reconcile"
+)
+
+func (t *TableSubAccountAutoMintReconcile) TableName() string {
+	return TableNameSubAccountAutoMintReconcile
+}
+
+func (d *DbDao) CreateAutoMintReconcileList(list []TableSubAccountAutoMintReconcile) error {
+	if len(list) == 0 {
+		return nil
+	}
+	return d.db.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"block_number", "recorded", "withdrawn", "balance", "status"}),
+	}).Create(&list).Error
+}
+
+// GetLastAutoMintReconcile the reconciliation goes on from its statement id
+func (d *DbDao) GetLastAutoMintReconcile() (info TableSubAccountAutoMintReconcile, err error) {
+	err = d.db.Order("statement_id DESC").Limit(1).Find(&info).Error
+	return
+}
+
+// GetAutoMintWithdrawnBefore the capacity withdrawn on chain by the reconciled withdrawals before the statement
+func (d *DbDao) GetAutoMintWithdrawnBefore(serviceProviderId, parentAccountId string, statementId uint64) (withdrawn decimal.Decimal, err error) {
+	var res struct {
+		Withdrawn decimal.Decimal `gorm:"column:withdrawn"`
+	}
+	err = d.db.Model(&TableSubAccountAutoMintReconcile{}).
+		Select("IFNULL(SUM(withdrawn),0) AS withdrawn").
+		Where("service_provider_id=? AND parent_account_id=? AND statement_id<?", serviceProviderId, parentAccountId, statementId).
+		Take(&res).Error
+	return res.Withdrawn, err
+}
+
+func (d *DbDao) GetAutoMintDiscrepancyList(serviceProviderId, parentAccountId string) (list []TableSubAccountAutoMintReconcile, err error) {
+	db := d.db.Where("service_provider_id=? AND status!=?", serviceProviderId, AutoMintReconcileStatusOk)
+	if parentAccountId != "" {
+		db = db.Where("parent_account_id=?", parentAccountId)
+	}
+	err = db.Order("block_number DESC,id DESC").Find(&list).Error
+	return
+}
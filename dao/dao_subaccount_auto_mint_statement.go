@@ -3,6 +3,7 @@ package dao
 import (
 	"github.com/dotbitHQ/das-lib/common"
 	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 	"time"
 )
 
@@ -18,7 +19,7 @@ type TableSubAccountAutoMintStatement struct {
 	BlockNumber       uint64                   `json:"block_number" gorm:"column:block_number; index:k_block_number; type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT '';"`
 	TxHash            string                   `json:"tx_hash" gorm:"column:tx_hash; uniqueIndex:uk_tx_wi; index:idx_hash; type:varchar(255) NOT NULL DEFAULT '' COMMENT '';"`
 	WitnessIndex      int                      `json:"witness_index" gorm:"column:witness_index; uniqueIndex:uk_tx_wi; type:int(11) NOT NULL DEFAULT '0' COMMENT '';"`
-	ParentAccountId   string                   `json:"parent_account_id" gorm:"column:parent_account_id; type:varchar(255) NOT NULL DEFAULT '' COMMENT '';"`
+	ParentAccountId   string                   `json:"parent_account_id" gorm:"column:parent_account_id; index:k_parent_account_id; type:varchar(255) NOT NULL DEFAULT '' COMMENT '';"`
 	ServiceProviderId string                   `json:"service_provider_id" gorm:"column:service_provider_id; index:idx_service_provider_id; type:varchar(255) NOT NULL DEFAULT '' COMMENT '';"`
 	Price             decimal.Decimal          `json:"price" gorm:"column:price; type:decimal(60,2) NOT NULL DEFAULT '0' COMMENT '';"`
 	Quote             decimal.Decimal          `json:"quote" gorm:"column:quote; type:decimal(50,10) NOT NULL DEFAULT '0' COMMENT '';"`
@@ -33,3 +34,111 @@ type TableSubAccountAutoMintStatement struct {
 func (t *TableSubAccountAutoMintStatement) TableName() string {
 	return "t_sub_account_auto_mint_statement"
 }
+
+// AutoMintFilter the parent account is optional, the time range is [StartAt, EndAt) of block_timestamp.
+// Price is the amount in shannon, income for create and renew, expenditure for the collected profit
+type AutoMintFilter struct {
+	ServiceProviderId string
+	ParentAccountId   string
+	StartAt           uint64
+	EndAt             uint64
+}
+
+type AutoMintDailySum struct {
+	Day    int64                    `json:"day" gorm:"column:day"` // days since 1970-01-01 UTC
+	TxType SubAccountAutoMintTxType `json:"tx_type" gorm:"column:tx_type"`
+	Amount decimal.Decimal          `json:"amount" gorm:"column:amount"`
+}
+
+type AutoMintBreakdown struct {
+	ParentAccountId string                   `json:"parent_account_id" gorm:"column:parent_account_id"`
+	SubAction       common.SubAction         `json:"sub_action" gorm:"column:sub_action"`
+	TxType          SubAccountAutoMintTxType `json:"tx_type" gorm:"column:tx_type"`
+	Count           int64                    `json:"count" gorm:"column:count"`
+	Amount          decimal.Decimal          `json:"amount" gorm:"column:amount"`
+}
+
+type AutoMintBalance struct {
+	Income      decimal.Decimal `json:"income" gorm:"column:income"`
+	Expenditure decimal.Decimal `json:"expenditure" gorm:"column:expenditure"`
+}
+
+func (b AutoMintBalance) Balance() decimal.Decimal {
+	return b.Income.Sub(b.Expenditure)
+}
+
+const autoMintBalanceSelect = "IFNULL(SUM(CASE WHEN tx_type=1 THEN price ELSE 0 END),0) AS income," +
+	"IFNULL(SUM(CASE WHEN tx_type=2 THEN price ELSE 0 END),0) AS expenditure"
+
+func (d *DbDao) autoMintQuery(f AutoMintFilter) *gorm.DB {
+	db := d.db.Model(&TableSubAccountAutoMintStatement{}).Where("service_provider_id=?", f.ServiceProviderId)
+	if f.ParentAccountId != "" {
+		db = db.Where("parent_account_id=?", f.ParentAccountId)
+	}
+	if f.StartAt > 0 {
+		db = db.Where("block_timestamp>=?", f.StartAt)
+	}
+	if f.EndAt > 0 {
+		db = db.Where("block_timestamp<?", f.EndAt)
+	}
+	return db
+}
+
+func (d *DbDao) GetAutoMintDailySum(f AutoMintFilter) (list []AutoMintDailySum, err error) {
+	err = d.autoMintQuery(f).
+		Select("block_timestamp DIV 86400000 AS day,tx_type,SUM(price) AS amount").
+		Group("day,tx_type").Order("day").
+		Find(&list).Error
+	return
+}
+
+func (d *DbDao) GetAutoMintBreakdown(f AutoMintFilter) (list []AutoMintBreakdown, err error) {
+	err = d.autoMintQuery(f).
+		Select("parent_account_id,sub_action,tx_type,COUNT(*) AS count,SUM(price) AS amount").
+		Group("parent_account_id,sub_action,tx_type").Order("parent_account_id,tx_type,sub_action").
+		Find(&list).Error
+	return
+}
+
+// GetAutoMintBalance the income and expenditure before EndAt, StartAt is ignored since a balance starts from the beginning
+func (d *DbDao) GetAutoMintBalance(f AutoMintFilter) (balance AutoMintBalance, err error) {
+	f.StartAt = 0
+	err = d.autoMintQuery(f).Select(autoMintBalanceSelect).Take(&balance).Error
+	return
+}
+
+// GetAutoMintBalanceAt the income and expenditure of a provider under a parent account up to and including the statement
+func (d *DbDao) GetAutoMintBalanceAt(serviceProviderId, parentAccountId string, blockNumber, id uint64) (balance AutoMintBalance, err error) {
+	err = d.autoMintQuery(AutoMintFilter{ServiceProviderId: serviceProviderId, ParentAccountId: parentAccountId}).
+		Where("(block_number<? OR (block_number=? AND id<=?))", blockNumber, blockNumber, id).
+		Select(autoMintBalanceSelect).Take(&balance).Error
+	return
+}
+
+// GetAutoMintIncomeAt the income of a provider under a parent account up to and including the block
+func (d *DbDao) GetAutoMintIncomeAt(serviceProviderId, parentAccountId string, blockNumber uint64) (income decimal.Decimal, err error) {
+	var balance AutoMintBalance
+	err = d.autoMintQuery(AutoMintFilter{ServiceProviderId: serviceProviderId, ParentAccountId: parentAccountId}).
+		Where("block_number<=?", blockNumber).
+		Select(autoMintBalanceSelect).Take(&balance).Error
+	return balance.Income, err
+}
+
+func (d *DbDao) GetAutoMintStatementList(f AutoMintFilter, cursor *Cursor, limit, offset int) (list []TableSubAccountAutoMintStatement, err error) {
+	err = afterCursor(d.autoMintQuery(f), cursor, "block_number", "id", true).
+		Order("block_number DESC,id DESC").
+		Limit(limit).Offset(offset).Find(&list).Error
+	return
+}
+
+func (d *DbDao) GetAutoMintStatementCount(f AutoMintFilter) (count int64, err error) {
+	err = d.autoMintQuery(f).Count(&count).Error
+	return
+}
+
+// GetAutoMintExpenditureList the collected profit after the statement id, in id order
+func (d *DbDao) GetAutoMintExpenditureList(afterId uint64, limit int) (list []TableSubAccountAutoMintStatement, err error) {
+	err = d.db.Where("id>? AND tx_type=?", afterId, SubAccountAutoMintTxTypeExpenditure).
+		Order("id").Limit(limit).Find(&list).Error
+	return
+}
@@ -3,6 +3,7 @@ package dao
 import (
 	"das_database/config"
 	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
 	"github.com/shopspring/decimal"
 	"testing"
 )
@@ -239,3 +240,68 @@ func TestAutoMigrate(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestRecordsAtBlock(t *testing.T) {
+	oldRecords := []TableRecordsInfo{
+		{AccountId: "0x01", Type: "address", Key: "60", Value: "0xaa"},
+		{AccountId: "0x01", Type: "profile", Key: "twitter", Value: "das"},
+		{AccountId: "0x01", Type: "address", Key: "0", Label: "btc", Value: "bc1"},
+		{AccountId: "0x01", Type: "address", Key: "0", Label: "btc", Value: "bc2"},
+	}
+	newRecords := []TableRecordsInfo{
+		{AccountId: "0x01", Type: "address", Key: "60", Value: "0xbb"},
+		{AccountId: "0x01", Type: "dweb", Key: "ipfs", Value: "Qm"},
+		{AccountId: "0x01", Type: "address", Key: "0", Label: "btc", Value: "bc2"},
+		{AccountId: "0x01", Type: "address", Key: "0", Label: "btc", Value: "bc3"},
+	}
+	history := DiffRecords(oldRecords, newRecords)
+	if len(history) != 4 {
+		t.Fatal("DiffRecords:", history)
+	}
+	// the same key and label with several values
+	if v := history[0]; v.Key != "0" || v.ValueBefore != "bc1" || v.ValueAfter != "bc3" {
+		t.Fatal("DiffRecords of values:", v)
+	}
+	for i := range history {
+		history[i].BlockNumber = 100
+	}
+	if len(DiffRecords(newRecords, newRecords)) != 0 {
+		t.Fatal("DiffRecords of same records should be empty")
+	}
+
+	before := RecordsAtBlock(history, newRecords, 99)
+	if len(DiffRecords(before, oldRecords)) != 0 {
+		t.Fatal("records at 99:", before)
+	}
+	after := RecordsAtBlock(history, newRecords, 100)
+	if len(DiffRecords(after, newRecords)) != 0 {
+		t.Fatal("records at 100:", after)
+	}
+}
+
+func TestOwnershipDiff(t *testing.T) {
+	before := TableAccountInfo{OwnerChainType: common.ChainTypeEth, Owner: "0xaa", ManagerChainType: common.ChainTypeEth, Manager: "0xaa"}
+	after := before
+	after.Manager = "0xbb"
+	if list := ownershipDiff(before, after); len(list) != 1 || list[0].Role != OwnershipRoleManager || list[0].ToAddress != "0xbb" {
+		t.Fatal("edit manager:", list)
+	}
+	if list := ownershipDiff(before, TableAccountInfo{}); len(list) != 2 || list[0].ToAddress != "" {
+		t.Fatal("recycle:", list)
+	}
+	if list := ownershipDiff(before, before); len(list) != 0 {
+		t.Fatal("unchanged:", list)
+	}
+}
+
+func TestAccountSearchFields(t *testing.T) {
+	if l, r := AccountSearchFields("abc.bit"); l != 3 || r != "tib.cba" {
+		t.Fatal("top:", l, r)
+	}
+	if l, r := AccountSearchFields("12.abc.bit"); l != 2 || r != "tib.cba.21" {
+		t.Fatal("sub:", l, r)
+	}
+	if l, _ := AccountSearchFields("😀a.bit"); l != 2 {
+		t.Fatal("emoji:", l)
+	}
+}
@@ -0,0 +1,53 @@
+package dao
+
+import (
+	"fmt"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+type TableTokenPriceHistory struct {
+	Id        uint64          `json:"id" gorm:"column:id;primaryKey;type:bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT ''"`
+	TokenId   string          `json:"token_id" gorm:"column:token_id;uniqueIndex:uk_ti_pa;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	Price     decimal.Decimal `json:"price" gorm:"column:price;type:decimal(50, 8) NOT NULL DEFAULT '0.00000000' COMMENT ''"`
+	PriceAt   int64           `json:"price_at" gorm:"column:price_at;uniqueIndex:uk_ti_pa;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT 'unix timestamp in seconds'"`
+	CreatedAt time.Time       `json:"created_at" gorm:"column:created_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT ''"`
+	UpdatedAt time.Time       `json:"updated_at" gorm:"column:updated_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT ''"`
+}
+
+const (
+	TableNameTokenPriceHistory = "t_token_price_history"
+)
+
+func (t *TableTokenPriceHistory) TableName() string {
+	return TableNameTokenPriceHistory
+}
+
+// SeedTokenPriceHistory records the current prices of t_token_price_info at their update time,
+// so the blocks parsed before the first price tick after a deploy get a price. A price already recorded is kept
+func (d *DbDao) SeedTokenPriceHistory() (rows int64, err error) {
+	sql := fmt.Sprintf(`INSERT IGNORE INTO %s(token_id,price,price_at)
+SELECT token_id,price,last_updated_at FROM %s WHERE price>0 AND last_updated_at>0`, TableNameTokenPriceHistory, TableNameTokenPriceInfo)
+	res := d.db.Exec(sql)
+	return res.RowsAffected, res.Error
+}
+
+// GetTokenPriceAt returns the latest price recorded at or before the timestamp (seconds), Id is 0 if none
+func (d *DbDao) GetTokenPriceAt(tokenId string, timestamp int64) (history TableTokenPriceHistory, err error) {
+	err = d.db.Where("token_id=? AND price_at<=?", tokenId, timestamp).
+		Order("price_at DESC").Limit(1).Find(&history).Error
+	return
+}
+
+func (d *DbDao) GetTokenPriceHistoryList(tokenId string, startAt, endAt int64, cursor *Cursor, limit, offset int) (list []TableTokenPriceHistory, err error) {
+	db := d.db.Where("token_id=? AND price_at>=? AND price_at<=?", tokenId, startAt, endAt)
+	err = afterCursor(db, cursor, "price_at", "id", true).
+		Order("price_at DESC,id DESC").Limit(limit).Offset(offset).Find(&list).Error
+	return
+}
+
+func (d *DbDao) GetTokenPriceHistoryCount(tokenId string, startAt, endAt int64) (count int64, err error) {
+	err = d.db.Model(&TableTokenPriceHistory{}).
+		Where("token_id=? AND price_at>=? AND price_at<=?", tokenId, startAt, endAt).Count(&count).Error
+	return
+}
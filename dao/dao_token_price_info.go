@@ -5,6 +5,9 @@ import (
 	"github.com/dotbitHQ/das-lib/common"
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -33,6 +36,9 @@ type TableTokenPriceInfo struct {
 
 const (
 	TableNameTokenPriceInfo = "t_token_price_info"
+
+	TokenStatusNormal = 0
+	TokenStatusBanned = 1
 )
 
 func (t *TableTokenPriceInfo) TableName() string {
@@ -49,13 +55,33 @@ func (d *DbDao) SearchTokenPriceInfoList() (tokenPriceInfos []TableTokenPriceInf
 	return
 }
 
+// UpdateTokenPriceInfoList sets the prices of the registered tokens and records them in the history,
+// the prices of the tokens which are not in t_token_price_info are dropped
 func (d *DbDao) UpdateTokenPriceInfoList(tokenList []TableTokenPriceInfo) error {
 	if len(tokenList) == 0 {
 		return nil
 	}
 	return d.db.Transaction(func(tx *gorm.DB) error {
+		tokenIds := make([]string, 0, len(tokenList))
+		for _, v := range tokenList {
+			tokenIds = append(tokenIds, v.TokenId)
+		}
+		var registered []string
+		if err := tx.Model(TableTokenPriceInfo{}).Where("token_id IN ?", tokenIds).
+			Pluck("token_id", &registered).Error; err != nil {
+			return err
+		}
+		registeredMap := make(map[string]bool, len(registered))
+		for _, v := range registered {
+			registeredMap[v] = true
+		}
+
+		var historyList []TableTokenPriceHistory
 		for i, _ := range tokenList {
-			if err := d.db.Model(TableTokenPriceInfo{}).
+			if !registeredMap[tokenList[i].TokenId] {
+				continue
+			}
+			if err := tx.Model(TableTokenPriceInfo{}).
 				Where("token_id=?", tokenList[i].TokenId).
 				Updates(map[string]interface{}{
 					"price":           tokenList[i].Price,
@@ -63,8 +89,16 @@ func (d *DbDao) UpdateTokenPriceInfoList(tokenList []TableTokenPriceInfo) error
 				}).Error; err != nil {
 				return err
 			}
+			historyList = append(historyList, TableTokenPriceHistory{
+				TokenId: tokenList[i].TokenId,
+				Price:   tokenList[i].Price,
+				PriceAt: tokenList[i].LastUpdatedAt,
+			})
 		}
-		return nil
+		if len(historyList) == 0 {
+			return nil
+		}
+		return tx.Clauses(clause.Insert{Modifier: "IGNORE"}).Create(&historyList).Error
 	})
 }
 
@@ -76,3 +110,79 @@ func (d *DbDao) UpdateCNYToUSDRate(tokenIds []string, price decimal.Decimal) err
 			LastUpdatedAt: time.Now().Unix(),
 		}).Error
 }
+
+func (t *TableTokenPriceInfo) Validate() error {
+	if t.TokenId == "" || strings.ToLower(t.TokenId) != t.TokenId || !strings.Contains(t.TokenId, "_") {
+		return fmt.Errorf("token_id [%s] invalid, should be like chain_symbol in lower case", t.TokenId)
+	}
+	if t.Symbol == "" {
+		return fmt.Errorf("symbol of [%s] is empty", t.TokenId)
+	}
+	if t.Decimals < 0 || t.Decimals > 30 {
+		return fmt.Errorf("decimals of [%s] invalid: %d", t.TokenId, t.Decimals)
+	}
+	if t.CoinType != "" {
+		if _, err := strconv.ParseUint(string(t.CoinType), 10, 64); err != nil {
+			return fmt.Errorf("coin_type of [%s] invalid: %s", t.TokenId, t.CoinType)
+		}
+	}
+	if t.Status != TokenStatusNormal && t.Status != TokenStatusBanned {
+		return fmt.Errorf("status of [%s] invalid: %d", t.TokenId, t.Status)
+	}
+	return nil
+}
+
+func prepareTokenList(tokenList []TableTokenPriceInfo) error {
+	for i := range tokenList {
+		if err := tokenList[i].Validate(); err != nil {
+			return err
+		}
+		if tokenList[i].GeckoId == "" {
+			tokenList[i].GeckoId = tokenList[i].TokenId
+		}
+	}
+	return nil
+}
+
+// InsertNewTokenList only inserts the tokens which don't exist yet, existing ones are left to the internal api
+func (d *DbDao) InsertNewTokenList(tokenList []TableTokenPriceInfo) error {
+	if len(tokenList) == 0 {
+		return nil
+	}
+	if err := prepareTokenList(tokenList); err != nil {
+		return err
+	}
+	return d.db.Clauses(clause.Insert{
+		Modifier: "IGNORE",
+	}).Create(&tokenList).Error
+}
+
+// UpsertTokenList inserts new tokens and refreshes the metadata of existing ones, price and status are left untouched
+func (d *DbDao) UpsertTokenList(tokenList []TableTokenPriceInfo) error {
+	if len(tokenList) == 0 {
+		return nil
+	}
+	if err := prepareTokenList(tokenList); err != nil {
+		return err
+	}
+	return d.db.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{
+			"gecko_id", "chain_type", "name", "symbol", "decimals",
+			"logo", "coin_type", "display_name", "icon",
+		}),
+	}).Create(&tokenList).Error
+}
+
+func (d *DbDao) GetTokenPriceInfoByTokenId(tokenId string) (tokenInfo TableTokenPriceInfo, err error) {
+	err = d.db.Where("token_id=?", tokenId).Limit(1).Find(&tokenInfo).Error
+	return
+}
+
+func (d *DbDao) GetTokenPriceInfoByGeckoId(geckoId string) (tokenInfo TableTokenPriceInfo, err error) {
+	err = d.db.Where("gecko_id=?", geckoId).Limit(1).Find(&tokenInfo).Error
+	return
+}
+
+func (d *DbDao) UpdateTokenStatus(tokenId string, status int) error {
+	return d.db.Model(TableTokenPriceInfo{}).Where("token_id=?", tokenId).Update("status", status).Error
+}
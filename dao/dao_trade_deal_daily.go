@@ -0,0 +1,116 @@
+package dao
+
+import (
+	"fmt"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"time"
+)
+
+type TableTradeDealDaily struct {
+	Id            uint64          `json:"id" gorm:"column:id;primaryKey;type:bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT ''"`
+	StatDate      string          `json:"stat_date" gorm:"column:stat_date;uniqueIndex:uk_sd_dt_al;type:varchar(10) NOT NULL DEFAULT '' COMMENT 'UTC date, 2006-01-02'"`
+	DealType      int             `json:"deal_type" gorm:"column:deal_type;uniqueIndex:uk_sd_dt_al;type:smallint(6) NOT NULL DEFAULT '0' COMMENT '0: sale 1: auction 2: offer'"`
+	AccountLength uint8           `json:"account_length" gorm:"column:account_length;uniqueIndex:uk_sd_dt_al;type:smallint(6) NOT NULL DEFAULT '0' COMMENT ''"`
+	DealCount     uint64          `json:"deal_count" gorm:"column:deal_count;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
+	VolumeCkb     uint64          `json:"volume_ckb" gorm:"column:volume_ckb;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT 'sum of price_ckb'"`
+	VolumeUsd     decimal.Decimal `json:"volume_usd" gorm:"column:volume_usd;type:decimal(50, 8) NOT NULL DEFAULT '0.00000000' COMMENT 'sum of price_usd'"`
+	FloorPriceCkb uint64          `json:"floor_price_ckb" gorm:"column:floor_price_ckb;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
+	TopPriceCkb   uint64          `json:"top_price_ckb" gorm:"column:top_price_ckb;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
+	CreatedAt     time.Time       `json:"created_at" gorm:"column:created_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT ''"`
+	UpdatedAt     time.Time       `json:"updated_at" gorm:"column:updated_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT ''"`
+}
+
+const (
+	TableNameTradeDealDaily = "t_trade_deal_daily"
+	StatDateLayout          = "2006-01-02"
+)
+
+func (t *TableTradeDealDaily) TableName() string {
+	return TableNameTradeDealDaily
+}
+
+// TableTradeDealPriceDaily the deals of a day grouped by price, for the floor and median prices by account length or charset
+type TableTradeDealPriceDaily struct {
+	Id            uint64          `json:"id" gorm:"column:id;primaryKey;type:bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT ''"`
+	StatDate      string          `json:"stat_date" gorm:"column:stat_date;uniqueIndex:uk_sd_al_cn_pc;type:varchar(10) NOT NULL DEFAULT '' COMMENT 'UTC date, 2006-01-02'"`
+	AccountLength uint8           `json:"account_length" gorm:"column:account_length;uniqueIndex:uk_sd_al_cn_pc;type:smallint(6) NOT NULL DEFAULT '0' COMMENT ''"`
+	CharsetNum    uint64          `json:"charset_num" gorm:"column:charset_num;uniqueIndex:uk_sd_al_cn_pc;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
+	PriceCkb      uint64          `json:"price_ckb" gorm:"column:price_ckb;uniqueIndex:uk_sd_al_cn_pc;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
+	DealCount     uint64          `json:"deal_count" gorm:"column:deal_count;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
+	VolumeUsd     decimal.Decimal `json:"volume_usd" gorm:"column:volume_usd;type:decimal(50, 8) NOT NULL DEFAULT '0.00000000' COMMENT 'sum of price_usd'"`
+	CreatedAt     time.Time       `json:"created_at" gorm:"column:created_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT ''"`
+	UpdatedAt     time.Time       `json:"updated_at" gorm:"column:updated_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT ''"`
+}
+
+const TableNameTradeDealPriceDaily = "t_trade_deal_price_daily"
+
+func (t *TableTradeDealPriceDaily) TableName() string {
+	return TableNameTradeDealPriceDaily
+}
+
+// refreshTradeDealDaily recomputes the rollup rows of the deal's day from t_trade_deal_info,
+// so that re-parsing the same block never double counts.
+func refreshTradeDealDaily(tx *gorm.DB, dealInfo TableTradeDealInfo) error {
+	return refreshTradeDealDay(tx, time.UnixMilli(int64(dealInfo.BlockTimestamp)))
+}
+
+func refreshTradeDealDay(tx *gorm.DB, t time.Time) error {
+	dayStart := t.UTC().Truncate(time.Hour * 24)
+	statDate := dayStart.Format(StatDateLayout)
+	startAt := uint64(dayStart.UnixMilli())
+	endAt := uint64(dayStart.Add(time.Hour * 24).UnixMilli())
+
+	if err := tx.Where("stat_date=?", statDate).Delete(&TableTradeDealDaily{}).Error; err != nil {
+		return err
+	}
+	sql := fmt.Sprintf(`INSERT INTO %s(stat_date,deal_type,account_length,deal_count,volume_ckb,volume_usd,floor_price_ckb,top_price_ckb)
+SELECT ?,deal_type,account_length,COUNT(*),IFNULL(SUM(price_ckb),0),IFNULL(SUM(price_usd),0),IFNULL(MIN(price_ckb),0),IFNULL(MAX(price_ckb),0)
+FROM %s WHERE block_timestamp>=? AND block_timestamp<? GROUP BY deal_type,account_length`,
+		TableNameTradeDealDaily, TableNameTradeDealInfo)
+	if err := tx.Exec(sql, statDate, startAt, endAt).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Where("stat_date=?", statDate).Delete(&TableTradeDealPriceDaily{}).Error; err != nil {
+		return err
+	}
+	sql = fmt.Sprintf(`INSERT INTO %s(stat_date,account_length,charset_num,price_ckb,deal_count,volume_usd)
+SELECT ?,d.account_length,IFNULL(a.charset_num,0) AS cn,d.price_ckb,COUNT(*),IFNULL(SUM(d.price_usd),0)
+FROM %s d LEFT JOIN %s a ON a.account_id=d.account_id
+WHERE d.block_timestamp>=? AND d.block_timestamp<? GROUP BY d.account_length,cn,d.price_ckb`,
+		TableNameTradeDealPriceDaily, TableNameTradeDealInfo, TableNameAccountInfo)
+	return tx.Exec(sql, statDate, startAt, endAt).Error
+}
+
+// RebuildTradeDealDaily recomputes the rollup rows of every day from t_trade_deal_info
+func (d *DbDao) RebuildTradeDealDaily() (days int, err error) {
+	var span struct {
+		MinAt uint64 `gorm:"column:min_at"`
+		MaxAt uint64 `gorm:"column:max_at"`
+	}
+	if err = d.db.Model(&TableTradeDealInfo{}).
+		Select("IFNULL(MIN(block_timestamp),0) AS min_at, IFNULL(MAX(block_timestamp),0) AS max_at").
+		Scan(&span).Error; err != nil || span.MaxAt == 0 {
+		return
+	}
+	end := time.UnixMilli(int64(span.MaxAt))
+	for t := time.UnixMilli(int64(span.MinAt)).UTC().Truncate(time.Hour * 24); !t.After(end); t = t.Add(time.Hour * 24) {
+		if err = d.db.Transaction(func(tx *gorm.DB) error {
+			return refreshTradeDealDay(tx, t)
+		}); err != nil {
+			return
+		}
+		days++
+	}
+	return
+}
+
+func (d *DbDao) GetTradeDealDailyList(startDate, endDate string, dealType int) (list []TableTradeDealDaily, err error) {
+	db := d.db.Where("stat_date>=? AND stat_date<=?", startDate, endDate)
+	if dealType >= 0 {
+		db = db.Where("deal_type=?", dealType)
+	}
+	err = db.Order("stat_date").Find(&list).Error
+	return
+}
@@ -3,6 +3,7 @@ package dao
 import (
 	"github.com/dotbitHQ/das-lib/common"
 	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 	"time"
 )
 
@@ -13,13 +14,14 @@ type TableTradeDealInfo struct {
 	AccountId      string           `json:"account_id" gorm:"account_id;index:k_account_id;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'hash of account'"`
 	Account        string           `json:"account" gorm:"column:account;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
 	DealType       int              `json:"deal_type" gorm:"column:deal_type;type:smallint(6) NOT NULL DEFAULT '0' COMMENT '0: sale 1: auction'"`
+	AccountLength  uint8            `json:"account_length" gorm:"column:account_length;type:smallint(6) NOT NULL DEFAULT '0' COMMENT ''"`
 	SellChainType  common.ChainType `json:"sell_chain_type" gorm:"column:sell_chain_type;index:k_sct_sa;type:int(11) NOT NULL DEFAULT '0' COMMENT ''"`
 	SellAddress    string           `json:"sell_address" gorm:"column:sell_address;index:k_sct_sa;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
 	BuyChainType   common.ChainType `json:"buy_chain_type" gorm:"column:buy_chain_type;index:k_bct_ba;type:int(11) NOT NULL DEFAULT '0' COMMENT ''"`
 	BuyAddress     string           `json:"buy_address" gorm:"column:buy_address;index:k_bct_ba;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
 	PriceCkb       uint64           `json:"price_ckb" gorm:"column:price_ckb;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT 'price in CKB'"`
 	PriceUsd       decimal.Decimal  `json:"price_usd" gorm:"column:price_usd;type:decimal(50, 8) NOT NULL DEFAULT '0.00000000' COMMENT 'price in dollar'"`
-	BlockTimestamp uint64           `json:"block_timestamp" gorm:"column:block_timestamp;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
+	BlockTimestamp uint64           `json:"block_timestamp" gorm:"column:block_timestamp;index:k_block_timestamp;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
 	CreatedAt      time.Time        `json:"created_at" gorm:"column:created_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT ''"`
 	UpdatedAt      time.Time        `json:"updated_at" gorm:"column:updated_at;type:timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT ''"`
 }
@@ -34,3 +36,116 @@ const (
 func (t *TableTradeDealInfo) TableName() string {
 	return TableNameTradeDealInfo
 }
+
+type DealPriceStats struct {
+	DealCount  int64           `json:"deal_count" gorm:"column:deal_count"`
+	FloorPrice uint64          `json:"floor_price" gorm:"column:floor_price"`
+	FloorUsd   decimal.Decimal `json:"floor_usd" gorm:"column:floor_usd"`
+}
+
+// DealPrice the deals at one price, the usd price is their average
+type DealPrice struct {
+	PriceCkb  uint64          `json:"price_ckb" gorm:"column:price_ckb"`
+	DealCount uint64          `json:"deal_count" gorm:"column:deal_count"`
+	VolumeUsd decimal.Decimal `json:"volume_usd" gorm:"column:volume_usd"`
+}
+
+func (p DealPrice) PriceUsd() decimal.Decimal {
+	if p.DealCount == 0 {
+		return decimal.Zero
+	}
+	return p.VolumeUsd.Div(decimal.NewFromInt(int64(p.DealCount)))
+}
+
+// dealPriceQuery the deals of the days grouped by price from the daily rollup
+func (d *DbDao) dealPriceQuery(startDate, endDate string, accountLength uint8, charsetNum uint64) *gorm.DB {
+	db := d.db.Table(TableNameTradeDealPriceDaily).
+		Where("stat_date>=? AND stat_date<=?", startDate, endDate)
+	if accountLength > 0 {
+		db = db.Where("account_length=?", accountLength)
+	}
+	if charsetNum > 0 {
+		db = db.Where("charset_num=?", charsetNum)
+	}
+	return db.Select("price_ckb, SUM(deal_count) AS deal_count, SUM(volume_usd) AS volume_usd").Group("price_ckb")
+}
+
+// GetDealPriceStats the floor prices in ckb and usd come from the same deals
+func (d *DbDao) GetDealPriceStats(startDate, endDate string, accountLength uint8, charsetNum uint64) (stats DealPriceStats, err error) {
+	var count struct {
+		DealCount int64 `gorm:"column:deal_count"`
+	}
+	if err = d.db.Table("(?) g", d.dealPriceQuery(startDate, endDate, accountLength, charsetNum)).
+		Select("IFNULL(SUM(g.deal_count),0) AS deal_count").Scan(&count).Error; err != nil || count.DealCount == 0 {
+		return
+	}
+	var floor DealPrice
+	if err = d.dealPriceQuery(startDate, endDate, accountLength, charsetNum).
+		Order("price_ckb").Limit(1).Scan(&floor).Error; err != nil {
+		return
+	}
+	stats.DealCount = count.DealCount
+	stats.FloorPrice = floor.PriceCkb
+	stats.FloorUsd = floor.PriceUsd()
+	return
+}
+
+// GetDealMedianPrice returns the price of the deal at the middle position when ordered by price_ckb
+func (d *DbDao) GetDealMedianPrice(startDate, endDate string, accountLength uint8, charsetNum uint64, dealCount int64) (price DealPrice, err error) {
+	if dealCount == 0 {
+		return
+	}
+	cumulative := d.db.Table("(?) g", d.dealPriceQuery(startDate, endDate, accountLength, charsetNum)).
+		Select("g.*, SUM(g.deal_count) OVER (ORDER BY g.price_ckb) AS cum")
+	err = d.db.Table("(?) m", cumulative).Select("m.price_ckb, m.deal_count, m.volume_usd").
+		Where("m.cum>?", dealCount/2).Order("m.price_ckb").Limit(1).Scan(&price).Error
+	return
+}
+
+func (d *DbDao) topSalesQuery(startAt, endAt uint64, dealType int) *gorm.DB {
+	db := d.db.Model(&TableTradeDealInfo{}).Where("block_timestamp>=? AND block_timestamp<?", startAt, endAt)
+	if dealType >= 0 {
+		db = db.Where("deal_type=?", dealType)
+	}
+	return db
+}
+
+func (d *DbDao) GetTopSales(startAt, endAt uint64, dealType int, cursor *Cursor, limit, offset int) (list []TableTradeDealInfo, err error) {
+	err = afterCursor(d.topSalesQuery(startAt, endAt, dealType), cursor, "price_ckb", "id", true).
+		Order("price_ckb DESC,id DESC").Limit(limit).Offset(offset).Find(&list).Error
+	return
+}
+
+func (d *DbDao) GetTopSalesCount(startAt, endAt uint64, dealType int) (count int64, err error) {
+	err = d.topSalesQuery(startAt, endAt, dealType).Count(&count).Error
+	return
+}
+
+// GetTradeDealNoLengthList the deals kept before the account length was recorded
+func (d *DbDao) GetTradeDealNoLengthList(afterId uint64, limit int) (list []TableTradeDealInfo, err error) {
+	err = d.db.Select("id", "account").Where("id>? AND account_length=0", afterId).
+		Order("id").Limit(limit).Find(&list).Error
+	return
+}
+
+func (d *DbDao) UpdateTradeDealAccountLength(list []TableTradeDealInfo) error {
+	return d.db.Transaction(func(tx *gorm.DB) error {
+		for _, v := range list {
+			if err := tx.Model(&TableTradeDealInfo{}).Where("id=?", v.Id).
+				Update("account_length", v.AccountLength).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (d *DbDao) GetTradeDealListByAccountId(accountId string) (list []TableTradeDealInfo, err error) {
+	err = d.db.Where("account_id=?", accountId).Order("block_number DESC,id DESC").Find(&list).Error
+	return
+}
+
+func (d *DbDao) GetTradeDealListByAccountIds(accountIds []string, limit, offset int) (list []TableTradeDealInfo, err error) {
+	err = d.findPerKey(&TableTradeDealInfo{}, &list, "account_id", "block_number DESC,id DESC", accountIds, limit, offset)
+	return
+}
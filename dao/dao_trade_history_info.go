@@ -33,3 +33,8 @@ const (
 func (t *TableTradeHistoryInfo) TableName() string {
 	return TableNameTradeHistoryInfo
 }
+
+func (d *DbDao) GetTradeHistoryListByAccountId(accountId string) (list []TableTradeHistoryInfo, err error) {
+	err = d.db.Where("account_id=?", accountId).Order("block_number DESC,id DESC").Find(&list).Error
+	return
+}
@@ -134,6 +134,10 @@ func (d *DbDao) CancelAccountSale(accountInfo TableAccountInfo, transactionInfo
 
 func (d *DbDao) BuyAccount(incomeCellInfos []TableIncomeCellInfo, accountInfo TableAccountInfo, dealInfo TableTradeDealInfo, transactionInfoBuy, transactionInfoSale TableTransactionInfo, rebateInfos []TableRebateInfo, recordsInfos []TableRecordsInfo) error {
 	return d.db.Transaction(func(tx *gorm.DB) error {
+		ownership, err := GetOwnership(tx, []string{accountInfo.AccountId})
+		if err != nil {
+			return err
+		}
 		if len(incomeCellInfos) > 0 {
 			if err := tx.Clauses(clause.OnConflict{
 				DoUpdates: clause.AssignmentColumns([]string{
@@ -157,11 +161,14 @@ func (d *DbDao) BuyAccount(incomeCellInfos []TableIncomeCellInfo, accountInfo Ta
 		if err := tx.Clauses(clause.OnConflict{
 			DoUpdates: clause.AssignmentColumns([]string{
 				"account_id", "account", "deal_type", "sell_chain_type", "sell_address",
-				"buy_chain_type", "buy_address", "price_ckb", "price_usd",
+				"buy_chain_type", "buy_address", "price_ckb", "price_usd", "account_length",
 			}),
 		}).Create(&dealInfo).Error; err != nil {
 			return err
 		}
+		if err := refreshTradeDealDaily(tx, dealInfo); err != nil {
+			return err
+		}
 
 		if err := tx.Clauses(clause.OnConflict{
 			DoUpdates: clause.AssignmentColumns([]string{
@@ -193,16 +200,22 @@ func (d *DbDao) BuyAccount(incomeCellInfos []TableIncomeCellInfo, accountInfo Ta
 			}
 		}
 
-		if err := tx.Where("account_id = ?", accountInfo.AccountId).Delete(&TableRecordsInfo{}).Error; err != nil {
+		if err := ReplaceRecords(tx, []string{accountInfo.AccountId}, recordsInfos, transactionInfoBuy); err != nil {
 			return err
 		}
 
-		if len(recordsInfos) > 0 {
-			if err := tx.Create(&recordsInfos).Error; err != nil {
-				return err
-			}
+		if err := CreateOwnershipLedger(tx, ownership, transactionInfoBuy); err != nil {
+			return err
 		}
-
 		return nil
 	})
 }
+
+// GetTradeListByAccountIds the current sales of the accounts
+func (d *DbDao) GetTradeListByAccountIds(accountIds []string) (list []TableTradeInfo, err error) {
+	if len(accountIds) == 0 {
+		return
+	}
+	err = d.db.Where("account_id IN ?", accountIds).Find(&list).Error
+	return
+}
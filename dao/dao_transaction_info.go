@@ -2,19 +2,20 @@ package dao
 
 import (
 	"github.com/dotbitHQ/das-lib/common"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 	"time"
 )
 
 type TableTransactionInfo struct {
 	Id             uint64           `json:"id" gorm:"column:id;primaryKey;type:bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT ''"`
-	BlockNumber    uint64           `json:"block_number" gorm:"column:block_number;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
-	AccountId      string           `json:"account_id" gorm:"account_id;index:k_ai_a;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'hash of account'"`
+	BlockNumber    uint64           `json:"block_number" gorm:"column:block_number;index:k_ai_bn,priority:2;index:k_ct_a_bn,priority:3;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
+	AccountId      string           `json:"account_id" gorm:"account_id;index:k_ai_a;index:k_ai_bn,priority:1;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'hash of account'"`
 	Account        string           `json:"account" gorm:"column:account;index:k_a_a;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
 	Action         string           `json:"action" gorm:"column:action;index:k_ct_a_a,priority:3;index:k_a_a;index:k_ai_a;uniqueIndex:uk_a_o;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
 	ServiceType    int              `json:"service_type" gorm:"column:service_type;type:smallint(6) NOT NULL DEFAULT '0' COMMENT '1: register 2: trade'"`
-	ChainType      common.ChainType `json:"chain_type" gorm:"column:chain_type;index:k_ct_a_a,priority:1;index:k_ct_a;type:smallint(6) NOT NULL DEFAULT '0' COMMENT ''"`
-	Address        string           `json:"address" gorm:"column:address;index:k_ct_a_a,priority:2;index:k_ct_a;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
+	ChainType      common.ChainType `json:"chain_type" gorm:"column:chain_type;index:k_ct_a_a,priority:1;index:k_ct_a;index:k_ct_a_bn,priority:1;type:smallint(6) NOT NULL DEFAULT '0' COMMENT ''"`
+	Address        string           `json:"address" gorm:"column:address;index:k_ct_a_a,priority:2;index:k_ct_a;index:k_ct_a_bn,priority:2;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
 	Capacity       uint64           `json:"capacity" gorm:"column:capacity;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
 	Outpoint       string           `json:"outpoint" gorm:"column:outpoint;index:k_outpoint;uniqueIndex:uk_a_o;type:varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT ''"`
 	BlockTimestamp uint64           `json:"block_timestamp" gorm:"column:block_timestamp;type:bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT ''"`
@@ -81,3 +82,62 @@ func (d *DbDao) FindTransactionInfoByAccountAction(account, action string) (tran
 	err = d.db.Where("account = ? AND action = ?", account, action).Limit(1).Find(&transactionInfo).Error
 	return
 }
+
+// GetLatestTxTimestamp the block_timestamp of the last parsed transaction, in ms
+func (d *DbDao) GetLatestTxTimestamp() (blockTimestamp uint64, err error) {
+	var info TableTransactionInfo
+	err = d.db.Select("block_timestamp").Order("id DESC").Limit(1).Find(&info).Error
+	return info.BlockTimestamp, err
+}
+
+// ActivityFilter either the account or the address is required, the time range is [StartAt, EndAt) of block_timestamp
+type ActivityFilter struct {
+	AccountId   string
+	ChainType   common.ChainType
+	Address     string
+	Actions     []string
+	ServiceType int
+	StartAt     uint64
+	EndAt       uint64
+}
+
+func (d *DbDao) activityQuery(f ActivityFilter) *gorm.DB {
+	db := d.db.Model(&TableTransactionInfo{})
+	if f.AccountId != "" {
+		db = db.Where("account_id=?", f.AccountId)
+	}
+	if f.Address != "" {
+		db = db.Where("chain_type=? AND address=?", f.ChainType, f.Address)
+	}
+	if len(f.Actions) > 0 {
+		db = db.Where("action IN ?", f.Actions)
+	}
+	if f.ServiceType > 0 {
+		db = db.Where("service_type=?", f.ServiceType)
+	}
+	if f.StartAt > 0 {
+		db = db.Where("block_timestamp>=?", f.StartAt)
+	}
+	if f.EndAt > 0 {
+		db = db.Where("block_timestamp<?", f.EndAt)
+	}
+	return db
+}
+
+func (d *DbDao) GetActivityList(f ActivityFilter, cursor *Cursor, limit, offset int) (list []TableTransactionInfo, err error) {
+	err = afterCursor(d.activityQuery(f), cursor, "block_number", "id", true).
+		Order("block_number DESC,id DESC").
+		Limit(limit).Offset(offset).Find(&list).Error
+	return
+}
+
+func (d *DbDao) GetActivityCount(f ActivityFilter) (count int64, err error) {
+	err = d.activityQuery(f).Count(&count).Error
+	return
+}
+
+// GetTransactionListByAccountIds the transactions of each account, the latest first, limit of each account after offset
+func (d *DbDao) GetTransactionListByAccountIds(accountIds []string, limit, offset int) (list []TableTransactionInfo, err error) {
+	err = d.findPerKey(&TableTransactionInfo{}, &list, "account_id", "block_number DESC,id DESC", accountIds, limit, offset)
+	return
+}
@@ -0,0 +1,93 @@
+package grpc_server
+
+import (
+	"context"
+	"das_database/block_parser"
+	"das_database/dao"
+	"das_database/http_server/handle"
+	"das_database/pb"
+	"das_database/prometheus"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/core"
+	"github.com/dotbitHQ/das-lib/http_api/logger"
+	"github.com/go-redis/redis"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"net"
+	"path"
+	"time"
+)
+
+var (
+	log = logger.NewLogger("grpc_server", logger.LevelDebug)
+)
+
+// GrpcServer the grpc api for the internal services, only served when grpc_server_addr is set
+type GrpcServer struct {
+	address string
+	srv     *grpc.Server
+}
+
+type GrpcServerParams struct {
+	Address string
+	DbDao   *dao.DbDao
+	Ctx     context.Context
+	DasCore *core.DasCore
+	Bp      *block_parser.BlockParser
+	Red     *redis.Client
+}
+
+func Initialize(p GrpcServerParams) *GrpcServer {
+	gs := GrpcServer{
+		address: p.Address,
+		srv:     grpc.NewServer(grpc.UnaryInterceptor(monitorInterceptor)),
+	}
+	h := handle.Initialize(handle.HttpHandleParams{
+		DbDao:   p.DbDao,
+		DasCore: p.DasCore,
+		Ctx:     p.Ctx,
+		Bp:      p.Bp,
+		Red:     p.Red,
+	})
+	pb.RegisterDasDatabaseServer(gs.srv, h.GrpcService())
+	return &gs
+}
+
+func (g *GrpcServer) Run() error {
+	if g.address == "" {
+		return nil
+	}
+	lis, err := net.Listen("tcp", g.address)
+	if err != nil {
+		return fmt.Errorf("net.Listen err: %s", err.Error())
+	}
+	go func() {
+		if err := g.srv.Serve(lis); err != nil {
+			log.Error("grpc_server run err:", err)
+		}
+	}()
+	return nil
+}
+
+func (g *GrpcServer) Shutdown() {
+	log.Warn("grpc server Shutdown ... ")
+	g.srv.GracefulStop()
+}
+
+// monitorInterceptor the log and the api metric of the json-rpc, labeled by the method name
+func monitorInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	startTime := time.Now()
+	method := path.Base(info.FullMethod)
+	log.Info("GrpcReq:", method)
+
+	resp, err := handler(ctx, req)
+
+	var errNo int64
+	var errMsg string
+	if e := pb.GetError(err); e != nil {
+		errNo, errMsg = e.ErrNo, e.ErrMsg
+		log.Warn("GrpcResp:", method, status.Code(err).String(), errNo, errMsg)
+	}
+	prometheus.Tools.Metrics.Api().WithLabelValues(method, status.Code(err).String(), fmt.Sprint(errNo), errMsg).Observe(time.Since(startTime).Seconds())
+	return resp, err
+}
@@ -0,0 +1,51 @@
+package grpc_server
+
+import (
+	"context"
+	"das_database/pb"
+	"das_database/prometheus"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/core"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"sync"
+	"testing"
+)
+
+func TestGrpcError(t *testing.T) {
+	prometheus.Init()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dc := core.NewDasCore(ctx, &sync.WaitGroup{}, core.WithDasNetType(common.DasNetTypeTestnet2))
+	gs := Initialize(GrpcServerParams{Ctx: ctx, DasCore: dc})
+
+	lis := bufconn.Listen(1024 * 1024)
+	go func() {
+		_ = gs.srv.Serve(lis)
+	}()
+	defer gs.Shutdown()
+
+	client, conn, err := pb.Dial(ctx, "bufnet", grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// rejected by the handler before any query
+	_, err = client.AccountInfo(ctx, &pb.AccountInfoRequest{Account: "test"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatal(err)
+	}
+	if e := pb.GetError(err); e.ErrNo != int64(http_api.ApiCodeParamsInvalid) || e.ErrMsg != "Invalid account parameter" {
+		t.Fatal(e)
+	}
+	if pb.GetError(nil) != nil {
+		t.Fatal("error of a successful call")
+	}
+}
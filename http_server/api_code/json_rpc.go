@@ -2,29 +2,130 @@ package api_code
 
 import "encoding/json"
 
+const JsonRpcVersion = "2.0"
+
+// JsonRequest the id is kept raw, a request without it is a notification
 type JsonRequest struct {
-	ID      interface{}     `json:"id"`
+	ID      json.RawMessage `json:"id"`
 	JsonRpc string          `json:"jsonrpc"`
 	Method  string          `json:"method"`
 	Params  json.RawMessage `json:"params"`
 }
 
+// JsonResponse has either the result or the error
 type JsonResponse struct {
-	ID      interface{} `json:"id"`
-	JsonRpc string      `json:"jsonrpc"`
-	Result  interface{} `json:"result"`
+	ID      json.RawMessage `json:"id"`
+	JsonRpc string          `json:"jsonrpc"`
+	Result  interface{}     `json:"result"`
+	Error   *JsonError      `json:"error"`
 }
 
 func (j *JsonResponse) ResultData(data interface{}) {
 	j.Result = data
 }
 
+func (j JsonResponse) MarshalJSON() ([]byte, error) {
+	id := j.ID
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	if j.Error != nil {
+		return json.Marshal(struct {
+			JsonRpc string          `json:"jsonrpc"`
+			ID      json.RawMessage `json:"id"`
+			Error   *JsonError      `json:"error"`
+		}{JsonRpc: j.JsonRpc, ID: id, Error: j.Error})
+	}
+	return json.Marshal(struct {
+		JsonRpc string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Result  interface{}     `json:"result"`
+	}{JsonRpc: j.JsonRpc, ID: id, Result: j.Result})
+}
+
+type JsonError struct {
+	Code    int            `json:"code"`
+	Message string         `json:"message"`
+	Data    *JsonErrorData `json:"data,omitempty"`
+}
+
+// JsonErrorData the err_no of the v1 apis, for the errors of the handlers
+type JsonErrorData struct {
+	ErrNo ApiCode `json:"err_no"`
+}
+
+const (
+	JsonRpcCodeParseError     = -32700
+	JsonRpcCodeInvalidRequest = -32600
+	JsonRpcCodeMethodNotFound = -32601
+	JsonRpcCodeInvalidParams  = -32602
+	JsonRpcCodeInternalError  = -32603
+)
+
+// JsonRpcErrorCode the codes of the spec for the errors it defines, the err_no of the api for the others
+func JsonRpcErrorCode(errNo ApiCode) int {
+	switch errNo {
+	case ApiCodeParamsInvalid:
+		return JsonRpcCodeInvalidParams
+	case ApiCodeMethodNotExist:
+		return JsonRpcCodeMethodNotFound
+	case ApiCodeError500:
+		return JsonRpcCodeInternalError
+	}
+	return errNo
+}
+
 type JsonRpcMethod = string
 
 const (
+	MethodDiscover JsonRpcMethod = "rpc.discover"
+
 	MethodSnapshotPermissionsInfo JsonRpcMethod = "snapshot_permissions_info"
 	MethodSnapshotAddressAccounts JsonRpcMethod = "snapshot_address_accounts"
 	MethodSnapshotRegisterHistory JsonRpcMethod = "snapshot_register_history"
 	MethodSnapshotDidList         JsonRpcMethod = "snapshot_did_list"
 	MethodSnapshotVerify          JsonRpcMethod = "snapshot_verify"
+
+	MethodMarketVolume                JsonRpcMethod = "market_volume"
+	MethodMarketPriceStats            JsonRpcMethod = "market_price_stats"
+	MethodMarketTopSales              JsonRpcMethod = "market_top_sales"
+	MethodMarketAccountPriceHistory   JsonRpcMethod = "market_account_price_history"
+	MethodMarketOfferBook             JsonRpcMethod = "market_offer_book"
+	MethodTokenPriceHistory           JsonRpcMethod = "token_price_history"
+	MethodTokenList                   JsonRpcMethod = "token_list"
+	MethodAccountExpiring             JsonRpcMethod = "account_expiring"
+	MethodAccountGrace                JsonRpcMethod = "account_grace"
+	MethodAccountRecyclable           JsonRpcMethod = "account_recyclable"
+	MethodRecordsSearch               JsonRpcMethod = "records_search"
+	MethodRecordsHistory              JsonRpcMethod = "records_history"
+	MethodRecordsDiff                 JsonRpcMethod = "records_diff"
+	MethodOwnershipHistory            JsonRpcMethod = "ownership_history"
+	MethodAccountSearch               JsonRpcMethod = "account_search"
+	MethodAccountActivity             JsonRpcMethod = "account_activity"
+	MethodAddressActivity             JsonRpcMethod = "address_activity"
+	MethodRebateList                  JsonRpcMethod = "rebate_list"
+	MethodRebateSummary               JsonRpcMethod = "rebate_summary"
+	MethodRebateLeaderboard           JsonRpcMethod = "rebate_leaderboard"
+	MethodSubAccountMintStatement     JsonRpcMethod = "sub_account_mint_statement"
+	MethodSubAccountMintStatementList JsonRpcMethod = "sub_account_mint_statement_list"
+	MethodSubAccountRules             JsonRpcMethod = "sub_account_rules"
+	MethodSubAccountQuote             JsonRpcMethod = "sub_account_quote"
+	MethodSubAccountList              JsonRpcMethod = "sub_account_list"
+	MethodSubAccountStats             JsonRpcMethod = "sub_account_stats"
+	MethodSubAccountConfig            JsonRpcMethod = "sub_account_config"
+	MethodSubAccountProfit            JsonRpcMethod = "sub_account_profit"
+	MethodApprovalPending             JsonRpcMethod = "approval_pending"
+	MethodApprovalHistory             JsonRpcMethod = "approval_history"
+	MethodDidCellInfo                 JsonRpcMethod = "did_cell_info"
+	MethodDidCellList                 JsonRpcMethod = "did_cell_list"
+	MethodCrossChainLocked            JsonRpcMethod = "cross_chain_locked"
+	MethodCrossChainReport            JsonRpcMethod = "cross_chain_report"
+	MethodDeviceKeyList               JsonRpcMethod = "device_key_list"
+	MethodDeviceKeyAuthorize          JsonRpcMethod = "device_key_authorize"
+	MethodDeviceKeyHistory            JsonRpcMethod = "device_key_history"
+	MethodAccountInfo                 JsonRpcMethod = "account_info"
+	MethodAccountRecords              JsonRpcMethod = "account_records"
+
+	MethodTokenUpdate JsonRpcMethod = "token_update"
+	MethodTokenStatus JsonRpcMethod = "token_status"
 )
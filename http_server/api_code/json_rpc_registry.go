@@ -0,0 +1,161 @@
+package api_code
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"time"
+)
+
+// JsonRpcFunc runs a method, the params are an array of one request
+type JsonRpcFunc func(p json.RawMessage, apiResp *http_api.ApiResp)
+
+// JsonRpcObserve is called after each call of a method which exists
+type JsonRpcObserve func(method string, apiResp *http_api.ApiResp, duration time.Duration)
+
+const JsonRpcMaxBatch = 100
+
+type jsonRpcMethod struct {
+	name   string
+	handle JsonRpcFunc
+	params interface{}
+	result interface{}
+}
+
+// JsonRpcRegistry dispatches the json-rpc 2.0 requests, single or batch, to the registered methods
+type JsonRpcRegistry struct {
+	methods map[string]jsonRpcMethod
+	names   []string
+	observe JsonRpcObserve
+}
+
+func NewJsonRpcRegistry(observe JsonRpcObserve) *JsonRpcRegistry {
+	return &JsonRpcRegistry{
+		methods: make(map[string]jsonRpcMethod),
+		observe: observe,
+	}
+}
+
+// Register params and result are zero values of the request and the data of the method, for rpc.discover
+func (r *JsonRpcRegistry) Register(name string, handle JsonRpcFunc, params, result interface{}) {
+	if _, ok := r.methods[name]; ok {
+		panic(fmt.Sprintf("method [%s] registered twice", name))
+	}
+	r.methods[name] = jsonRpcMethod{name: name, handle: handle, params: params, result: result}
+	r.names = append(r.names, name)
+}
+
+func errResponse(id json.RawMessage, code int, message string) *JsonResponse {
+	return &JsonResponse{
+		ID:      id,
+		JsonRpc: JsonRpcVersion,
+		Error:   &JsonError{Code: code, Message: message},
+	}
+}
+
+// Do the response of a body, a JsonResponse or a list of them for a batch,
+// false when there is nothing to send back as all the requests are notifications
+func (r *JsonRpcRegistry) Do(body []byte) (interface{}, bool) {
+	body = bytes.TrimSpace(body)
+	if !json.Valid(body) {
+		return errResponse(nil, JsonRpcCodeParseError, "parse error"), true
+	}
+	if len(body) == 0 || body[0] != '[' {
+		resp := r.call(body)
+		return resp, resp != nil
+	}
+
+	var list []json.RawMessage
+	if err := json.Unmarshal(body, &list); err != nil {
+		return errResponse(nil, JsonRpcCodeParseError, "parse error"), true
+	}
+	if len(list) == 0 {
+		return errResponse(nil, JsonRpcCodeInvalidRequest, "empty batch"), true
+	}
+	if len(list) > JsonRpcMaxBatch {
+		return errResponse(nil, JsonRpcCodeInvalidRequest, fmt.Sprintf("batch of more than %d requests", JsonRpcMaxBatch)), true
+	}
+	res := make([]*JsonResponse, 0, len(list))
+	for _, v := range list {
+		if resp := r.call(v); resp != nil {
+			res = append(res, resp)
+		}
+	}
+	return res, len(res) > 0
+}
+
+// validId a string, a number or null
+func validId(id json.RawMessage) bool {
+	if len(id) == 0 {
+		return true
+	}
+	switch c := id[0]; {
+	case c == '"', c == '-', c == 'n', c >= '0' && c <= '9':
+		return true
+	}
+	return false
+}
+
+// call nil for a notification
+func (r *JsonRpcRegistry) call(raw json.RawMessage) *JsonResponse {
+	var req JsonRequest
+	if err := json.Unmarshal(raw, &req); err != nil || !validId(req.ID) {
+		return errResponse(nil, JsonRpcCodeInvalidRequest, "invalid request")
+	}
+	if req.JsonRpc != JsonRpcVersion || req.Method == "" {
+		return errResponse(req.ID, JsonRpcCodeInvalidRequest, "invalid request")
+	}
+	notification := len(req.ID) == 0
+	log.Info("JsonRpcCall:", req.Method, string(req.ID), string(req.Params))
+
+	resp := r.callMethod(req)
+	if notification {
+		return nil
+	}
+	resp.ID = req.ID
+	return resp
+}
+
+func (r *JsonRpcRegistry) callMethod(req JsonRequest) (resp *JsonResponse) {
+	if req.Method == MethodDiscover {
+		return &JsonResponse{JsonRpc: JsonRpcVersion, Result: r.discover()}
+	}
+	m, ok := r.methods[req.Method]
+	if !ok {
+		log.Warn("method not exist:", req.Method)
+		return errResponse(nil, JsonRpcCodeMethodNotFound, fmt.Sprintf("method [%s] not exist", req.Method))
+	}
+
+	// by-position params are an array of one request, by-name params are that request
+	params := bytes.TrimSpace(req.Params)
+	switch {
+	case len(params) == 0 || bytes.Equal(params, []byte("null")):
+		params = []byte("[{}]")
+	case params[0] == '{':
+		params = append(append([]byte("["), params...), ']')
+	case params[0] != '[':
+		return errResponse(nil, JsonRpcCodeInvalidParams, "params invalid")
+	}
+
+	var apiResp http_api.ApiResp
+	start := time.Now()
+	defer func() {
+		if err := recover(); err != nil {
+			log.Error("JsonRpcCall panic:", req.Method, err)
+			apiResp.ApiRespErr(http_api.ApiCodeError500, "internal error")
+			resp = errResponse(nil, JsonRpcCodeInternalError, "internal error")
+		}
+		if r.observe != nil {
+			r.observe(req.Method, &apiResp, time.Since(start))
+		}
+	}()
+	m.handle(params, &apiResp)
+
+	if apiResp.ErrNo != http_api.ApiCodeSuccess {
+		resp = errResponse(nil, JsonRpcErrorCode(apiResp.ErrNo), apiResp.ErrMsg)
+		resp.Error.Data = &JsonErrorData{ErrNo: apiResp.ErrNo}
+		return resp
+	}
+	return &JsonResponse{JsonRpc: JsonRpcVersion, Result: apiResp.Data}
+}
@@ -0,0 +1,139 @@
+package api_code
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// the rpc.discover result follows the OpenRPC document, with the schemas built from the json tags
+// of the requests and the results
+
+type OpenRpcDoc struct {
+	OpenRpc string          `json:"openrpc"`
+	Info    OpenRpcInfo     `json:"info"`
+	Methods []OpenRpcMethod `json:"methods"`
+}
+
+type OpenRpcInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type OpenRpcMethod struct {
+	Name           string           `json:"name"`
+	ParamStructure string           `json:"paramStructure"`
+	Params         []OpenRpcContent `json:"params"`
+	Result         OpenRpcContent   `json:"result"`
+}
+
+type OpenRpcContent struct {
+	Name   string     `json:"name"`
+	Schema JsonSchema `json:"schema"`
+}
+
+type JsonSchema map[string]interface{}
+
+func (r *JsonRpcRegistry) discover() OpenRpcDoc {
+	doc := OpenRpcDoc{
+		OpenRpc: "1.2.6",
+		Info:    OpenRpcInfo{Title: "das-database", Version: "1.0.0"},
+		Methods: make([]OpenRpcMethod, 0, len(r.names)),
+	}
+	for _, name := range r.names {
+		m := r.methods[name]
+		doc.Methods = append(doc.Methods, OpenRpcMethod{
+			Name:           m.name,
+			ParamStructure: "either",
+			Params:         []OpenRpcContent{{Name: "req", Schema: NewJsonSchema(m.params)}},
+			Result:         OpenRpcContent{Name: "result", Schema: NewJsonSchema(m.result)},
+		})
+	}
+	return doc
+}
+
+var (
+	typeTime      = reflect.TypeOf(time.Time{})
+	typeMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// NewJsonSchema the schema of the json encoding of v
+func NewJsonSchema(v interface{}) JsonSchema {
+	if v == nil {
+		return JsonSchema{}
+	}
+	return typeSchema(reflect.TypeOf(v), make(map[reflect.Type]bool))
+}
+
+func typeSchema(t reflect.Type, seen map[reflect.Type]bool) JsonSchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == typeTime {
+		return JsonSchema{"type": "string", "format": "date-time"}
+	}
+	// custom encodings, e.g. decimals as strings, are left open
+	if t.Implements(typeMarshaler) || reflect.PtrTo(t).Implements(typeMarshaler) {
+		return JsonSchema{}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return JsonSchema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return JsonSchema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return JsonSchema{"type": "number"}
+	case reflect.String:
+		return JsonSchema{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return JsonSchema{"type": "string"}
+		}
+		return JsonSchema{"type": "array", "items": typeSchema(t.Elem(), seen)}
+	case reflect.Map:
+		return JsonSchema{"type": "object", "additionalProperties": typeSchema(t.Elem(), seen)}
+	case reflect.Struct:
+		if seen[t] {
+			return JsonSchema{}
+		}
+		seen[t] = true
+		defer delete(seen, t)
+		props := make(map[string]JsonSchema)
+		structProperties(t, seen, props)
+		return JsonSchema{"type": "object", "properties": props}
+	}
+	return JsonSchema{}
+}
+
+// structProperties the fields of the embedded structs are promoted as encoding/json does
+func structProperties(t reflect.Type, seen map[reflect.Type]bool, props map[string]JsonSchema) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			structProperties(ft, seen, props)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if strings.Contains(opts, "string") {
+			props[name] = JsonSchema{"type": "string"}
+			continue
+		}
+		props[name] = typeSchema(f.Type, seen)
+	}
+}
@@ -0,0 +1,119 @@
+package api_code
+
+import (
+	"encoding/json"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"testing"
+	"time"
+)
+
+type pageEcho struct {
+	Size int `json:"size"`
+}
+
+type reqEcho struct {
+	pageEcho
+	Account string `json:"account"`
+}
+
+type respEcho struct {
+	Account string    `json:"account"`
+	List    []string  `json:"list"`
+	Time    time.Time `json:"time"`
+}
+
+func newTestRegistry(calls *[]string) *JsonRpcRegistry {
+	r := NewJsonRpcRegistry(func(method string, apiResp *http_api.ApiResp, duration time.Duration) {
+		*calls = append(*calls, method)
+	})
+	r.Register("echo", func(p json.RawMessage, apiResp *http_api.ApiResp) {
+		var req []reqEcho
+		if err := json.Unmarshal(p, &req); err != nil || len(req) != 1 {
+			apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+			return
+		}
+		if req[0].Account == "" {
+			apiResp.ApiRespErr(ApiCodeAccountExpired, "Account expired")
+			return
+		}
+		apiResp.ApiRespOK(respEcho{Account: req[0].Account})
+	}, reqEcho{}, respEcho{})
+	return r
+}
+
+func doJson(t *testing.T, r *JsonRpcRegistry, body string) (string, bool) {
+	resp, ok := r.Do([]byte(body))
+	if !ok {
+		return "", false
+	}
+	bys, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(bys), true
+}
+
+func TestJsonRpcRegistry(t *testing.T) {
+	var calls []string
+	r := newTestRegistry(&calls)
+
+	for _, v := range []struct {
+		body string
+		resp string
+	}{
+		{`{"jsonrpc":"2.0","id":1,"method":"echo","params":[{"account":"a.bit"}]}`,
+			`{"jsonrpc":"2.0","id":1,"result":{"account":"a.bit","list":null,"time":"0001-01-01T00:00:00Z"}}`},
+		{`{"jsonrpc":"2.0","id":"x","method":"echo","params":{"account":"a.bit"}}`,
+			`{"jsonrpc":"2.0","id":"x","result":{"account":"a.bit","list":null,"time":"0001-01-01T00:00:00Z"}}`},
+		{`{"jsonrpc":"2.0","id":2,"method":"echo","params":[]}`,
+			`{"jsonrpc":"2.0","id":2,"error":{"code":-32602,"message":"params invalid","data":{"err_no":10000}}}`},
+		{`{"jsonrpc":"2.0","id":3,"method":"echo"}`,
+			`{"jsonrpc":"2.0","id":3,"error":{"code":20023,"message":"Account expired","data":{"err_no":20023}}}`},
+		{`{"jsonrpc":"2.0","id":4,"method":"none"}`,
+			`{"jsonrpc":"2.0","id":4,"error":{"code":-32601,"message":"method [none] not exist"}}`},
+		{`{"jsonrpc":"1.0","id":5,"method":"echo"}`,
+			`{"jsonrpc":"2.0","id":5,"error":{"code":-32600,"message":"invalid request"}}`},
+		{`{"jsonrpc":"2.0","id":{},"method":"echo"}`,
+			`{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"invalid request"}}`},
+		{`{"jsonrpc":"2.0","method":"echo"`,
+			`{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"parse error"}}`},
+		{`[]`,
+			`{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"empty batch"}}`},
+		{`[1,{"jsonrpc":"2.0","method":"echo","params":[{"account":"a.bit"}]},{"jsonrpc":"2.0","id":6,"method":"echo","params":[{"account":"b.bit"}]}]`,
+			`[{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"invalid request"}},{"jsonrpc":"2.0","id":6,"result":{"account":"b.bit","list":null,"time":"0001-01-01T00:00:00Z"}}]`},
+	} {
+		resp, ok := doJson(t, r, v.body)
+		if !ok || resp != v.resp {
+			t.Fatal(v.body, resp)
+		}
+	}
+
+	// notifications run but get nothing back
+	calls = nil
+	if resp, ok := doJson(t, r, `[{"jsonrpc":"2.0","method":"echo","params":[{"account":"a.bit"}]},{"jsonrpc":"2.0","method":"none"}]`); ok {
+		t.Fatal(resp)
+	}
+	if len(calls) != 1 || calls[0] != "echo" {
+		t.Fatal(calls)
+	}
+}
+
+func TestJsonRpcDiscover(t *testing.T) {
+	var calls []string
+	r := newTestRegistry(&calls)
+
+	resp, ok := r.Do([]byte(`{"jsonrpc":"2.0","id":1,"method":"rpc.discover"}`))
+	if !ok {
+		t.Fatal("no response")
+	}
+	doc, ok := resp.(*JsonResponse).Result.(OpenRpcDoc)
+	if !ok || len(doc.Methods) != 1 || doc.Methods[0].Name != "echo" {
+		t.Fatal(resp)
+	}
+	bys, _ := json.Marshal(doc.Methods[0])
+	want := `{"name":"echo","paramStructure":"either","params":[{"name":"req","schema":{"properties":{"account":{"type":"string"},"size":{"type":"integer"}},"type":"object"}}],` +
+		`"result":{"name":"result","schema":{"properties":{"account":{"type":"string"},"list":{"items":{"type":"string"},"type":"array"},"time":{"format":"date-time","type":"string"}},"type":"object"}}}`
+	if string(bys) != want {
+		t.Fatal(string(bys))
+	}
+}
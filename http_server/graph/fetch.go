@@ -0,0 +1,198 @@
+package graph
+
+import (
+	"das_database/dao"
+	"fmt"
+)
+
+// the fetch funcs return a value for each key, an empty list for the list fields
+// and nothing for the object fields which are not found
+
+func errQuery(name string) error {
+	return fmt.Errorf("failed to query %s", name)
+}
+
+func (g *Graph) fetchAccounts(keys []string) (map[string]interface{}, error) {
+	list, err := g.dbDao.GetAccountListByAccountIds(keys)
+	if err != nil {
+		log.Error("GetAccountListByAccountIds err:", err.Error())
+		return nil, errQuery("accounts")
+	}
+	res := make(map[string]interface{})
+	for _, v := range list {
+		res[v.AccountId] = v
+	}
+	return res, nil
+}
+
+func (g *Graph) fetchRecords(keys []string) (map[string]interface{}, error) {
+	list, err := g.dbDao.GetRecordsByAccountIds(keys)
+	if err != nil {
+		log.Error("GetRecordsByAccountIds err:", err.Error())
+		return nil, errQuery("records")
+	}
+	group := make(map[string][]dao.TableRecordsInfo)
+	for _, k := range keys {
+		group[k] = make([]dao.TableRecordsInfo, 0)
+	}
+	for _, v := range list {
+		group[v.AccountId] = append(group[v.AccountId], v)
+	}
+	res := make(map[string]interface{})
+	for k, v := range group {
+		res[k] = v
+	}
+	return res, nil
+}
+
+func (g *Graph) fetchSubAccounts(limit, offset int) fetchFunc {
+	return func(keys []string) (map[string]interface{}, error) {
+		list, err := g.dbDao.GetSubAccountListByParentIds(keys, limit, offset)
+		if err != nil {
+			log.Error("GetSubAccountListByParentIds err:", err.Error())
+			return nil, errQuery("sub-accounts")
+		}
+		group := make(map[string][]dao.TableAccountInfo)
+		for _, k := range keys {
+			group[k] = make([]dao.TableAccountInfo, 0)
+		}
+		for _, v := range list {
+			group[v.ParentAccountId] = append(group[v.ParentAccountId], v)
+		}
+		res := make(map[string]interface{})
+		for k, v := range group {
+			res[k] = v
+		}
+		return res, nil
+	}
+}
+
+func (g *Graph) fetchSubAccountCounts(keys []string) (map[string]interface{}, error) {
+	list, err := g.dbDao.GetSubAccountCountByParentIds(keys)
+	if err != nil {
+		log.Error("GetSubAccountCountByParentIds err:", err.Error())
+		return nil, errQuery("sub-account count")
+	}
+	res := make(map[string]interface{})
+	for _, k := range keys {
+		res[k] = int64(0)
+	}
+	for _, v := range list {
+		res[v.ParentAccountId] = v.Count
+	}
+	return res, nil
+}
+
+func (g *Graph) fetchDidCells(keys []string) (map[string]interface{}, error) {
+	list, err := g.dbDao.GetDidCellListByAccountIds(keys)
+	if err != nil {
+		log.Error("GetDidCellListByAccountIds err:", err.Error())
+		return nil, errQuery("did cells")
+	}
+	res := make(map[string]interface{})
+	for _, v := range list {
+		res[v.AccountId] = v
+	}
+	return res, nil
+}
+
+func (g *Graph) fetchSales(keys []string) (map[string]interface{}, error) {
+	list, err := g.dbDao.GetTradeListByAccountIds(keys)
+	if err != nil {
+		log.Error("GetTradeListByAccountIds err:", err.Error())
+		return nil, errQuery("sales")
+	}
+	res := make(map[string]interface{})
+	for _, v := range list {
+		res[v.AccountId] = v
+	}
+	return res, nil
+}
+
+func (g *Graph) fetchOffers(limit, offset int) fetchFunc {
+	return func(keys []string) (map[string]interface{}, error) {
+		list, err := g.dbDao.GetOfferListByAccountIds(keys, limit, offset)
+		if err != nil {
+			log.Error("GetOfferListByAccountIds err:", err.Error())
+			return nil, errQuery("offers")
+		}
+		group := make(map[string][]dao.TableOfferInfo)
+		for _, k := range keys {
+			group[k] = make([]dao.TableOfferInfo, 0)
+		}
+		for _, v := range list {
+			group[v.AccountId] = append(group[v.AccountId], v)
+		}
+		res := make(map[string]interface{})
+		for k, v := range group {
+			res[k] = v
+		}
+		return res, nil
+	}
+}
+
+func (g *Graph) fetchDeals(limit, offset int) fetchFunc {
+	return func(keys []string) (map[string]interface{}, error) {
+		list, err := g.dbDao.GetTradeDealListByAccountIds(keys, limit, offset)
+		if err != nil {
+			log.Error("GetTradeDealListByAccountIds err:", err.Error())
+			return nil, errQuery("deals")
+		}
+		group := make(map[string][]dao.TableTradeDealInfo)
+		for _, k := range keys {
+			group[k] = make([]dao.TableTradeDealInfo, 0)
+		}
+		for _, v := range list {
+			group[v.AccountId] = append(group[v.AccountId], v)
+		}
+		res := make(map[string]interface{})
+		for k, v := range group {
+			res[k] = v
+		}
+		return res, nil
+	}
+}
+
+func (g *Graph) fetchReverseHolders(limit, offset int) fetchFunc {
+	return func(keys []string) (map[string]interface{}, error) {
+		list, err := g.dbDao.GetReverseListByAccountIds(keys, limit, offset)
+		if err != nil {
+			log.Error("GetReverseListByAccountIds err:", err.Error())
+			return nil, errQuery("reverse records")
+		}
+		group := make(map[string][]dao.TableReverseInfo)
+		for _, k := range keys {
+			group[k] = make([]dao.TableReverseInfo, 0)
+		}
+		for _, v := range list {
+			group[v.AccountId] = append(group[v.AccountId], v)
+		}
+		res := make(map[string]interface{})
+		for k, v := range group {
+			res[k] = v
+		}
+		return res, nil
+	}
+}
+
+func (g *Graph) fetchTransactions(limit, offset int) fetchFunc {
+	return func(keys []string) (map[string]interface{}, error) {
+		list, err := g.dbDao.GetTransactionListByAccountIds(keys, limit, offset)
+		if err != nil {
+			log.Error("GetTransactionListByAccountIds err:", err.Error())
+			return nil, errQuery("transactions")
+		}
+		group := make(map[string][]dao.TableTransactionInfo)
+		for _, k := range keys {
+			group[k] = make([]dao.TableTransactionInfo, 0)
+		}
+		for _, v := range list {
+			group[v.AccountId] = append(group[v.AccountId], v)
+		}
+		res := make(map[string]interface{})
+		for k, v := range group {
+			res[k] = v
+		}
+		return res, nil
+	}
+}
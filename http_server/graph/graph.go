@@ -0,0 +1,91 @@
+package graph
+
+import (
+	"context"
+	"das_database/dao"
+	"das_database/normalize"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/core"
+	"github.com/dotbitHQ/das-lib/http_api/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"net/http"
+)
+
+var (
+	log = logger.NewLogger("graph", logger.LevelDebug)
+)
+
+// Graph the graphql api over the indexed data, beside the json-rpc and the v1 routes
+type Graph struct {
+	dbDao  *dao.DbDao
+	an     *normalize.AddressNormalizer
+	schema graphql.Schema
+}
+
+type GraphParams struct {
+	DbDao   *dao.DbDao
+	DasCore *core.DasCore
+}
+
+func Initialize(p GraphParams) (*Graph, error) {
+	g := Graph{
+		dbDao: p.DbDao,
+		an:    normalize.NewAddressNormalizer(p.DasCore.NetType()),
+	}
+	schema, err := g.newSchema()
+	if err != nil {
+		return nil, fmt.Errorf("newSchema err: %s", err.Error())
+	}
+	g.schema = schema
+	return &g, nil
+}
+
+type ReqGraphQL struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+func (g *Graph) GraphQL(ctx *gin.Context) {
+	var (
+		req      ReqGraphQL
+		clientIp = fmt.Sprintf("%v", ctx.Request.Header.Get("X-Real-IP"))
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), clientIp)
+		ctx.JSON(http.StatusOK, graphql.Result{Errors: []gqlerrors.FormattedError{{Message: "params invalid"}}})
+		return
+	}
+	log.Info("GraphQL:", clientIp, req.OperationName)
+
+	ctx.JSON(http.StatusOK, g.Do(ctx.Request.Context(), req))
+}
+
+// Do runs a query with the loaders of the request, a query over the depth or the cost limit is rejected before it runs
+func (g *Graph) Do(ctx context.Context, req ReqGraphQL) *graphql.Result {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(req.Query),
+		Name: "GraphQL request",
+	})})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+	if res := graphql.ValidateDocument(&g.schema, doc, nil); !res.IsValid {
+		return &graphql.Result{Errors: res.Errors}
+	}
+	if err := checkQueryCost(&g.schema, doc, req.OperationName, req.Variables); err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        g.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       context.WithValue(ctx, loadersKey{}, loaders{}),
+	})
+}
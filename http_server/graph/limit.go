@@ -0,0 +1,161 @@
+package graph
+
+import (
+	"fmt"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxDepth = 8
+	maxCost  = 5000
+)
+
+// queryCost the depth and the cost of a query before it runs. A field costs 1 and the fields below
+// a list are counted once for each item of its page, the page size of the first argument, the size
+// of the accounts argument or the default page size. The introspection fields are not counted
+type queryCost struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+	schema    *graphql.Schema
+	spreads   map[string][2]int
+}
+
+func checkQueryCost(schema *graphql.Schema, doc *ast.Document, operationName string, variables map[string]interface{}) error {
+	q := queryCost{
+		fragments: make(map[string]*ast.FragmentDefinition),
+		variables: variables,
+		schema:    schema,
+		spreads:   make(map[string][2]int),
+	}
+	var op *ast.OperationDefinition
+	for _, v := range doc.Definitions {
+		switch d := v.(type) {
+		case *ast.FragmentDefinition:
+			q.fragments[d.Name.Value] = d
+		case *ast.OperationDefinition:
+			if op == nil && (operationName == "" || (d.Name != nil && d.Name.Value == operationName)) {
+				op = d
+			}
+		}
+	}
+	if op == nil {
+		return nil
+	}
+
+	depth, cost := q.selectionSet(schema.QueryType(), op.SelectionSet)
+	if depth > maxDepth {
+		return fmt.Errorf("query depth %d exceeds %d", depth, maxDepth)
+	}
+	if cost > maxCost {
+		return fmt.Errorf("query cost exceeds %d", maxCost)
+	}
+	return nil
+}
+
+func (q *queryCost) selectionSet(parent *graphql.Object, set *ast.SelectionSet) (depth, cost int) {
+	if parent == nil || set == nil {
+		return
+	}
+	for _, s := range set.Selections {
+		d, c := 0, 0
+		switch v := s.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(v.Name.Value, "__") {
+				continue
+			}
+			def, ok := parent.Fields()[v.Name.Value]
+			if !ok {
+				continue
+			}
+			obj, _ := graphql.GetNamed(def.Type).(*graphql.Object)
+			d, c = q.selectionSet(obj, v.SelectionSet)
+			d, c = d+1, 1+q.pageSize(def, v)*c
+		case *ast.InlineFragment:
+			obj := parent
+			if v.TypeCondition != nil {
+				obj, _ = q.schema.Type(v.TypeCondition.Name.Value).(*graphql.Object)
+			}
+			d, c = q.selectionSet(obj, v.SelectionSet)
+		case *ast.FragmentSpread:
+			d, c = q.fragmentSpread(v.Name.Value)
+		}
+		if d > depth {
+			depth = d
+		}
+		// the cost only grows, stop counting once it is over the limit
+		if cost += c; cost > maxCost {
+			return depth, maxCost + 1
+		}
+	}
+	return
+}
+
+// fragmentSpread the depth and the cost of a fragment, counted once however often it is spread
+func (q *queryCost) fragmentSpread(name string) (depth, cost int) {
+	if v, ok := q.spreads[name]; ok {
+		return v[0], v[1]
+	}
+	// the cycles of fragments are rejected by the validation
+	f, ok := q.fragments[name]
+	if !ok || f.TypeCondition == nil {
+		return
+	}
+	obj, _ := q.schema.Type(f.TypeCondition.Name.Value).(*graphql.Object)
+	depth, cost = q.selectionSet(obj, f.SelectionSet)
+	q.spreads[name] = [2]int{depth, cost}
+	return
+}
+
+// pageSize the items of a list field as resolved by getPage, 1 for the other fields
+func (q *queryCost) pageSize(def *graphql.FieldDefinition, field *ast.Field) int {
+	t := def.Type
+	if nonNull, ok := t.(*graphql.NonNull); ok {
+		t = nonNull.OfType
+	}
+	if _, ok := t.(*graphql.List); !ok {
+		return 1
+	}
+	for _, a := range field.Arguments {
+		switch a.Name.Value {
+		case "first":
+			if n := q.intValue(a.Value); n >= 1 && n <= maxFirst {
+				return n
+			}
+			return maxFirst
+		case "accounts":
+			return q.listLen(a.Value)
+		}
+	}
+	return defaultFirst
+}
+
+func (q *queryCost) intValue(v ast.Value) int {
+	switch x := v.(type) {
+	case *ast.IntValue:
+		n, _ := strconv.Atoi(x.Value)
+		return n
+	case *ast.Variable:
+		switch n := q.variables[x.Name.Value].(type) {
+		case int:
+			return n
+		case float64:
+			return int(n)
+		}
+	}
+	return 0
+}
+
+func (q *queryCost) listLen(v ast.Value) int {
+	switch x := v.(type) {
+	case *ast.ListValue:
+		return len(x.Values)
+	case *ast.Variable:
+		if list, ok := q.variables[x.Name.Value].([]interface{}); ok {
+			return len(list)
+		}
+	}
+	return 1
+}
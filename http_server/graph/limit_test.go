@@ -0,0 +1,47 @@
+package graph
+
+import (
+	"context"
+	"das_database/normalize"
+	"github.com/dotbitHQ/das-lib/common"
+	"strings"
+	"testing"
+)
+
+func TestQueryCost(t *testing.T) {
+	g := Graph{an: normalize.NewAddressNormalizer(common.DasNetTypeTestnet2)}
+	schema, err := g.newSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.schema = schema
+
+	list := []struct {
+		query     string
+		variables map[string]interface{}
+		err       string
+	}{
+		{`{__schema{types{name fields{name type{name ofType{name ofType{name ofType{name ofType{name}}}}}}}}}`, nil, ""},
+		// 1+1+100*(1+1+20*2)
+		{`{account(account:"a.bit"){account sub_accounts(first:100){account records{key value}}}}`, nil, ""},
+		{`{account(account:"a.bit"){sub_accounts(first:100){sub_accounts(first:100){account}}}}`, nil, "query cost exceeds"},
+		{`query q($n:Int){account(account:"a.bit"){sub_accounts(first:$n){sub_accounts(first:$n){account}}}}`, map[string]interface{}{"n": float64(10)}, ""},
+		{`{accounts(accounts:["a.bit","b.bit"]){deals(first:100){outpoint price_ckb}}}`, nil, ""},
+		{`{account(account:"a.bit"){...f sub_accounts{...f}} } fragment f on Account{offers(first:100){account}}`, nil, ""},
+		{`{account(account:"a.bit"){parent{parent{parent{parent{parent{parent{parent{parent{account}}}}}}}}}}`, nil, "query depth 10 exceeds"},
+	}
+	for _, v := range list {
+		res := g.Do(context.Background(), ReqGraphQL{Query: v.query, Variables: v.variables})
+		if v.err == "" {
+			for _, e := range res.Errors {
+				if strings.HasPrefix(e.Message, "query ") {
+					t.Fatal(v.query, e.Message)
+				}
+			}
+			continue
+		}
+		if len(res.Errors) != 1 || !strings.HasPrefix(res.Errors[0].Message, v.err) {
+			t.Fatal(v.query, res.Errors)
+		}
+	}
+}
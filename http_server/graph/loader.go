@@ -0,0 +1,82 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+)
+
+type fetchFunc func(keys []string) (map[string]interface{}, error)
+
+// loader batches the keys of a nested field over all the parents of a level.
+// The resolvers only queue their keys and return thunks, the executor calls the thunks
+// of a level after all of its resolvers, so the first thunk fetches the queued keys with one query
+type loader struct {
+	fetch  fetchFunc
+	queue  []string
+	queued map[string]struct{}
+	loaded map[string]interface{}
+	errs   map[string]error
+}
+
+func newLoader(fetch fetchFunc) *loader {
+	return &loader{
+		fetch:  fetch,
+		queued: make(map[string]struct{}),
+		loaded: make(map[string]interface{}),
+		errs:   make(map[string]error),
+	}
+}
+
+func (l *loader) load(key string) func() (interface{}, error) {
+	_, loaded := l.loaded[key]
+	_, queued := l.queued[key]
+	if !loaded && !queued {
+		l.queue = append(l.queue, key)
+		l.queued[key] = struct{}{}
+	}
+	return func() (interface{}, error) {
+		// the keys queued by the children of the loaded parents wait for the next level
+		if _, ok := l.queued[key]; ok {
+			l.flush()
+		}
+		if err, ok := l.errs[key]; ok {
+			return nil, err
+		}
+		return l.loaded[key], nil
+	}
+}
+
+func (l *loader) flush() {
+	keys := l.queue
+	l.queue = nil
+	res, err := l.fetch(keys)
+	for _, k := range keys {
+		delete(l.queued, k)
+		if err != nil {
+			l.errs[k] = err
+			continue
+		}
+		l.loaded[k] = res[k]
+	}
+}
+
+// loaders the loaders of a request, by the field and its arguments
+type loaders map[string]*loader
+
+type loadersKey struct{}
+
+func (l loaders) get(fetch fetchFunc, name string, args ...interface{}) *loader {
+	key := name + fmt.Sprint(args...)
+	if v, ok := l[key]; ok {
+		return v
+	}
+	l[key] = newLoader(fetch)
+	return l[key]
+}
+
+func getLoaders(ctx context.Context) loaders {
+	if l, ok := ctx.Value(loadersKey{}).(loaders); ok {
+		return l
+	}
+	return loaders{}
+}
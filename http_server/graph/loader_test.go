@@ -0,0 +1,99 @@
+package graph
+
+import (
+	"context"
+	"das_database/normalize"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/graphql-go/graphql"
+	"testing"
+)
+
+func TestLoader(t *testing.T) {
+	var fetched [][]string
+	fetch := func(keys []string) (map[string]interface{}, error) {
+		fetched = append(fetched, keys)
+		res := make(map[string]interface{})
+		for _, k := range keys {
+			res[k] = []string{k + ".a", k + ".b"}
+		}
+		return res, nil
+	}
+
+	var nodeType *graphql.Object
+	nodeType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Node",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Source, nil
+					},
+				},
+				"children": &graphql.Field{
+					Type: graphql.NewList(nodeType),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return getLoaders(p.Context).get(fetch, "children").load(p.Source.(string)), nil
+					},
+				},
+			}
+		}),
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"nodes": &graphql.Field{
+				Type: graphql.NewList(nodeType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return []string{"x", "y", "z"}, nil
+				},
+			},
+		},
+	})})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: "{nodes{id children{id children{id}}}}",
+		Context:       context.WithValue(context.Background(), loadersKey{}, loaders{}),
+	})
+	if len(res.Errors) > 0 {
+		t.Fatal(res.Errors)
+	}
+	// one query for each level
+	if len(fetched) != 2 || len(fetched[0]) != 3 || len(fetched[1]) != 6 {
+		t.Fatal(fetched)
+	}
+	nodes := res.Data.(map[string]interface{})["nodes"].([]interface{})
+	children := nodes[2].(map[string]interface{})["children"].([]interface{})
+	if children[1].(map[string]interface{})["id"] != "z.b" {
+		t.Fatal(children)
+	}
+}
+
+func TestSchema(t *testing.T) {
+	g := Graph{an: normalize.NewAddressNormalizer(common.DasNetTypeTestnet2)}
+	schema, err := g.newSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{__type(name:"Account"){fields{name}}}`,
+	})
+	if len(res.Errors) > 0 {
+		t.Fatal(res.Errors)
+	}
+	fields := res.Data.(map[string]interface{})["__type"].(map[string]interface{})["fields"].([]interface{})
+	names := make(map[string]bool)
+	for _, v := range fields {
+		names[v.(map[string]interface{})["name"].(string)] = true
+	}
+	for _, v := range []string{"records", "sub_accounts", "did_cell", "sale", "offers", "deals", "reverse_holders", "transactions"} {
+		if !names[v] {
+			t.Fatal(v)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/shopspring/decimal"
+	"reflect"
+	"strconv"
+)
+
+// Long the integers of 64 bits, block numbers, timestamps in ms and capacities overflow Int
+var Long = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Long",
+	Description: "The `Long` scalar type represents whole numbers of 64 bits.",
+	Serialize:   coerceLong,
+	ParseValue:  coerceLong,
+	ParseLiteral: func(valueAST ast.Value) interface{} {
+		if v, ok := valueAST.(*ast.IntValue); ok {
+			if i, err := strconv.ParseInt(v.Value, 10, 64); err == nil {
+				return i
+			}
+		}
+		return nil
+	},
+})
+
+// coerceLong also takes the named integer types, as the chain types and the algorithm ids
+func coerceLong(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint()
+	default:
+		return nil
+	}
+}
+
+// Decimal the prices in usd, as strings to keep the precision
+var Decimal = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Decimal",
+	Description: "The `Decimal` scalar type represents decimal numbers as strings.",
+	Serialize: func(value interface{}) interface{} {
+		switch v := value.(type) {
+		case decimal.Decimal:
+			return v.String()
+		case *decimal.Decimal:
+			if v == nil {
+				return nil
+			}
+			return v.String()
+		default:
+			return nil
+		}
+	},
+	ParseValue: func(value interface{}) interface{} {
+		if s, ok := value.(string); ok {
+			if d, err := decimal.NewFromString(s); err == nil {
+				return d
+			}
+		}
+		return nil
+	},
+	ParseLiteral: func(valueAST ast.Value) interface{} {
+		if v, ok := valueAST.(*ast.StringValue); ok {
+			if d, err := decimal.NewFromString(v.Value); err == nil {
+				return d
+			}
+		}
+		return nil
+	},
+})
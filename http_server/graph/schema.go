@@ -0,0 +1,405 @@
+package graph
+
+import (
+	"das_database/dao"
+	"das_database/normalize"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/graphql-go/graphql"
+	"github.com/nervosnetwork/ckb-sdk-go/types"
+)
+
+const (
+	defaultFirst = 20
+	maxFirst     = 100
+)
+
+var pageArgs = graphql.FieldConfigArgument{
+	"first": &graphql.ArgumentConfig{
+		Type:         graphql.Int,
+		DefaultValue: defaultFirst,
+		Description:  fmt.Sprintf("at most %d", maxFirst),
+	},
+	"offset": &graphql.ArgumentConfig{
+		Type:         graphql.Int,
+		DefaultValue: 0,
+	},
+}
+
+func getPage(p graphql.ResolveParams) (limit, offset int) {
+	limit, _ = p.Args["first"].(int)
+	offset, _ = p.Args["offset"].(int)
+	if limit < 1 || limit > maxFirst {
+		limit = maxFirst
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return
+}
+
+func nonNullList(t graphql.Type) graphql.Output {
+	return graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(t)))
+}
+
+func (g *Graph) newSchema() (graphql.Schema, error) {
+	addressType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Address",
+		Description: "hex is the form kept in the db, normal is the address of the chain",
+		Fields: graphql.Fields{
+			"chain_type":       &graphql.Field{Type: Long, Resolve: resolveAddress(func(a normalize.Address) interface{} { return a.ChainType })},
+			"algorithm_id":     &graphql.Field{Type: Long, Resolve: resolveAddress(func(a normalize.Address) interface{} { return a.AlgorithmId })},
+			"sub_algorithm_id": &graphql.Field{Type: Long, Resolve: resolveAddress(func(a normalize.Address) interface{} { return a.SubAlgorithmId })},
+			"hex":              &graphql.Field{Type: graphql.String, Resolve: resolveAddress(func(a normalize.Address) interface{} { return a.Hex })},
+			"normal": &graphql.Field{
+				Type: graphql.String,
+				Resolve: resolveAddress(func(a normalize.Address) interface{} {
+					addr, err := g.an.ToNormal(a)
+					if err != nil {
+						log.Warn("ToNormal err:", err.Error(), a.AlgorithmId, a.Hex)
+						return nil
+					}
+					return addr
+				}),
+			},
+		},
+	})
+
+	recordType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Record",
+		Fields: graphql.Fields{
+			"key":   &graphql.Field{Type: graphql.String},
+			"type":  &graphql.Field{Type: graphql.String},
+			"label": &graphql.Field{Type: graphql.String},
+			"value": &graphql.Field{Type: graphql.String},
+			"ttl":   &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	didCellType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "DidCell",
+		Fields: graphql.Fields{
+			"account":        &graphql.Field{Type: graphql.String},
+			"outpoint":       &graphql.Field{Type: graphql.String},
+			"block_number":   &graphql.Field{Type: Long},
+			"lock_code_hash": &graphql.Field{Type: graphql.String},
+			"args":           &graphql.Field{Type: graphql.String},
+			"expired_at":     &graphql.Field{Type: Long},
+			"owner": &graphql.Field{
+				Type:        graphql.String,
+				Description: "the ckb address of the lock",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					v := p.Source.(dao.TableDidCellInfo)
+					addr, err := g.an.AnyLock(&types.Script{
+						CodeHash: types.HexToHash(v.LockCodeHash),
+						HashType: types.HashTypeType,
+						Args:     common.Hex2Bytes(v.Args),
+					})
+					if err != nil {
+						log.Warn("AnyLock err:", err.Error(), v.Account)
+						return nil, nil
+					}
+					return addr.Hex, nil
+				},
+			},
+		},
+	})
+
+	transactionType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Transaction",
+		Fields: graphql.Fields{
+			"block_number":    &graphql.Field{Type: Long},
+			"block_timestamp": &graphql.Field{Type: Long, Description: "in ms"},
+			"outpoint":        &graphql.Field{Type: graphql.String},
+			"tx_hash": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					txHash, _ := common.String2OutPoint(p.Source.(dao.TableTransactionInfo).Outpoint)
+					return txHash, nil
+				},
+			},
+			"account":      &graphql.Field{Type: graphql.String},
+			"action":       &graphql.Field{Type: graphql.String},
+			"service_type": &graphql.Field{Type: Long},
+			"chain_type":   &graphql.Field{Type: Long},
+			"address":      &graphql.Field{Type: graphql.String},
+			"capacity":     &graphql.Field{Type: Long},
+			"status":       &graphql.Field{Type: Long},
+		},
+	})
+
+	var accountType *graphql.Object
+
+	tradeType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Trade",
+		Fields: graphql.Fields{
+			"account":      &graphql.Field{Type: graphql.String},
+			"outpoint":     &graphql.Field{Type: graphql.String},
+			"block_number": &graphql.Field{Type: Long},
+			"owner": &graphql.Field{
+				Type: addressType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					v := p.Source.(dao.TableTradeInfo)
+					return normalize.Address{ChainType: v.OwnerChainType, AlgorithmId: v.OwnerAlgorithmId, Hex: v.OwnerAddress}, nil
+				},
+			},
+			"description":     &graphql.Field{Type: graphql.String},
+			"started_at":      &graphql.Field{Type: Long},
+			"block_timestamp": &graphql.Field{Type: Long, Description: "in ms"},
+			"price_ckb":       &graphql.Field{Type: Long},
+			"price_usd":       &graphql.Field{Type: Decimal},
+			"profit_rate":     &graphql.Field{Type: Long},
+			"status":          &graphql.Field{Type: Long},
+		},
+	})
+
+	offerType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Offer",
+		Fields: graphql.Fields{
+			"account":      &graphql.Field{Type: graphql.String},
+			"outpoint":     &graphql.Field{Type: graphql.String},
+			"block_number": &graphql.Field{Type: Long},
+			"address": &graphql.Field{
+				Type: addressType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					v := p.Source.(dao.TableOfferInfo)
+					return normalize.Address{ChainType: v.ChainType, AlgorithmId: v.AlgorithmId, Hex: v.Address}, nil
+				},
+			},
+			"block_timestamp": &graphql.Field{Type: Long, Description: "in ms"},
+			"price":           &graphql.Field{Type: Long},
+			"price_usd":       &graphql.Field{Type: Decimal},
+			"message":         &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	dealType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Deal",
+		Description: "a sale, an auction or an accepted offer of the account",
+		Fields: graphql.Fields{
+			"account":         &graphql.Field{Type: graphql.String},
+			"outpoint":        &graphql.Field{Type: graphql.String},
+			"block_number":    &graphql.Field{Type: Long},
+			"block_timestamp": &graphql.Field{Type: Long, Description: "in ms"},
+			"deal_type":       &graphql.Field{Type: Long, Description: "0: sale 1: auction 2: offer"},
+			"sell_chain_type": &graphql.Field{Type: Long},
+			"sell_address":    &graphql.Field{Type: graphql.String},
+			"buy_chain_type":  &graphql.Field{Type: Long},
+			"buy_address":     &graphql.Field{Type: graphql.String},
+			"price_ckb":       &graphql.Field{Type: Long},
+			"price_usd":       &graphql.Field{Type: Decimal},
+		},
+	})
+
+	reverseType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Reverse",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"account":         &graphql.Field{Type: graphql.String},
+				"outpoint":        &graphql.Field{Type: graphql.String},
+				"block_number":    &graphql.Field{Type: Long},
+				"block_timestamp": &graphql.Field{Type: Long, Description: "in ms"},
+				"reverse_type":    &graphql.Field{Type: Long},
+				"address": &graphql.Field{
+					Type: addressType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						v := p.Source.(dao.TableReverseInfo)
+						return normalize.Address{ChainType: v.ChainType, AlgorithmId: v.AlgorithmId, Hex: v.Address}, nil
+					},
+				},
+				"account_info": &graphql.Field{
+					Type: accountType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						v := p.Source.(dao.TableReverseInfo)
+						return getLoaders(p.Context).get(g.fetchAccounts, "account").load(v.AccountId), nil
+					},
+				},
+			}
+		}),
+	})
+
+	accountType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Account",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"account_id":        &graphql.Field{Type: graphql.String},
+				"account":           &graphql.Field{Type: graphql.String},
+				"parent_account_id": &graphql.Field{Type: graphql.String},
+				"outpoint":          &graphql.Field{Type: graphql.String},
+				"block_number":      &graphql.Field{Type: Long},
+				"status":            &graphql.Field{Type: Long},
+				"registered_at":     &graphql.Field{Type: Long},
+				"expired_at":        &graphql.Field{Type: Long},
+				"owner": &graphql.Field{
+					Type: addressType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						v := p.Source.(dao.TableAccountInfo)
+						return normalize.Address{ChainType: v.OwnerChainType, AlgorithmId: v.OwnerAlgorithmId, SubAlgorithmId: v.OwnerSubAid, Hex: v.Owner}, nil
+					},
+				},
+				"manager": &graphql.Field{
+					Type: addressType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						v := p.Source.(dao.TableAccountInfo)
+						return normalize.Address{ChainType: v.ManagerChainType, AlgorithmId: v.ManagerAlgorithmId, SubAlgorithmId: v.ManagerSubAid, Hex: v.Manager}, nil
+					},
+				},
+				"parent": &graphql.Field{
+					Type:        accountType,
+					Description: "null for a top level account",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						v := p.Source.(dao.TableAccountInfo)
+						if v.ParentAccountId == "" {
+							return nil, nil
+						}
+						return getLoaders(p.Context).get(g.fetchAccounts, "account").load(v.ParentAccountId), nil
+					},
+				},
+				"records": &graphql.Field{
+					Type: nonNullList(recordType),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						v := p.Source.(dao.TableAccountInfo)
+						return getLoaders(p.Context).get(g.fetchRecords, "records").load(v.AccountId), nil
+					},
+				},
+				"sub_accounts": &graphql.Field{
+					Type:        nonNullList(accountType),
+					Description: "ordered by account",
+					Args:        pageArgs,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						v := p.Source.(dao.TableAccountInfo)
+						limit, offset := getPage(p)
+						return getLoaders(p.Context).get(g.fetchSubAccounts(limit, offset), "sub_accounts", limit, offset).load(v.AccountId), nil
+					},
+				},
+				"sub_account_count": &graphql.Field{
+					Type: Long,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						v := p.Source.(dao.TableAccountInfo)
+						return getLoaders(p.Context).get(g.fetchSubAccountCounts, "sub_account_count").load(v.AccountId), nil
+					},
+				},
+				"did_cell": &graphql.Field{
+					Type:        didCellType,
+					Description: "null unless the account is upgraded to a did cell",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						v := p.Source.(dao.TableAccountInfo)
+						return getLoaders(p.Context).get(g.fetchDidCells, "did_cell").load(v.AccountId), nil
+					},
+				},
+				"sale": &graphql.Field{
+					Type:        tradeType,
+					Description: "null unless the account is on sale",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						v := p.Source.(dao.TableAccountInfo)
+						return getLoaders(p.Context).get(g.fetchSales, "sale").load(v.AccountId), nil
+					},
+				},
+				"offers": &graphql.Field{
+					Type:        nonNullList(offerType),
+					Description: "the highest price first",
+					Args:        pageArgs,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						v := p.Source.(dao.TableAccountInfo)
+						limit, offset := getPage(p)
+						return getLoaders(p.Context).get(g.fetchOffers(limit, offset), "offers", limit, offset).load(v.AccountId), nil
+					},
+				},
+				"deals": &graphql.Field{
+					Type:        nonNullList(dealType),
+					Description: "the deal history, the latest first",
+					Args:        pageArgs,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						v := p.Source.(dao.TableAccountInfo)
+						limit, offset := getPage(p)
+						return getLoaders(p.Context).get(g.fetchDeals(limit, offset), "deals", limit, offset).load(v.AccountId), nil
+					},
+				},
+				"reverse_holders": &graphql.Field{
+					Type:        nonNullList(reverseType),
+					Description: "the addresses which set the account as their reverse record, the latest first",
+					Args:        pageArgs,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						v := p.Source.(dao.TableAccountInfo)
+						limit, offset := getPage(p)
+						return getLoaders(p.Context).get(g.fetchReverseHolders(limit, offset), "reverse_holders", limit, offset).load(v.AccountId), nil
+					},
+				},
+				"transactions": &graphql.Field{
+					Type:        nonNullList(transactionType),
+					Description: "the latest first",
+					Args:        pageArgs,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						v := p.Source.(dao.TableAccountInfo)
+						limit, offset := getPage(p)
+						return getLoaders(p.Context).get(g.fetchTransactions(limit, offset), "transactions", limit, offset).load(v.AccountId), nil
+					},
+				},
+			}
+		}),
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"account": &graphql.Field{
+				Type: accountType,
+				Args: graphql.FieldConfigArgument{
+					"account": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					accountId := common.Bytes2Hex(common.GetAccountIdByAccount(p.Args["account"].(string)))
+					return getLoaders(p.Context).get(g.fetchAccounts, "account").load(accountId), nil
+				},
+			},
+			"accounts": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(accountType)),
+				Description: fmt.Sprintf("at most %d accounts, null for the accounts not found", maxFirst),
+				Args: graphql.FieldConfigArgument{
+					"accounts": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					accounts, _ := p.Args["accounts"].([]interface{})
+					if len(accounts) > maxFirst {
+						return nil, fmt.Errorf("more than %d accounts", maxFirst)
+					}
+					l := getLoaders(p.Context).get(g.fetchAccounts, "account")
+					list := make([]interface{}, 0, len(accounts))
+					for _, v := range accounts {
+						accountId := common.Bytes2Hex(common.GetAccountIdByAccount(v.(string)))
+						list = append(list, l.load(accountId))
+					}
+					return list, nil
+				},
+			},
+			"reverse": &graphql.Field{
+				Type:        nonNullList(reverseType),
+				Description: "the reverse records of an address, the latest first",
+				Args: graphql.FieldConfigArgument{
+					"chain_type": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					"address":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					addr, err := g.an.FromNormal(common.ChainType(p.Args["chain_type"].(int)), p.Args["address"].(string))
+					if err != nil {
+						return nil, fmt.Errorf("address invalid")
+					}
+					list, err := g.dbDao.GetReverseListByAddress(addr.ChainType, addr.Hex)
+					if err != nil {
+						log.Error("GetReverseListByAddress err:", err.Error())
+						return nil, errQuery("reverse records")
+					}
+					return list, nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
+
+func resolveAddress(field func(a normalize.Address) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return field(p.Source.(normalize.Address)), nil
+	}
+}
@@ -0,0 +1,229 @@
+package handle
+
+import (
+	"das_database/dao"
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+	"strings"
+)
+
+type ReqAccountActivity struct {
+	Pagination
+	Account string `json:"account"`
+	ActivityFilter
+}
+
+// ActivityFilter the time range is [start_time, end_time) in seconds
+type ActivityFilter struct {
+	Actions     []string `json:"actions"`
+	ServiceType int      `json:"service_type"`
+	StartTime   int64    `json:"start_time"`
+	EndTime     int64    `json:"end_time"`
+}
+
+type RespActivity struct {
+	Total      int64          `json:"total"`
+	NextCursor string         `json:"next_cursor"`
+	List       []ActivityData `json:"list"`
+}
+
+type ActivityData struct {
+	BlockNumber    uint64           `json:"block_number"`
+	BlockTimestamp uint64           `json:"block_timestamp"`
+	TxHash         string           `json:"tx_hash"`
+	ExplorerUrl    string           `json:"explorer_url"`
+	Account        string           `json:"account"`
+	Action         string           `json:"action"`
+	ActionLabel    string           `json:"action_label"`
+	ServiceType    int              `json:"service_type"`
+	ChainType      common.ChainType `json:"chain_type"`
+	Address        string           `json:"address"`
+	Capacity       uint64           `json:"capacity"`
+	Status         int              `json:"status"`
+}
+
+var activityActionLabels = map[string]string{
+	common.DasActionApplyRegister:            "Apply for registration",
+	common.DasActionPreRegister:              "Pre-register",
+	common.DasActionPropose:                  "Propose",
+	common.DasActionConfirmProposal:          "Register",
+	common.DasActionRenewAccount:             "Renew",
+	common.DasActionEditRecords:              "Edit records",
+	common.DasActionEditManager:              "Change manager",
+	common.DasActionTransferAccount:          "Transfer",
+	common.DasActionStartAccountSale:         "List for sale",
+	common.DasActionEditAccountSale:          "Edit sale",
+	common.DasActionCancelAccountSale:        "Cancel sale",
+	common.DasActionBuyAccount:               "Buy",
+	common.DasActionMakeOffer:                "Make offer",
+	common.DasActionCancelOffer:              "Cancel offer",
+	common.DasActionAcceptOffer:              "Accept offer",
+	common.DasActionRecycleExpiredAccount:    "Recycle",
+	common.DasActionBidExpiredAccountAuction: "Win expired account auction",
+	common.DasActionEnableSubAccount:         "Enable sub-account",
+	common.DasActionCreateSubAccount:         "Create sub-account",
+	common.DasActionEditSubAccount:           "Edit sub-account",
+	common.DasActionRenewSubAccount:          "Renew sub-account",
+	common.DasActionDeclareReverseRecord:     "Declare reverse record",
+	common.DasActionRedeclareReverseRecord:   "Redeclare reverse record",
+	common.DasActionRetractReverseRecord:     "Retract reverse record",
+	common.DasActionConsolidateIncome:        "Consolidate income",
+	common.DasActionFulfillApproval:          "Fulfill approval",
+	common.DidCellActionEditOwner:            "Transfer DID cell",
+	common.DidCellActionEditRecords:          "Edit DID cell records",
+	common.DidCellActionRenew:                "Renew DID cell",
+	common.DidCellActionRecycle:              "Recycle DID cell",
+	dao.DasActionTransferBalance:             "Transfer balance",
+	dao.DasActionSaleAccount:                 "Sold",
+	dao.DasActionOfferAccepted:               "Offer accepted",
+	dao.DasActionEditOfferAdd:                "Raise offer",
+	dao.DasActionEditOfferSub:                "Lower offer",
+	dao.DasActionOrderRefund:                 "Order refund",
+	dao.DasActionBalanceDeposit:              "Deposit balance",
+	dao.DasActionCrossRefund:                 "Cross-chain refund",
+}
+
+// activityActionLabel falls back to the action itself, e.g. config_sub_account -> Config sub account
+func activityActionLabel(action string) string {
+	if label, ok := activityActionLabels[action]; ok {
+		return label
+	}
+	label := strings.ReplaceAll(action, "_", " ")
+	if label == "" {
+		return label
+	}
+	return strings.ToUpper(label[:1]) + label[1:]
+}
+
+func txExplorerUrl(netType common.DasNetType, txHash string) string {
+	if netType == common.DasNetTypeMainNet {
+		return "https://explorer.nervos.org/transaction/" + txHash
+	}
+	return "https://pudge.explorer.nervos.org/transaction/" + txHash
+}
+
+func (h *HttpHandle) JsonRpcAccountActivity(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqAccountActivity
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doAccountActivity(&req[0], apiResp); err != nil {
+		log.Error("doAccountActivity err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) AccountActivity(ctx *gin.Context) {
+	var (
+		funcName = "AccountActivity"
+		req      ReqAccountActivity
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doAccountActivity(&req, &apiResp); err != nil {
+		log.Error("doAccountActivity err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doAccountActivity(req *ReqAccountActivity, apiResp *http_api.ApiResp) error {
+	account := strings.ToLower(strings.TrimSpace(req.Account))
+	if account == "" {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "account is empty")
+		return nil
+	}
+	filter, err := req.ActivityFilter.toDaoFilter()
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+	filter.AccountId = common.Bytes2Hex(common.GetAccountIdByAccount(account))
+
+	return h.doActivityList(filter, req.Pagination, apiResp)
+}
+
+func (f *ActivityFilter) toDaoFilter() (filter dao.ActivityFilter, err error) {
+	switch f.ServiceType {
+	case 0, dao.ServiceTypeRegister, dao.ServiceTypeTransaction, dao.ServiceTypeSubAccount:
+	default:
+		return filter, fmt.Errorf("service_type invalid")
+	}
+	if f.StartTime < 0 || f.EndTime < 0 || (f.EndTime > 0 && f.StartTime > f.EndTime) {
+		return filter, fmt.Errorf("time range invalid")
+	}
+	filter.Actions = f.Actions
+	filter.ServiceType = f.ServiceType
+	// block_timestamp is in milliseconds
+	filter.StartAt, filter.EndAt = uint64(f.StartTime*1e3), uint64(f.EndTime*1e3)
+	return filter, nil
+}
+
+func (h *HttpHandle) doActivityList(filter dao.ActivityFilter, page Pagination, apiResp *http_api.ApiResp) error {
+	var resp RespActivity
+	resp.List = make([]ActivityData, 0)
+
+	cursor, err := page.GetCursor()
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+
+	resp.Total, err = h.dbDao.GetActivityCount(filter)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query activity")
+		return fmt.Errorf("GetActivityCount err: %s", err.Error())
+	}
+	list, err := h.dbDao.GetActivityList(filter, cursor, page.GetLimit(), page.GetOffset())
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query activity")
+		return fmt.Errorf("GetActivityList err: %s", err.Error())
+	}
+	netType := h.dasCore.NetType()
+	for _, v := range list {
+		txHash, _ := common.String2OutPoint(v.Outpoint)
+		resp.List = append(resp.List, ActivityData{
+			BlockNumber:    v.BlockNumber,
+			BlockTimestamp: v.BlockTimestamp,
+			TxHash:         txHash,
+			ExplorerUrl:    txExplorerUrl(netType, txHash),
+			Account:        v.Account,
+			Action:         v.Action,
+			ActionLabel:    activityActionLabel(v.Action),
+			ServiceType:    v.ServiceType,
+			ChainType:      v.ChainType,
+			Address:        v.Address,
+			Capacity:       v.Capacity,
+			Status:         v.Status,
+		})
+	}
+	if len(list) > 0 {
+		last := list[len(list)-1]
+		resp.NextCursor = page.NextCursor(len(list), dao.Cursor{Key: last.BlockNumber, Id: last.Id})
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
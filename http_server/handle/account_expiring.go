@@ -0,0 +1,177 @@
+package handle
+
+import (
+	"das_database/dao"
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/core"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+	"time"
+)
+
+type ReqAccountExpiring struct {
+	Pagination
+	core.ChainTypeAddress
+	ParentAccount string `json:"parent_account"`
+	Days          uint64 `json:"days"`
+}
+
+type RespAccountExpiring struct {
+	Total      int64               `json:"total"`
+	NextCursor string              `json:"next_cursor"`
+	List       []AccountExpiryData `json:"list"`
+}
+
+type AccountExpiryData struct {
+	Account        string           `json:"account"`
+	OwnerChainType common.ChainType `json:"owner_chain_type"`
+	Owner          string           `json:"owner"`
+	Status         uint8            `json:"status"`
+	ExpiredAt      uint64           `json:"expired_at"`
+	GraceEndAt     uint64           `json:"grace_end_at"`
+}
+
+func (h *HttpHandle) JsonRpcAccountExpiring(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqAccountExpiring
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doAccountExpiring(&req[0], apiResp); err != nil {
+		log.Error("doAccountExpiring err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) AccountExpiring(ctx *gin.Context) {
+	var (
+		funcName = "AccountExpiring"
+		req      ReqAccountExpiring
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doAccountExpiring(&req, &apiResp); err != nil {
+		log.Error("doAccountExpiring err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doAccountExpiring(req *ReqAccountExpiring, apiResp *http_api.ApiResp) error {
+	if req.Days == 0 {
+		req.Days = 30
+	} else if req.Days > 365 {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "days should be in [1,365]")
+		return nil
+	}
+	filter, err := h.getExpiryFilter(req.ChainTypeAddress, req.ParentAccount)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return nil
+	}
+	now := uint64(time.Now().Unix())
+	filter.ExpiredFrom, filter.ExpiredTo = now, now+req.Days*86400
+
+	return h.doAccountExpiryList(filter, req.Pagination, apiResp)
+}
+
+// getExpiryFilter both the address and the parent account are optional
+func (h *HttpHandle) getExpiryFilter(chainTypeAddress core.ChainTypeAddress, parentAccount string) (filter dao.ExpiryFilter, err error) {
+	if chainTypeAddress.KeyInfo.Key != "" {
+		addrHex, err := h.an.FromChainTypeAddress(&chainTypeAddress)
+		if err != nil {
+			return filter, fmt.Errorf("FromChainTypeAddress err: %s", err.Error())
+		}
+		filter.OwnerChainType, filter.Owner = addrHex.ChainType, addrHex.Hex
+		filter.DidCellHolder = h.getDidCellHolder(chainTypeAddress)
+	}
+	if parentAccount != "" {
+		filter.ParentAccountId = common.Bytes2Hex(common.GetAccountIdByAccount(parentAccount))
+	}
+	return filter, nil
+}
+
+func (h *HttpHandle) getExpirationGracePeriod() (uint64, error) {
+	builder, err := h.dasCore.ConfigCellDataBuilderByTypeArgs(common.ConfigCellTypeArgsAccount)
+	if err != nil {
+		return 0, fmt.Errorf("ConfigCellDataBuilderByTypeArgs err: %s", err.Error())
+	}
+	gracePeriod, err := builder.ExpirationGracePeriod()
+	if err != nil {
+		return 0, fmt.Errorf("ExpirationGracePeriod err: %s", err.Error())
+	}
+	return uint64(gracePeriod), nil
+}
+
+func (h *HttpHandle) doAccountExpiryList(filter dao.ExpiryFilter, page Pagination, apiResp *http_api.ApiResp) error {
+	var resp RespAccountExpiring
+	resp.List = make([]AccountExpiryData, 0)
+
+	cursor, err := page.GetCursor()
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+	gracePeriod, err := h.getExpirationGracePeriod()
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeError500, "Failed to get grace period")
+		return fmt.Errorf("getExpirationGracePeriod err: %s", err.Error())
+	}
+
+	resp.Total, err = h.dbDao.GetAccountCountByExpiry(filter)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query account count")
+		return fmt.Errorf("GetAccountCountByExpiry err: %s", err.Error())
+	}
+	list, err := h.dbDao.GetAccountListByExpiry(filter, cursor, page.GetLimit(), page.GetOffset())
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query account list")
+		return fmt.Errorf("GetAccountListByExpiry err: %s", err.Error())
+	}
+	didCellOwners, err := h.getDidCellOwners(list)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query account list")
+		return fmt.Errorf("getDidCellOwners err: %s", err.Error())
+	}
+	for _, v := range list {
+		data := AccountExpiryData{
+			Account:        v.Account,
+			OwnerChainType: v.OwnerChainType,
+			Owner:          v.Owner,
+			Status:         v.Status,
+			ExpiredAt:      v.ExpiredAt,
+			GraceEndAt:     v.ExpiredAt + gracePeriod,
+		}
+		if owner, ok := didCellOwners[v.AccountId]; ok {
+			data.OwnerChainType, data.Owner = common.ChainTypeAnyLock, owner
+		}
+		resp.List = append(resp.List, data)
+	}
+	if len(list) > 0 {
+		last := list[len(list)-1]
+		resp.NextCursor = page.NextCursor(len(list), dao.Cursor{Key: last.ExpiredAt, Id: last.Id})
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
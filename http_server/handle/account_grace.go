@@ -0,0 +1,78 @@
+package handle
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/core"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+	"time"
+)
+
+type ReqAccountGrace struct {
+	Pagination
+	core.ChainTypeAddress
+	ParentAccount string `json:"parent_account"`
+}
+
+func (h *HttpHandle) JsonRpcAccountGrace(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqAccountGrace
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doAccountGrace(&req[0], apiResp); err != nil {
+		log.Error("doAccountGrace err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) AccountGrace(ctx *gin.Context) {
+	var (
+		funcName = "AccountGrace"
+		req      ReqAccountGrace
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doAccountGrace(&req, &apiResp); err != nil {
+		log.Error("doAccountGrace err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+// doAccountGrace accounts that have expired but are still within the grace period
+func (h *HttpHandle) doAccountGrace(req *ReqAccountGrace, apiResp *http_api.ApiResp) error {
+	filter, err := h.getExpiryFilter(req.ChainTypeAddress, req.ParentAccount)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return nil
+	}
+	gracePeriod, err := h.getExpirationGracePeriod()
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeError500, "Failed to get grace period")
+		return fmt.Errorf("getExpirationGracePeriod err: %s", err.Error())
+	}
+	now := uint64(time.Now().Unix())
+	filter.ExpiredFrom, filter.ExpiredTo = now-gracePeriod, now
+
+	return h.doAccountExpiryList(filter, req.Pagination, apiResp)
+}
@@ -0,0 +1,142 @@
+package handle
+
+import (
+	"das_database/dao"
+	"das_database/normalize"
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+	"strings"
+)
+
+type ReqAccountInfo struct {
+	Account string `json:"account"`
+}
+
+type RespAccountInfo struct {
+	Account            string                `json:"account"`
+	AccountId          string                `json:"account_id"`
+	ParentAccountId    string                `json:"parent_account_id"`
+	OwnerChainType     common.ChainType      `json:"owner_chain_type"`
+	Owner              string                `json:"owner"`
+	OwnerAlgorithmId   common.DasAlgorithmId `json:"owner_algorithm_id"`
+	ManagerChainType   common.ChainType      `json:"manager_chain_type"`
+	Manager            string                `json:"manager"`
+	ManagerAlgorithmId common.DasAlgorithmId `json:"manager_algorithm_id"`
+	Status             uint8                 `json:"status"`
+	EnableSubAccount   uint8                 `json:"enable_sub_account"`
+	RegisteredAt       uint64                `json:"registered_at"`
+	ExpiredAt          uint64                `json:"expired_at"`
+}
+
+func (h *HttpHandle) JsonRpcAccountInfo(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqAccountInfo
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doAccountInfo(&req[0], apiResp); err != nil {
+		log.Error("doAccountInfo err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) AccountInfo(ctx *gin.Context) {
+	var (
+		funcName = "AccountInfo"
+		req      ReqAccountInfo
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doAccountInfo(&req, &apiResp); err != nil {
+		log.Error("doAccountInfo err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doAccountInfo(req *ReqAccountInfo, apiResp *http_api.ApiResp) error {
+	var resp RespAccountInfo
+
+	account := strings.ToLower(strings.TrimSpace(req.Account))
+	if account == "" || !strings.HasSuffix(account, common.DasAccountSuffix) {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "Invalid account parameter")
+		return nil
+	}
+	accountId := common.Bytes2Hex(common.GetAccountIdByAccount(account))
+	info, err := h.dbDao.GetAccountInfoByAccountId(accountId)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query account")
+		return fmt.Errorf("GetAccountInfoByAccountId err: %s", err.Error())
+	} else if info.Id == 0 {
+		apiResp.ApiRespErr(http_api.ApiCodeAccountNotExist, "account not exist")
+		return nil
+	}
+
+	resp = RespAccountInfo{
+		Account:            info.Account,
+		AccountId:          info.AccountId,
+		ParentAccountId:    info.ParentAccountId,
+		OwnerChainType:     info.OwnerChainType,
+		OwnerAlgorithmId:   info.OwnerAlgorithmId,
+		ManagerChainType:   info.ManagerChainType,
+		ManagerAlgorithmId: info.ManagerAlgorithmId,
+		Status:             info.Status,
+		EnableSubAccount:   info.EnableSubAccount,
+		RegisteredAt:       info.RegisteredAt,
+		ExpiredAt:          info.ExpiredAt,
+	}
+	resp.Owner, err = h.an.ToNormal(normalize.Address{
+		ChainType:      info.OwnerChainType,
+		AlgorithmId:    info.OwnerAlgorithmId,
+		SubAlgorithmId: info.OwnerSubAid,
+		Hex:            info.Owner,
+	})
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeError500, "HexToNormal Err")
+		return fmt.Errorf("ToNormal err: %s", err.Error())
+	}
+	resp.Manager, err = h.an.ToNormal(normalize.Address{
+		ChainType:      info.ManagerChainType,
+		AlgorithmId:    info.ManagerAlgorithmId,
+		SubAlgorithmId: info.ManagerSubAid,
+		Hex:            info.Manager,
+	})
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeError500, "HexToNormal Err")
+		return fmt.Errorf("ToNormal err: %s", err.Error())
+	}
+
+	// the upgraded accounts are held by their did cells
+	didCellOwners, err := h.getDidCellOwners([]dao.TableAccountInfo{info})
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query did cell")
+		return fmt.Errorf("getDidCellOwners err: %s", err.Error())
+	}
+	if owner, ok := didCellOwners[info.AccountId]; ok {
+		resp.OwnerChainType, resp.OwnerAlgorithmId, resp.Owner = common.ChainTypeAnyLock, common.DasAlgorithmIdAnyLock, owner
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
@@ -0,0 +1,101 @@
+package handle
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+	"strings"
+)
+
+type ReqAccountRecords struct {
+	Account string `json:"account"`
+}
+
+type RespAccountRecords struct {
+	Account string          `json:"account"`
+	Records []AccountRecord `json:"records"`
+}
+
+type AccountRecord struct {
+	Key   string `json:"key"`
+	Type  string `json:"type"`
+	Label string `json:"label"`
+	Value string `json:"value"`
+	Ttl   string `json:"ttl"`
+}
+
+func (h *HttpHandle) JsonRpcAccountRecords(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqAccountRecords
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doAccountRecords(&req[0], apiResp); err != nil {
+		log.Error("doAccountRecords err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) AccountRecords(ctx *gin.Context) {
+	var (
+		funcName = "AccountRecords"
+		req      ReqAccountRecords
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doAccountRecords(&req, &apiResp); err != nil {
+		log.Error("doAccountRecords err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doAccountRecords(req *ReqAccountRecords, apiResp *http_api.ApiResp) error {
+	var resp RespAccountRecords
+	resp.Records = make([]AccountRecord, 0)
+
+	account := strings.ToLower(strings.TrimSpace(req.Account))
+	if account == "" || !strings.HasSuffix(account, common.DasAccountSuffix) {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "Invalid account parameter")
+		return nil
+	}
+	resp.Account = account
+
+	list, err := h.dbDao.GetRecordsByAccountId(common.Bytes2Hex(common.GetAccountIdByAccount(account)))
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query records")
+		return fmt.Errorf("GetRecordsByAccountId err: %s", err.Error())
+	}
+	for _, v := range list {
+		resp.Records = append(resp.Records, AccountRecord{
+			Key:   v.Key,
+			Type:  v.Type,
+			Label: v.Label,
+			Value: v.Value,
+			Ttl:   v.Ttl,
+		})
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
@@ -0,0 +1,77 @@
+package handle
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/core"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+	"time"
+)
+
+type ReqAccountRecyclable struct {
+	Pagination
+	core.ChainTypeAddress
+	ParentAccount string `json:"parent_account"`
+}
+
+func (h *HttpHandle) JsonRpcAccountRecyclable(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqAccountRecyclable
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doAccountRecyclable(&req[0], apiResp); err != nil {
+		log.Error("doAccountRecyclable err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) AccountRecyclable(ctx *gin.Context) {
+	var (
+		funcName = "AccountRecyclable"
+		req      ReqAccountRecyclable
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doAccountRecyclable(&req, &apiResp); err != nil {
+		log.Error("doAccountRecyclable err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+// doAccountRecyclable accounts past the grace period which have not been recycled yet
+func (h *HttpHandle) doAccountRecyclable(req *ReqAccountRecyclable, apiResp *http_api.ApiResp) error {
+	filter, err := h.getExpiryFilter(req.ChainTypeAddress, req.ParentAccount)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return nil
+	}
+	gracePeriod, err := h.getExpirationGracePeriod()
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeError500, "Failed to get grace period")
+		return fmt.Errorf("getExpirationGracePeriod err: %s", err.Error())
+	}
+	filter.ExpiredFrom, filter.ExpiredTo = 1, uint64(time.Now().Unix())-gracePeriod
+
+	return h.doAccountExpiryList(filter, req.Pagination, apiResp)
+}
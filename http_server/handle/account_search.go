@@ -0,0 +1,208 @@
+package handle
+
+import (
+	"das_database/dao"
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+type ReqAccountSearch struct {
+	Pagination
+	Keyword        string   `json:"keyword"`
+	Match          string   `json:"match"`
+	LengthMin      uint8    `json:"length_min"`
+	LengthMax      uint8    `json:"length_max"`
+	Charsets       []string `json:"charsets"`
+	Status         []string `json:"status"`
+	RegisteredFrom uint64   `json:"registered_from"`
+	RegisteredTo   uint64   `json:"registered_to"`
+	Level          string   `json:"level"`
+	ParentAccount  string   `json:"parent_account"`
+}
+
+type RespAccountSearch struct {
+	Total      int64               `json:"total"`
+	NextCursor string              `json:"next_cursor"`
+	List       []AccountSearchData `json:"list"`
+}
+
+type AccountSearchData struct {
+	Account        string           `json:"account"`
+	OwnerChainType common.ChainType `json:"owner_chain_type"`
+	Owner          string           `json:"owner"`
+	Status         uint8            `json:"status"`
+	AccountLength  uint8            `json:"account_length"`
+	CharsetNum     uint64           `json:"charset_num"`
+	RegisteredAt   uint64           `json:"registered_at"`
+	ExpiredAt      uint64           `json:"expired_at"`
+}
+
+var accountCharsetNames = map[string]common.AccountCharType{
+	"emoji": common.AccountCharTypeEmoji,
+	"digit": common.AccountCharTypeDigit,
+	"en":    common.AccountCharTypeEn,
+	"hans":  common.AccountCharTypeHanS,
+	"hant":  common.AccountCharTypeHanT,
+	"ja":    common.AccountCharTypeJa,
+	"ko":    common.AccountCharTypeKo,
+	"ru":    common.AccountCharTypeRu,
+	"tr":    common.AccountCharTypeTr,
+	"th":    common.AccountCharTypeTh,
+	"vi":    common.AccountCharTypeVi,
+}
+
+var accountStatusNames = map[string]dao.AccountStatus{
+	"normal":     dao.AccountStatusNormal,
+	"on_sale":    dao.AccountStatusOnSale,
+	"on_auction": dao.AccountStatusOnAuction,
+	"on_lock":    dao.AccountStatusOnLock,
+	"approval":   dao.AccountStatusApproval,
+	"upgrade":    dao.AccountStatusOnUpgrade,
+}
+
+func (h *HttpHandle) JsonRpcAccountSearch(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqAccountSearch
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doAccountSearch(&req[0], apiResp); err != nil {
+		log.Error("doAccountSearch err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) AccountSearch(ctx *gin.Context) {
+	var (
+		funcName = "AccountSearch"
+		req      ReqAccountSearch
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doAccountSearch(&req, &apiResp); err != nil {
+		log.Error("doAccountSearch err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doAccountSearch(req *ReqAccountSearch, apiResp *http_api.ApiResp) error {
+	var resp RespAccountSearch
+	resp.List = make([]AccountSearchData, 0)
+
+	filter := dao.AccountSearchFilter{
+		Keyword:        strings.ToLower(strings.TrimSpace(req.Keyword)),
+		Match:          req.Match,
+		LengthMin:      req.LengthMin,
+		LengthMax:      req.LengthMax,
+		RegisteredFrom: req.RegisteredFrom,
+		RegisteredTo:   req.RegisteredTo,
+		Level:          req.Level,
+	}
+	switch filter.Match {
+	case "":
+		filter.Match = dao.AccountMatchPrefix
+	case dao.AccountMatchPrefix, dao.AccountMatchSuffix:
+	case dao.AccountMatchContains:
+		// the ngram index needs at least 2 characters
+		if utf8.RuneCountInString(filter.Keyword) < 2 {
+			apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "keyword is too short")
+			return nil
+		}
+	default:
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "match invalid")
+		return nil
+	}
+	switch filter.Level {
+	case "", dao.AccountLevelTop, dao.AccountLevelSub:
+	default:
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "level invalid")
+		return nil
+	}
+	for _, v := range req.Charsets {
+		charType, ok := accountCharsetNames[v]
+		if !ok {
+			apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, fmt.Sprintf("charset [%s] invalid", v))
+			return nil
+		}
+		filter.CharsetNum |= common.AccountCharTypeToUint64(charType)
+	}
+	for _, v := range req.Status {
+		status, ok := accountStatusNames[v]
+		if !ok {
+			apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, fmt.Sprintf("status [%s] invalid", v))
+			return nil
+		}
+		filter.Status = append(filter.Status, status)
+	}
+	if req.ParentAccount != "" {
+		filter.ParentAccountId = common.Bytes2Hex(common.GetAccountIdByAccount(req.ParentAccount))
+	}
+	cursor, err := req.GetCursor()
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+
+	list, err := h.dbDao.SearchAccountList(filter, cursor, req.GetLimit(), req.GetOffset())
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to search account")
+		return fmt.Errorf("SearchAccountList err: %s", err.Error())
+	}
+	didCellOwners, err := h.getDidCellOwners(list)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to search account")
+		return fmt.Errorf("getDidCellOwners err: %s", err.Error())
+	}
+	for _, v := range list {
+		data := AccountSearchData{
+			Account:        v.Account,
+			OwnerChainType: v.OwnerChainType,
+			Owner:          v.Owner,
+			Status:         v.Status,
+			AccountLength:  v.AccountLength,
+			CharsetNum:     v.CharsetNum,
+			RegisteredAt:   v.RegisteredAt,
+			ExpiredAt:      v.ExpiredAt,
+		}
+		if owner, ok := didCellOwners[v.AccountId]; ok {
+			data.OwnerChainType, data.Owner = common.ChainTypeAnyLock, owner
+		}
+		resp.List = append(resp.List, data)
+	}
+	if len(list) > 0 {
+		resp.NextCursor = req.NextCursor(len(list), dao.Cursor{Id: list[len(list)-1].Id})
+	}
+
+	resp.Total, err = h.dbDao.CountAccountList(filter)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to search account")
+		return fmt.Errorf("CountAccountList err: %s", err.Error())
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
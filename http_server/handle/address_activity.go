@@ -0,0 +1,74 @@
+package handle
+
+import (
+	"encoding/json"
+	"github.com/dotbitHQ/das-lib/core"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+)
+
+type ReqAddressActivity struct {
+	Pagination
+	core.ChainTypeAddress
+	ActivityFilter
+}
+
+func (h *HttpHandle) JsonRpcAddressActivity(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqAddressActivity
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doAddressActivity(&req[0], apiResp); err != nil {
+		log.Error("doAddressActivity err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) AddressActivity(ctx *gin.Context) {
+	var (
+		funcName = "AddressActivity"
+		req      ReqAddressActivity
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doAddressActivity(&req, &apiResp); err != nil {
+		log.Error("doAddressActivity err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doAddressActivity(req *ReqAddressActivity, apiResp *http_api.ApiResp) error {
+	addrHex, err := h.an.FromChainTypeAddress(&req.ChainTypeAddress)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "Invalid key info parameter")
+		return nil
+	}
+	filter, err := req.ActivityFilter.toDaoFilter()
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+	filter.ChainType, filter.Address = addrHex.ChainType, addrHex.Hex
+
+	return h.doActivityList(filter, req.Pagination, apiResp)
+}
@@ -0,0 +1,74 @@
+package handle
+
+import (
+	"das_database/dao"
+	"encoding/json"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+)
+
+// ReqApprovalHistory status is optional, 1: pending 2: fulfilled 3: revoked
+type ReqApprovalHistory struct {
+	Pagination
+	ApprovalFilter
+	Status []dao.ApprovalStatus `json:"status"`
+}
+
+func (h *HttpHandle) JsonRpcApprovalHistory(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqApprovalHistory
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doApprovalHistory(&req[0], apiResp); err != nil {
+		log.Error("doApprovalHistory err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) ApprovalHistory(ctx *gin.Context) {
+	var (
+		funcName = "ApprovalHistory"
+		req      ReqApprovalHistory
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doApprovalHistory(&req, &apiResp); err != nil {
+		log.Error("doApprovalHistory err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doApprovalHistory(req *ReqApprovalHistory, apiResp *http_api.ApiResp) error {
+	filter, err := h.getApprovalFilter(req.ApprovalFilter)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+	if filter.AccountId == "" && filter.Owner == "" && filter.Platform == "" && filter.To == "" {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "one of account, owner, platform and to is required")
+		return nil
+	}
+	filter.Status = req.Status
+
+	return h.doApprovalList(filter, req.Pagination, apiResp)
+}
@@ -0,0 +1,217 @@
+package handle
+
+import (
+	"das_database/dao"
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/core"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type ReqApprovalPending struct {
+	Pagination
+	ApprovalFilter
+}
+
+// ApprovalFilter all of them are optional, to is the recipient of the transfer
+type ApprovalFilter struct {
+	Account  string                 `json:"account"`
+	Owner    *core.ChainTypeAddress `json:"owner"`
+	Platform *core.ChainTypeAddress `json:"platform"`
+	To       *core.ChainTypeAddress `json:"to"`
+}
+
+type RespApprovalList struct {
+	Total      int64          `json:"total"`
+	NextCursor string         `json:"next_cursor"`
+	List       []ApprovalData `json:"list"`
+}
+
+// ApprovalData times are in seconds, revocable and fulfillable are as of now and only for pending approvals
+type ApprovalData struct {
+	Account          string                `json:"account"`
+	Outpoint         string                `json:"outpoint"`
+	BlockNumber      uint64                `json:"block_number"`
+	Platform         string                `json:"platform"`
+	OwnerAlgorithmId common.DasAlgorithmId `json:"owner_algorithm_id"`
+	Owner            string                `json:"owner"`
+	ToAlgorithmId    common.DasAlgorithmId `json:"to_algorithm_id"`
+	To               string                `json:"to"`
+	ProtectedUntil   uint64                `json:"protected_until"`
+	SealedUntil      uint64                `json:"sealed_until"`
+	MaxDelayCount    uint8                 `json:"max_delay_count"`
+	PostponedCount   int                   `json:"postponed_count"`
+	Status           dao.ApprovalStatus    `json:"status"`
+	Revocable        bool                  `json:"revocable"`
+	Fulfillable      bool                  `json:"fulfillable"`
+	Events           []ApprovalEventData   `json:"events"`
+}
+
+type ApprovalEventData struct {
+	Event     dao.ApprovalEvent `json:"event"`
+	EventAt   uint64            `json:"event_at"`
+	EmittedAt int64             `json:"emitted_at"`
+}
+
+func (h *HttpHandle) JsonRpcApprovalPending(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqApprovalPending
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doApprovalPending(&req[0], apiResp); err != nil {
+		log.Error("doApprovalPending err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) ApprovalPending(ctx *gin.Context) {
+	var (
+		funcName = "ApprovalPending"
+		req      ReqApprovalPending
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doApprovalPending(&req, &apiResp); err != nil {
+		log.Error("doApprovalPending err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doApprovalPending(req *ReqApprovalPending, apiResp *http_api.ApiResp) error {
+	filter, err := h.getApprovalFilter(req.ApprovalFilter)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+	filter.Status = []dao.ApprovalStatus{dao.ApprovalStatusEnable}
+
+	return h.doApprovalList(filter, req.Pagination, apiResp)
+}
+
+func (h *HttpHandle) getApprovalFilter(f ApprovalFilter) (filter dao.ApprovalFilter, err error) {
+	if account := strings.ToLower(strings.TrimSpace(f.Account)); account != "" {
+		filter.AccountId = common.Bytes2Hex(common.GetAccountIdByAccount(account))
+	}
+	if f.Owner != nil && f.Owner.KeyInfo.Key != "" {
+		addrHex, err := h.an.FromChainTypeAddress(f.Owner)
+		if err != nil {
+			return filter, fmt.Errorf("owner invalid")
+		}
+		filter.Owner = addrHex.Hex
+	}
+	if f.Platform != nil && f.Platform.KeyInfo.Key != "" {
+		addrHex, err := h.an.FromChainTypeAddress(f.Platform)
+		if err != nil {
+			return filter, fmt.Errorf("platform invalid")
+		}
+		filter.Platform = addrHex.Hex
+	}
+	// the recipient is kept as a normal address
+	if f.To != nil && f.To.KeyInfo.Key != "" {
+		addrHex, err := h.an.FromChainTypeAddress(f.To)
+		if err != nil {
+			return filter, fmt.Errorf("to invalid")
+		}
+		addrNormal, err := h.an.ToNormal(addrHex)
+		if err != nil {
+			return filter, fmt.Errorf("to invalid")
+		}
+		filter.To = addrNormal
+	}
+	return filter, nil
+}
+
+func (h *HttpHandle) doApprovalList(filter dao.ApprovalFilter, page Pagination, apiResp *http_api.ApiResp) error {
+	var resp RespApprovalList
+	resp.List = make([]ApprovalData, 0)
+
+	cursor, err := page.GetCursor()
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+
+	resp.Total, err = h.dbDao.GetApprovalCount(filter)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query approval")
+		return fmt.Errorf("GetApprovalCount err: %s", err.Error())
+	}
+	list, err := h.dbDao.GetApprovalList(filter, cursor, page.GetLimit(), page.GetOffset())
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query approval")
+		return fmt.Errorf("GetApprovalList err: %s", err.Error())
+	}
+	approvalIds := make([]uint64, 0, len(list))
+	for _, v := range list {
+		approvalIds = append(approvalIds, v.ID)
+	}
+	events, err := h.dbDao.GetApprovalEventList(approvalIds)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query approval")
+		return fmt.Errorf("GetApprovalEventList err: %s", err.Error())
+	}
+	eventMap := make(map[uint64][]ApprovalEventData)
+	for _, v := range events {
+		eventMap[v.ApprovalId] = append(eventMap[v.ApprovalId], ApprovalEventData{
+			Event:     v.Event,
+			EventAt:   v.EventAt,
+			EmittedAt: v.CreatedAt.Unix(),
+		})
+	}
+
+	now := uint64(time.Now().Unix())
+	for _, v := range list {
+		data := ApprovalData{
+			Account:          v.Account,
+			Outpoint:         v.Outpoint,
+			BlockNumber:      v.BlockNumber,
+			Platform:         v.Platform,
+			OwnerAlgorithmId: v.OwnerAlgorithmID,
+			Owner:            v.Owner,
+			ToAlgorithmId:    v.ToAlgorithmID,
+			To:               v.To,
+			ProtectedUntil:   v.ProtectedUntil,
+			SealedUntil:      v.SealedUntil,
+			MaxDelayCount:    v.MaxDelayCount,
+			PostponedCount:   v.PostponedCount,
+			Status:           v.Status,
+			Revocable:        v.Status == dao.ApprovalStatusEnable && now >= v.ProtectedUntil,
+			Fulfillable:      v.Status == dao.ApprovalStatusEnable && now >= v.SealedUntil,
+			Events:           eventMap[v.ID],
+		}
+		if data.Events == nil {
+			data.Events = make([]ApprovalEventData, 0)
+		}
+		resp.List = append(resp.List, data)
+	}
+	if len(list) > 0 {
+		resp.NextCursor = page.NextCursor(len(list), dao.Cursor{Id: list[len(list)-1].ID})
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
@@ -0,0 +1,151 @@
+package handle
+
+import (
+	"das_database/dao"
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/core"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+	"time"
+)
+
+type ReqCrossChainLocked struct {
+	Pagination
+	core.ChainTypeAddress
+	ParentAccount string `json:"parent_account"`
+	Days          uint64 `json:"days"`
+}
+
+type RespCrossChainLocked struct {
+	Total      int64                `json:"total"`
+	NextCursor string               `json:"next_cursor"`
+	List       []CrossChainLockData `json:"list"`
+}
+
+type CrossChainLockData struct {
+	Account         string           `json:"account"`
+	OwnerChainType  common.ChainType `json:"owner_chain_type"`
+	Owner           string           `json:"owner"`
+	CoinType        string           `json:"coin_type"`
+	ChainId         string           `json:"chain_id"`
+	LockTxHash      string           `json:"lock_tx_hash"`
+	LockBlockNumber uint64           `json:"lock_block_number"`
+	LockedAt        uint64           `json:"locked_at"`
+	LockDuration    uint64           `json:"lock_duration"`
+}
+
+func (h *HttpHandle) JsonRpcCrossChainLocked(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqCrossChainLocked
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doCrossChainLocked(&req[0], apiResp); err != nil {
+		log.Error("doCrossChainLocked err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) CrossChainLocked(ctx *gin.Context) {
+	var (
+		funcName = "CrossChainLocked"
+		req      ReqCrossChainLocked
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doCrossChainLocked(&req, &apiResp); err != nil {
+		log.Error("doCrossChainLocked err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doCrossChainLocked(req *ReqCrossChainLocked, apiResp *http_api.ApiResp) error {
+	var resp RespCrossChainLocked
+	resp.List = make([]CrossChainLockData, 0)
+
+	var filter dao.CrossChainFilter
+	if req.KeyInfo.Key != "" {
+		addrHex, err := h.an.FromChainTypeAddress(&req.ChainTypeAddress)
+		if err != nil {
+			apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+			return nil
+		}
+		filter.OwnerChainType, filter.Owner = addrHex.ChainType, addrHex.Hex
+	}
+	if req.ParentAccount != "" {
+		filter.ParentAccountId = common.Bytes2Hex(common.GetAccountIdByAccount(req.ParentAccount))
+	}
+	now := time.Now()
+	if req.Days > 0 {
+		filter.LockedBefore = uint64(now.Add(-time.Duration(req.Days) * 24 * time.Hour).UnixMilli())
+	}
+	cursor, err := req.GetCursor()
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+
+	resp.Total, err = h.dbDao.GetCrossChainLockedCount(filter)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query locked count")
+		return fmt.Errorf("GetCrossChainLockedCount err: %s", err.Error())
+	}
+	list, err := h.dbDao.GetCrossChainLockedList(filter, cursor, req.GetLimit(), req.GetOffset())
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query locked list")
+		return fmt.Errorf("GetCrossChainLockedList err: %s", err.Error())
+	}
+	for _, v := range list {
+		resp.List = append(resp.List, toCrossChainLockData(v, now))
+	}
+	if len(list) > 0 {
+		last := list[len(list)-1]
+		resp.NextCursor = req.NextCursor(len(list), dao.Cursor{Key: last.LockedAt, Id: last.Id})
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
+
+// toCrossChainLockData times in seconds, the duration is up to now for an open bridge
+func toCrossChainLockData(v dao.TableCrossChainInfo, now time.Time) CrossChainLockData {
+	data := CrossChainLockData{
+		Account:         v.Account,
+		OwnerChainType:  v.OwnerChainType,
+		Owner:           v.Owner,
+		CoinType:        v.CoinType,
+		ChainId:         v.ChainId,
+		LockTxHash:      v.LockTxHash,
+		LockBlockNumber: v.LockBlockNumber,
+		LockedAt:        v.LockedAt / 1e3,
+	}
+	end := uint64(now.Unix())
+	if v.Status == dao.CrossChainStatusUnlocked {
+		end = v.UnlockedAt / 1e3
+	}
+	if v.LockedAt > 0 && end > data.LockedAt {
+		data.LockDuration = end - data.LockedAt
+	}
+	return data
+}
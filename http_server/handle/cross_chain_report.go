@@ -0,0 +1,133 @@
+package handle
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+	"time"
+)
+
+type ReqCrossChainReport struct {
+	StuckDays uint64 `json:"stuck_days"`
+}
+
+type RespCrossChainReport struct {
+	StuckDays      uint64                     `json:"stuck_days"`
+	StuckTotal     int64                      `json:"stuck_total"`
+	Stuck          []CrossChainLockData       `json:"stuck"`
+	OrphanUnlocks  []CrossChainUnlockData     `json:"orphan_unlocks"`
+	DoubleLocks    []CrossChainLockData       `json:"double_locks"`
+	StatusMismatch []CrossChainLockData       `json:"status_mismatch"`
+	Unregistered   []CrossChainUnregisterData `json:"unregistered"`
+}
+
+type CrossChainUnlockData struct {
+	Account           string           `json:"account"`
+	OwnerChainType    common.ChainType `json:"owner_chain_type"`
+	Owner             string           `json:"owner"`
+	UnlockTxHash      string           `json:"unlock_tx_hash"`
+	UnlockBlockNumber uint64           `json:"unlock_block_number"`
+	UnlockedAt        uint64           `json:"unlocked_at"`
+}
+
+type CrossChainUnregisterData struct {
+	Account     string `json:"account"`
+	BlockNumber uint64 `json:"block_number"`
+}
+
+func (h *HttpHandle) JsonRpcCrossChainReport(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqCrossChainReport
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doCrossChainReport(&req[0], apiResp); err != nil {
+		log.Error("doCrossChainReport err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) CrossChainReport(ctx *gin.Context) {
+	var (
+		funcName = "CrossChainReport"
+		req      ReqCrossChainReport
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doCrossChainReport(&req, &apiResp); err != nil {
+		log.Error("doCrossChainReport err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doCrossChainReport(req *ReqCrossChainReport, apiResp *http_api.ApiResp) error {
+	if req.StuckDays == 0 {
+		req.StuckDays = 30
+	}
+	resp := RespCrossChainReport{
+		StuckDays:      req.StuckDays,
+		Stuck:          make([]CrossChainLockData, 0),
+		OrphanUnlocks:  make([]CrossChainUnlockData, 0),
+		DoubleLocks:    make([]CrossChainLockData, 0),
+		StatusMismatch: make([]CrossChainLockData, 0),
+		Unregistered:   make([]CrossChainUnregisterData, 0),
+	}
+
+	now := time.Now()
+	stuckBefore := uint64(now.Add(-time.Duration(req.StuckDays) * 24 * time.Hour).UnixMilli())
+	report, err := h.dbDao.GetCrossChainReport(stuckBefore, 100)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query cross chain report")
+		return fmt.Errorf("GetCrossChainReport err: %s", err.Error())
+	}
+	resp.StuckTotal = report.StuckCount
+	for _, v := range report.Stuck {
+		resp.Stuck = append(resp.Stuck, toCrossChainLockData(v, now))
+	}
+	for _, v := range report.OrphanUnlocks {
+		resp.OrphanUnlocks = append(resp.OrphanUnlocks, CrossChainUnlockData{
+			Account:           v.Account,
+			OwnerChainType:    v.UnlockOwnerChainType,
+			Owner:             v.UnlockOwner,
+			UnlockTxHash:      v.UnlockTxHash,
+			UnlockBlockNumber: v.UnlockBlockNumber,
+			UnlockedAt:        v.UnlockedAt / 1e3,
+		})
+	}
+	for _, v := range report.DoubleLocks {
+		resp.DoubleLocks = append(resp.DoubleLocks, toCrossChainLockData(v, now))
+	}
+	for _, v := range report.StatusMismatch {
+		resp.StatusMismatch = append(resp.StatusMismatch, toCrossChainLockData(v, now))
+	}
+	for _, v := range report.Unregistered {
+		resp.Unregistered = append(resp.Unregistered, CrossChainUnregisterData{
+			Account:     v.Account,
+			BlockNumber: v.BlockNumber,
+		})
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
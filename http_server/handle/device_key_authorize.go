@@ -0,0 +1,111 @@
+package handle
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+)
+
+type ReqDeviceKeyAuthorize struct {
+	ReqDeviceKeyList
+}
+
+// RespDeviceKeyAuthorize slaves: the keys in the key list of this key, masters: the keys which have this key in their key lists
+type RespDeviceKeyAuthorize struct {
+	Cid     string               `json:"cid"`
+	Slaves  []DeviceKeyAuthorize `json:"slaves"`
+	Masters []DeviceKeyAuthorize `json:"masters"`
+}
+
+// DeviceKeyAuthorize outpoint is the key list cell of the master
+type DeviceKeyAuthorize struct {
+	DeviceKeyData
+	Outpoint string `json:"outpoint"`
+}
+
+func (h *HttpHandle) JsonRpcDeviceKeyAuthorize(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqDeviceKeyAuthorize
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doDeviceKeyAuthorize(&req[0], apiResp); err != nil {
+		log.Error("doDeviceKeyAuthorize err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) DeviceKeyAuthorize(ctx *gin.Context) {
+	var (
+		funcName = "DeviceKeyAuthorize"
+		req      ReqDeviceKeyAuthorize
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doDeviceKeyAuthorize(&req, &apiResp); err != nil {
+		log.Error("doDeviceKeyAuthorize err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doDeviceKeyAuthorize(req *ReqDeviceKeyAuthorize, apiResp *http_api.ApiResp) error {
+	var resp RespDeviceKeyAuthorize
+	resp.Slaves = make([]DeviceKeyAuthorize, 0)
+	resp.Masters = make([]DeviceKeyAuthorize, 0)
+
+	cid, err := h.getDeviceKeyCid(req.Cid, req.Address)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+	resp.Cid = cid
+
+	slaves, err := h.dbDao.GetAuthorizeByMasterCid(cid)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query authorize")
+		return fmt.Errorf("GetAuthorizeByMasterCid err: %s", err.Error())
+	}
+	for _, v := range slaves {
+		if v.SlaveCid == cid {
+			continue
+		}
+		resp.Slaves = append(resp.Slaves, DeviceKeyAuthorize{
+			DeviceKeyData: h.toDeviceKeyData(v.SlaveAlgId, v.SlaveSubAlgId, v.SlaveCid, v.SlavePk),
+			Outpoint:      v.Outpoint,
+		})
+	}
+	masters, err := h.dbDao.GetAuthorizeBySlaveCid(cid)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query authorize")
+		return fmt.Errorf("GetAuthorizeBySlaveCid err: %s", err.Error())
+	}
+	for _, v := range masters {
+		resp.Masters = append(resp.Masters, DeviceKeyAuthorize{
+			DeviceKeyData: h.toDeviceKeyData(v.MasterAlgId, v.MasterSubAlgId, v.MasterCid, v.MasterPk),
+			Outpoint:      v.Outpoint,
+		})
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
@@ -0,0 +1,131 @@
+package handle
+
+import (
+	"das_database/dao"
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+)
+
+// ReqDeviceKeyHistory the key list cells of a master key, outpoint is optional
+type ReqDeviceKeyHistory struct {
+	Pagination
+	ReqDeviceKeyList
+	Outpoint string `json:"outpoint"`
+}
+
+type RespDeviceKeyHistory struct {
+	Total      int64                  `json:"total"`
+	NextCursor string                 `json:"next_cursor"`
+	List       []DeviceKeyHistoryData `json:"list"`
+}
+
+// DeviceKeyHistoryData the keys of a key list cell, block_timestamp is in seconds
+type DeviceKeyHistoryData struct {
+	Outpoint       string           `json:"outpoint"`
+	Action         common.DasAction `json:"action"`
+	BlockNumber    uint64           `json:"block_number"`
+	BlockTimestamp uint64           `json:"block_timestamp"`
+	List           []DeviceKeyData  `json:"list"`
+}
+
+func (h *HttpHandle) JsonRpcDeviceKeyHistory(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqDeviceKeyHistory
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doDeviceKeyHistory(&req[0], apiResp); err != nil {
+		log.Error("doDeviceKeyHistory err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) DeviceKeyHistory(ctx *gin.Context) {
+	var (
+		funcName = "DeviceKeyHistory"
+		req      ReqDeviceKeyHistory
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doDeviceKeyHistory(&req, &apiResp); err != nil {
+		log.Error("doDeviceKeyHistory err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doDeviceKeyHistory(req *ReqDeviceKeyHistory, apiResp *http_api.ApiResp) error {
+	var resp RespDeviceKeyHistory
+	resp.List = make([]DeviceKeyHistoryData, 0)
+
+	cid, err := h.getDeviceKeyCid(req.Cid, req.Address)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+	cursor, err := req.GetCursor()
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+
+	resp.Total, err = h.dbDao.GetAuthorizeHistoryOutpointCount(cid, req.Outpoint)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query key history count")
+		return fmt.Errorf("GetAuthorizeHistoryOutpointCount err: %s", err.Error())
+	}
+	outpoints, err := h.dbDao.GetAuthorizeHistoryOutpoints(cid, req.Outpoint, cursor, req.GetLimit(), req.GetOffset())
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query key history")
+		return fmt.Errorf("GetAuthorizeHistoryOutpoints err: %s", err.Error())
+	}
+	var outpointList []string
+	for _, v := range outpoints {
+		outpointList = append(outpointList, v.Outpoint)
+	}
+	list, err := h.dbDao.GetAuthorizeHistoryByOutpoints(outpointList)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query key history")
+		return fmt.Errorf("GetAuthorizeHistoryByOutpoints err: %s", err.Error())
+	}
+	keys := make(map[string][]DeviceKeyData)
+	for _, v := range list {
+		keys[v.Outpoint] = append(keys[v.Outpoint], h.toDeviceKeyData(v.SlaveAlgId, v.SlaveSubAlgId, v.SlaveCid, v.SlavePk))
+	}
+	for _, v := range outpoints {
+		resp.List = append(resp.List, DeviceKeyHistoryData{
+			Outpoint:       v.Outpoint,
+			Action:         v.Action,
+			BlockNumber:    v.BlockNumber,
+			BlockTimestamp: v.BlockTimestamp / 1e3,
+			List:           keys[v.Outpoint],
+		})
+	}
+	if len(outpoints) > 0 {
+		resp.NextCursor = req.NextCursor(len(outpoints), dao.Cursor{Id: outpoints[len(outpoints)-1].Id})
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
@@ -0,0 +1,168 @@
+package handle
+
+import (
+	"das_database/normalize"
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// ReqDeviceKeyList one of cid and the passkey address is required
+type ReqDeviceKeyList struct {
+	Cid     string `json:"cid"`
+	Address string `json:"address"`
+}
+
+// RespDeviceKeyList outpoint is the live key list cell of the key, empty when it has none
+type RespDeviceKeyList struct {
+	Cid             string          `json:"cid"`
+	Pk              string          `json:"pk"`
+	Address         string          `json:"address"`
+	EnableAuthorize uint8           `json:"enable_authorize"`
+	Outpoint        string          `json:"outpoint"`
+	List            []DeviceKeyData `json:"list"`
+}
+
+type DeviceKeyData struct {
+	AlgId    common.DasAlgorithmId `json:"alg_id"`
+	SubAlgId common.DasAlgorithmId `json:"sub_alg_id"`
+	Cid      string                `json:"cid"`
+	Pk       string                `json:"pk"`
+	Address  string                `json:"address"`
+}
+
+func (h *HttpHandle) JsonRpcDeviceKeyList(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqDeviceKeyList
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doDeviceKeyList(&req[0], apiResp); err != nil {
+		log.Error("doDeviceKeyList err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) DeviceKeyList(ctx *gin.Context) {
+	var (
+		funcName = "DeviceKeyList"
+		req      ReqDeviceKeyList
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doDeviceKeyList(&req, &apiResp); err != nil {
+		log.Error("doDeviceKeyList err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doDeviceKeyList(req *ReqDeviceKeyList, apiResp *http_api.ApiResp) error {
+	var resp RespDeviceKeyList
+	resp.List = make([]DeviceKeyData, 0)
+
+	cid, err := h.getDeviceKeyCid(req.Cid, req.Address)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+	cidPk, err := h.dbDao.GetCidPk(cid)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query device key")
+		return fmt.Errorf("GetCidPk err: %s", err.Error())
+	} else if cidPk.Id == 0 {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "device key not exist")
+		return nil
+	}
+	resp.Cid, resp.Pk = cidPk.Cid, cidPk.Pk
+	resp.Address = h.passkeyAddress(common.DasAlgorithmIdWebauthn, common.DasAlgorithmId(common.DasWebauthnSubAlgorithmIdES256), cidPk.Cid, cidPk.Pk)
+	resp.EnableAuthorize = cidPk.EnableAuthorize
+	resp.Outpoint = cidPk.Outpoint
+
+	list, err := h.dbDao.GetAuthorizeByMasterCid(cid)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query device key list")
+		return fmt.Errorf("GetAuthorizeByMasterCid err: %s", err.Error())
+	}
+	for _, v := range list {
+		resp.List = append(resp.List, h.toDeviceKeyData(v.SlaveAlgId, v.SlaveSubAlgId, v.SlaveCid, v.SlavePk))
+	}
+	// a key list cell which is only created holds the master key alone
+	if len(resp.List) == 0 && resp.Outpoint != "" {
+		resp.List = append(resp.List, h.toDeviceKeyData(common.DasAlgorithmIdWebauthn, common.DasAlgorithmId(common.DasWebauthnSubAlgorithmIdES256), cidPk.Cid, cidPk.Pk))
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
+
+var regexpCid = regexp.MustCompile("^0x[0-9a-f]{20}$")
+
+// getDeviceKeyCid the cid of the passkey address, or the cid itself
+func (h *HttpHandle) getDeviceKeyCid(cid, address string) (string, error) {
+	if address != "" {
+		addr, err := h.an.FromNormal(common.ChainTypeWebauthn, address)
+		if err != nil || addr.AlgorithmId != common.DasAlgorithmIdWebauthn || len(addr.Payload()) != 20 {
+			return "", fmt.Errorf("address invalid")
+		}
+		return common.Bytes2Hex(addr.Payload()[:10]), nil
+	}
+	cid = strings.ToLower(cid)
+	if !strings.HasPrefix(cid, common.HexPreFix) {
+		cid = common.HexPreFix + cid
+	}
+	if !regexpCid.MatchString(cid) {
+		return "", fmt.Errorf("cid invalid")
+	}
+	return cid, nil
+}
+
+// passkeyAddress is empty for the keys which are not passkeys
+func (h *HttpHandle) passkeyAddress(algId, subAlgId common.DasAlgorithmId, cid, pk string) string {
+	if algId != common.DasAlgorithmIdWebauthn {
+		return ""
+	}
+	addr, err := h.an.ToNormal(normalize.Address{
+		ChainType:      common.ChainTypeWebauthn,
+		AlgorithmId:    algId,
+		SubAlgorithmId: common.DasSubAlgorithmId(subAlgId),
+		Hex:            strings.TrimPrefix(cid, common.HexPreFix) + strings.TrimPrefix(pk, common.HexPreFix),
+	})
+	if err != nil {
+		log.Warn("ToNormal err:", err.Error(), cid, pk)
+		return ""
+	}
+	return addr
+}
+
+func (h *HttpHandle) toDeviceKeyData(algId, subAlgId common.DasAlgorithmId, cid, pk string) DeviceKeyData {
+	return DeviceKeyData{
+		AlgId:    algId,
+		SubAlgId: subAlgId,
+		Cid:      cid,
+		Pk:       pk,
+		Address:  h.passkeyAddress(algId, subAlgId, cid, pk),
+	}
+}
@@ -0,0 +1,175 @@
+package handle
+
+import (
+	"das_database/dao"
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/core"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/nervosnetwork/ckb-sdk-go/address"
+	"github.com/nervosnetwork/ckb-sdk-go/types"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+	"strings"
+)
+
+type ReqDidCellInfo struct {
+	Account string `json:"account"`
+}
+
+type RespDidCellInfo struct {
+	DidCellData
+}
+
+// DidCellData owner is the ckb address of the lock, expired_at is in seconds
+type DidCellData struct {
+	Account      string `json:"account"`
+	Outpoint     string `json:"outpoint"`
+	BlockNumber  uint64 `json:"block_number"`
+	Owner        string `json:"owner"`
+	LockCodeHash string `json:"lock_code_hash"`
+	Args         string `json:"args"`
+	ExpiredAt    uint64 `json:"expired_at"`
+}
+
+func (h *HttpHandle) JsonRpcDidCellInfo(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqDidCellInfo
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doDidCellInfo(&req[0], apiResp); err != nil {
+		log.Error("doDidCellInfo err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) DidCellInfo(ctx *gin.Context) {
+	var (
+		funcName = "DidCellInfo"
+		req      ReqDidCellInfo
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doDidCellInfo(&req, &apiResp); err != nil {
+		log.Error("doDidCellInfo err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doDidCellInfo(req *ReqDidCellInfo, apiResp *http_api.ApiResp) error {
+	var resp RespDidCellInfo
+
+	account := strings.ToLower(strings.TrimSpace(req.Account))
+	if account == "" {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "account is empty")
+		return nil
+	}
+	accountId := common.Bytes2Hex(common.GetAccountIdByAccount(account))
+	info, err := h.dbDao.GetDidCellByAccountId(accountId)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query did cell")
+		return fmt.Errorf("GetDidCellByAccountId err: %s", err.Error())
+	} else if info.Id == 0 {
+		apiResp.ApiRespErr(http_api.ApiCodeAccountNotExist, "did cell not exist")
+		return nil
+	}
+	resp.DidCellData = h.toDidCellData(info)
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
+
+func (h *HttpHandle) toDidCellData(info dao.TableDidCellInfo) DidCellData {
+	return DidCellData{
+		Account:      info.Account,
+		Outpoint:     info.Outpoint,
+		BlockNumber:  info.BlockNumber,
+		Owner:        h.didCellOwner(info),
+		LockCodeHash: info.LockCodeHash,
+		Args:         info.Args,
+		ExpiredAt:    info.ExpiredAt,
+	}
+}
+
+// didCellOwner the ckb address of the did cell lock, empty if it can not be encoded
+func (h *HttpHandle) didCellOwner(info dao.TableDidCellInfo) string {
+	addr, err := h.an.DidCellLock(info.LockCodeHash, info.Args)
+	if err != nil {
+		log.Warn("DidCellLock err:", err.Error(), info.Account)
+		return ""
+	}
+	return addr.Hex
+}
+
+// parseCkbAddress the lock script of a ckb address of the current net
+func (h *HttpHandle) parseCkbAddress(addr string) (*types.Script, error) {
+	parsed, err := address.Parse(addr)
+	if err != nil {
+		return nil, fmt.Errorf("address.Parse err: %s", err.Error())
+	}
+	if parsed.Mode != h.an.Mode() {
+		return nil, fmt.Errorf("address of another net")
+	}
+	return parsed.Script, nil
+}
+
+// getDidCellHolder only ckb addresses hold did cells, nil for the addresses of other chains
+func (h *HttpHandle) getDidCellHolder(chainTypeAddress core.ChainTypeAddress) *dao.DidCellHolder {
+	if chainTypeAddress.KeyInfo.CoinType != common.CoinTypeCKB {
+		return nil
+	}
+	lock, err := h.parseCkbAddress(chainTypeAddress.KeyInfo.Key)
+	if err != nil {
+		return nil
+	}
+	return &dao.DidCellHolder{LockCodeHash: lock.CodeHash.Hex(), Args: common.Bytes2Hex(lock.Args)}
+}
+
+// getSnapshotOwners the forms of the address in the snapshot, the owner of a did cell is kept as the ckb address
+func (h *HttpHandle) getSnapshotOwners(chainTypeAddress core.ChainTypeAddress, addressHex string) []string {
+	owners := []string{addressHex}
+	if chainTypeAddress.KeyInfo.CoinType != common.CoinTypeCKB {
+		return owners
+	}
+	lock, err := h.parseCkbAddress(chainTypeAddress.KeyInfo.Key)
+	if err != nil {
+		return owners
+	}
+	if addr, err := h.an.AnyLock(lock); err == nil && addr.Hex != addressHex {
+		owners = append(owners, addr.Hex)
+	}
+	return owners
+}
+
+// getDidCellOwners the holders of the upgraded accounts in the list, by account id
+func (h *HttpHandle) getDidCellOwners(list []dao.TableAccountInfo) (map[string]string, error) {
+	didCells, err := h.dbDao.GetUpgradedDidCellList(list)
+	if err != nil {
+		return nil, err
+	}
+	owners := make(map[string]string)
+	for _, v := range didCells {
+		owners[v.AccountId] = h.didCellOwner(v)
+	}
+	return owners, nil
+}
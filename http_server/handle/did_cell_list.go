@@ -0,0 +1,124 @@
+package handle
+
+import (
+	"das_database/dao"
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+)
+
+// ReqDidCellList one of ckb_address and lock, the hash type of the lock is not compared
+type ReqDidCellList struct {
+	Pagination
+	CkbAddress string      `json:"ckb_address"`
+	Lock       *LockScript `json:"lock"`
+}
+
+type LockScript struct {
+	CodeHash string `json:"code_hash"`
+	HashType string `json:"hash_type"`
+	Args     string `json:"args"`
+}
+
+type RespDidCellList struct {
+	Total      int64         `json:"total"`
+	NextCursor string        `json:"next_cursor"`
+	List       []DidCellData `json:"list"`
+}
+
+func (h *HttpHandle) JsonRpcDidCellList(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqDidCellList
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doDidCellList(&req[0], apiResp); err != nil {
+		log.Error("doDidCellList err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) DidCellList(ctx *gin.Context) {
+	var (
+		funcName = "DidCellList"
+		req      ReqDidCellList
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doDidCellList(&req, &apiResp); err != nil {
+		log.Error("doDidCellList err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doDidCellList(req *ReqDidCellList, apiResp *http_api.ApiResp) error {
+	var resp RespDidCellList
+	resp.List = make([]DidCellData, 0)
+
+	var holder dao.DidCellHolder
+	switch {
+	case req.CkbAddress != "":
+		lock, err := h.parseCkbAddress(req.CkbAddress)
+		if err != nil {
+			apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "ckb_address invalid")
+			return nil
+		}
+		holder = dao.DidCellHolder{LockCodeHash: lock.CodeHash.Hex(), Args: common.Bytes2Hex(lock.Args)}
+	case req.Lock != nil && req.Lock.CodeHash != "":
+		codeHash := common.Hex2Bytes(req.Lock.CodeHash)
+		if len(codeHash) != 32 {
+			apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "lock code_hash invalid")
+			return nil
+		}
+		holder = dao.DidCellHolder{LockCodeHash: common.Bytes2Hex(codeHash), Args: common.Bytes2Hex(common.Hex2Bytes(req.Lock.Args))}
+	default:
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "ckb_address or lock is required")
+		return nil
+	}
+	cursor, err := req.GetCursor()
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+
+	resp.Total, err = h.dbDao.GetDidCellCountByHolder(holder)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query did cell count")
+		return fmt.Errorf("GetDidCellCountByHolder err: %s", err.Error())
+	}
+	list, err := h.dbDao.GetDidCellListByHolder(holder, cursor, req.GetLimit(), req.GetOffset())
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query did cell list")
+		return fmt.Errorf("GetDidCellListByHolder err: %s", err.Error())
+	}
+	for _, v := range list {
+		resp.List = append(resp.List, h.toDidCellData(v))
+	}
+	if len(list) > 0 {
+		resp.NextCursor = req.NextCursor(len(list), dao.Cursor{Id: list[len(list)-1].Id})
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
@@ -0,0 +1,235 @@
+package handle
+
+import (
+	"context"
+	"das_database/dao"
+	"das_database/http_server/api_code"
+	"das_database/pb"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/core"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GrpcService the grpc transport of the handlers, it runs the same do funcs as the json-rpc
+// and the v1 routes and only converts the messages
+type GrpcService struct {
+	pb.UnimplementedDasDatabaseServer
+	h *HttpHandle
+}
+
+func (h *HttpHandle) GrpcService() *GrpcService {
+	return &GrpcService{h: h}
+}
+
+var grpcCodes = map[http_api.ApiCode]codes.Code{
+	http_api.ApiCodeError500:                     codes.Internal,
+	http_api.ApiCodeParamsInvalid:                codes.InvalidArgument,
+	http_api.ApiCodeMethodNotExist:               codes.Unimplemented,
+	http_api.ApiCodeDbError:                      codes.Unavailable,
+	http_api.ApiCodeCacheError:                   codes.Unavailable,
+	http_api.ApiCodeSignError:                    codes.InvalidArgument,
+	http_api.ApiCodeAccountNotExist:              codes.NotFound,
+	api_code.ApiCodeAccountPermissionsDoNotExist: codes.NotFound,
+	api_code.ApiCodeAccountHasBeenRecycled:       codes.FailedPrecondition,
+	api_code.ApiCodeAccountCrossChain:            codes.FailedPrecondition,
+	api_code.ApiCodeAccountExpired:               codes.FailedPrecondition,
+}
+
+// grpcError the status of a failed call, with the err_no of the json-rpc api in its details
+func grpcError(apiResp *http_api.ApiResp) error {
+	if apiResp.ErrNo == http_api.ApiCodeSuccess {
+		return nil
+	}
+	code, ok := grpcCodes[apiResp.ErrNo]
+	if !ok {
+		code = codes.Unknown
+	}
+	st, err := status.New(code, apiResp.ErrMsg).WithDetails(&pb.Error{
+		ErrNo:  int64(apiResp.ErrNo),
+		ErrMsg: apiResp.ErrMsg,
+	})
+	if err != nil {
+		return status.Error(code, apiResp.ErrMsg)
+	}
+	return st.Err()
+}
+
+func toChainTypeAddress(in *pb.ChainTypeAddress) core.ChainTypeAddress {
+	var res core.ChainTypeAddress
+	if in == nil {
+		return res
+	}
+	res.Type = in.Type
+	if in.KeyInfo != nil {
+		res.KeyInfo = core.KeyInfo{
+			CoinType: common.CoinType(in.KeyInfo.CoinType),
+			ChainId:  common.ChainId(in.KeyInfo.ChainId),
+			Key:      in.KeyInfo.Key,
+		}
+	}
+	return res
+}
+
+func toPagination(in *pb.Pagination) Pagination {
+	if in == nil {
+		return Pagination{}
+	}
+	return Pagination{Page: int(in.Page), Size: int(in.Size), Cursor: in.Cursor}
+}
+
+func (s *GrpcService) SnapshotPermissionsInfo(ctx context.Context, in *pb.SnapshotPermissionsInfoRequest) (*pb.SnapshotPermissionsInfoReply, error) {
+	var apiResp http_api.ApiResp
+	req := ReqSnapshotPermissionsInfo{
+		Account:     in.Account,
+		BlockNumber: in.BlockNumber,
+	}
+	if err := s.h.doSnapshotPermissionsInfo(&req, &apiResp); err != nil {
+		log.Error("doSnapshotPermissionsInfo err:", err.Error())
+	}
+	if err := grpcError(&apiResp); err != nil {
+		return nil, err
+	}
+	resp, _ := apiResp.Data.(RespSnapshotPermissionsInfo)
+	return &pb.SnapshotPermissionsInfoReply{
+		Account:            resp.Account,
+		AccountId:          resp.AccountId,
+		BlockNumber:        resp.BlockNumber,
+		Owner:              resp.Owner,
+		OwnerAlgorithmId:   int64(resp.OwnerAlgorithmId),
+		Manager:            resp.Manager,
+		ManagerAlgorithmId: int64(resp.ManagerAlgorithmId),
+	}, nil
+}
+
+func (s *GrpcService) SnapshotAddressAccounts(ctx context.Context, in *pb.SnapshotAddressAccountsRequest) (*pb.SnapshotAddressAccountsReply, error) {
+	var apiResp http_api.ApiResp
+	req := ReqSnapshotAddressAccounts{
+		ChainTypeAddress: toChainTypeAddress(in.ChainTypeAddress),
+		BlockNumber:      in.BlockNumber,
+		RoleType:         dao.RoleType(in.RoleType),
+		Pagination:       toPagination(in.Pagination),
+	}
+	if err := s.h.doSnapshotAddressAccounts(&req, &apiResp); err != nil {
+		log.Error("doSnapshotAddressAccounts err:", err.Error())
+	}
+	if err := grpcError(&apiResp); err != nil {
+		return nil, err
+	}
+	resp, _ := apiResp.Data.(RespSnapshotAddressAccounts)
+	reply := pb.SnapshotAddressAccountsReply{
+		Total:      resp.Total,
+		NextCursor: resp.NextCursor,
+	}
+	for _, v := range resp.Accounts {
+		reply.Accounts = append(reply.Accounts, v.Account)
+	}
+	return &reply, nil
+}
+
+func (s *GrpcService) SnapshotDidList(ctx context.Context, in *pb.SnapshotDidListRequest) (*pb.SnapshotDidListReply, error) {
+	var apiResp http_api.ApiResp
+	req := ReqSnapshotDidList{
+		ChainTypeAddress: toChainTypeAddress(in.ChainTypeAddress),
+		BlockNumber:      in.BlockNumber,
+		AccountLength:    in.AccountLength,
+		Pagination:       toPagination(in.Pagination),
+	}
+	if err := s.h.doSnapshotDidList(&req, &apiResp); err != nil {
+		log.Error("doSnapshotDidList err:", err.Error())
+	}
+	if err := grpcError(&apiResp); err != nil {
+		return nil, err
+	}
+	resp, _ := apiResp.Data.(RespSnapshotDidList)
+	reply := pb.SnapshotDidListReply{
+		Total:      int64(resp.Total),
+		NextCursor: resp.NextCursor,
+	}
+	for _, v := range resp.Accounts {
+		reply.Accounts = append(reply.Accounts, v.Account)
+	}
+	return &reply, nil
+}
+
+func (s *GrpcService) SnapshotVerify(ctx context.Context, in *pb.SnapshotVerifyRequest) (*pb.SnapshotVerifyReply, error) {
+	var apiResp http_api.ApiResp
+	req := ReqSnapshotVerify{
+		ChainTypeAddress:   toChainTypeAddress(in.ChainTypeAddress),
+		Message:            in.Message,
+		Signature:          in.Signature,
+		PasskeySignAddress: in.PasskeySignAddress,
+	}
+	if err := s.h.doSnapshotVerify(&req, &apiResp); err != nil {
+		log.Error("doSnapshotVerify err:", err.Error())
+	}
+	if err := grpcError(&apiResp); err != nil {
+		return nil, err
+	}
+	resp, _ := apiResp.Data.(RespSnapshotVerify)
+	return &pb.SnapshotVerifyReply{Verified: resp.Verified}, nil
+}
+
+func (s *GrpcService) SnapshotRegisterHistory(ctx context.Context, in *pb.SnapshotRegisterHistoryRequest) (*pb.SnapshotRegisterHistoryReply, error) {
+	var apiResp http_api.ApiResp
+	req := ReqSnapshotRegisterHistory{StartTime: in.StartTime}
+	if err := s.h.doSnapshotRegisterHistory(&req, &apiResp); err != nil {
+		log.Error("doSnapshotRegisterHistory err:", err.Error())
+	}
+	if err := grpcError(&apiResp); err != nil {
+		return nil, err
+	}
+	resp, _ := apiResp.Data.(RespSnapshotRegisterHistory)
+	return &pb.SnapshotRegisterHistoryReply{Result: resp.Result}, nil
+}
+
+func (s *GrpcService) AccountInfo(ctx context.Context, in *pb.AccountInfoRequest) (*pb.AccountInfoReply, error) {
+	var apiResp http_api.ApiResp
+	req := ReqAccountInfo{Account: in.Account}
+	if err := s.h.doAccountInfo(&req, &apiResp); err != nil {
+		log.Error("doAccountInfo err:", err.Error())
+	}
+	if err := grpcError(&apiResp); err != nil {
+		return nil, err
+	}
+	resp, _ := apiResp.Data.(RespAccountInfo)
+	return &pb.AccountInfoReply{
+		Account:            resp.Account,
+		AccountId:          resp.AccountId,
+		ParentAccountId:    resp.ParentAccountId,
+		OwnerChainType:     int64(resp.OwnerChainType),
+		Owner:              resp.Owner,
+		OwnerAlgorithmId:   int64(resp.OwnerAlgorithmId),
+		ManagerChainType:   int64(resp.ManagerChainType),
+		Manager:            resp.Manager,
+		ManagerAlgorithmId: int64(resp.ManagerAlgorithmId),
+		Status:             uint32(resp.Status),
+		EnableSubAccount:   uint32(resp.EnableSubAccount),
+		RegisteredAt:       resp.RegisteredAt,
+		ExpiredAt:          resp.ExpiredAt,
+	}, nil
+}
+
+func (s *GrpcService) AccountRecords(ctx context.Context, in *pb.AccountRecordsRequest) (*pb.AccountRecordsReply, error) {
+	var apiResp http_api.ApiResp
+	req := ReqAccountRecords{Account: in.Account}
+	if err := s.h.doAccountRecords(&req, &apiResp); err != nil {
+		log.Error("doAccountRecords err:", err.Error())
+	}
+	if err := grpcError(&apiResp); err != nil {
+		return nil, err
+	}
+	resp, _ := apiResp.Data.(RespAccountRecords)
+	reply := pb.AccountRecordsReply{Account: resp.Account}
+	for _, v := range resp.Records {
+		reply.Records = append(reply.Records, &pb.Record{
+			Key:   v.Key,
+			Type:  v.Type,
+			Label: v.Label,
+			Value: v.Value,
+			Ttl:   v.Ttl,
+		})
+	}
+	return &reply, nil
+}
@@ -5,8 +5,11 @@ import (
 	"das_database/block_parser"
 	"das_database/dao"
 	"das_database/http_server/api_code"
+	"das_database/normalize"
+	"encoding/json"
 	"fmt"
 	"github.com/dotbitHQ/das-lib/core"
+	"github.com/dotbitHQ/das-lib/http_api"
 	"github.com/dotbitHQ/das-lib/http_api/logger"
 	"github.com/dotbitHQ/das-lib/witness"
 	"github.com/gin-gonic/gin"
@@ -25,6 +28,8 @@ type HttpHandle struct {
 	dasCore *core.DasCore
 	bp      *block_parser.BlockParser
 	red     *redis.Client
+	an      *normalize.AddressNormalizer
+	rpc     *api_code.JsonRpcRegistry
 }
 
 type HttpHandleParams struct {
@@ -42,7 +47,9 @@ func Initialize(p HttpHandleParams) *HttpHandle {
 		ctx:     p.Ctx,
 		bp:      p.Bp,
 		red:     p.Red,
+		an:      normalize.NewAddressNormalizer(p.DasCore.NetType()),
 	}
+	hh.rpc = hh.newJsonRpcRegistry()
 	return &hh
 }
 
@@ -50,20 +57,56 @@ func GetClientIp(ctx *gin.Context) string {
 	return fmt.Sprintf("%v", ctx.Request.Header.Get("X-Real-IP"))
 }
 
+type ReqLatestBlockNumber struct {
+}
+
+type RespLatestBlockNumber struct {
+	BlockNumber         uint64 `json:"blockNumber"`
+	IsLatestBlockNumber bool   `json:"isLatestBlockNumber"`
+}
+
+func (h *HttpHandle) JsonRpcLatestBlockNumber(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqLatestBlockNumber
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) > 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doLatestBlockNumber(apiResp); err != nil {
+		log.Error("doLatestBlockNumber err:", err.Error())
+	}
+}
+
 func (h *HttpHandle) IsLatestBlockNumber(ctx *gin.Context) {
+	var apiResp http_api.ApiResp
 	log.Info("IsLatestBlockNumber", GetClientIp(ctx))
 
+	if err := h.doLatestBlockNumber(&apiResp); err != nil {
+		log.Error("doLatestBlockNumber err:", err.Error())
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doLatestBlockNumber(apiResp *http_api.ApiResp) error {
 	blockNumber, err := h.dasCore.Client().GetTipBlockNumber(h.ctx)
 	if err != nil {
-		log.Error("GetTipBlockNumber err: ", err.Error())
-		ctx.JSON(http.StatusOK, api_code.ApiRespErr(api_code.ApiCodeBlockError, "search block number err"))
-		return
+		apiResp.ApiRespErr(api_code.ApiCodeBlockError, "search block number err")
+		return fmt.Errorf("GetTipBlockNumber err: %s", err.Error())
 	}
 
-	ctx.JSON(http.StatusOK, api_code.ApiRespOKData(map[string]interface{}{
-		"blockNumber":         blockNumber,
-		"isLatestBlockNumber": block_parser.IsLatestBlockNumber,
-	}))
+	apiResp.ApiRespOK(RespLatestBlockNumber{
+		BlockNumber:         blockNumber,
+		IsLatestBlockNumber: block_parser.IsLatestBlockNumber,
+	})
+	return nil
 }
 
 type ParserTransactionData struct {
@@ -2,56 +2,30 @@ package handle
 
 import (
 	"das_database/http_server/api_code"
-	"das_database/prometheus"
-	"fmt"
-	"github.com/dotbitHQ/das-lib/http_api"
 	"github.com/gin-gonic/gin"
-	"github.com/scorpiotzh/toolib"
 	"net/http"
-	"time"
 )
 
+// JasonRpcHandle the json-rpc 2.0 endpoint, a request without an id is a notification and gets no response,
+// a batch gets the list of the responses of its requests which are not notifications
 func (h *HttpHandle) JasonRpcHandle(ctx *gin.Context) {
-	var (
-		req      api_code.JsonRequest
-		resp     api_code.JsonResponse
-		apiResp  http_api.ApiResp
-		clientIp = GetClientIp(ctx)
-	)
-	resp.Result = &apiResp
+	clientIp := GetClientIp(ctx)
 
-	start := time.Now()
-
-	defer func() {
-		prometheus.Tools.Metrics.Api().WithLabelValues(req.Method, "200", fmt.Sprint(apiResp.ErrNo), apiResp.ErrMsg).Observe(time.Since(start).Seconds())
-	}()
-
-	err := ctx.ShouldBindJSON(&req)
+	body, err := ctx.GetRawData()
 	if err != nil {
-		log.Error("ShouldBindJSON err:", err.Error())
-		apiResp.ApiRespErr(api_code.ApiCodeParamsInvalid, "params invalid")
-		ctx.JSON(http.StatusOK, resp)
+		log.Error("GetRawData err:", err.Error())
+		ctx.JSON(http.StatusOK, api_code.JsonResponse{
+			JsonRpc: api_code.JsonRpcVersion,
+			Error:   &api_code.JsonError{Code: api_code.JsonRpcCodeParseError, Message: "parse error"},
+		})
 		return
 	}
+	log.Info("JasonRpcHandle:", clientIp, string(body))
 
-	resp.ID, resp.JsonRpc = req.ID, req.JsonRpc
-	log.Info("JasonRpcHandle:", req.Method, clientIp, toolib.JsonString(req))
-
-	switch req.Method {
-	case api_code.MethodSnapshotPermissionsInfo:
-		h.JsonRpcSnapshotPermissionsInfo(req.Params, &apiResp)
-	case api_code.MethodSnapshotAddressAccounts:
-		h.JsonRpcSnapshotAddressAccounts(req.Params, &apiResp)
-	case api_code.MethodSnapshotRegisterHistory:
-		h.JsonRpcSnapshotRegisterHistory(req.Params, &apiResp)
-	case api_code.MethodSnapshotDidList:
-		h.JsonRpcSnapshotDidList(req.Params, &apiResp)
-	case api_code.MethodSnapshotVerify:
-		h.JsonRpcSnapshotVerify(req.Params, &apiResp)
-	default:
-		log.Error("method not exist:", req.Method)
-		apiResp.ApiRespErr(api_code.ApiCodeMethodNotExist, fmt.Sprintf("method [%s] not exits", req.Method))
+	resp, ok := h.rpc.Do(body)
+	if !ok {
+		ctx.Status(http.StatusNoContent)
+		return
 	}
 	ctx.JSON(http.StatusOK, resp)
-	return
 }
@@ -0,0 +1,63 @@
+package handle
+
+import (
+	"das_database/http_server/api_code"
+	"das_database/prometheus"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"time"
+)
+
+// newJsonRpcRegistry the methods of the json-rpc, with their requests and results for rpc.discover
+func (h *HttpHandle) newJsonRpcRegistry() *api_code.JsonRpcRegistry {
+	r := api_code.NewJsonRpcRegistry(func(method string, apiResp *http_api.ApiResp, duration time.Duration) {
+		prometheus.Tools.Metrics.Api().WithLabelValues(method, "200", fmt.Sprint(apiResp.ErrNo), apiResp.ErrMsg).Observe(duration.Seconds())
+	})
+	r.Register(api_code.MethodSnapshotPermissionsInfo, h.JsonRpcSnapshotPermissionsInfo, ReqSnapshotPermissionsInfo{}, RespSnapshotPermissionsInfo{})
+	r.Register(api_code.MethodSnapshotAddressAccounts, h.JsonRpcSnapshotAddressAccounts, ReqSnapshotAddressAccounts{}, RespSnapshotAddressAccounts{})
+	r.Register(api_code.MethodSnapshotRegisterHistory, h.JsonRpcSnapshotRegisterHistory, ReqSnapshotRegisterHistory{}, RespSnapshotRegisterHistory{})
+	r.Register(api_code.MethodSnapshotDidList, h.JsonRpcSnapshotDidList, ReqSnapshotDidList{}, RespSnapshotDidList{})
+	r.Register(api_code.MethodSnapshotVerify, h.JsonRpcSnapshotVerify, ReqSnapshotVerify{}, RespSnapshotVerify{})
+	r.Register(api_code.MethodSnapshotProgress, h.JsonRpcSnapshotProgress, ReqSnapshotProgress{}, RespSnapshotProgress{})
+	r.Register(api_code.MethodLatestBlockNumber, h.JsonRpcLatestBlockNumber, ReqLatestBlockNumber{}, RespLatestBlockNumber{})
+	r.Register(api_code.MethodMarketVolume, h.JsonRpcMarketVolume, ReqMarketVolume{}, RespMarketVolume{})
+	r.Register(api_code.MethodMarketPriceStats, h.JsonRpcMarketPriceStats, ReqMarketPriceStats{}, RespMarketPriceStats{})
+	r.Register(api_code.MethodMarketTopSales, h.JsonRpcMarketTopSales, ReqMarketTopSales{}, RespMarketTopSales{})
+	r.Register(api_code.MethodMarketAccountPriceHistory, h.JsonRpcMarketAccountPriceHistory, ReqMarketAccountPriceHistory{}, RespMarketAccountPriceHistory{})
+	r.Register(api_code.MethodMarketOfferBook, h.JsonRpcMarketOfferBook, ReqMarketOfferBook{}, RespMarketOfferBook{})
+	r.Register(api_code.MethodTokenPriceHistory, h.JsonRpcTokenPriceHistory, ReqTokenPriceHistory{}, RespTokenPriceHistory{})
+	r.Register(api_code.MethodTokenList, h.JsonRpcTokenList, ReqTokenList{}, RespTokenList{})
+	r.Register(api_code.MethodAccountExpiring, h.JsonRpcAccountExpiring, ReqAccountExpiring{}, RespAccountExpiring{})
+	r.Register(api_code.MethodAccountGrace, h.JsonRpcAccountGrace, ReqAccountGrace{}, RespAccountExpiring{})
+	r.Register(api_code.MethodAccountRecyclable, h.JsonRpcAccountRecyclable, ReqAccountRecyclable{}, RespAccountExpiring{})
+	r.Register(api_code.MethodRecordsSearch, h.JsonRpcRecordsSearch, ReqRecordsSearch{}, RespRecordsSearch{})
+	r.Register(api_code.MethodRecordsHistory, h.JsonRpcRecordsHistory, ReqRecordsHistory{}, RespRecordsHistory{})
+	r.Register(api_code.MethodRecordsDiff, h.JsonRpcRecordsDiff, ReqRecordsDiff{}, RespRecordsDiff{})
+	r.Register(api_code.MethodOwnershipHistory, h.JsonRpcOwnershipHistory, ReqOwnershipHistory{}, RespOwnershipHistory{})
+	r.Register(api_code.MethodAccountSearch, h.JsonRpcAccountSearch, ReqAccountSearch{}, RespAccountSearch{})
+	r.Register(api_code.MethodAccountActivity, h.JsonRpcAccountActivity, ReqAccountActivity{}, RespActivity{})
+	r.Register(api_code.MethodAddressActivity, h.JsonRpcAddressActivity, ReqAddressActivity{}, RespActivity{})
+	r.Register(api_code.MethodRebateList, h.JsonRpcRebateList, ReqRebateList{}, RespRebateList{})
+	r.Register(api_code.MethodRebateSummary, h.JsonRpcRebateSummary, ReqRebateSummary{}, RespRebateSummary{})
+	r.Register(api_code.MethodRebateLeaderboard, h.JsonRpcRebateLeaderboard, ReqRebateLeaderboard{}, RespRebateLeaderboard{})
+	r.Register(api_code.MethodSubAccountMintStatement, h.JsonRpcSubAccountMintStatement, ReqSubAccountMintStatement{}, RespSubAccountMintStatement{})
+	r.Register(api_code.MethodSubAccountMintStatementList, h.JsonRpcSubAccountMintStatementList, ReqSubAccountMintStatementList{}, RespSubAccountMintStatementList{})
+	r.Register(api_code.MethodSubAccountRules, h.JsonRpcSubAccountRules, ReqSubAccountRules{}, RespSubAccountRules{})
+	r.Register(api_code.MethodSubAccountQuote, h.JsonRpcSubAccountQuote, ReqSubAccountQuote{}, RespSubAccountQuote{})
+	r.Register(api_code.MethodSubAccountList, h.JsonRpcSubAccountList, ReqSubAccountList{}, RespSubAccountList{})
+	r.Register(api_code.MethodSubAccountStats, h.JsonRpcSubAccountStats, ReqSubAccountStats{}, RespSubAccountStats{})
+	r.Register(api_code.MethodSubAccountConfig, h.JsonRpcSubAccountConfig, ReqSubAccountConfig{}, RespSubAccountConfig{})
+	r.Register(api_code.MethodSubAccountProfit, h.JsonRpcSubAccountProfit, ReqSubAccountProfit{}, RespActivity{})
+	r.Register(api_code.MethodApprovalPending, h.JsonRpcApprovalPending, ReqApprovalPending{}, RespApprovalList{})
+	r.Register(api_code.MethodApprovalHistory, h.JsonRpcApprovalHistory, ReqApprovalHistory{}, RespApprovalList{})
+	r.Register(api_code.MethodDidCellInfo, h.JsonRpcDidCellInfo, ReqDidCellInfo{}, RespDidCellInfo{})
+	r.Register(api_code.MethodDidCellList, h.JsonRpcDidCellList, ReqDidCellList{}, RespDidCellList{})
+	r.Register(api_code.MethodCrossChainLocked, h.JsonRpcCrossChainLocked, ReqCrossChainLocked{}, RespCrossChainLocked{})
+	r.Register(api_code.MethodCrossChainReport, h.JsonRpcCrossChainReport, ReqCrossChainReport{}, RespCrossChainReport{})
+	r.Register(api_code.MethodDeviceKeyList, h.JsonRpcDeviceKeyList, ReqDeviceKeyList{}, RespDeviceKeyList{})
+	r.Register(api_code.MethodDeviceKeyAuthorize, h.JsonRpcDeviceKeyAuthorize, ReqDeviceKeyAuthorize{}, RespDeviceKeyAuthorize{})
+	r.Register(api_code.MethodDeviceKeyHistory, h.JsonRpcDeviceKeyHistory, ReqDeviceKeyHistory{}, RespDeviceKeyHistory{})
+	r.Register(api_code.MethodAccountInfo, h.JsonRpcAccountInfo, ReqAccountInfo{}, RespAccountInfo{})
+	r.Register(api_code.MethodAccountRecords, h.JsonRpcAccountRecords, ReqAccountRecords{}, RespAccountRecords{})
+	return r
+}
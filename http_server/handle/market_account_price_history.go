@@ -0,0 +1,117 @@
+package handle
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"github.com/shopspring/decimal"
+	"net/http"
+)
+
+type ReqMarketAccountPriceHistory struct {
+	Account string `json:"account"`
+}
+
+type RespMarketAccountPriceHistory struct {
+	Deals    []MarketDealData    `json:"deals"`
+	Listings []MarketListingData `json:"listings"`
+}
+
+type MarketListingData struct {
+	Status         uint8           `json:"status"`
+	PriceCkb       decimal.Decimal `json:"price_ckb"`
+	PriceUsd       decimal.Decimal `json:"price_usd"`
+	BlockNumber    uint64          `json:"block_number"`
+	BlockTimestamp uint64          `json:"block_timestamp"`
+}
+
+func (h *HttpHandle) JsonRpcMarketAccountPriceHistory(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqMarketAccountPriceHistory
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doMarketAccountPriceHistory(&req[0], apiResp); err != nil {
+		log.Error("doMarketAccountPriceHistory err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) MarketAccountPriceHistory(ctx *gin.Context) {
+	var (
+		funcName = "MarketAccountPriceHistory"
+		req      ReqMarketAccountPriceHistory
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doMarketAccountPriceHistory(&req, &apiResp); err != nil {
+		log.Error("doMarketAccountPriceHistory err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doMarketAccountPriceHistory(req *ReqMarketAccountPriceHistory, apiResp *http_api.ApiResp) error {
+	var resp RespMarketAccountPriceHistory
+	resp.Deals = make([]MarketDealData, 0)
+	resp.Listings = make([]MarketListingData, 0)
+
+	if req.Account == "" {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "account is empty")
+		return nil
+	}
+	accountId := common.Bytes2Hex(common.GetAccountIdByAccount(req.Account))
+
+	deals, err := h.dbDao.GetTradeDealListByAccountId(accountId)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query deal list")
+		return fmt.Errorf("GetTradeDealListByAccountId err: %s", err.Error())
+	}
+	for _, v := range deals {
+		resp.Deals = append(resp.Deals, MarketDealData{
+			Account:        v.Account,
+			DealType:       v.DealType,
+			PriceCkb:       shannonToCkb(v.PriceCkb),
+			PriceUsd:       v.PriceUsd,
+			BlockNumber:    v.BlockNumber,
+			BlockTimestamp: v.BlockTimestamp,
+		})
+	}
+
+	histories, err := h.dbDao.GetTradeHistoryListByAccountId(accountId)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query trade history")
+		return fmt.Errorf("GetTradeHistoryListByAccountId err: %s", err.Error())
+	}
+	for _, v := range histories {
+		resp.Listings = append(resp.Listings, MarketListingData{
+			Status:         v.Status,
+			PriceCkb:       shannonToCkb(v.PriceCkb),
+			PriceUsd:       v.PriceUsd,
+			BlockNumber:    v.BlockNumber,
+			BlockTimestamp: v.BlockTimestamp,
+		})
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
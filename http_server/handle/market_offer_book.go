@@ -0,0 +1,133 @@
+package handle
+
+import (
+	"das_database/dao"
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"github.com/shopspring/decimal"
+	"net/http"
+)
+
+type ReqMarketOfferBook struct {
+	Pagination
+	Account string `json:"account"`
+}
+
+type RespMarketOfferBook struct {
+	OfferCount  uint64            `json:"offer_count"`
+	TopPriceCkb decimal.Decimal   `json:"top_price_ckb"`
+	TopPriceUsd decimal.Decimal   `json:"top_price_usd"`
+	Total       int64             `json:"total"`
+	NextCursor  string            `json:"next_cursor"`
+	List        []MarketOfferData `json:"list"`
+}
+
+type MarketOfferData struct {
+	ChainType      common.ChainType `json:"chain_type"`
+	Address        string           `json:"address"`
+	PriceCkb       decimal.Decimal  `json:"price_ckb"`
+	PriceUsd       decimal.Decimal  `json:"price_usd"`
+	Message        string           `json:"message"`
+	BlockNumber    uint64           `json:"block_number"`
+	BlockTimestamp uint64           `json:"block_timestamp"`
+}
+
+func (h *HttpHandle) JsonRpcMarketOfferBook(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqMarketOfferBook
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doMarketOfferBook(&req[0], apiResp); err != nil {
+		log.Error("doMarketOfferBook err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) MarketOfferBook(ctx *gin.Context) {
+	var (
+		funcName = "MarketOfferBook"
+		req      ReqMarketOfferBook
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doMarketOfferBook(&req, &apiResp); err != nil {
+		log.Error("doMarketOfferBook err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doMarketOfferBook(req *ReqMarketOfferBook, apiResp *http_api.ApiResp) error {
+	var resp RespMarketOfferBook
+	resp.List = make([]MarketOfferData, 0)
+
+	if req.Account == "" {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "account is empty")
+		return nil
+	}
+	accountId := common.Bytes2Hex(common.GetAccountIdByAccount(req.Account))
+	cursor, err := req.GetCursor()
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+
+	stat, err := h.dbDao.GetOfferBookStat(accountId)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query offer stat")
+		return fmt.Errorf("GetOfferBookStat err: %s", err.Error())
+	}
+	resp.OfferCount = stat.OfferCount
+	resp.TopPriceCkb = shannonToCkb(stat.TopPrice)
+	resp.TopPriceUsd = stat.TopPriceUsd
+
+	resp.Total, err = h.dbDao.GetOfferCountByAccountId(accountId)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query offer count")
+		return fmt.Errorf("GetOfferCountByAccountId err: %s", err.Error())
+	}
+	list, err := h.dbDao.GetOfferListByAccountId(accountId, cursor, req.GetLimit(), req.GetOffset())
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query offer list")
+		return fmt.Errorf("GetOfferListByAccountId err: %s", err.Error())
+	}
+	for _, v := range list {
+		resp.List = append(resp.List, MarketOfferData{
+			ChainType:      v.ChainType,
+			Address:        v.Address,
+			PriceCkb:       shannonToCkb(v.Price),
+			PriceUsd:       v.PriceUsd,
+			Message:        v.Message,
+			BlockNumber:    v.BlockNumber,
+			BlockTimestamp: v.BlockTimestamp,
+		})
+	}
+	if len(list) > 0 {
+		last := list[len(list)-1]
+		resp.NextCursor = req.NextCursor(len(list), dao.Cursor{Key: last.Price, Id: last.Id})
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
@@ -0,0 +1,104 @@
+package handle
+
+import (
+	"das_database/dao"
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"github.com/shopspring/decimal"
+	"net/http"
+	"time"
+)
+
+type ReqMarketPriceStats struct {
+	StartTime     int64  `json:"start_time"`
+	EndTime       int64  `json:"end_time"`
+	AccountLength uint8  `json:"account_length"`
+	CharsetNum    uint64 `json:"charset_num"`
+}
+
+type RespMarketPriceStats struct {
+	DealCount      int64           `json:"deal_count"`
+	FloorPriceCkb  decimal.Decimal `json:"floor_price_ckb"`
+	FloorPriceUsd  decimal.Decimal `json:"floor_price_usd"`
+	MedianPriceCkb decimal.Decimal `json:"median_price_ckb"`
+	MedianPriceUsd decimal.Decimal `json:"median_price_usd"`
+}
+
+func (h *HttpHandle) JsonRpcMarketPriceStats(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqMarketPriceStats
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doMarketPriceStats(&req[0], apiResp); err != nil {
+		log.Error("doMarketPriceStats err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) MarketPriceStats(ctx *gin.Context) {
+	var (
+		funcName = "MarketPriceStats"
+		req      ReqMarketPriceStats
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doMarketPriceStats(&req, &apiResp); err != nil {
+		log.Error("doMarketPriceStats err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doMarketPriceStats(req *ReqMarketPriceStats, apiResp *http_api.ApiResp) error {
+	var resp RespMarketPriceStats
+
+	if req.EndTime == 0 {
+		req.EndTime = time.Now().Unix()
+	}
+	if req.StartTime > req.EndTime {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "time range invalid")
+		return nil
+	}
+	startDate := time.Unix(req.StartTime, 0).UTC().Format(dao.StatDateLayout)
+	endDate := time.Unix(req.EndTime, 0).UTC().Format(dao.StatDateLayout)
+
+	stats, err := h.dbDao.GetDealPriceStats(startDate, endDate, req.AccountLength, req.CharsetNum)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query price stats")
+		return fmt.Errorf("GetDealPriceStats err: %s", err.Error())
+	}
+	median, err := h.dbDao.GetDealMedianPrice(startDate, endDate, req.AccountLength, req.CharsetNum, stats.DealCount)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query median price")
+		return fmt.Errorf("GetDealMedianPrice err: %s", err.Error())
+	}
+
+	resp.DealCount = stats.DealCount
+	resp.FloorPriceCkb = shannonToCkb(stats.FloorPrice)
+	resp.FloorPriceUsd = stats.FloorUsd
+	resp.MedianPriceCkb = shannonToCkb(median.PriceCkb)
+	resp.MedianPriceUsd = median.PriceUsd()
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
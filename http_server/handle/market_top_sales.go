@@ -0,0 +1,128 @@
+package handle
+
+import (
+	"das_database/dao"
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"github.com/shopspring/decimal"
+	"net/http"
+	"time"
+)
+
+type ReqMarketTopSales struct {
+	Pagination
+	StartTime int64 `json:"start_time"`
+	EndTime   int64 `json:"end_time"`
+	DealType  *int  `json:"deal_type"`
+}
+
+type RespMarketTopSales struct {
+	Total      int64            `json:"total"`
+	NextCursor string           `json:"next_cursor"`
+	List       []MarketDealData `json:"list"`
+}
+
+type MarketDealData struct {
+	Account        string          `json:"account"`
+	DealType       int             `json:"deal_type"`
+	PriceCkb       decimal.Decimal `json:"price_ckb"`
+	PriceUsd       decimal.Decimal `json:"price_usd"`
+	BlockNumber    uint64          `json:"block_number"`
+	BlockTimestamp uint64          `json:"block_timestamp"`
+}
+
+func (h *HttpHandle) JsonRpcMarketTopSales(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqMarketTopSales
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doMarketTopSales(&req[0], apiResp); err != nil {
+		log.Error("doMarketTopSales err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) MarketTopSales(ctx *gin.Context) {
+	var (
+		funcName = "MarketTopSales"
+		req      ReqMarketTopSales
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doMarketTopSales(&req, &apiResp); err != nil {
+		log.Error("doMarketTopSales err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doMarketTopSales(req *ReqMarketTopSales, apiResp *http_api.ApiResp) error {
+	var resp RespMarketTopSales
+	resp.List = make([]MarketDealData, 0)
+
+	if req.EndTime == 0 {
+		req.EndTime = time.Now().Unix()
+	}
+	if req.StartTime > req.EndTime {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "time range invalid")
+		return nil
+	}
+	dealType := -1
+	if req.DealType != nil {
+		dealType = *req.DealType
+	}
+	cursor, err := req.GetCursor()
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+	startAt, endAt := uint64(req.StartTime*1e3), uint64(req.EndTime*1e3)
+
+	resp.Total, err = h.dbDao.GetTopSalesCount(startAt, endAt, dealType)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query deal count")
+		return fmt.Errorf("GetTopSalesCount err: %s", err.Error())
+	}
+	list, err := h.dbDao.GetTopSales(startAt, endAt, dealType, cursor, req.GetLimit(), req.GetOffset())
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query top sales")
+		return fmt.Errorf("GetTopSales err: %s", err.Error())
+	}
+	for _, v := range list {
+		resp.List = append(resp.List, MarketDealData{
+			Account:        v.Account,
+			DealType:       v.DealType,
+			PriceCkb:       shannonToCkb(v.PriceCkb),
+			PriceUsd:       v.PriceUsd,
+			BlockNumber:    v.BlockNumber,
+			BlockTimestamp: v.BlockTimestamp,
+		})
+	}
+	if len(list) > 0 {
+		last := list[len(list)-1]
+		resp.NextCursor = req.NextCursor(len(list), dao.Cursor{Key: last.PriceCkb, Id: last.Id})
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
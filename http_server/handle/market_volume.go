@@ -0,0 +1,150 @@
+package handle
+
+import (
+	"das_database/dao"
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"github.com/shopspring/decimal"
+	"math/big"
+	"net/http"
+	"time"
+)
+
+const (
+	MarketPeriodDay  = "day"
+	MarketPeriodWeek = "week"
+)
+
+type ReqMarketVolume struct {
+	Period    string `json:"period"`
+	StartTime int64  `json:"start_time"`
+	EndTime   int64  `json:"end_time"`
+	DealType  *int   `json:"deal_type"`
+}
+
+type RespMarketVolume struct {
+	List []MarketVolumeData `json:"list"`
+}
+
+type MarketVolumeData struct {
+	Date      string          `json:"date"`
+	DealCount uint64          `json:"deal_count"`
+	VolumeCkb decimal.Decimal `json:"volume_ckb"`
+	VolumeUsd decimal.Decimal `json:"volume_usd"`
+}
+
+func (h *HttpHandle) JsonRpcMarketVolume(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqMarketVolume
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doMarketVolume(&req[0], apiResp); err != nil {
+		log.Error("doMarketVolume err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) MarketVolume(ctx *gin.Context) {
+	var (
+		funcName = "MarketVolume"
+		req      ReqMarketVolume
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doMarketVolume(&req, &apiResp); err != nil {
+		log.Error("doMarketVolume err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doMarketVolume(req *ReqMarketVolume, apiResp *http_api.ApiResp) error {
+	var resp RespMarketVolume
+	resp.List = make([]MarketVolumeData, 0)
+
+	if req.Period == "" {
+		req.Period = MarketPeriodDay
+	}
+	if req.Period != MarketPeriodDay && req.Period != MarketPeriodWeek {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "period invalid")
+		return nil
+	}
+	if req.EndTime == 0 {
+		req.EndTime = time.Now().Unix()
+	}
+	if req.StartTime > req.EndTime {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "time range invalid")
+		return nil
+	}
+	dealType := -1
+	if req.DealType != nil {
+		dealType = *req.DealType
+	}
+
+	startDate := time.Unix(req.StartTime, 0).UTC().Format(dao.StatDateLayout)
+	endDate := time.Unix(req.EndTime, 0).UTC().Format(dao.StatDateLayout)
+	list, err := h.dbDao.GetTradeDealDailyList(startDate, endDate, dealType)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query deal volume")
+		return fmt.Errorf("GetTradeDealDailyList err: %s", err.Error())
+	}
+
+	indexMap := make(map[string]int)
+	for _, v := range list {
+		key, err := marketPeriodKey(v.StatDate, req.Period)
+		if err != nil {
+			apiResp.ApiRespErr(http_api.ApiCodeError500, "Failed to group deal volume")
+			return fmt.Errorf("marketPeriodKey err: %s", err.Error())
+		}
+		idx, ok := indexMap[key]
+		if !ok {
+			idx = len(resp.List)
+			indexMap[key] = idx
+			resp.List = append(resp.List, MarketVolumeData{Date: key})
+		}
+		resp.List[idx].DealCount += v.DealCount
+		resp.List[idx].VolumeCkb = resp.List[idx].VolumeCkb.Add(shannonToCkb(v.VolumeCkb))
+		resp.List[idx].VolumeUsd = resp.List[idx].VolumeUsd.Add(v.VolumeUsd)
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
+
+// marketPeriodKey returns the stat date itself for daily periods, or the Monday starting its ISO week
+func marketPeriodKey(statDate, period string) (string, error) {
+	if period != MarketPeriodWeek {
+		return statDate, nil
+	}
+	t, err := time.Parse(dao.StatDateLayout, statDate)
+	if err != nil {
+		return "", err
+	}
+	offset := (int(t.Weekday()) + 6) % 7
+	return t.AddDate(0, 0, -offset).Format(dao.StatDateLayout), nil
+}
+
+func shannonToCkb(shannon uint64) decimal.Decimal {
+	return decimal.NewFromBigInt(new(big.Int).SetUint64(shannon), 0).Div(decimal.NewFromInt(int64(common.OneCkb)))
+}
@@ -0,0 +1,137 @@
+package handle
+
+import (
+	"das_database/dao"
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+	"strings"
+)
+
+type ReqOwnershipHistory struct {
+	Pagination
+	Account string `json:"account"`
+	Role    string `json:"role"`
+}
+
+type RespOwnershipHistory struct {
+	Total      int64                  `json:"total"`
+	NextCursor string                 `json:"next_cursor"`
+	List       []OwnershipHistoryData `json:"list"`
+}
+
+type OwnershipHistoryData struct {
+	BlockNumber     uint64                `json:"block_number"`
+	BlockTimestamp  uint64                `json:"block_timestamp"`
+	TxHash          string                `json:"tx_hash"`
+	Action          string                `json:"action"`
+	Role            string                `json:"role"`
+	FromAlgorithmId common.DasAlgorithmId `json:"from_algorithm_id"`
+	FromChainType   common.ChainType      `json:"from_chain_type"`
+	FromAddress     string                `json:"from_address"`
+	ToAlgorithmId   common.DasAlgorithmId `json:"to_algorithm_id"`
+	ToChainType     common.ChainType      `json:"to_chain_type"`
+	ToAddress       string                `json:"to_address"`
+}
+
+func (h *HttpHandle) JsonRpcOwnershipHistory(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqOwnershipHistory
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doOwnershipHistory(&req[0], apiResp); err != nil {
+		log.Error("doOwnershipHistory err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) OwnershipHistory(ctx *gin.Context) {
+	var (
+		funcName = "OwnershipHistory"
+		req      ReqOwnershipHistory
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doOwnershipHistory(&req, &apiResp); err != nil {
+		log.Error("doOwnershipHistory err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doOwnershipHistory(req *ReqOwnershipHistory, apiResp *http_api.ApiResp) error {
+	var resp RespOwnershipHistory
+	resp.List = make([]OwnershipHistoryData, 0)
+
+	req.Account = strings.TrimSpace(req.Account)
+	if req.Account == "" {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "account is empty")
+		return nil
+	}
+	switch req.Role {
+	case "", dao.OwnershipRoleOwner, dao.OwnershipRoleManager:
+	default:
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "role invalid")
+		return nil
+	}
+	accountId := common.Bytes2Hex(common.GetAccountIdByAccount(req.Account))
+	cursor, err := req.GetCursor()
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+
+	resp.Total, err = h.dbDao.GetOwnershipLedgerCount(accountId, req.Role)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query ownership history count")
+		return fmt.Errorf("GetOwnershipLedgerCount err: %s", err.Error())
+	}
+	list, err := h.dbDao.GetOwnershipLedgerList(accountId, req.Role, cursor, req.GetLimit(), req.GetOffset())
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query ownership history")
+		return fmt.Errorf("GetOwnershipLedgerList err: %s", err.Error())
+	}
+	for _, v := range list {
+		resp.List = append(resp.List, OwnershipHistoryData{
+			BlockNumber:     v.BlockNumber,
+			BlockTimestamp:  v.BlockTimestamp,
+			TxHash:          v.TxHash,
+			Action:          v.Action,
+			Role:            v.Role,
+			FromAlgorithmId: v.FromAlgorithmId,
+			FromChainType:   v.FromChainType,
+			FromAddress:     v.FromAddress,
+			ToAlgorithmId:   v.ToAlgorithmId,
+			ToChainType:     v.ToChainType,
+			ToAddress:       v.ToAddress,
+		})
+	}
+	if len(list) > 0 {
+		last := list[len(list)-1]
+		resp.NextCursor = req.NextCursor(len(list), dao.Cursor{Key: last.BlockNumber, Id: last.Id})
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
@@ -1,8 +1,18 @@
 package handle
 
+import (
+	"das_database/dao"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// Pagination pages by cursor when one is given, the cursor is opaque to clients: pass back the next_cursor
+// of the previous page, page numbers are still accepted for the first pages but get slow on deep ones
 type Pagination struct {
-	Page    int `json:"page"`
-	Size    int `json:"size"`
+	Page    int    `json:"page"`
+	Size    int    `json:"size"`
+	Cursor  string `json:"cursor"`
 	maxSize int
 }
 
@@ -22,6 +32,9 @@ func (p *Pagination) GetLimit() int {
 }
 
 func (p *Pagination) GetOffset() int {
+	if p.Cursor != "" {
+		return 0
+	}
 	page := p.Page
 	if p.Page < 1 {
 		page = 1
@@ -29,3 +42,51 @@ func (p *Pagination) GetOffset() int {
 	size := p.GetLimit()
 	return (page - 1) * size
 }
+
+// GetCursor is nil for the first page and for page number requests, the cursor must carry a numeric key
+func (p *Pagination) GetCursor() (*dao.Cursor, error) {
+	cursor, err := p.decodeCursor()
+	if err != nil || cursor == nil {
+		return cursor, err
+	}
+	if cursor.KeyStr != "" {
+		return nil, fmt.Errorf("cursor invalid")
+	}
+	return cursor, nil
+}
+
+// GetStrCursor is GetCursor for lists ordered by a string key
+func (p *Pagination) GetStrCursor() (*dao.Cursor, error) {
+	cursor, err := p.decodeCursor()
+	if err != nil || cursor == nil {
+		return cursor, err
+	}
+	if cursor.KeyStr == "" || cursor.Key != 0 {
+		return nil, fmt.Errorf("cursor invalid")
+	}
+	return cursor, nil
+}
+
+func (p *Pagination) decodeCursor() (*dao.Cursor, error) {
+	if p.Cursor == "" {
+		return nil, nil
+	}
+	bys, err := base64.RawURLEncoding.DecodeString(p.Cursor)
+	if err != nil {
+		return nil, fmt.Errorf("cursor invalid")
+	}
+	var cursor dao.Cursor
+	if err := json.Unmarshal(bys, &cursor); err != nil {
+		return nil, fmt.Errorf("cursor invalid")
+	}
+	return &cursor, nil
+}
+
+// NextCursor is empty when the page is not full, i.e. there is nothing left
+func (p *Pagination) NextCursor(pageLen int, last dao.Cursor) string {
+	if pageLen < p.GetLimit() {
+		return ""
+	}
+	bys, _ := json.Marshal(last)
+	return base64.RawURLEncoding.EncodeToString(bys)
+}
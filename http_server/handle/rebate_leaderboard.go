@@ -0,0 +1,104 @@
+package handle
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+)
+
+// ReqRebateLeaderboard ranks the inviters, the inviter params of RebateFilter are ignored
+type ReqRebateLeaderboard struct {
+	RebateFilter
+	Size int `json:"size"`
+}
+
+type RespRebateLeaderboard struct {
+	List []RebateLeaderboardData `json:"list"`
+}
+
+type RebateLeaderboardData struct {
+	Rank             int              `json:"rank"`
+	InviterChainType common.ChainType `json:"inviter_chain_type"`
+	InviterAddress   string           `json:"inviter_address"`
+	Count            int64            `json:"count"`
+	Reward           uint64           `json:"reward"`
+}
+
+func (h *HttpHandle) JsonRpcRebateLeaderboard(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqRebateLeaderboard
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doRebateLeaderboard(&req[0], apiResp); err != nil {
+		log.Error("doRebateLeaderboard err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) RebateLeaderboard(ctx *gin.Context) {
+	var (
+		funcName = "RebateLeaderboard"
+		req      ReqRebateLeaderboard
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doRebateLeaderboard(&req, &apiResp); err != nil {
+		log.Error("doRebateLeaderboard err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doRebateLeaderboard(req *ReqRebateLeaderboard, apiResp *http_api.ApiResp) error {
+	var resp RespRebateLeaderboard
+	resp.List = make([]RebateLeaderboardData, 0)
+
+	req.KeyInfo.Key, req.InviterAccount = "", ""
+	filter, err := h.getRebateFilter(req.RebateFilter)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+	if req.Size < 1 || req.Size > 100 {
+		req.Size = 100
+	}
+
+	list, err := h.dbDao.GetRebateInviterSum(filter, req.Size)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query rebate")
+		return fmt.Errorf("GetRebateInviterSum err: %s", err.Error())
+	}
+	for i, v := range list {
+		resp.List = append(resp.List, RebateLeaderboardData{
+			Rank:             i + 1,
+			InviterChainType: v.InviterChainType,
+			InviterAddress:   v.InviterAddress,
+			Count:            v.Count,
+			Reward:           v.Reward,
+		})
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
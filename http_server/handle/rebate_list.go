@@ -0,0 +1,191 @@
+package handle
+
+import (
+	"das_database/dao"
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/core"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+	"strings"
+)
+
+type ReqRebateList struct {
+	Pagination
+	RebateFilter
+}
+
+// RebateFilter the inviter is optional, given by address or by account, the time range is [start_time, end_time) in seconds
+type RebateFilter struct {
+	core.ChainTypeAddress
+	InviterAccount string `json:"inviter_account"`
+	RewardType     string `json:"reward_type"`
+	ServiceType    int    `json:"service_type"`
+	StartTime      int64  `json:"start_time"`
+	EndTime        int64  `json:"end_time"`
+}
+
+type RespRebateList struct {
+	Total      int64        `json:"total"`
+	NextCursor string       `json:"next_cursor"`
+	List       []RebateData `json:"list"`
+}
+
+type RebateData struct {
+	BlockNumber      uint64           `json:"block_number"`
+	BlockTimestamp   uint64           `json:"block_timestamp"`
+	TxHash           string           `json:"tx_hash"`
+	Action           string           `json:"action"`
+	ServiceType      int              `json:"service_type"`
+	RewardType       string           `json:"reward_type"`
+	Reward           uint64           `json:"reward"`
+	InviteeAccount   string           `json:"invitee_account"`
+	InviterChainType common.ChainType `json:"inviter_chain_type"`
+	InviterAddress   string           `json:"inviter_address"`
+}
+
+const (
+	RebateRewardTypeInviter = "inviter"
+	RebateRewardTypeChannel = "channel"
+)
+
+var rebateRewardTypes = map[string]int{
+	RebateRewardTypeInviter: dao.RewardTypeInviter,
+	RebateRewardTypeChannel: dao.RewardTypeChannel,
+}
+
+func rebateRewardTypeName(rewardType int) string {
+	for k, v := range rebateRewardTypes {
+		if v == rewardType {
+			return k
+		}
+	}
+	return ""
+}
+
+func (h *HttpHandle) JsonRpcRebateList(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqRebateList
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doRebateList(&req[0], apiResp); err != nil {
+		log.Error("doRebateList err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) RebateList(ctx *gin.Context) {
+	var (
+		funcName = "RebateList"
+		req      ReqRebateList
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doRebateList(&req, &apiResp); err != nil {
+		log.Error("doRebateList err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) getRebateFilter(req RebateFilter) (filter dao.RebateFilter, err error) {
+	filter.RewardType = -1
+	if req.RewardType != "" {
+		rewardType, ok := rebateRewardTypes[req.RewardType]
+		if !ok {
+			return filter, fmt.Errorf("reward_type invalid")
+		}
+		filter.RewardType = rewardType
+	}
+	switch req.ServiceType {
+	case 0, dao.ServiceTypeRegister, dao.ServiceTypeTransaction:
+	default:
+		return filter, fmt.Errorf("service_type invalid")
+	}
+	if req.StartTime < 0 || req.EndTime < 0 || (req.EndTime > 0 && req.StartTime > req.EndTime) {
+		return filter, fmt.Errorf("time range invalid")
+	}
+	filter.ServiceType = req.ServiceType
+	filter.StartAt, filter.EndAt = uint64(req.StartTime*1e3), uint64(req.EndTime*1e3)
+
+	if req.KeyInfo.Key != "" {
+		addrHex, err := h.an.FromChainTypeAddress(&req.ChainTypeAddress)
+		if err != nil {
+			return filter, fmt.Errorf("Invalid key info parameter")
+		}
+		filter.InviterChainType, filter.InviterAddress = addrHex.ChainType, addrHex.Hex
+	}
+	if account := strings.ToLower(strings.TrimSpace(req.InviterAccount)); account != "" {
+		filter.InviterId = common.Bytes2Hex(common.GetAccountIdByAccount(account))
+	}
+	return filter, nil
+}
+
+func (h *HttpHandle) doRebateList(req *ReqRebateList, apiResp *http_api.ApiResp) error {
+	var resp RespRebateList
+	resp.List = make([]RebateData, 0)
+
+	filter, err := h.getRebateFilter(req.RebateFilter)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+	cursor, err := req.GetCursor()
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+
+	resp.Total, err = h.dbDao.GetRebateCount(filter)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query rebate")
+		return fmt.Errorf("GetRebateCount err: %s", err.Error())
+	}
+	list, err := h.dbDao.GetRebateList(filter, cursor, req.GetLimit(), req.GetOffset())
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query rebate")
+		return fmt.Errorf("GetRebateList err: %s", err.Error())
+	}
+	for _, v := range list {
+		txHash, _ := common.String2OutPoint(v.Outpoint)
+		resp.List = append(resp.List, RebateData{
+			BlockNumber:      v.BlockNumber,
+			BlockTimestamp:   v.BlockTimestamp,
+			TxHash:           txHash,
+			Action:           v.Action,
+			ServiceType:      v.ServiceType,
+			RewardType:       rebateRewardTypeName(v.RewardType),
+			Reward:           v.Reward,
+			InviteeAccount:   v.InviteeAccount,
+			InviterChainType: v.InviterChainType,
+			InviterAddress:   v.InviterAddress,
+		})
+	}
+	if len(list) > 0 {
+		last := list[len(list)-1]
+		resp.NextCursor = req.NextCursor(len(list), dao.Cursor{Key: last.BlockNumber, Id: last.Id})
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
@@ -0,0 +1,99 @@
+package handle
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+)
+
+type ReqRebateSummary struct {
+	RebateFilter
+}
+
+type RespRebateSummary struct {
+	Count  int64               `json:"count"`
+	Reward uint64              `json:"reward"`
+	List   []RebateSummaryData `json:"list"`
+}
+
+type RebateSummaryData struct {
+	RewardType  string `json:"reward_type"`
+	ServiceType int    `json:"service_type"`
+	Count       int64  `json:"count"`
+	Reward      uint64 `json:"reward"`
+}
+
+func (h *HttpHandle) JsonRpcRebateSummary(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqRebateSummary
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doRebateSummary(&req[0], apiResp); err != nil {
+		log.Error("doRebateSummary err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) RebateSummary(ctx *gin.Context) {
+	var (
+		funcName = "RebateSummary"
+		req      ReqRebateSummary
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doRebateSummary(&req, &apiResp); err != nil {
+		log.Error("doRebateSummary err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doRebateSummary(req *ReqRebateSummary, apiResp *http_api.ApiResp) error {
+	var resp RespRebateSummary
+	resp.List = make([]RebateSummaryData, 0)
+
+	filter, err := h.getRebateFilter(req.RebateFilter)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+
+	list, err := h.dbDao.GetRebateSum(filter)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query rebate")
+		return fmt.Errorf("GetRebateSum err: %s", err.Error())
+	}
+	for _, v := range list {
+		resp.Count += v.Count
+		resp.Reward += v.Reward
+		resp.List = append(resp.List, RebateSummaryData{
+			RewardType:  rebateRewardTypeName(v.RewardType),
+			ServiceType: v.ServiceType,
+			Count:       v.Count,
+			Reward:      v.Reward,
+		})
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
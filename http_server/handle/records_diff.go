@@ -0,0 +1,121 @@
+package handle
+
+import (
+	"das_database/dao"
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+	"strings"
+)
+
+type ReqRecordsDiff struct {
+	Account   string `json:"account"`
+	FromBlock uint64 `json:"from_block"`
+	ToBlock   uint64 `json:"to_block"`
+}
+
+type RespRecordsDiff struct {
+	Added   []RecordsDiffData `json:"added"`
+	Removed []RecordsDiffData `json:"removed"`
+	Changed []RecordsDiffData `json:"changed"`
+}
+
+type RecordsDiffData struct {
+	Type        string `json:"type"`
+	Key         string `json:"key"`
+	Label       string `json:"label"`
+	ValueBefore string `json:"value_before"`
+	ValueAfter  string `json:"value_after"`
+}
+
+func (h *HttpHandle) JsonRpcRecordsDiff(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqRecordsDiff
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doRecordsDiff(&req[0], apiResp); err != nil {
+		log.Error("doRecordsDiff err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) RecordsDiff(ctx *gin.Context) {
+	var (
+		funcName = "RecordsDiff"
+		req      ReqRecordsDiff
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doRecordsDiff(&req, &apiResp); err != nil {
+		log.Error("doRecordsDiff err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doRecordsDiff(req *ReqRecordsDiff, apiResp *http_api.ApiResp) error {
+	var resp RespRecordsDiff
+	resp.Added = make([]RecordsDiffData, 0)
+	resp.Removed = make([]RecordsDiffData, 0)
+	resp.Changed = make([]RecordsDiffData, 0)
+
+	req.Account = strings.TrimSpace(req.Account)
+	if req.Account == "" {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "account is empty")
+		return nil
+	}
+	if req.FromBlock > req.ToBlock {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "from_block is greater than to_block")
+		return nil
+	}
+	accountId := common.Bytes2Hex(common.GetAccountIdByAccount(req.Account))
+
+	history, err := h.dbDao.GetAllRecordsHistory(accountId)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query records history")
+		return fmt.Errorf("GetAllRecordsHistory err: %s", err.Error())
+	}
+	current, err := h.dbDao.GetRecordsByAccountId(accountId)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query records")
+		return fmt.Errorf("GetRecordsByAccountId err: %s", err.Error())
+	}
+
+	from := dao.RecordsAtBlock(history, current, req.FromBlock)
+	to := dao.RecordsAtBlock(history, current, req.ToBlock)
+	for _, v := range dao.DiffRecords(from, to) {
+		data := RecordsDiffData{Type: v.Type, Key: v.Key, Label: v.Label, ValueBefore: v.ValueBefore, ValueAfter: v.ValueAfter}
+		switch {
+		case v.ValueBefore == "":
+			resp.Added = append(resp.Added, data)
+		case v.ValueAfter == "":
+			resp.Removed = append(resp.Removed, data)
+		default:
+			resp.Changed = append(resp.Changed, data)
+		}
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
@@ -0,0 +1,126 @@
+package handle
+
+import (
+	"das_database/dao"
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+	"strings"
+)
+
+type ReqRecordsHistory struct {
+	Pagination
+	Account string `json:"account"`
+}
+
+type RespRecordsHistory struct {
+	Total      int64                `json:"total"`
+	NextCursor string               `json:"next_cursor"`
+	List       []RecordsHistoryData `json:"list"`
+}
+
+type RecordsHistoryData struct {
+	BlockNumber    uint64 `json:"block_number"`
+	BlockTimestamp uint64 `json:"block_timestamp"`
+	TxHash         string `json:"tx_hash"`
+	Action         string `json:"action"`
+	Type           string `json:"type"`
+	Key            string `json:"key"`
+	Label          string `json:"label"`
+	ValueBefore    string `json:"value_before"`
+	ValueAfter     string `json:"value_after"`
+}
+
+func (h *HttpHandle) JsonRpcRecordsHistory(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqRecordsHistory
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doRecordsHistory(&req[0], apiResp); err != nil {
+		log.Error("doRecordsHistory err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) RecordsHistory(ctx *gin.Context) {
+	var (
+		funcName = "RecordsHistory"
+		req      ReqRecordsHistory
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doRecordsHistory(&req, &apiResp); err != nil {
+		log.Error("doRecordsHistory err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doRecordsHistory(req *ReqRecordsHistory, apiResp *http_api.ApiResp) error {
+	var resp RespRecordsHistory
+	resp.List = make([]RecordsHistoryData, 0)
+
+	req.Account = strings.TrimSpace(req.Account)
+	if req.Account == "" {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "account is empty")
+		return nil
+	}
+	accountId := common.Bytes2Hex(common.GetAccountIdByAccount(req.Account))
+	cursor, err := req.GetCursor()
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+
+	resp.Total, err = h.dbDao.GetRecordsHistoryCount(accountId)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query records history count")
+		return fmt.Errorf("GetRecordsHistoryCount err: %s", err.Error())
+	}
+	list, err := h.dbDao.GetRecordsHistoryList(accountId, cursor, req.GetLimit(), req.GetOffset())
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query records history")
+		return fmt.Errorf("GetRecordsHistoryList err: %s", err.Error())
+	}
+	for _, v := range list {
+		resp.List = append(resp.List, RecordsHistoryData{
+			BlockNumber:    v.BlockNumber,
+			BlockTimestamp: v.BlockTimestamp,
+			TxHash:         v.TxHash,
+			Action:         v.Action,
+			Type:           v.Type,
+			Key:            v.Key,
+			Label:          v.Label,
+			ValueBefore:    v.ValueBefore,
+			ValueAfter:     v.ValueAfter,
+		})
+	}
+	if len(list) > 0 {
+		last := list[len(list)-1]
+		resp.NextCursor = req.NextCursor(len(list), dao.Cursor{Key: last.BlockNumber, Id: last.Id})
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
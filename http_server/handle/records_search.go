@@ -0,0 +1,130 @@
+package handle
+
+import (
+	"das_database/dao"
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type ReqRecordsSearch struct {
+	Pagination
+	Value  string `json:"value"`
+	Prefix bool   `json:"prefix"`
+	Key    string `json:"key"`
+	Type   string `json:"type"`
+}
+
+type RespRecordsSearch struct {
+	Total      int64               `json:"total"`
+	NextCursor string              `json:"next_cursor"`
+	List       []RecordsSearchData `json:"list"`
+}
+
+type RecordsSearchData struct {
+	Account   string `json:"account"`
+	Key       string `json:"key"`
+	Type      string `json:"type"`
+	Label     string `json:"label"`
+	Value     string `json:"value"`
+	Ttl       string `json:"ttl"`
+	ExpiredAt uint64 `json:"expired_at"`
+}
+
+func (h *HttpHandle) JsonRpcRecordsSearch(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqRecordsSearch
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doRecordsSearch(&req[0], apiResp); err != nil {
+		log.Error("doRecordsSearch err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) RecordsSearch(ctx *gin.Context) {
+	var (
+		funcName = "RecordsSearch"
+		req      ReqRecordsSearch
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doRecordsSearch(&req, &apiResp); err != nil {
+		log.Error("doRecordsSearch err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doRecordsSearch(req *ReqRecordsSearch, apiResp *http_api.ApiResp) error {
+	var resp RespRecordsSearch
+	resp.List = make([]RecordsSearchData, 0)
+
+	req.Value = strings.TrimSpace(req.Value)
+	if req.Value == "" || (req.Prefix && len(req.Value) < 4) {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "value is too short")
+		return nil
+	}
+	filter := dao.RecordsSearchFilter{
+		Value:        req.Value,
+		Prefix:       req.Prefix,
+		Key:          req.Key,
+		Type:         req.Type,
+		ExpiredAfter: uint64(time.Now().Unix()),
+	}
+	cursor, err := req.GetCursor()
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+
+	resp.Total, err = h.dbDao.CountRecordsByValue(filter)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query records count")
+		return fmt.Errorf("CountRecordsByValue err: %s", err.Error())
+	}
+	list, err := h.dbDao.SearchRecordsByValue(filter, cursor, req.GetLimit(), req.GetOffset())
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to search records")
+		return fmt.Errorf("SearchRecordsByValue err: %s", err.Error())
+	}
+	for _, v := range list {
+		resp.List = append(resp.List, RecordsSearchData{
+			Account:   v.Account,
+			Key:       v.Key,
+			Type:      v.Type,
+			Label:     v.Label,
+			Value:     v.Value,
+			Ttl:       v.Ttl,
+			ExpiredAt: v.ExpiredAt,
+		})
+	}
+	if len(list) > 0 {
+		resp.NextCursor = req.NextCursor(len(list), dao.Cursor{Id: list[len(list)-1].Id})
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
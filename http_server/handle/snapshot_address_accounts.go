@@ -20,8 +20,9 @@ type ReqSnapshotAddressAccounts struct {
 }
 
 type RespSnapshotAddressAccounts struct {
-	Total    int64                    `json:"total"`
-	Accounts []SnapshotAddressAccount `json:"accounts"`
+	Total      int64                    `json:"total"`
+	Accounts   []SnapshotAddressAccount `json:"accounts"`
+	NextCursor string                   `json:"next_cursor"`
 }
 
 type SnapshotAddressAccount struct {
@@ -74,7 +75,7 @@ func (h *HttpHandle) doSnapshotAddressAccounts(req *ReqSnapshotAddressAccounts,
 	var resp RespSnapshotAddressAccounts
 	resp.Accounts = make([]SnapshotAddressAccount, 0)
 
-	addrHex, err := req.ChainTypeAddress.FormatChainTypeAddress(h.dasCore.NetType(), false)
+	addrHex, err := h.an.FromChainTypeAddress(&req.ChainTypeAddress)
 	if err != nil {
 		apiResp.ApiRespErr(api_code.ApiCodeParamsInvalid, "Invalid key info parameter")
 		return nil
@@ -89,8 +90,15 @@ func (h *HttpHandle) doSnapshotAddressAccounts(req *ReqSnapshotAddressAccounts,
 	//	return nil
 	//}
 
+	owners := h.getSnapshotOwners(req.ChainTypeAddress, addrHex.Hex)
+	cursor, err := req.GetStrCursor()
+	if err != nil {
+		apiResp.ApiRespErr(api_code.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+
 	// snapshot
-	list, err := h.dbDao.GetSnapshotAddressAccounts(addrHex.AddressHex, req.RoleType, req.BlockNumber, req.GetLimit(), req.GetOffset())
+	list, err := h.dbDao.GetSnapshotAddressAccounts(owners, req.RoleType, req.BlockNumber, cursor, req.GetLimit(), req.GetOffset())
 	if err != nil {
 		apiResp.ApiRespErr(api_code.ApiCodeDbError, "Failed to query historical account holding")
 		return fmt.Errorf("GetSnapshotAddressAccounts err: %s", err.Error())
@@ -98,8 +106,11 @@ func (h *HttpHandle) doSnapshotAddressAccounts(req *ReqSnapshotAddressAccounts,
 	for _, v := range list {
 		resp.Accounts = append(resp.Accounts, SnapshotAddressAccount{Account: v.Account})
 	}
+	if len(list) > 0 {
+		resp.NextCursor = req.NextCursor(len(list), dao.Cursor{KeyStr: list[len(list)-1].Account})
+	}
 
-	total, err := h.dbDao.GetSnapshotAddressAccountsTotal(addrHex.AddressHex, req.RoleType, req.BlockNumber)
+	total, err := h.dbDao.GetSnapshotAddressAccountsTotal(owners, req.RoleType, req.BlockNumber)
 	if err != nil {
 		apiResp.ApiRespErr(api_code.ApiCodeDbError, "Failed to query historical account holding")
 		return fmt.Errorf("GetSnapshotAddressAccountsTotal err: %s", err.Error())
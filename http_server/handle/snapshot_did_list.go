@@ -1,13 +1,14 @@
 package handle
 
 import (
+	"das_database/dao"
 	"encoding/json"
 	"fmt"
-	"github.com/dotbitHQ/das-lib/common"
 	"github.com/dotbitHQ/das-lib/core"
 	"github.com/dotbitHQ/das-lib/http_api"
 	"github.com/gin-gonic/gin"
 	"github.com/scorpiotzh/toolib"
+	"math"
 	"net/http"
 )
 
@@ -15,11 +16,13 @@ type ReqSnapshotDidList struct {
 	core.ChainTypeAddress
 	BlockNumber   uint64 `json:"block_number"`
 	AccountLength uint64 `json:"account_length"`
+	Pagination
 }
 
 type RespSnapshotDidList struct {
-	Total    int           `json:"total"`
-	Accounts []SnapshotDid `json:"accounts"`
+	Total      int           `json:"total"`
+	Accounts   []SnapshotDid `json:"accounts"`
+	NextCursor string        `json:"next_cursor"`
 }
 
 type SnapshotDid struct {
@@ -72,31 +75,50 @@ func (h *HttpHandle) doSnapshotDidList(req *ReqSnapshotDidList, apiResp *http_ap
 	var resp RespSnapshotDidList
 	resp.Accounts = make([]SnapshotDid, 0)
 
-	addrHex, err := req.ChainTypeAddress.FormatChainTypeAddress(h.dasCore.NetType(), false)
+	addrHex, err := h.an.FromChainTypeAddress(&req.ChainTypeAddress)
 	if err != nil {
 		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "Invalid key info parameter")
 		return nil
 	}
-	log.Info("doSnapshotDidList:", addrHex.AddressHex, addrHex.DasAlgorithmId)
+	log.Info("doSnapshotDidList:", addrHex.Hex, addrHex.AlgorithmId)
+
+	owners := h.getSnapshotOwners(req.ChainTypeAddress, addrHex.Hex)
+	cursor, err := req.GetStrCursor()
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+	if req.AccountLength > math.MaxUint8 {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "account_length invalid")
+		return nil
+	}
+	accLen := uint8(req.AccountLength)
+
+	// requests without any paging params keep getting the whole list
+	limit, offset := 0, 0
+	if req.Page != 0 || req.Size != 0 || cursor != nil {
+		limit, offset = req.GetLimit(), req.GetOffset()
+	}
 
 	// snapshot
-	list, err := h.dbDao.GetSnapshotDidList(addrHex.AddressHex, req.BlockNumber, req.AccountLength)
+	list, err := h.dbDao.GetSnapshotDidList(owners, req.BlockNumber, accLen, cursor, limit, offset)
 	if err != nil {
 		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query did list")
 		return fmt.Errorf("GetSnapshotDidList err: %s", err.Error())
 	}
 	for _, v := range list {
-		_, accLen, err := common.GetDotBitAccountLength(v.Account)
-		if err != nil {
-			apiResp.ApiRespErr(http_api.ApiCodeError500, err.Error())
-			return fmt.Errorf("GetDotBitAccountLength err: %s", err.Error())
-		}
-		if uint64(accLen) != req.AccountLength {
-			continue
-		}
 		resp.Accounts = append(resp.Accounts, SnapshotDid{Account: v.Account})
 	}
-	resp.Total = len(list)
+	if limit > 0 && len(list) > 0 {
+		resp.NextCursor = req.NextCursor(len(list), dao.Cursor{KeyStr: list[len(list)-1].Account})
+	}
+
+	total, err := h.dbDao.GetSnapshotDidListTotal(owners, req.BlockNumber, accLen)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query did list")
+		return fmt.Errorf("GetSnapshotDidListTotal err: %s", err.Error())
+	}
+	resp.Total = int(total)
 
 	apiResp.ApiRespOK(resp)
 	return nil
@@ -3,10 +3,10 @@ package handle
 import (
 	"das_database/dao"
 	"das_database/http_server/api_code"
+	"das_database/normalize"
 	"encoding/json"
 	"fmt"
 	"github.com/dotbitHQ/das-lib/common"
-	"github.com/dotbitHQ/das-lib/core"
 	"github.com/dotbitHQ/das-lib/http_api"
 	"github.com/gin-gonic/gin"
 	"github.com/scorpiotzh/toolib"
@@ -170,39 +170,29 @@ func (h *HttpHandle) doSnapshotPermissionsInfo(req *ReqSnapshotPermissionsInfo,
 	resp.OwnerAlgorithmId = info.OwnerAlgorithmId
 	resp.ManagerAlgorithmId = info.ManagerAlgorithmId
 
-	if info.OwnerAlgorithmId == common.DasAlgorithmIdAnyLock {
-		resp.Owner = info.Owner
-	} else {
-		owner, err := h.dasCore.Daf().HexToNormal(core.DasAddressHex{
-			DasAlgorithmId:    info.OwnerAlgorithmId,
-			DasSubAlgorithmId: info.OwnerSubAid,
-			AddressHex:        info.Owner,
-			IsMulti:           false,
-			ChainType:         info.OwnerAlgorithmId.ToChainType(),
-		})
-		if err != nil {
-			apiResp.ApiRespErr(http_api.ApiCodeError500, "HexToNormal Err")
-			return fmt.Errorf("HexToNormal err: %s", err.Error())
-		}
-		resp.Owner = owner.AddressNormal
-	}
-
-	if info.ManagerAlgorithmId == common.DasAlgorithmIdAnyLock {
-		resp.Manager = info.Manager
-	} else {
-		manager, err := h.dasCore.Daf().HexToNormal(core.DasAddressHex{
-			DasAlgorithmId:    info.ManagerAlgorithmId,
-			DasSubAlgorithmId: info.ManagerSubAid,
-			AddressHex:        info.Manager,
-			IsMulti:           false,
-			ChainType:         info.ManagerAlgorithmId.ToChainType(),
-		})
-		if err != nil {
-			apiResp.ApiRespErr(http_api.ApiCodeError500, "HexToNormal Err")
-			return fmt.Errorf("HexToNormal err: %s", err.Error())
-		}
-		resp.Manager = manager.AddressNormal
+	owner, err := h.an.ToNormal(normalize.Address{
+		ChainType:      info.OwnerAlgorithmId.ToChainType(),
+		AlgorithmId:    info.OwnerAlgorithmId,
+		SubAlgorithmId: info.OwnerSubAid,
+		Hex:            info.Owner,
+	})
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeError500, "HexToNormal Err")
+		return fmt.Errorf("ToNormal err: %s", err.Error())
+	}
+	resp.Owner = owner
+
+	manager, err := h.an.ToNormal(normalize.Address{
+		ChainType:      info.ManagerAlgorithmId.ToChainType(),
+		AlgorithmId:    info.ManagerAlgorithmId,
+		SubAlgorithmId: info.ManagerSubAid,
+		Hex:            info.Manager,
+	})
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeError500, "HexToNormal Err")
+		return fmt.Errorf("ToNormal err: %s", err.Error())
 	}
+	resp.Manager = manager
 
 	log.Info("doSnapshotPermissionsInfo:", resp)
 	apiResp.ApiRespOK(resp)
@@ -1,9 +1,9 @@
 package handle
 
 import (
-	"das_database/http_server/api_code"
 	"encoding/json"
 	"fmt"
+	"github.com/dotbitHQ/das-lib/http_api"
 	"github.com/gin-gonic/gin"
 	"github.com/scorpiotzh/toolib"
 	"net/http"
@@ -17,17 +17,17 @@ type RespSnapshotProgress struct {
 	BlockNumber uint64 `json:"block_number"`
 }
 
-func (h *HttpHandle) JsonRpcSnapshotProgress(p json.RawMessage, apiResp *api_code.ApiResp) {
+func (h *HttpHandle) JsonRpcSnapshotProgress(p json.RawMessage, apiResp *http_api.ApiResp) {
 	var req []ReqSnapshotProgress
 	err := json.Unmarshal(p, &req)
 	if err != nil {
 		log.Error("json.Unmarshal err:", err.Error())
-		apiResp.ApiRespErr(api_code.ApiCodeParamsInvalid, "params invalid")
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
 		return
 	}
 	if len(req) != 1 {
 		log.Error("len(req) is :", len(req))
-		apiResp.ApiRespErr(api_code.ApiCodeParamsInvalid, "params invalid")
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
 		return
 	}
 
@@ -40,13 +40,13 @@ func (h *HttpHandle) SnapshotProgress(ctx *gin.Context) {
 	var (
 		funcName = "SnapshotProgress"
 		req      ReqSnapshotProgress
-		apiResp  api_code.ApiResp
+		apiResp  http_api.ApiResp
 		err      error
 	)
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
-		apiResp.ApiRespErr(api_code.ApiCodeParamsInvalid, "params invalid")
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
 		ctx.JSON(http.StatusOK, apiResp)
 		return
 	}
@@ -59,12 +59,12 @@ func (h *HttpHandle) SnapshotProgress(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, apiResp)
 }
 
-func (h *HttpHandle) doSnapshotProgress(req *ReqSnapshotProgress, apiResp *api_code.ApiResp) error {
+func (h *HttpHandle) doSnapshotProgress(req *ReqSnapshotProgress, apiResp *http_api.ApiResp) error {
 	var resp RespSnapshotProgress
 
 	txS, err := h.dbDao.GetTxSnapshotSchedule()
 	if err != nil {
-		apiResp.ApiRespErr(api_code.ApiCodeDbError, "Failed to query snapshot progress")
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query snapshot progress")
 		return fmt.Errorf("GetTxSnapshotSchedule err: %s", err.Error())
 	} else if txS.Id > 0 {
 		resp.BlockNumber = txS.BlockNumber
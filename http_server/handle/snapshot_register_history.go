@@ -1,6 +1,7 @@
 package handle
 
 import (
+	"das_database/dao"
 	"das_database/http_server/api_code"
 	"encoding/json"
 	"fmt"
@@ -81,22 +82,18 @@ func (h *HttpHandle) doSnapshotRegisterHistory(req *ReqSnapshotRegisterHistory,
 	theTimestamp := uint64(theTime.Unix())
 	log.Info("theTimestamp:", theTimestamp)
 
-	page := Pagination{
-		Page:    1,
-		Size:    10000,
-		maxSize: 10000,
-	}
+	limit := 10000
+	var cursor *dao.Cursor
 
 	var res = make(map[string]registerInfo)
 	var owner = make(map[string]struct{})
 
 	for {
-		list, err := h.dbDao.GetRegisterHistory(page.GetLimit(), page.GetOffset())
+		list, err := h.dbDao.GetRegisterHistory(cursor, limit)
 		if err != nil {
 			apiResp.ApiRespErr(api_code.ApiCodeDbError, "Failed to query history info")
 			return fmt.Errorf("GetRegisterHistory err: %s", err.Error())
 		}
-		page.Page++
 		for _, v := range list {
 			_, length, _ := common.GetDotBitAccountLength(v.Account)
 			tm := time.Unix(int64(v.RegisteredAt), 0)
@@ -124,9 +121,11 @@ func (h *HttpHandle) doSnapshotRegisterHistory(req *ReqSnapshotRegisterHistory,
 			}
 			res[registeredAt] = tmp
 		}
-		if len(list) == 0 || len(list) < page.GetLimit() {
+		if len(list) < limit {
 			break
 		}
+		last := list[len(list)-1]
+		cursor = &dao.Cursor{Key: last.BlockNumber, Id: last.Id}
 	}
 	log.Info("res:", len(res))
 
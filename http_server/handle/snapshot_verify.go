@@ -67,40 +67,37 @@ func (h *HttpHandle) SnapshotVerify(ctx *gin.Context) {
 func (h *HttpHandle) doSnapshotVerify(req *ReqSnapshotVerify, apiResp *http_api.ApiResp) error {
 	var resp RespSnapshotVerify
 
-	addrHex, err := req.ChainTypeAddress.FormatChainTypeAddress(h.dasCore.NetType(), false)
+	addrHex, err := h.an.FromChainTypeAddress(&req.ChainTypeAddress)
 	if err != nil {
 		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "Invalid key info")
 		return nil
 	}
-	if addrHex.DasAlgorithmId == common.DasAlgorithmIdAnyLock {
+	if addrHex.AlgorithmId == common.DasAlgorithmIdAnyLock {
 		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "Invalid key info")
 		return nil
 	}
 
 	signature := req.Signature
-	addressHex := addrHex.AddressHex
-	if addrHex.DasAlgorithmId == common.DasAlgorithmIdWebauthn {
-		signAddressHex, err := h.dasCore.Daf().NormalToHex(core.DasAddressNormal{
-			ChainType:     common.ChainTypeWebauthn,
-			AddressNormal: req.PasskeySignAddress,
-		})
+	addressHex := addrHex.Hex
+	if addrHex.AlgorithmId == common.DasAlgorithmIdWebauthn {
+		signAddressHex, err := h.an.FromNormal(common.ChainTypeWebauthn, req.PasskeySignAddress)
 		if err != nil {
 			apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "Invalid key info")
 			return nil
 		}
-		addressHex = signAddressHex.AddressHex
+		addressHex = signAddressHex.Hex
 		loginAddrHex := core.DasAddressHex{
 			DasAlgorithmId:    common.DasAlgorithmIdWebauthn,
 			DasSubAlgorithmId: common.DasWebauthnSubAlgorithmIdES256,
-			AddressHex:        addrHex.AddressHex,
-			AddressPayload:    common.Hex2Bytes(addrHex.AddressHex),
+			AddressHex:        addrHex.Hex,
+			AddressPayload:    addrHex.Payload(),
 			ChainType:         common.ChainTypeWebauthn,
 		}
 		signAddrHex := core.DasAddressHex{
 			DasAlgorithmId:    common.DasAlgorithmIdWebauthn,
 			DasSubAlgorithmId: common.DasWebauthnSubAlgorithmIdES256,
-			AddressHex:        signAddressHex.AddressHex,
-			AddressPayload:    common.Hex2Bytes(signAddressHex.AddressHex),
+			AddressHex:        signAddressHex.Hex,
+			AddressPayload:    signAddressHex.Payload(),
 			ChainType:         common.ChainTypeWebauthn,
 		}
 		idx, err := h.dasCore.GetIdxOfKeylist(loginAddrHex, signAddrHex)
@@ -115,7 +112,7 @@ func (h *HttpHandle) doSnapshotVerify(req *ReqSnapshotVerify, apiResp *http_api.
 		h.dasCore.AddPkIndexForSignMsg(&signature, idx)
 	}
 
-	resp.Verified, _, err = http_api.VerifySignature(addrHex.DasAlgorithmId, req.Message, signature, addressHex)
+	resp.Verified, _, err = http_api.VerifySignature(addrHex.AlgorithmId, req.Message, signature, addressHex)
 	if err != nil {
 		apiResp.ApiRespErr(http_api.ApiCodeSignError, "VerifySignature err")
 		return fmt.Errorf("VerifySignature err: %s", err.Error())
@@ -0,0 +1,169 @@
+package handle
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/core"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/dotbitHQ/das-lib/witness"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+	"strings"
+)
+
+type ReqSubAccountConfig struct {
+	ParentAccount string `json:"parent_account"`
+}
+
+// RespSubAccountConfig the sub-account cell fields are read from the live cell, they are empty
+// when sub-accounts are not enabled. Profits are in shannon
+type RespSubAccountConfig struct {
+	ParentAccount        string              `json:"parent_account"`
+	EnableSubAccount     uint8               `json:"enable_sub_account"`
+	RenewSubAccountPrice uint64              `json:"renew_sub_account_price"`
+	Outpoint             string              `json:"outpoint"`
+	Flag                 string              `json:"flag"`
+	DasProfit            uint64              `json:"das_profit"`
+	OwnerProfit          uint64              `json:"owner_profit"`
+	CustomScriptArgs     string              `json:"custom_script_args"`
+	CustomScriptConfig   string              `json:"custom_script_config"`
+	AutoDistribution     bool                `json:"auto_distribution"`
+	PriceRulesHash       string              `json:"price_rules_hash"`
+	PreservedRulesHash   string              `json:"preserved_rules_hash"`
+	CustomScript         *SubAccountConfigTx `json:"custom_script"`
+	Rules                *SubAccountConfigTx `json:"rules"`
+}
+
+// SubAccountConfigTx the last tx which configured the custom script or the rules
+type SubAccountConfigTx struct {
+	TxHash         string `json:"tx_hash"`
+	BlockNumber    uint64 `json:"block_number"`
+	BlockTimestamp uint64 `json:"block_timestamp"`
+}
+
+var subAccountFlagNames = map[witness.FlagType]string{
+	witness.FlagTypeDefault:     "default",
+	witness.FlagTypeCustomPrice: "custom_price",
+	witness.FlagTypeCustomRule:  "custom_rule",
+}
+
+func (h *HttpHandle) JsonRpcSubAccountConfig(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqSubAccountConfig
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doSubAccountConfig(&req[0], apiResp); err != nil {
+		log.Error("doSubAccountConfig err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) SubAccountConfig(ctx *gin.Context) {
+	var (
+		funcName = "SubAccountConfig"
+		req      ReqSubAccountConfig
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doSubAccountConfig(&req, &apiResp); err != nil {
+		log.Error("doSubAccountConfig err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doSubAccountConfig(req *ReqSubAccountConfig, apiResp *http_api.ApiResp) error {
+	var resp RespSubAccountConfig
+
+	resp.ParentAccount = strings.ToLower(strings.TrimSpace(req.ParentAccount))
+	if resp.ParentAccount == "" {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "parent_account is empty")
+		return nil
+	}
+	parentAccountId := common.Bytes2Hex(common.GetAccountIdByAccount(resp.ParentAccount))
+
+	parentInfo, err := h.dbDao.GetAccountInfoByAccountId(parentAccountId)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query parent account")
+		return fmt.Errorf("GetAccountInfoByAccountId err: %s", err.Error())
+	} else if parentInfo.Id == 0 {
+		apiResp.ApiRespErr(http_api.ApiCodeAccountNotExist, "parent account not exist")
+		return nil
+	}
+	resp.EnableSubAccount = parentInfo.EnableSubAccount
+	resp.RenewSubAccountPrice = parentInfo.RenewSubAccountPrice
+
+	customScript, err := h.dbDao.GetCustomScriptInfo(parentAccountId)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query custom script")
+		return fmt.Errorf("GetCustomScriptInfo err: %s", err.Error())
+	}
+	if customScript.Id > 0 {
+		txHash, _ := common.String2OutPoint(customScript.Outpoint)
+		resp.CustomScript = &SubAccountConfigTx{
+			TxHash:         txHash,
+			BlockNumber:    customScript.BlockNumber,
+			BlockTimestamp: customScript.BlockTimestamp,
+		}
+	}
+	ruleConfig, err := h.dbDao.GetRuleConfig(parentAccountId)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query rule config")
+		return fmt.Errorf("GetRuleConfig err: %s", err.Error())
+	}
+	if ruleConfig.Id > 0 {
+		resp.Rules = &SubAccountConfigTx{
+			TxHash:         ruleConfig.TxHash,
+			BlockNumber:    ruleConfig.BlockNumber,
+			BlockTimestamp: ruleConfig.BlockTimestamp,
+		}
+	}
+
+	if resp.EnableSubAccount == 1 {
+		subAccountCell, err := h.dasCore.GetSubAccountCell(parentAccountId)
+		if err != nil && err != core.SubAccountNotFound {
+			apiResp.ApiRespErr(http_api.ApiCodeError500, "Failed to get sub-account cell")
+			return fmt.Errorf("GetSubAccountCell err: %s", err.Error())
+		}
+		if subAccountCell != nil {
+			detail := witness.ConvertSubAccountCellOutputData(subAccountCell.OutputData)
+			resp.Outpoint = common.OutPointStruct2String(subAccountCell.OutPoint)
+			resp.Flag = subAccountFlagNames[detail.Flag]
+			resp.DasProfit = detail.DasProfit
+			resp.OwnerProfit = detail.OwnerProfit
+			if detail.HasCustomScriptArgs() {
+				resp.CustomScriptArgs = common.Bytes2Hex(detail.CustomScriptArgs)
+				resp.CustomScriptConfig = common.Bytes2Hex(detail.CustomScriptConfig)
+			}
+			resp.AutoDistribution = detail.AutoDistribution == witness.AutoDistributionEnable
+			if len(detail.PriceRulesHash) > 0 {
+				resp.PriceRulesHash = common.Bytes2Hex(detail.PriceRulesHash)
+			}
+			if len(detail.PreservedRulesHash) > 0 {
+				resp.PreservedRulesHash = common.Bytes2Hex(detail.PreservedRulesHash)
+			}
+		}
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
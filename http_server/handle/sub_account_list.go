@@ -0,0 +1,170 @@
+package handle
+
+import (
+	"das_database/dao"
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/core"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ReqSubAccountList the owner, status and expiry filters are optional, expiry is one of active, expiring and expired,
+// days is the window of expiring, 30 by default
+type ReqSubAccountList struct {
+	Pagination
+	core.ChainTypeAddress
+	ParentAccount string              `json:"parent_account"`
+	Status        []dao.AccountStatus `json:"status"`
+	Expiry        string              `json:"expiry"`
+	Days          uint64              `json:"days"`
+}
+
+type RespSubAccountList struct {
+	Total      int64            `json:"total"`
+	NextCursor string           `json:"next_cursor"`
+	List       []SubAccountData `json:"list"`
+}
+
+type SubAccountData struct {
+	Account          string           `json:"account"`
+	OwnerChainType   common.ChainType `json:"owner_chain_type"`
+	Owner            string           `json:"owner"`
+	ManagerChainType common.ChainType `json:"manager_chain_type"`
+	Manager          string           `json:"manager"`
+	Status           uint8            `json:"status"`
+	RegisteredAt     uint64           `json:"registered_at"`
+	ExpiredAt        uint64           `json:"expired_at"`
+}
+
+func (h *HttpHandle) JsonRpcSubAccountList(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqSubAccountList
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doSubAccountList(&req[0], apiResp); err != nil {
+		log.Error("doSubAccountList err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) SubAccountList(ctx *gin.Context) {
+	var (
+		funcName = "SubAccountList"
+		req      ReqSubAccountList
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doSubAccountList(&req, &apiResp); err != nil {
+		log.Error("doSubAccountList err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doSubAccountList(req *ReqSubAccountList, apiResp *http_api.ApiResp) error {
+	var resp RespSubAccountList
+	resp.List = make([]SubAccountData, 0)
+
+	parentAccount := strings.ToLower(strings.TrimSpace(req.ParentAccount))
+	if parentAccount == "" {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "parent_account is empty")
+		return nil
+	}
+	switch req.Expiry {
+	case "", dao.SubAccountExpiryActive, dao.SubAccountExpiryExpiring, dao.SubAccountExpiryExpired:
+	default:
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "expiry invalid")
+		return nil
+	}
+	if req.Days == 0 {
+		req.Days = 30
+	} else if req.Days > 365 {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "days should be in [1,365]")
+		return nil
+	}
+	expiryFilter, err := h.getExpiryFilter(req.ChainTypeAddress, parentAccount)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return nil
+	}
+	now := uint64(time.Now().Unix())
+	filter := dao.SubAccountFilter{
+		ParentAccountId: expiryFilter.ParentAccountId,
+		OwnerChainType:  expiryFilter.OwnerChainType,
+		Owner:           expiryFilter.Owner,
+		DidCellHolder:   expiryFilter.DidCellHolder,
+		Status:          req.Status,
+		Expiry:          req.Expiry,
+		Now:             now,
+		ExpiringTo:      now + req.Days*86400,
+	}
+	cursor, err := req.GetCursor()
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+
+	resp.Total, err = h.dbDao.GetSubAccountCount(filter)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query sub-account count")
+		return fmt.Errorf("GetSubAccountCount err: %s", err.Error())
+	}
+	list, err := h.dbDao.GetSubAccountList(filter, cursor, req.GetLimit(), req.GetOffset())
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query sub-account list")
+		return fmt.Errorf("GetSubAccountList err: %s", err.Error())
+	}
+	didCellOwners, err := h.getDidCellOwners(list)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query sub-account list")
+		return fmt.Errorf("getDidCellOwners err: %s", err.Error())
+	}
+	for _, v := range list {
+		data := SubAccountData{
+			Account:          v.Account,
+			OwnerChainType:   v.OwnerChainType,
+			Owner:            v.Owner,
+			ManagerChainType: v.ManagerChainType,
+			Manager:          v.Manager,
+			Status:           v.Status,
+			RegisteredAt:     v.RegisteredAt,
+			ExpiredAt:        v.ExpiredAt,
+		}
+		// a did cell has no manager, the holder of the lock is both
+		if owner, ok := didCellOwners[v.AccountId]; ok {
+			data.OwnerChainType, data.Owner = common.ChainTypeAnyLock, owner
+			data.ManagerChainType, data.Manager = common.ChainTypeAnyLock, owner
+		}
+		resp.List = append(resp.List, data)
+	}
+	if len(list) > 0 {
+		last := list[len(list)-1]
+		resp.NextCursor = req.NextCursor(len(list), dao.Cursor{Key: last.RegisteredAt, Id: last.Id})
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
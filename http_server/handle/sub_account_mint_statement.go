@@ -0,0 +1,252 @@
+package handle
+
+import (
+	"das_database/dao"
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"github.com/shopspring/decimal"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ReqSubAccountMintStatement the time range is [start_time, end_time) in seconds, periods are in UTC
+type ReqSubAccountMintStatement struct {
+	ServiceProviderId string `json:"service_provider_id"`
+	ParentAccount     string `json:"parent_account"`
+	Period            string `json:"period"`
+	StartTime         int64  `json:"start_time"`
+	EndTime           int64  `json:"end_time"`
+}
+
+// RespSubAccountMintStatement amounts are in shannon
+type RespSubAccountMintStatement struct {
+	OpeningBalance decimal.Decimal            `json:"opening_balance"`
+	Income         decimal.Decimal            `json:"income"`
+	Expenditure    decimal.Decimal            `json:"expenditure"`
+	ClosingBalance decimal.Decimal            `json:"closing_balance"`
+	Periods        []MintStatementPeriod      `json:"periods"`
+	Breakdown      []MintStatementBreakdown   `json:"breakdown"`
+	Discrepancies  []MintStatementDiscrepancy `json:"discrepancies"`
+}
+
+type MintStatementPeriod struct {
+	Period      string          `json:"period"`
+	Income      decimal.Decimal `json:"income"`
+	Expenditure decimal.Decimal `json:"expenditure"`
+	Balance     decimal.Decimal `json:"balance"`
+}
+
+type MintStatementBreakdown struct {
+	ParentAccount string                       `json:"parent_account"`
+	SubAction     common.SubAction             `json:"sub_action"`
+	TxType        dao.SubAccountAutoMintTxType `json:"tx_type"`
+	Count         int64                        `json:"count"`
+	Amount        decimal.Decimal              `json:"amount"`
+}
+
+type MintStatementDiscrepancy struct {
+	BlockNumber   uint64                      `json:"block_number"`
+	TxHash        string                      `json:"tx_hash"`
+	ParentAccount string                      `json:"parent_account"`
+	Recorded      decimal.Decimal             `json:"recorded"`
+	Withdrawn     decimal.Decimal             `json:"withdrawn"`
+	Balance       decimal.Decimal             `json:"balance"`
+	Status        dao.AutoMintReconcileStatus `json:"status"`
+}
+
+const (
+	MintStatementPeriodDay   = "day"
+	MintStatementPeriodMonth = "month"
+)
+
+func (h *HttpHandle) JsonRpcSubAccountMintStatement(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqSubAccountMintStatement
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doSubAccountMintStatement(&req[0], apiResp); err != nil {
+		log.Error("doSubAccountMintStatement err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) SubAccountMintStatement(ctx *gin.Context) {
+	var (
+		funcName = "SubAccountMintStatement"
+		req      ReqSubAccountMintStatement
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doSubAccountMintStatement(&req, &apiResp); err != nil {
+		log.Error("doSubAccountMintStatement err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) getAutoMintFilter(serviceProviderId, parentAccount string, startTime, endTime int64) (filter dao.AutoMintFilter, err error) {
+	filter.ServiceProviderId = strings.ToLower(strings.TrimSpace(serviceProviderId))
+	if filter.ServiceProviderId == "" {
+		return filter, fmt.Errorf("service_provider_id is empty")
+	}
+	if startTime < 0 || endTime < 0 || (endTime > 0 && startTime > endTime) {
+		return filter, fmt.Errorf("time range invalid")
+	}
+	if parentAccount = strings.ToLower(strings.TrimSpace(parentAccount)); parentAccount != "" {
+		filter.ParentAccountId = common.Bytes2Hex(common.GetAccountIdByAccount(parentAccount))
+	}
+	filter.StartAt, filter.EndAt = uint64(startTime*1e3), uint64(endTime*1e3)
+	return filter, nil
+}
+
+// getAccountNames falls back to the account id for the accounts no longer in t_account_info
+func (h *HttpHandle) getAccountNames(accountIds []string) (map[string]string, error) {
+	res := make(map[string]string)
+	list, err := h.dbDao.GetAccountNameList(accountIds)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range list {
+		res[v.AccountId] = v.Account
+	}
+	for _, v := range accountIds {
+		if _, ok := res[v]; !ok {
+			res[v] = v
+		}
+	}
+	return res, nil
+}
+
+func (h *HttpHandle) doSubAccountMintStatement(req *ReqSubAccountMintStatement, apiResp *http_api.ApiResp) error {
+	var resp RespSubAccountMintStatement
+	resp.Periods = make([]MintStatementPeriod, 0)
+	resp.Breakdown = make([]MintStatementBreakdown, 0)
+	resp.Discrepancies = make([]MintStatementDiscrepancy, 0)
+
+	if req.Period == "" {
+		req.Period = MintStatementPeriodMonth
+	}
+	if req.Period != MintStatementPeriodDay && req.Period != MintStatementPeriodMonth {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "period invalid")
+		return nil
+	}
+	filter, err := h.getAutoMintFilter(req.ServiceProviderId, req.ParentAccount, req.StartTime, req.EndTime)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+
+	// opening balance
+	if filter.StartAt > 0 {
+		opening, err := h.dbDao.GetAutoMintBalance(dao.AutoMintFilter{
+			ServiceProviderId: filter.ServiceProviderId,
+			ParentAccountId:   filter.ParentAccountId,
+			EndAt:             filter.StartAt,
+		})
+		if err != nil {
+			apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query statement")
+			return fmt.Errorf("GetAutoMintBalance err: %s", err.Error())
+		}
+		resp.OpeningBalance = opening.Balance()
+	}
+
+	// periods
+	dailyList, err := h.dbDao.GetAutoMintDailySum(filter)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query statement")
+		return fmt.Errorf("GetAutoMintDailySum err: %s", err.Error())
+	}
+	layout := "2006-01-02"
+	if req.Period == MintStatementPeriodMonth {
+		layout = "2006-01"
+	}
+	balance := resp.OpeningBalance
+	for _, v := range dailyList {
+		key := time.Unix(v.Day*86400, 0).UTC().Format(layout)
+		if len(resp.Periods) == 0 || resp.Periods[len(resp.Periods)-1].Period != key {
+			resp.Periods = append(resp.Periods, MintStatementPeriod{Period: key})
+		}
+		period := &resp.Periods[len(resp.Periods)-1]
+		switch v.TxType {
+		case dao.SubAccountAutoMintTxTypeIncome:
+			period.Income = period.Income.Add(v.Amount)
+			resp.Income = resp.Income.Add(v.Amount)
+			balance = balance.Add(v.Amount)
+		case dao.SubAccountAutoMintTxTypeExpenditure:
+			period.Expenditure = period.Expenditure.Add(v.Amount)
+			resp.Expenditure = resp.Expenditure.Add(v.Amount)
+			balance = balance.Sub(v.Amount)
+		}
+		period.Balance = balance
+	}
+	resp.ClosingBalance = balance
+
+	// breakdown
+	breakdownList, err := h.dbDao.GetAutoMintBreakdown(filter)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query statement")
+		return fmt.Errorf("GetAutoMintBreakdown err: %s", err.Error())
+	}
+	discrepancyList, err := h.dbDao.GetAutoMintDiscrepancyList(filter.ServiceProviderId, filter.ParentAccountId)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query statement")
+		return fmt.Errorf("GetAutoMintDiscrepancyList err: %s", err.Error())
+	}
+	var accountIds []string
+	for _, v := range breakdownList {
+		accountIds = append(accountIds, v.ParentAccountId)
+	}
+	for _, v := range discrepancyList {
+		accountIds = append(accountIds, v.ParentAccountId)
+	}
+	accountNames, err := h.getAccountNames(accountIds)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query statement")
+		return fmt.Errorf("getAccountNames err: %s", err.Error())
+	}
+	for _, v := range breakdownList {
+		resp.Breakdown = append(resp.Breakdown, MintStatementBreakdown{
+			ParentAccount: accountNames[v.ParentAccountId],
+			SubAction:     v.SubAction,
+			TxType:        v.TxType,
+			Count:         v.Count,
+			Amount:        v.Amount,
+		})
+	}
+	for _, v := range discrepancyList {
+		resp.Discrepancies = append(resp.Discrepancies, MintStatementDiscrepancy{
+			BlockNumber:   v.BlockNumber,
+			TxHash:        v.TxHash,
+			ParentAccount: accountNames[v.ParentAccountId],
+			Recorded:      v.Recorded,
+			Withdrawn:     v.Withdrawn,
+			Balance:       v.Balance,
+			Status:        v.Status,
+		})
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
@@ -0,0 +1,156 @@
+package handle
+
+import (
+	"das_database/dao"
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"github.com/shopspring/decimal"
+	"net/http"
+)
+
+type ReqSubAccountMintStatementList struct {
+	Pagination
+	ServiceProviderId string `json:"service_provider_id"`
+	ParentAccount     string `json:"parent_account"`
+	StartTime         int64  `json:"start_time"`
+	EndTime           int64  `json:"end_time"`
+}
+
+type RespSubAccountMintStatementList struct {
+	Total      int64                    `json:"total"`
+	NextCursor string                   `json:"next_cursor"`
+	List       []MintStatementEntryData `json:"list"`
+}
+
+// MintStatementEntryData balance is the running balance of the provider under the parent account after the entry
+type MintStatementEntryData struct {
+	BlockNumber    uint64                       `json:"block_number"`
+	BlockTimestamp uint64                       `json:"block_timestamp"`
+	TxHash         string                       `json:"tx_hash"`
+	ParentAccount  string                       `json:"parent_account"`
+	TxType         dao.SubAccountAutoMintTxType `json:"tx_type"`
+	SubAction      common.SubAction             `json:"sub_action"`
+	Years          uint64                       `json:"years"`
+	Amount         decimal.Decimal              `json:"amount"`
+	Balance        decimal.Decimal              `json:"balance"`
+}
+
+func (h *HttpHandle) JsonRpcSubAccountMintStatementList(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqSubAccountMintStatementList
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doSubAccountMintStatementList(&req[0], apiResp); err != nil {
+		log.Error("doSubAccountMintStatementList err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) SubAccountMintStatementList(ctx *gin.Context) {
+	var (
+		funcName = "SubAccountMintStatementList"
+		req      ReqSubAccountMintStatementList
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doSubAccountMintStatementList(&req, &apiResp); err != nil {
+		log.Error("doSubAccountMintStatementList err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doSubAccountMintStatementList(req *ReqSubAccountMintStatementList, apiResp *http_api.ApiResp) error {
+	var resp RespSubAccountMintStatementList
+	resp.List = make([]MintStatementEntryData, 0)
+
+	filter, err := h.getAutoMintFilter(req.ServiceProviderId, req.ParentAccount, req.StartTime, req.EndTime)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+	cursor, err := req.GetCursor()
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+
+	resp.Total, err = h.dbDao.GetAutoMintStatementCount(filter)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query statement")
+		return fmt.Errorf("GetAutoMintStatementCount err: %s", err.Error())
+	}
+	list, err := h.dbDao.GetAutoMintStatementList(filter, cursor, req.GetLimit(), req.GetOffset())
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query statement")
+		return fmt.Errorf("GetAutoMintStatementList err: %s", err.Error())
+	}
+
+	// the balance after the newest entry of each parent account in the page, then walk back
+	var accountIds []string
+	balanceMap := make(map[string]decimal.Decimal)
+	for _, v := range list {
+		if _, ok := balanceMap[v.ParentAccountId]; ok {
+			continue
+		}
+		balance, err := h.dbDao.GetAutoMintBalanceAt(v.ServiceProviderId, v.ParentAccountId, v.BlockNumber, v.Id)
+		if err != nil {
+			apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query statement")
+			return fmt.Errorf("GetAutoMintBalanceAt err: %s", err.Error())
+		}
+		balanceMap[v.ParentAccountId] = balance.Balance()
+		accountIds = append(accountIds, v.ParentAccountId)
+	}
+	accountNames, err := h.getAccountNames(accountIds)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query statement")
+		return fmt.Errorf("getAccountNames err: %s", err.Error())
+	}
+	for _, v := range list {
+		balance := balanceMap[v.ParentAccountId]
+		resp.List = append(resp.List, MintStatementEntryData{
+			BlockNumber:    v.BlockNumber,
+			BlockTimestamp: v.BlockTimestamp,
+			TxHash:         v.TxHash,
+			ParentAccount:  accountNames[v.ParentAccountId],
+			TxType:         v.TxType,
+			SubAction:      v.SubAction,
+			Years:          v.Years,
+			Amount:         v.Price,
+			Balance:        balance,
+		})
+		if v.TxType == dao.SubAccountAutoMintTxTypeIncome {
+			balanceMap[v.ParentAccountId] = balance.Sub(v.Price)
+		} else {
+			balanceMap[v.ParentAccountId] = balance.Add(v.Price)
+		}
+	}
+	if len(list) > 0 {
+		last := list[len(list)-1]
+		resp.NextCursor = req.NextCursor(len(list), dao.Cursor{Key: last.BlockNumber, Id: last.Id})
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
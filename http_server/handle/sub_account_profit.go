@@ -0,0 +1,83 @@
+package handle
+
+import (
+	"encoding/json"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+	"strings"
+)
+
+// ReqSubAccountProfit the time range is [start_time, end_time) in seconds
+type ReqSubAccountProfit struct {
+	Pagination
+	ParentAccount string `json:"parent_account"`
+	StartTime     int64  `json:"start_time"`
+	EndTime       int64  `json:"end_time"`
+}
+
+func (h *HttpHandle) JsonRpcSubAccountProfit(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqSubAccountProfit
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doSubAccountProfit(&req[0], apiResp); err != nil {
+		log.Error("doSubAccountProfit err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) SubAccountProfit(ctx *gin.Context) {
+	var (
+		funcName = "SubAccountProfit"
+		req      ReqSubAccountProfit
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doSubAccountProfit(&req, &apiResp); err != nil {
+		log.Error("doSubAccountProfit err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+// doSubAccountProfit the collected profits of the parent account, one entry for each receiver of a collect tx
+func (h *HttpHandle) doSubAccountProfit(req *ReqSubAccountProfit, apiResp *http_api.ApiResp) error {
+	parentAccount := strings.ToLower(strings.TrimSpace(req.ParentAccount))
+	if parentAccount == "" {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "parent_account is empty")
+		return nil
+	}
+	activityFilter := ActivityFilter{
+		Actions:   []string{common.DasActionCollectSubAccountProfit},
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+	}
+	filter, err := activityFilter.toDaoFilter()
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+	filter.AccountId = common.Bytes2Hex(common.GetAccountIdByAccount(parentAccount))
+
+	return h.doActivityList(filter, req.Pagination, apiResp)
+}
@@ -0,0 +1,167 @@
+package handle
+
+import (
+	"das_database/dao"
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/dotbitHQ/das-lib/witness"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"github.com/shopspring/decimal"
+	"net/http"
+	"strings"
+)
+
+// ReqSubAccountQuote account is the sub-account name with or without the parent account, years defaults to 1
+type ReqSubAccountQuote struct {
+	ParentAccount string `json:"parent_account"`
+	Account       string `json:"account"`
+	Years         uint64 `json:"years"`
+}
+
+// RespSubAccountQuote prices are in usd, available is false when the name is registered,
+// preserved, or not matched by any price rule
+type RespSubAccountQuote struct {
+	Account       string              `json:"account"`
+	Registered    bool                `json:"registered"`
+	Preserved     bool                `json:"preserved"`
+	PreservedRule *SubAccountRuleData `json:"preserved_rule"`
+	PriceRule     *SubAccountRuleData `json:"price_rule"`
+	Available     bool                `json:"available"`
+	Price         decimal.Decimal     `json:"price"`
+	Years         uint64              `json:"years"`
+	TotalPrice    decimal.Decimal     `json:"total_price"`
+}
+
+func (h *HttpHandle) JsonRpcSubAccountQuote(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqSubAccountQuote
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doSubAccountQuote(&req[0], apiResp); err != nil {
+		log.Error("doSubAccountQuote err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) SubAccountQuote(ctx *gin.Context) {
+	var (
+		funcName = "SubAccountQuote"
+		req      ReqSubAccountQuote
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doSubAccountQuote(&req, &apiResp); err != nil {
+		log.Error("doSubAccountQuote err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doSubAccountQuote(req *ReqSubAccountQuote, apiResp *http_api.ApiResp) error {
+	var resp RespSubAccountQuote
+
+	parentAccount := strings.ToLower(strings.TrimSpace(req.ParentAccount))
+	name := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(req.Account)), "."+parentAccount)
+	if parentAccount == "" || name == "" || strings.Contains(name, ".") {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "parent_account or account invalid")
+		return nil
+	}
+	resp.Years = req.Years
+	if resp.Years == 0 {
+		resp.Years = 1
+	}
+	resp.Account = name + "." + parentAccount
+
+	accountInfo, err := h.dbDao.GetAccountInfoByAccountId(common.Bytes2Hex(common.GetAccountIdByAccount(resp.Account)))
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query account")
+		return fmt.Errorf("GetAccountInfoByAccountId err: %s", err.Error())
+	}
+	resp.Registered = accountInfo.Id > 0
+
+	list, err := h.dbDao.GetSubAccountRuleList(common.Bytes2Hex(common.GetAccountIdByAccount(parentAccount)), "")
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query rules")
+		return fmt.Errorf("GetSubAccountRuleList err: %s", err.Error())
+	}
+	var priceRules, preservedRules []dao.TableSubAccountRule
+	for _, v := range list {
+		if v.RuleType == dao.SubAccountRuleTypePreserved {
+			preservedRules = append(preservedRules, v)
+		} else {
+			priceRules = append(priceRules, v)
+		}
+	}
+
+	preservedRule, err := hitSubAccountRule(parentAccount, name, preservedRules)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeError500, "Failed to evaluate preserved rules")
+		return fmt.Errorf("hitSubAccountRule err: %s", err.Error())
+	}
+	if preservedRule != nil {
+		resp.Preserved = true
+		resp.PreservedRule = preservedRule
+	}
+	priceRule, err := hitSubAccountRule(parentAccount, name, priceRules)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeError500, "Failed to evaluate price rules")
+		return fmt.Errorf("hitSubAccountRule err: %s", err.Error())
+	}
+	if priceRule != nil {
+		resp.PriceRule = priceRule
+		resp.Price = priceRule.Price
+		resp.TotalPrice = priceRule.Price.Mul(decimal.NewFromInt(int64(resp.Years)))
+	}
+	resp.Available = !resp.Registered && !resp.Preserved && resp.PriceRule != nil
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
+
+// hitSubAccountRule the first enabled rule the name matches, rules are rebuilt from the indexed ast
+func hitSubAccountRule(parentAccount, name string, list []dao.TableSubAccountRule) (*SubAccountRuleData, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+	entity := witness.NewSubAccountRuleEntity(parentAccount)
+	for _, v := range list {
+		rule := witness.NewSubAccountRule()
+		if err := json.Unmarshal([]byte(v.Ast), &rule.Ast); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal rule %d err: %s", v.Idx, err.Error())
+		}
+		rule.Index = v.Idx
+		rule.Name = v.Name
+		rule.Note = v.Note
+		rule.Price = float64(v.Price)
+		rule.Status = v.Status
+		entity.Rules = append(entity.Rules, rule)
+	}
+	hit, index, err := entity.Hit(name)
+	if err != nil {
+		return nil, err
+	} else if !hit {
+		return nil, nil
+	}
+	data := toSubAccountRuleData(list[index])
+	return &data, nil
+}
@@ -0,0 +1,128 @@
+package handle
+
+import (
+	"das_database/dao"
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"github.com/shopspring/decimal"
+	"net/http"
+	"strings"
+)
+
+type ReqSubAccountRules struct {
+	ParentAccount string `json:"parent_account"`
+}
+
+type RespSubAccountRules struct {
+	ParentAccount  string               `json:"parent_account"`
+	PriceRules     []SubAccountRuleData `json:"price_rules"`
+	PreservedRules []SubAccountRuleData `json:"preserved_rules"`
+}
+
+// SubAccountRuleData price is usd per year, 0 for the preserved rules
+type SubAccountRuleData struct {
+	Index          uint32          `json:"index"`
+	Name           string          `json:"name"`
+	Note           string          `json:"note"`
+	Price          decimal.Decimal `json:"price"`
+	Status         uint8           `json:"status"`
+	LengthMin      uint32          `json:"length_min"`
+	LengthMax      uint32          `json:"length_max"`
+	Condition      string          `json:"condition"`
+	Ast            json.RawMessage `json:"ast"`
+	TxHash         string          `json:"tx_hash"`
+	BlockNumber    uint64          `json:"block_number"`
+	BlockTimestamp uint64          `json:"block_timestamp"`
+}
+
+func (h *HttpHandle) JsonRpcSubAccountRules(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqSubAccountRules
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doSubAccountRules(&req[0], apiResp); err != nil {
+		log.Error("doSubAccountRules err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) SubAccountRules(ctx *gin.Context) {
+	var (
+		funcName = "SubAccountRules"
+		req      ReqSubAccountRules
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doSubAccountRules(&req, &apiResp); err != nil {
+		log.Error("doSubAccountRules err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doSubAccountRules(req *ReqSubAccountRules, apiResp *http_api.ApiResp) error {
+	var resp RespSubAccountRules
+	resp.PriceRules = make([]SubAccountRuleData, 0)
+	resp.PreservedRules = make([]SubAccountRuleData, 0)
+
+	resp.ParentAccount = strings.ToLower(strings.TrimSpace(req.ParentAccount))
+	if resp.ParentAccount == "" {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "parent_account is empty")
+		return nil
+	}
+	parentAccountId := common.Bytes2Hex(common.GetAccountIdByAccount(resp.ParentAccount))
+
+	list, err := h.dbDao.GetSubAccountRuleList(parentAccountId, "")
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query rules")
+		return fmt.Errorf("GetSubAccountRuleList err: %s", err.Error())
+	}
+	for _, v := range list {
+		if v.RuleType == dao.SubAccountRuleTypePreserved {
+			resp.PreservedRules = append(resp.PreservedRules, toSubAccountRuleData(v))
+		} else {
+			resp.PriceRules = append(resp.PriceRules, toSubAccountRuleData(v))
+		}
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
+
+func toSubAccountRuleData(v dao.TableSubAccountRule) SubAccountRuleData {
+	return SubAccountRuleData{
+		Index:          v.Idx,
+		Name:           v.Name,
+		Note:           v.Note,
+		Price:          decimal.NewFromInt(int64(v.Price)).Div(decimal.NewFromInt(common.UsdRateBase)),
+		Status:         v.Status,
+		LengthMin:      v.LengthMin,
+		LengthMax:      v.LengthMax,
+		Condition:      v.Condition,
+		Ast:            json.RawMessage(v.Ast),
+		TxHash:         v.TxHash,
+		BlockNumber:    v.BlockNumber,
+		BlockTimestamp: v.BlockTimestamp,
+	}
+}
@@ -0,0 +1,128 @@
+package handle
+
+import (
+	"das_database/dao"
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ReqSubAccountStats days is the window of expiring, 30 by default
+type ReqSubAccountStats struct {
+	ParentAccount string `json:"parent_account"`
+	Days          uint64 `json:"days"`
+}
+
+// RespSubAccountStats months are in UTC
+type RespSubAccountStats struct {
+	ParentAccount        string `json:"parent_account"`
+	EnableSubAccount     uint8  `json:"enable_sub_account"`
+	RenewSubAccountPrice uint64 `json:"renew_sub_account_price"`
+	dao.SubAccountStats
+	MintedMonthly []SubAccountMinted `json:"minted_monthly"`
+}
+
+type SubAccountMinted struct {
+	Month string `json:"month"`
+	Count int64  `json:"count"`
+}
+
+func (h *HttpHandle) JsonRpcSubAccountStats(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqSubAccountStats
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doSubAccountStats(&req[0], apiResp); err != nil {
+		log.Error("doSubAccountStats err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) SubAccountStats(ctx *gin.Context) {
+	var (
+		funcName = "SubAccountStats"
+		req      ReqSubAccountStats
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doSubAccountStats(&req, &apiResp); err != nil {
+		log.Error("doSubAccountStats err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doSubAccountStats(req *ReqSubAccountStats, apiResp *http_api.ApiResp) error {
+	var resp RespSubAccountStats
+	resp.MintedMonthly = make([]SubAccountMinted, 0)
+
+	resp.ParentAccount = strings.ToLower(strings.TrimSpace(req.ParentAccount))
+	if resp.ParentAccount == "" {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "parent_account is empty")
+		return nil
+	}
+	if req.Days == 0 {
+		req.Days = 30
+	} else if req.Days > 365 {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "days should be in [1,365]")
+		return nil
+	}
+	parentAccountId := common.Bytes2Hex(common.GetAccountIdByAccount(resp.ParentAccount))
+
+	parentInfo, err := h.dbDao.GetAccountInfoByAccountId(parentAccountId)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query parent account")
+		return fmt.Errorf("GetAccountInfoByAccountId err: %s", err.Error())
+	} else if parentInfo.Id == 0 {
+		apiResp.ApiRespErr(http_api.ApiCodeAccountNotExist, "parent account not exist")
+		return nil
+	}
+	resp.EnableSubAccount = parentInfo.EnableSubAccount
+	resp.RenewSubAccountPrice = parentInfo.RenewSubAccountPrice
+
+	now := uint64(time.Now().Unix())
+	resp.SubAccountStats, err = h.dbDao.GetSubAccountStats(parentAccountId, now, now+req.Days*86400)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query sub-account stats")
+		return fmt.Errorf("GetSubAccountStats err: %s", err.Error())
+	}
+	daily, err := h.dbDao.GetSubAccountMintedDaily(parentAccountId)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query sub-account stats")
+		return fmt.Errorf("GetSubAccountMintedDaily err: %s", err.Error())
+	}
+	for _, v := range daily {
+		month := time.Unix(v.Day*86400, 0).UTC().Format("2006-01")
+		if n := len(resp.MintedMonthly); n > 0 && resp.MintedMonthly[n-1].Month == month {
+			resp.MintedMonthly[n-1].Count += v.Count
+			continue
+		}
+		resp.MintedMonthly = append(resp.MintedMonthly, SubAccountMinted{Month: month, Count: v.Count})
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
@@ -0,0 +1,109 @@
+package handle
+
+import (
+	"das_database/dao"
+	"das_database/timer"
+	"encoding/json"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"github.com/shopspring/decimal"
+	"net/http"
+	"sort"
+)
+
+type ReqTokenList struct {
+}
+
+type RespTokenList struct {
+	List []TokenData `json:"list"`
+}
+
+type TokenData struct {
+	TokenId       string          `json:"token_id"`
+	ChainType     int             `json:"chain_type"`
+	CoinType      common.CoinType `json:"coin_type"`
+	Name          string          `json:"name"`
+	Symbol        string          `json:"symbol"`
+	Decimals      int32           `json:"decimals"`
+	Logo          string          `json:"logo"`
+	Icon          string          `json:"icon"`
+	DisplayName   string          `json:"display_name"`
+	Price         decimal.Decimal `json:"price"`
+	LastUpdatedAt int64           `json:"last_updated_at"`
+	Stale         bool            `json:"stale"`
+}
+
+func (h *HttpHandle) JsonRpcTokenList(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqTokenList
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doTokenList(&req[0], apiResp); err != nil {
+		log.Error("doTokenList err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) TokenList(ctx *gin.Context) {
+	var (
+		funcName = "TokenList"
+		req      ReqTokenList
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doTokenList(&req, &apiResp); err != nil {
+		log.Error("doTokenList err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doTokenList(req *ReqTokenList, apiResp *http_api.ApiResp) error {
+	var resp RespTokenList
+	resp.List = make([]TokenData, 0)
+
+	for _, v := range timer.GetTokenPriceInfoList() {
+		if v.Status != dao.TokenStatusNormal {
+			continue
+		}
+		resp.List = append(resp.List, TokenData{
+			TokenId:       v.TokenId,
+			ChainType:     v.ChainType,
+			CoinType:      v.CoinType,
+			Name:          v.Name,
+			Symbol:        v.Symbol,
+			Decimals:      v.Decimals,
+			Logo:          v.Logo,
+			Icon:          v.Icon,
+			DisplayName:   v.DisplayName,
+			Price:         v.Price,
+			LastUpdatedAt: v.LastUpdatedAt,
+			Stale:         timer.IsTokenPriceStale(v.TokenId),
+		})
+	}
+	sort.Slice(resp.List, func(i, j int) bool {
+		return resp.List[i].TokenId < resp.List[j].TokenId
+	})
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
@@ -0,0 +1,120 @@
+package handle
+
+import (
+	"das_database/dao"
+	"encoding/json"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"github.com/shopspring/decimal"
+	"net/http"
+	"time"
+)
+
+type ReqTokenPriceHistory struct {
+	Pagination
+	TokenId   string `json:"token_id"`
+	StartTime int64  `json:"start_time"`
+	EndTime   int64  `json:"end_time"`
+}
+
+type RespTokenPriceHistory struct {
+	Total      int64                   `json:"total"`
+	NextCursor string                  `json:"next_cursor"`
+	List       []TokenPriceHistoryData `json:"list"`
+}
+
+type TokenPriceHistoryData struct {
+	Price   decimal.Decimal `json:"price"`
+	PriceAt int64           `json:"price_at"`
+}
+
+func (h *HttpHandle) JsonRpcTokenPriceHistory(p json.RawMessage, apiResp *http_api.ApiResp) {
+	var req []ReqTokenPriceHistory
+	err := json.Unmarshal(p, &req)
+	if err != nil {
+		log.Error("json.Unmarshal err:", err.Error())
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+	if len(req) != 1 {
+		log.Error("len(req) is :", len(req))
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		return
+	}
+
+	if err = h.doTokenPriceHistory(&req[0], apiResp); err != nil {
+		log.Error("doTokenPriceHistory err:", err.Error())
+	}
+}
+
+func (h *HttpHandle) TokenPriceHistory(ctx *gin.Context) {
+	var (
+		funcName = "TokenPriceHistory"
+		req      ReqTokenPriceHistory
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doTokenPriceHistory(&req, &apiResp); err != nil {
+		log.Error("doTokenPriceHistory err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doTokenPriceHistory(req *ReqTokenPriceHistory, apiResp *http_api.ApiResp) error {
+	var resp RespTokenPriceHistory
+	resp.List = make([]TokenPriceHistoryData, 0)
+
+	if req.TokenId == "" {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "token_id is empty")
+		return nil
+	}
+	if req.EndTime == 0 {
+		req.EndTime = time.Now().Unix()
+	}
+	if req.StartTime > req.EndTime {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "time range invalid")
+		return nil
+	}
+
+	cursor, err := req.GetCursor()
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+
+	resp.Total, err = h.dbDao.GetTokenPriceHistoryCount(req.TokenId, req.StartTime, req.EndTime)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query price history")
+		return fmt.Errorf("GetTokenPriceHistoryCount err: %s", err.Error())
+	}
+	list, err := h.dbDao.GetTokenPriceHistoryList(req.TokenId, req.StartTime, req.EndTime, cursor, req.GetLimit(), req.GetOffset())
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query price history")
+		return fmt.Errorf("GetTokenPriceHistoryList err: %s", err.Error())
+	}
+	for _, v := range list {
+		resp.List = append(resp.List, TokenPriceHistoryData{
+			Price:   v.Price,
+			PriceAt: v.PriceAt,
+		})
+	}
+	if len(list) > 0 {
+		last := list[len(list)-1]
+		resp.NextCursor = req.NextCursor(len(list), dao.Cursor{Key: uint64(last.PriceAt), Id: last.Id})
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
@@ -0,0 +1,66 @@
+package handle
+
+import (
+	"das_database/dao"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+)
+
+type ReqTokenStatus struct {
+	TokenId string `json:"token_id"`
+	Status  int    `json:"status"`
+}
+
+type RespTokenStatus struct {
+}
+
+// TokenStatus internal api, enables or bans a token
+func (h *HttpHandle) TokenStatus(ctx *gin.Context) {
+	var (
+		funcName = "TokenStatus"
+		req      ReqTokenStatus
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doTokenStatus(&req, &apiResp); err != nil {
+		log.Error("doTokenStatus err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doTokenStatus(req *ReqTokenStatus, apiResp *http_api.ApiResp) error {
+	var resp RespTokenStatus
+
+	if req.Status != dao.TokenStatusNormal && req.Status != dao.TokenStatusBanned {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "status invalid")
+		return nil
+	}
+	tokenInfo, err := h.dbDao.GetTokenPriceInfoByTokenId(req.TokenId)
+	if err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query token")
+		return fmt.Errorf("GetTokenPriceInfoByTokenId err: %s", err.Error())
+	} else if tokenInfo.Id == 0 {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "token not exist")
+		return nil
+	}
+	if err = h.dbDao.UpdateTokenStatus(req.TokenId, req.Status); err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to update token status")
+		return fmt.Errorf("UpdateTokenStatus err: %s", err.Error())
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
@@ -0,0 +1,96 @@
+package handle
+
+import (
+	"das_database/dao"
+	"das_database/timer"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/gin-gonic/gin"
+	"github.com/scorpiotzh/toolib"
+	"net/http"
+	"time"
+)
+
+type ReqTokenUpdate struct {
+	TokenId     string          `json:"token_id"`
+	ChainType   int             `json:"chain_type"`
+	CoinType    common.CoinType `json:"coin_type"`
+	GeckoId     string          `json:"gecko_id"`
+	Name        string          `json:"name"`
+	Symbol      string          `json:"symbol"`
+	Decimals    int32           `json:"decimals"`
+	Logo        string          `json:"logo"`
+	DisplayName string          `json:"display_name"`
+	Icon        string          `json:"icon"`
+}
+
+type RespTokenUpdate struct {
+}
+
+// TokenUpdate internal api, adds a token or updates its metadata
+func (h *HttpHandle) TokenUpdate(ctx *gin.Context) {
+	var (
+		funcName = "TokenUpdate"
+		req      ReqTokenUpdate
+		apiResp  http_api.ApiResp
+		err      error
+	)
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Error("ShouldBindJSON err: ", err.Error(), funcName)
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, "params invalid")
+		ctx.JSON(http.StatusOK, apiResp)
+		return
+	}
+	log.Info("ApiReq:", funcName, toolib.JsonString(req))
+
+	if err = h.doTokenUpdate(&req, &apiResp); err != nil {
+		log.Error("doTokenUpdate err:", err.Error(), funcName)
+	}
+
+	ctx.JSON(http.StatusOK, apiResp)
+}
+
+func (h *HttpHandle) doTokenUpdate(req *ReqTokenUpdate, apiResp *http_api.ApiResp) error {
+	var resp RespTokenUpdate
+
+	tokenInfo := dao.TableTokenPriceInfo{
+		TokenId:       req.TokenId,
+		GeckoId:       req.GeckoId,
+		ChainType:     req.ChainType,
+		CoinType:      req.CoinType,
+		Name:          req.Name,
+		Symbol:        req.Symbol,
+		Decimals:      req.Decimals,
+		Logo:          req.Logo,
+		LastUpdatedAt: time.Now().Unix(),
+		DisplayName:   req.DisplayName,
+		Icon:          req.Icon,
+	}
+	if err := tokenInfo.Validate(); err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, err.Error())
+		return nil
+	}
+	if !timer.HasPriceSource(tokenInfo.TokenId) {
+		apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, fmt.Sprintf("token [%s] has no price source in price.tokens", tokenInfo.TokenId))
+		return nil
+	}
+	if tokenInfo.GeckoId != "" {
+		other, err := h.dbDao.GetTokenPriceInfoByGeckoId(tokenInfo.GeckoId)
+		if err != nil {
+			apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to query token")
+			return fmt.Errorf("GetTokenPriceInfoByGeckoId err: %s", err.Error())
+		} else if other.Id > 0 && other.TokenId != tokenInfo.TokenId {
+			apiResp.ApiRespErr(http_api.ApiCodeParamsInvalid, fmt.Sprintf("gecko_id [%s] is used by [%s]", tokenInfo.GeckoId, other.TokenId))
+			return nil
+		}
+	}
+	if err := h.dbDao.UpsertTokenList([]dao.TableTokenPriceInfo{tokenInfo}); err != nil {
+		apiResp.ApiRespErr(http_api.ApiCodeDbError, "Failed to update token")
+		return fmt.Errorf("UpsertTokenList err: %s", err.Error())
+	}
+
+	apiResp.ApiRespOK(resp)
+	return nil
+}
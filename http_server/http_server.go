@@ -1,19 +1,25 @@
 package http_server
 
 import (
+	"bytes"
 	"context"
+	"crypto/subtle"
 	"das_database/block_parser"
 	"das_database/config"
 	"das_database/dao"
 	"das_database/http_server/api_code"
+	"das_database/http_server/graph"
 	"das_database/http_server/handle"
 	"encoding/json"
+	"fmt"
 	"github.com/dotbitHQ/das-lib/core"
+	"github.com/dotbitHQ/das-lib/http_api"
 	"github.com/dotbitHQ/das-lib/http_api/logger"
 	sentrygin "github.com/getsentry/sentry-go/gin"
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis"
 	"github.com/scorpiotzh/toolib"
+	"io"
 	"net/http"
 	"time"
 )
@@ -23,27 +29,34 @@ var (
 )
 
 type HttpServer struct {
-	address string
-	engine  *gin.Engine
-	h       *handle.HttpHandle
-	srv     *http.Server
-	ctx     context.Context
-	red     *redis.Client
+	address         string
+	internalAddress string
+	engine          *gin.Engine
+	internalEngine  *gin.Engine
+	h               *handle.HttpHandle
+	g               *graph.Graph
+	srv             *http.Server
+	internalSrv     *http.Server
+	ctx             context.Context
+	red             *redis.Client
 }
 
 type HttpServerParams struct {
-	Address string
-	DbDao   *dao.DbDao
-	Ctx     context.Context
-	DasCore *core.DasCore
-	Bp      *block_parser.BlockParser
-	Red     *redis.Client
+	Address         string
+	InternalAddress string
+	DbDao           *dao.DbDao
+	Ctx             context.Context
+	DasCore         *core.DasCore
+	Bp              *block_parser.BlockParser
+	Red             *redis.Client
 }
 
 func Initialize(p HttpServerParams) (*HttpServer, error) {
 	hs := HttpServer{
-		address: p.Address,
-		engine:  gin.New(),
+		address:         p.Address,
+		internalAddress: p.InternalAddress,
+		engine:          gin.New(),
+		internalEngine:  gin.New(),
 		h: handle.Initialize(handle.HttpHandleParams{
 			DbDao:   p.DbDao,
 			DasCore: p.DasCore,
@@ -54,6 +67,14 @@ func Initialize(p HttpServerParams) (*HttpServer, error) {
 		ctx: p.Ctx,
 		red: p.Red,
 	}
+	g, err := graph.Initialize(graph.GraphParams{
+		DbDao:   p.DbDao,
+		DasCore: p.DasCore,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("graph.Initialize err: %s", err.Error())
+	}
+	hs.g = g
 	return &hs, nil
 }
 
@@ -66,7 +87,8 @@ func (h *HttpServer) Run() {
 	}
 
 	h.engine.Use(toolib.MiddlewareCors())
-	h.engine.POST("", cacheHandle, h.h.JasonRpcHandle)
+	h.engine.POST("", jsonRpcCacheHandle(toolib.MiddlewareCacheByRedis(h.red, false, shortDataTime, lockTime, shortExpireTime, jsonRpcRespHandle)), h.h.JasonRpcHandle)
+	h.engine.POST("/graphql", api_code.DoMonitorLog("graphql"), cacheHandle, h.g.GraphQL)
 	h.engine.Use(sentrygin.New(sentrygin.Options{
 		Repanic: true,
 	}))
@@ -80,6 +102,46 @@ func (h *HttpServer) Run() {
 
 		v1.POST("/snapshot/did/list", api_code.DoMonitorLog(api_code.MethodSnapshotDidList), cacheHandle, h.h.SnapshotDidList)
 		v1.POST("/snapshot/verify", api_code.DoMonitorLog(api_code.MethodSnapshotVerify), cacheHandle, h.h.SnapshotVerify)
+
+		v1.POST("/market/volume", api_code.DoMonitorLog(api_code.MethodMarketVolume), cacheHandle, h.h.MarketVolume)
+		v1.POST("/market/price/stats", api_code.DoMonitorLog(api_code.MethodMarketPriceStats), cacheHandle, h.h.MarketPriceStats)
+		v1.POST("/market/top/sales", api_code.DoMonitorLog(api_code.MethodMarketTopSales), cacheHandle, h.h.MarketTopSales)
+		v1.POST("/market/account/price/history", api_code.DoMonitorLog(api_code.MethodMarketAccountPriceHistory), cacheHandle, h.h.MarketAccountPriceHistory)
+		v1.POST("/market/offer/book", api_code.DoMonitorLog(api_code.MethodMarketOfferBook), cacheHandle, h.h.MarketOfferBook)
+		v1.POST("/token/price/history", api_code.DoMonitorLog(api_code.MethodTokenPriceHistory), cacheHandle, h.h.TokenPriceHistory)
+		v1.POST("/token/list", api_code.DoMonitorLog(api_code.MethodTokenList), cacheHandle, h.h.TokenList)
+		v1.POST("/account/expiring", api_code.DoMonitorLog(api_code.MethodAccountExpiring), cacheHandle, h.h.AccountExpiring)
+		v1.POST("/account/grace", api_code.DoMonitorLog(api_code.MethodAccountGrace), cacheHandle, h.h.AccountGrace)
+		v1.POST("/account/recyclable", api_code.DoMonitorLog(api_code.MethodAccountRecyclable), cacheHandle, h.h.AccountRecyclable)
+		v1.POST("/records/search", api_code.DoMonitorLog(api_code.MethodRecordsSearch), cacheHandle, h.h.RecordsSearch)
+		v1.POST("/records/history", api_code.DoMonitorLog(api_code.MethodRecordsHistory), cacheHandle, h.h.RecordsHistory)
+		v1.POST("/records/diff", api_code.DoMonitorLog(api_code.MethodRecordsDiff), cacheHandle, h.h.RecordsDiff)
+		v1.POST("/account/ownership/history", api_code.DoMonitorLog(api_code.MethodOwnershipHistory), cacheHandle, h.h.OwnershipHistory)
+		v1.POST("/account/search", api_code.DoMonitorLog(api_code.MethodAccountSearch), cacheHandle, h.h.AccountSearch)
+		v1.POST("/account/activity", api_code.DoMonitorLog(api_code.MethodAccountActivity), cacheHandle, h.h.AccountActivity)
+		v1.POST("/address/activity", api_code.DoMonitorLog(api_code.MethodAddressActivity), cacheHandle, h.h.AddressActivity)
+		v1.POST("/rebate/list", api_code.DoMonitorLog(api_code.MethodRebateList), cacheHandle, h.h.RebateList)
+		v1.POST("/rebate/summary", api_code.DoMonitorLog(api_code.MethodRebateSummary), cacheHandle, h.h.RebateSummary)
+		v1.POST("/rebate/leaderboard", api_code.DoMonitorLog(api_code.MethodRebateLeaderboard), cacheHandle, h.h.RebateLeaderboard)
+		v1.POST("/sub/account/mint/statement", api_code.DoMonitorLog(api_code.MethodSubAccountMintStatement), cacheHandle, h.h.SubAccountMintStatement)
+		v1.POST("/sub/account/mint/statement/list", api_code.DoMonitorLog(api_code.MethodSubAccountMintStatementList), cacheHandle, h.h.SubAccountMintStatementList)
+		v1.POST("/sub/account/rules", api_code.DoMonitorLog(api_code.MethodSubAccountRules), cacheHandle, h.h.SubAccountRules)
+		v1.POST("/sub/account/quote", api_code.DoMonitorLog(api_code.MethodSubAccountQuote), cacheHandle, h.h.SubAccountQuote)
+		v1.POST("/sub/account/list", api_code.DoMonitorLog(api_code.MethodSubAccountList), cacheHandle, h.h.SubAccountList)
+		v1.POST("/sub/account/stats", api_code.DoMonitorLog(api_code.MethodSubAccountStats), cacheHandle, h.h.SubAccountStats)
+		v1.POST("/sub/account/config", api_code.DoMonitorLog(api_code.MethodSubAccountConfig), cacheHandle, h.h.SubAccountConfig)
+		v1.POST("/sub/account/profit", api_code.DoMonitorLog(api_code.MethodSubAccountProfit), cacheHandle, h.h.SubAccountProfit)
+		v1.POST("/approval/pending", api_code.DoMonitorLog(api_code.MethodApprovalPending), cacheHandle, h.h.ApprovalPending)
+		v1.POST("/approval/history", api_code.DoMonitorLog(api_code.MethodApprovalHistory), cacheHandle, h.h.ApprovalHistory)
+		v1.POST("/did/cell/info", api_code.DoMonitorLog(api_code.MethodDidCellInfo), cacheHandle, h.h.DidCellInfo)
+		v1.POST("/did/cell/list", api_code.DoMonitorLog(api_code.MethodDidCellList), cacheHandle, h.h.DidCellList)
+		v1.POST("/cross/chain/locked", api_code.DoMonitorLog(api_code.MethodCrossChainLocked), cacheHandle, h.h.CrossChainLocked)
+		v1.POST("/cross/chain/report", api_code.DoMonitorLog(api_code.MethodCrossChainReport), cacheHandle, h.h.CrossChainReport)
+		v1.POST("/device/key/list", api_code.DoMonitorLog(api_code.MethodDeviceKeyList), cacheHandle, h.h.DeviceKeyList)
+		v1.POST("/device/key/authorize", api_code.DoMonitorLog(api_code.MethodDeviceKeyAuthorize), cacheHandle, h.h.DeviceKeyAuthorize)
+		v1.POST("/device/key/history", api_code.DoMonitorLog(api_code.MethodDeviceKeyHistory), cacheHandle, h.h.DeviceKeyHistory)
+		v1.POST("/account/info", api_code.DoMonitorLog(api_code.MethodAccountInfo), cacheHandle, h.h.AccountInfo)
+		v1.POST("/account/records", api_code.DoMonitorLog(api_code.MethodAccountRecords), cacheHandle, h.h.AccountRecords)
 		v1.GET("/test/jenkins", func(c *gin.Context) {
 			c.JSON(200, "main--v1.0.0")
 		})
@@ -94,6 +156,33 @@ func (h *HttpServer) Run() {
 			log.Error("http_server run err:", err)
 		}
 	}()
+
+	h.runInternal()
+}
+
+// runInternal the admin api, only served when http_server_internal_addr is set
+func (h *HttpServer) runInternal() {
+	if h.internalAddress == "" {
+		return
+	}
+	if config.Cfg.Server.InternalApiKey == "" {
+		log.Warn("internal_api_key is empty, the internal api refuses every call")
+	}
+	v1 := h.internalEngine.Group("v1", internalAuth)
+	{
+		v1.POST("/token/update", api_code.DoMonitorLog(api_code.MethodTokenUpdate), h.h.TokenUpdate)
+		v1.POST("/token/status", api_code.DoMonitorLog(api_code.MethodTokenStatus), h.h.TokenStatus)
+	}
+
+	h.internalSrv = &http.Server{
+		Addr:    h.internalAddress,
+		Handler: h.internalEngine,
+	}
+	go func() {
+		if err := h.internalSrv.ListenAndServe(); err != nil {
+			log.Error("http_server internal run err:", err)
+		}
+	}()
 }
 
 func (h *HttpServer) Shutdown() {
@@ -103,6 +192,22 @@ func (h *HttpServer) Shutdown() {
 			log.Error("http server Shutdown err:", err.Error())
 		}
 	}
+	if h.internalSrv != nil {
+		if err := h.internalSrv.Shutdown(h.ctx); err != nil {
+			log.Error("http server internal Shutdown err:", err.Error())
+		}
+	}
+}
+
+// internalAuth checks the shared secret of the internal api
+func internalAuth(c *gin.Context) {
+	key := config.Cfg.Server.InternalApiKey
+	if key == "" || subtle.ConstantTimeCompare([]byte(c.GetHeader("X-Api-Key")), []byte(key)) != 1 {
+		log.Warn("internalAuth failed:", c.ClientIP(), c.Request.URL.Path)
+		c.AbortWithStatusJSON(http.StatusUnauthorized, api_code.ApiRespErr(http_api.ApiCodePermissionDenied, "permission denied"))
+		return
+	}
+	c.Next()
 }
 
 func respHandle(c *gin.Context, res string, err error) {
@@ -115,3 +220,49 @@ func respHandle(c *gin.Context, res string, err error) {
 		c.AbortWithStatusJSON(http.StatusOK, respMap)
 	}
 }
+
+// jsonRpcUncached the json-rpc methods which are always served fresh
+var jsonRpcUncached = map[string]bool{
+	api_code.MethodLatestBlockNumber: true,
+	api_code.MethodSnapshotProgress:  true,
+}
+
+// jsonRpcCacheHandle runs the cache of the json-rpc, unless the request or a request of the batch is of an uncached method
+func jsonRpcCacheHandle(cacheHandle gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := c.GetRawData()
+		if err != nil {
+			log.Warn("GetRawData err:", err.Error())
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewBuffer(body))
+
+		var list []api_code.JsonRequest
+		if err := json.Unmarshal(body, &list); err != nil {
+			var req api_code.JsonRequest
+			_ = json.Unmarshal(body, &req)
+			list = append(list, req)
+		}
+		for _, v := range list {
+			if jsonRpcUncached[v.Method] {
+				return
+			}
+		}
+		cacheHandle(c)
+	}
+}
+
+// jsonRpcRespHandle sends the cached body as it is, a batch is a list. The requests which were not served
+// from the cache already got their response from the handler, or get it when the cache failed before the handler
+func jsonRpcRespHandle(c *gin.Context, res string, err error) {
+	if err != nil {
+		if c.Writer.Status() != http.StatusNoContent {
+			log.Warn("jsonRpcRespHandle err:", err.Error())
+		}
+		return
+	}
+	if res != "" {
+		c.Abort()
+		c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(res))
+	}
+}
@@ -0,0 +1,246 @@
+package normalize
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/core"
+	"github.com/nervosnetwork/ckb-sdk-go/address"
+	"github.com/nervosnetwork/ckb-sdk-go/types"
+	"strings"
+)
+
+// Address an address as kept in the db, the canonical hex with the chain type and the algorithm id.
+// The hex is the one of das-lib: lower case, with 0x for ckb, evm and ed25519, with 41 for tron and without
+// a prefix for doge, bitcoin and webauthn. A lock which is not a das lock is kept as its full ckb address
+type Address struct {
+	ChainType      common.ChainType
+	AlgorithmId    common.DasAlgorithmId
+	SubAlgorithmId common.DasSubAlgorithmId
+	Hex            string
+}
+
+func (a Address) DasAddressHex() core.DasAddressHex {
+	return core.DasAddressHex{
+		DasAlgorithmId:    a.AlgorithmId,
+		DasSubAlgorithmId: a.SubAlgorithmId,
+		AddressHex:        a.Hex,
+		ChainType:         a.ChainType,
+	}
+}
+
+// Payload the bytes of the hex without the prefix, empty for any lock
+func (a Address) Payload() []byte {
+	if a.AlgorithmId == common.DasAlgorithmIdAnyLock {
+		return nil
+	}
+	return common.Hex2Bytes(common.FormatHexToPayload(a.Hex, a.AlgorithmId))
+}
+
+// ReverseHex the hex kept in the reverse records, the payload as formatted by das-lib: without 0x but for evm
+func (a Address) ReverseHex() string {
+	switch a.AlgorithmId {
+	case common.DasAlgorithmIdEth, common.DasAlgorithmIdEth712, common.DasAlgorithmIdTron:
+		return a.Hex
+	}
+	return strings.TrimPrefix(a.Hex, common.HexPreFix)
+}
+
+// HexRule the canonical prefix of the hex of the algorithms
+type HexRule struct {
+	AlgorithmIds []common.DasAlgorithmId
+	Prefix       string
+}
+
+var HexRules = []HexRule{
+	{
+		AlgorithmIds: []common.DasAlgorithmId{
+			common.DasAlgorithmIdCkb, common.DasAlgorithmIdCkbMulti, common.DasAlgorithmIdCkbSingle,
+			common.DasAlgorithmIdEth, common.DasAlgorithmIdEth712, common.DasAlgorithmIdEd25519,
+		},
+		Prefix: common.HexPreFix,
+	},
+	{
+		AlgorithmIds: []common.DasAlgorithmId{common.DasAlgorithmIdTron},
+		Prefix:       common.TronPreFix,
+	},
+	{
+		AlgorithmIds: []common.DasAlgorithmId{
+			common.DasAlgorithmIdDogeChain, common.DasAlgorithmIdWebauthn, common.DasAlgorithmIdBitcoin,
+		},
+		Prefix: "",
+	},
+}
+
+// CanonicalHex the hex of the algorithm in the canonical form, unchanged for the algorithms without a rule
+// and for a value which is not a hex
+func CanonicalHex(algId common.DasAlgorithmId, addrHex string) string {
+	s := strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(addrHex, "0x"), "0X"))
+	if s == "" || strings.Trim(s, "0123456789abcdef") != "" {
+		return addrHex
+	}
+	for _, r := range HexRules {
+		for _, v := range r.AlgorithmIds {
+			if v != algId {
+				continue
+			}
+			switch r.Prefix {
+			case common.TronPreFix:
+				// the 20 bytes payload without the version byte
+				if len(s) == 40 {
+					return common.TronPreFix + s
+				}
+				return s
+			default:
+				return r.Prefix + s
+			}
+		}
+	}
+	return addrHex
+}
+
+// AddressNormalizer converts the addresses of the parser and the api from and to the canonical form
+type AddressNormalizer struct {
+	daf *core.DasAddressFormat
+}
+
+func NewAddressNormalizer(netType common.DasNetType) *AddressNormalizer {
+	return &AddressNormalizer{daf: &core.DasAddressFormat{DasNetType: netType}}
+}
+
+// Mode the ckb address mode of the net
+func (n *AddressNormalizer) Mode() address.Mode {
+	if n.daf.DasNetType == common.DasNetTypeMainNet {
+		return address.Mainnet
+	}
+	return address.Testnet
+}
+
+func (n *AddressNormalizer) fromHex(p core.DasAddressHex) Address {
+	a := Address{
+		ChainType:      p.ChainType,
+		AlgorithmId:    p.DasAlgorithmId,
+		SubAlgorithmId: p.DasSubAlgorithmId,
+		Hex:            p.AddressHex,
+	}
+	if a.AlgorithmId != common.DasAlgorithmIdAnyLock {
+		a.Hex = CanonicalHex(a.AlgorithmId, a.Hex)
+	}
+	return a
+}
+
+// FromArgs the owner and manager of the args of a das lock
+func (n *AddressNormalizer) FromArgs(args []byte) (owner, manager Address, err error) {
+	ownerHex, managerHex, err := n.daf.ArgsToHex(args)
+	if err != nil {
+		return owner, manager, fmt.Errorf("ArgsToHex err: %s", err.Error())
+	}
+	return n.fromHex(ownerHex), n.fromHex(managerHex), nil
+}
+
+// FromScript the owner and manager of a das lock, the owner is the full ckb address of any other lock
+func (n *AddressNormalizer) FromScript(s *types.Script) (owner, manager Address, err error) {
+	ownerHex, managerHex, err := n.daf.ScriptToHex(s)
+	if err != nil {
+		return owner, manager, fmt.Errorf("ScriptToHex err: %s", err.Error())
+	}
+	return n.fromHex(ownerHex), n.fromHex(managerHex), nil
+}
+
+// ToArgs the args of a das lock of the owner and manager
+func (n *AddressNormalizer) ToArgs(owner, manager Address) ([]byte, error) {
+	args, err := n.daf.HexToArgs(owner.DasAddressHex(), manager.DasAddressHex())
+	if err != nil {
+		return nil, fmt.Errorf("HexToArgs err: %s", err.Error())
+	}
+	return args, nil
+}
+
+// AnyLock the ckb address of a lock which is not a das lock, as the holder of a did cell
+func (n *AddressNormalizer) AnyLock(s *types.Script) (Address, error) {
+	addr, err := address.ConvertScriptToAddress(n.Mode(), s)
+	if err != nil {
+		return Address{}, fmt.Errorf("ConvertScriptToAddress err: %s", err.Error())
+	}
+	return Address{
+		ChainType:   common.ChainTypeAnyLock,
+		AlgorithmId: common.DasAlgorithmIdAnyLock,
+		Hex:         addr,
+	}, nil
+}
+
+// DidCellLock the holder of a did cell by the lock code hash and the args kept in t_did_cell_info
+func (n *AddressNormalizer) DidCellLock(lockCodeHash, args string) (Address, error) {
+	return n.AnyLock(&types.Script{
+		CodeHash: types.HexToHash(lockCodeHash),
+		HashType: types.HashTypeType,
+		Args:     common.Hex2Bytes(args),
+	})
+}
+
+// FromPayload the address of a payload signed with the algorithm, as in the reverse records
+func (n *AddressNormalizer) FromPayload(payload []byte, algId common.DasAlgorithmId) Address {
+	return Address{
+		ChainType:   algId.ToChainType(),
+		AlgorithmId: algId,
+		Hex:         CanonicalHex(algId, hex.EncodeToString(payload)),
+	}
+}
+
+// FromNormal the address of a normal address, as submitted to the api
+func (n *AddressNormalizer) FromNormal(chainType common.ChainType, addrNormal string) (Address, error) {
+	addrHex, err := n.daf.NormalToHex(core.DasAddressNormal{
+		ChainType:     chainType,
+		AddressNormal: addrNormal,
+	})
+	if err != nil {
+		return Address{}, fmt.Errorf("NormalToHex err: %s", err.Error())
+	}
+	return n.fromHex(addrHex), nil
+}
+
+// FromChainTypeAddress the address of a chain type address, as submitted to the api
+func (n *AddressNormalizer) FromChainTypeAddress(c *core.ChainTypeAddress) (Address, error) {
+	addrHex, err := c.FormatChainTypeAddress(n.daf.DasNetType, false)
+	if err != nil {
+		return Address{}, fmt.Errorf("FormatChainTypeAddress err: %s", err.Error())
+	}
+	return n.fromHex(*addrHex), nil
+}
+
+// ToNormal the normal address to render, the ckb address itself for any lock
+func (n *AddressNormalizer) ToNormal(a Address) (string, error) {
+	if a.AlgorithmId == common.DasAlgorithmIdAnyLock {
+		return a.Hex, nil
+	}
+	addrNormal, err := n.daf.HexToNormal(a.DasAddressHex())
+	if err != nil {
+		return "", fmt.Errorf("HexToNormal err: %s", err.Error())
+	}
+	return addrNormal.AddressNormal, nil
+}
+
+// Canonical an address kept before the normalization in the canonical form,
+// the short and the bech32 full ckb addresses are encoded as bech32m full addresses
+func (n *AddressNormalizer) Canonical(a Address) (Address, error) {
+	if a.AlgorithmId != common.DasAlgorithmIdAnyLock && a.ChainType != common.ChainTypeAnyLock {
+		a.Hex = CanonicalHex(a.AlgorithmId, a.Hex)
+		return a, nil
+	}
+	if a.Hex == "" {
+		return a, nil
+	}
+	parsed, err := address.Parse(a.Hex)
+	if err != nil {
+		return a, fmt.Errorf("address.Parse err: %s", err.Error())
+	}
+	if parsed.Mode != n.Mode() {
+		return a, fmt.Errorf("address of another net")
+	}
+	addr, err := address.ConvertScriptToAddress(parsed.Mode, parsed.Script)
+	if err != nil {
+		return a, fmt.Errorf("ConvertScriptToAddress err: %s", err.Error())
+	}
+	a.Hex = addr
+	return a, nil
+}
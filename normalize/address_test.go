@@ -0,0 +1,97 @@
+package normalize
+
+import (
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/nervosnetwork/ckb-sdk-go/address"
+	"testing"
+)
+
+func TestCanonicalHex(t *testing.T) {
+	list := []struct {
+		algId common.DasAlgorithmId
+		hex   string
+		res   string
+	}{
+		{common.DasAlgorithmIdEth712, "0x15A33588908CF8EDB27D1ABE3852BF287ABD3891", "0x15a33588908cf8edb27d1abe3852bf287abd3891"},
+		{common.DasAlgorithmIdCkbSingle, "c866479211cadf63ad115b9da50a6c16bd3d226d", "0xc866479211cadf63ad115b9da50a6c16bd3d226d"},
+		{common.DasAlgorithmIdEd25519, "0X00", "0x00"},
+		{common.DasAlgorithmIdTron, "e2d8a9e39d4c9fdf4cbee5b9c6a7f9c4bcd4c0e1", "41e2d8a9e39d4c9fdf4cbee5b9c6a7f9c4bcd4c0e1"},
+		{common.DasAlgorithmIdTron, "41E2D8A9E39D4C9FDF4CBEE5B9C6A7F9C4BCD4C0E1", "41e2d8a9e39d4c9fdf4cbee5b9c6a7f9c4bcd4c0e1"},
+		{common.DasAlgorithmIdDogeChain, "0xAB", "ab"},
+		{common.DasAlgorithmIdWebauthn, "0x60cd187da5231e24a421cfdca1d24955007807b5", "60cd187da5231e24a421cfdca1d24955007807b5"},
+		{common.DasAlgorithmIdAnyLock, "ckt1qyqrdsefa43s6m882pcj53m4gdnj4k440axqswmu83", "ckt1qyqrdsefa43s6m882pcj53m4gdnj4k440axqswmu83"},
+		{common.DasAlgorithmIdCkb, "ckt1qyqrdsefa43s6m882pcj53m4gdnj4k440axqswmu83", "ckt1qyqrdsefa43s6m882pcj53m4gdnj4k440axqswmu83"},
+		{common.DasAlgorithmIdEth, "", ""},
+	}
+	for _, v := range list {
+		if res := CanonicalHex(v.algId, v.hex); res != v.res {
+			t.Fatal(v.algId, v.hex, res)
+		}
+	}
+}
+
+func TestFromArgs(t *testing.T) {
+	an := NewAddressNormalizer(common.DasNetTypeTestnet2)
+	args := common.Hex2Bytes("0x0515a33588908cf8edb27d1abe3852bf287abd38910515a33588908cf8edb27d1abe3852bf287abd3891")
+	owner, manager, err := an.FromArgs(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owner.Hex != "0x15a33588908cf8edb27d1abe3852bf287abd3891" || owner.ChainType != common.ChainTypeEth || manager != owner {
+		t.Fatal(owner, manager)
+	}
+	if common.Bytes2Hex(owner.Payload()) != "0x15a33588908cf8edb27d1abe3852bf287abd3891" {
+		t.Fatal(common.Bytes2Hex(owner.Payload()))
+	}
+	res, err := an.ToArgs(owner, manager)
+	if err != nil || common.Bytes2Hex(res) != common.Bytes2Hex(args) {
+		t.Fatal(common.Bytes2Hex(res), err)
+	}
+}
+
+func TestFromPayload(t *testing.T) {
+	an := NewAddressNormalizer(common.DasNetTypeTestnet2)
+	list := []struct {
+		payload string
+		algId   common.DasAlgorithmId
+	}{
+		{"0xc866479211cadf63ad115b9da50a6c16bd3d226d", common.DasAlgorithmIdCkbSingle},
+		{"0x15a33588908cf8edb27d1abe3852bf287abd3891", common.DasAlgorithmIdEth712},
+		{"0xe2d8a9e39d4c9fdf4cbee5b9c6a7f9c4bcd4c0e1", common.DasAlgorithmIdTron},
+		{"0x6fcd187da5231e24a421cfdca1d24955007807b5", common.DasAlgorithmIdDogeChain},
+	}
+	for _, v := range list {
+		payload := common.Hex2Bytes(v.payload)
+		res := an.FromPayload(payload, v.algId)
+		if res.ChainType != v.algId.ToChainType() || res.Hex != CanonicalHex(v.algId, res.Hex) {
+			t.Fatal(res)
+		}
+		if common.Bytes2Hex(res.Payload()) != common.Bytes2Hex(payload) {
+			t.Fatal(common.Bytes2Hex(res.Payload()))
+		}
+		// the reverse records keep the payload as das-lib formats it
+		if res.ReverseHex() != common.FormatAddressPayload(payload, v.algId) {
+			t.Fatal(res.ReverseHex(), common.FormatAddressPayload(payload, v.algId))
+		}
+	}
+}
+
+func TestCanonicalAnyLock(t *testing.T) {
+	short := "ckt1qyqrdsefa43s6m882pcj53m4gdnj4k440axqswmu83"
+	an := NewAddressNormalizer(common.DasNetTypeTestnet2)
+	res, err := an.Canonical(Address{ChainType: common.ChainTypeAnyLock, Hex: short})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsedShort, _ := address.Parse(short)
+	parsed, err := address.Parse(res.Hex)
+	if err != nil || res.Hex == short || !parsed.Script.Equals(parsedShort.Script) {
+		t.Fatal(res.Hex, err)
+	}
+	if again, _ := an.Canonical(res); again.Hex != res.Hex {
+		t.Fatal(again.Hex)
+	}
+	if _, err := NewAddressNormalizer(common.DasNetTypeMainNet).Canonical(res); err == nil {
+		t.Fatal("address of another net")
+	}
+}
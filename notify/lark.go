@@ -60,3 +60,16 @@ func SendLarkErrNotify(title, text string) {
 	}
 	prometheus.Tools.Metrics.ErrNotify().WithLabelValues(title, text).Inc()
 }
+
+func SendWebhook(url string, data interface{}) error {
+	if url == "" {
+		return nil
+	}
+	resp, _, errs := gorequest.New().Post(url).Timeout(time.Second * 10).SendStruct(data).End()
+	if len(errs) > 0 {
+		return fmt.Errorf("errs:%v", errs)
+	} else if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("http code:%d", resp.StatusCode)
+	}
+	return nil
+}
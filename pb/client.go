@@ -0,0 +1,40 @@
+// Package pb the grpc api of das-database, generated from das_database.proto, and the client of the internal services
+package pb
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative das_database.proto
+
+// ErrNoTransport the err_no of the failed calls which did not reach the handlers
+const ErrNoTransport = -1
+
+// Dial the client of grpc_server_addr, the api is served without tls inside the private network
+func Dial(ctx context.Context, addr string, opts ...grpc.DialOption) (DasDatabaseClient, *grpc.ClientConn, error) {
+	opts = append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, opts...)
+	conn, err := grpc.DialContext(ctx, addr, opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("grpc.DialContext err: %s", err.Error())
+	}
+	return NewDasDatabaseClient(conn), conn, nil
+}
+
+// GetError the err_no and err_msg of the json-rpc api kept in the status of a failed call,
+// nil for a successful one
+func GetError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	st := status.Convert(err)
+	for _, v := range st.Details() {
+		if e, ok := v.(*Error); ok {
+			return e
+		}
+	}
+	return &Error{ErrNo: ErrNoTransport, ErrMsg: st.Message()}
+}
@@ -0,0 +1,331 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: das_database.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	DasDatabase_SnapshotPermissionsInfo_FullMethodName = "/das_database.DasDatabase/SnapshotPermissionsInfo"
+	DasDatabase_SnapshotAddressAccounts_FullMethodName = "/das_database.DasDatabase/SnapshotAddressAccounts"
+	DasDatabase_SnapshotDidList_FullMethodName         = "/das_database.DasDatabase/SnapshotDidList"
+	DasDatabase_SnapshotVerify_FullMethodName          = "/das_database.DasDatabase/SnapshotVerify"
+	DasDatabase_SnapshotRegisterHistory_FullMethodName = "/das_database.DasDatabase/SnapshotRegisterHistory"
+	DasDatabase_AccountInfo_FullMethodName             = "/das_database.DasDatabase/AccountInfo"
+	DasDatabase_AccountRecords_FullMethodName          = "/das_database.DasDatabase/AccountRecords"
+)
+
+// DasDatabaseClient is the client API for DasDatabase service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DasDatabaseClient interface {
+	SnapshotPermissionsInfo(ctx context.Context, in *SnapshotPermissionsInfoRequest, opts ...grpc.CallOption) (*SnapshotPermissionsInfoReply, error)
+	SnapshotAddressAccounts(ctx context.Context, in *SnapshotAddressAccountsRequest, opts ...grpc.CallOption) (*SnapshotAddressAccountsReply, error)
+	SnapshotDidList(ctx context.Context, in *SnapshotDidListRequest, opts ...grpc.CallOption) (*SnapshotDidListReply, error)
+	SnapshotVerify(ctx context.Context, in *SnapshotVerifyRequest, opts ...grpc.CallOption) (*SnapshotVerifyReply, error)
+	SnapshotRegisterHistory(ctx context.Context, in *SnapshotRegisterHistoryRequest, opts ...grpc.CallOption) (*SnapshotRegisterHistoryReply, error)
+	AccountInfo(ctx context.Context, in *AccountInfoRequest, opts ...grpc.CallOption) (*AccountInfoReply, error)
+	AccountRecords(ctx context.Context, in *AccountRecordsRequest, opts ...grpc.CallOption) (*AccountRecordsReply, error)
+}
+
+type dasDatabaseClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDasDatabaseClient(cc grpc.ClientConnInterface) DasDatabaseClient {
+	return &dasDatabaseClient{cc}
+}
+
+func (c *dasDatabaseClient) SnapshotPermissionsInfo(ctx context.Context, in *SnapshotPermissionsInfoRequest, opts ...grpc.CallOption) (*SnapshotPermissionsInfoReply, error) {
+	out := new(SnapshotPermissionsInfoReply)
+	err := c.cc.Invoke(ctx, DasDatabase_SnapshotPermissionsInfo_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dasDatabaseClient) SnapshotAddressAccounts(ctx context.Context, in *SnapshotAddressAccountsRequest, opts ...grpc.CallOption) (*SnapshotAddressAccountsReply, error) {
+	out := new(SnapshotAddressAccountsReply)
+	err := c.cc.Invoke(ctx, DasDatabase_SnapshotAddressAccounts_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dasDatabaseClient) SnapshotDidList(ctx context.Context, in *SnapshotDidListRequest, opts ...grpc.CallOption) (*SnapshotDidListReply, error) {
+	out := new(SnapshotDidListReply)
+	err := c.cc.Invoke(ctx, DasDatabase_SnapshotDidList_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dasDatabaseClient) SnapshotVerify(ctx context.Context, in *SnapshotVerifyRequest, opts ...grpc.CallOption) (*SnapshotVerifyReply, error) {
+	out := new(SnapshotVerifyReply)
+	err := c.cc.Invoke(ctx, DasDatabase_SnapshotVerify_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dasDatabaseClient) SnapshotRegisterHistory(ctx context.Context, in *SnapshotRegisterHistoryRequest, opts ...grpc.CallOption) (*SnapshotRegisterHistoryReply, error) {
+	out := new(SnapshotRegisterHistoryReply)
+	err := c.cc.Invoke(ctx, DasDatabase_SnapshotRegisterHistory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dasDatabaseClient) AccountInfo(ctx context.Context, in *AccountInfoRequest, opts ...grpc.CallOption) (*AccountInfoReply, error) {
+	out := new(AccountInfoReply)
+	err := c.cc.Invoke(ctx, DasDatabase_AccountInfo_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dasDatabaseClient) AccountRecords(ctx context.Context, in *AccountRecordsRequest, opts ...grpc.CallOption) (*AccountRecordsReply, error) {
+	out := new(AccountRecordsReply)
+	err := c.cc.Invoke(ctx, DasDatabase_AccountRecords_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DasDatabaseServer is the server API for DasDatabase service.
+// All implementations must embed UnimplementedDasDatabaseServer
+// for forward compatibility
+type DasDatabaseServer interface {
+	SnapshotPermissionsInfo(context.Context, *SnapshotPermissionsInfoRequest) (*SnapshotPermissionsInfoReply, error)
+	SnapshotAddressAccounts(context.Context, *SnapshotAddressAccountsRequest) (*SnapshotAddressAccountsReply, error)
+	SnapshotDidList(context.Context, *SnapshotDidListRequest) (*SnapshotDidListReply, error)
+	SnapshotVerify(context.Context, *SnapshotVerifyRequest) (*SnapshotVerifyReply, error)
+	SnapshotRegisterHistory(context.Context, *SnapshotRegisterHistoryRequest) (*SnapshotRegisterHistoryReply, error)
+	AccountInfo(context.Context, *AccountInfoRequest) (*AccountInfoReply, error)
+	AccountRecords(context.Context, *AccountRecordsRequest) (*AccountRecordsReply, error)
+	mustEmbedUnimplementedDasDatabaseServer()
+}
+
+// UnimplementedDasDatabaseServer must be embedded to have forward compatible implementations.
+type UnimplementedDasDatabaseServer struct {
+}
+
+func (UnimplementedDasDatabaseServer) SnapshotPermissionsInfo(context.Context, *SnapshotPermissionsInfoRequest) (*SnapshotPermissionsInfoReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SnapshotPermissionsInfo not implemented")
+}
+func (UnimplementedDasDatabaseServer) SnapshotAddressAccounts(context.Context, *SnapshotAddressAccountsRequest) (*SnapshotAddressAccountsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SnapshotAddressAccounts not implemented")
+}
+func (UnimplementedDasDatabaseServer) SnapshotDidList(context.Context, *SnapshotDidListRequest) (*SnapshotDidListReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SnapshotDidList not implemented")
+}
+func (UnimplementedDasDatabaseServer) SnapshotVerify(context.Context, *SnapshotVerifyRequest) (*SnapshotVerifyReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SnapshotVerify not implemented")
+}
+func (UnimplementedDasDatabaseServer) SnapshotRegisterHistory(context.Context, *SnapshotRegisterHistoryRequest) (*SnapshotRegisterHistoryReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SnapshotRegisterHistory not implemented")
+}
+func (UnimplementedDasDatabaseServer) AccountInfo(context.Context, *AccountInfoRequest) (*AccountInfoReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AccountInfo not implemented")
+}
+func (UnimplementedDasDatabaseServer) AccountRecords(context.Context, *AccountRecordsRequest) (*AccountRecordsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AccountRecords not implemented")
+}
+func (UnimplementedDasDatabaseServer) mustEmbedUnimplementedDasDatabaseServer() {}
+
+// UnsafeDasDatabaseServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DasDatabaseServer will
+// result in compilation errors.
+type UnsafeDasDatabaseServer interface {
+	mustEmbedUnimplementedDasDatabaseServer()
+}
+
+func RegisterDasDatabaseServer(s grpc.ServiceRegistrar, srv DasDatabaseServer) {
+	s.RegisterService(&DasDatabase_ServiceDesc, srv)
+}
+
+func _DasDatabase_SnapshotPermissionsInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnapshotPermissionsInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DasDatabaseServer).SnapshotPermissionsInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DasDatabase_SnapshotPermissionsInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DasDatabaseServer).SnapshotPermissionsInfo(ctx, req.(*SnapshotPermissionsInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DasDatabase_SnapshotAddressAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnapshotAddressAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DasDatabaseServer).SnapshotAddressAccounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DasDatabase_SnapshotAddressAccounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DasDatabaseServer).SnapshotAddressAccounts(ctx, req.(*SnapshotAddressAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DasDatabase_SnapshotDidList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnapshotDidListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DasDatabaseServer).SnapshotDidList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DasDatabase_SnapshotDidList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DasDatabaseServer).SnapshotDidList(ctx, req.(*SnapshotDidListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DasDatabase_SnapshotVerify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnapshotVerifyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DasDatabaseServer).SnapshotVerify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DasDatabase_SnapshotVerify_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DasDatabaseServer).SnapshotVerify(ctx, req.(*SnapshotVerifyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DasDatabase_SnapshotRegisterHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnapshotRegisterHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DasDatabaseServer).SnapshotRegisterHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DasDatabase_SnapshotRegisterHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DasDatabaseServer).SnapshotRegisterHistory(ctx, req.(*SnapshotRegisterHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DasDatabase_AccountInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DasDatabaseServer).AccountInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DasDatabase_AccountInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DasDatabaseServer).AccountInfo(ctx, req.(*AccountInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DasDatabase_AccountRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountRecordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DasDatabaseServer).AccountRecords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DasDatabase_AccountRecords_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DasDatabaseServer).AccountRecords(ctx, req.(*AccountRecordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DasDatabase_ServiceDesc is the grpc.ServiceDesc for DasDatabase service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DasDatabase_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "das_database.DasDatabase",
+	HandlerType: (*DasDatabaseServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SnapshotPermissionsInfo",
+			Handler:    _DasDatabase_SnapshotPermissionsInfo_Handler,
+		},
+		{
+			MethodName: "SnapshotAddressAccounts",
+			Handler:    _DasDatabase_SnapshotAddressAccounts_Handler,
+		},
+		{
+			MethodName: "SnapshotDidList",
+			Handler:    _DasDatabase_SnapshotDidList_Handler,
+		},
+		{
+			MethodName: "SnapshotVerify",
+			Handler:    _DasDatabase_SnapshotVerify_Handler,
+		},
+		{
+			MethodName: "SnapshotRegisterHistory",
+			Handler:    _DasDatabase_SnapshotRegisterHistory_Handler,
+		},
+		{
+			MethodName: "AccountInfo",
+			Handler:    _DasDatabase_AccountInfo_Handler,
+		},
+		{
+			MethodName: "AccountRecords",
+			Handler:    _DasDatabase_AccountRecords_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "das_database.proto",
+}
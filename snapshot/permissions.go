@@ -1,13 +1,12 @@
 package snapshot
 
 import (
-	"das_database/config"
 	"das_database/dao"
+	"das_database/normalize"
 	"fmt"
 	"github.com/dotbitHQ/das-lib/common"
 	"github.com/dotbitHQ/das-lib/core"
 	"github.com/dotbitHQ/das-lib/witness"
-	"github.com/nervosnetwork/ckb-sdk-go/address"
 	"github.com/nervosnetwork/ckb-sdk-go/types"
 )
 
@@ -35,13 +34,9 @@ func (t *ToolSnapshot) addAccountPermissionsForDidCell(info dao.TableSnapshotTxI
 		account := cellDataNew.Account
 		accountId := common.Bytes2Hex(common.GetAccountIdByAccount(account))
 		if !v.Lock.Equals(n.Lock) {
-			mode := address.Mainnet
-			if config.Cfg.Server.Net != common.DasNetTypeMainNet {
-				mode = address.Testnet
-			}
-			addr, err := address.ConvertScriptToAddress(mode, n.Lock)
+			addr, err := t.an.AnyLock(n.Lock)
 			if err != nil {
-				return fmt.Errorf("address.ConvertScriptToAddress err: %s", err.Error())
+				return fmt.Errorf("AnyLock err: %s", err.Error())
 			}
 
 			tmp := dao.TableSnapshotPermissionsInfo{
@@ -50,10 +45,10 @@ func (t *ToolSnapshot) addAccountPermissionsForDidCell(info dao.TableSnapshotTxI
 				Hash:               info.Hash,
 				Account:            account,
 				BlockTimestamp:     info.BlockTimestamp,
-				Owner:              addr,
-				Manager:            addr,
-				OwnerAlgorithmId:   common.DasAlgorithmIdAnyLock,
-				ManagerAlgorithmId: common.DasAlgorithmIdAnyLock,
+				Owner:              addr.Hex,
+				Manager:            addr.Hex,
+				OwnerAlgorithmId:   addr.AlgorithmId,
+				ManagerAlgorithmId: addr.AlgorithmId,
 				Status:             dao.AccountStatusOnUpgrade,
 				ExpiredAt:          cellDataNew.ExpireAt,
 			}
@@ -76,9 +71,9 @@ func (t *ToolSnapshot) addAccountPermissions(info dao.TableSnapshotTxInfo, tx *t
 	}
 	var list []dao.TableSnapshotPermissionsInfo
 	for k, v := range mapAcc {
-		owner, manager, err := t.DasCore.Daf().ArgsToHex(tx.Outputs[v.Index].Lock.Args)
+		owner, manager, err := t.an.FromArgs(tx.Outputs[v.Index].Lock.Args)
 		if err != nil {
-			return fmt.Errorf("ArgsToHex err: %s", err.Error())
+			return fmt.Errorf("FromArgs err: %s", err.Error())
 		}
 		tmp := dao.TableSnapshotPermissionsInfo{
 			BlockNumber:        info.BlockNumber,
@@ -86,10 +81,10 @@ func (t *ToolSnapshot) addAccountPermissions(info dao.TableSnapshotTxInfo, tx *t
 			Hash:               info.Hash,
 			Account:            v.Account,
 			BlockTimestamp:     info.BlockTimestamp,
-			Owner:              owner.AddressHex,
-			Manager:            manager.AddressHex,
-			OwnerAlgorithmId:   owner.DasAlgorithmId,
-			ManagerAlgorithmId: manager.DasAlgorithmId,
+			Owner:              owner.Hex,
+			Manager:            manager.Hex,
+			OwnerAlgorithmId:   owner.AlgorithmId,
+			ManagerAlgorithmId: manager.AlgorithmId,
 			Status:             dao.AccountStatusNormal,
 			ExpiredAt:          v.ExpiredAt,
 		}
@@ -97,7 +92,7 @@ func (t *ToolSnapshot) addAccountPermissions(info dao.TableSnapshotTxInfo, tx *t
 			tmp.Status = dao.AccountStatusOnSale
 		} else if info.Action == common.DasActionLockAccountForCrossChain {
 			tmp.Status = dao.AccountStatusOnLock
-			if owner.AddressHex == "0x0000000000000000000000000000000000000000" {
+			if owner.Hex == "0x0000000000000000000000000000000000000000" {
 				log.Info("cross black address:", info.Action, info.Hash)
 
 				refOutpoint := tx.Inputs[0].PreviousOutput
@@ -105,14 +100,14 @@ func (t *ToolSnapshot) addAccountPermissions(info dao.TableSnapshotTxInfo, tx *t
 				if err != nil {
 					return fmt.Errorf("GetTransaction err: %s", err.Error())
 				}
-				owner, manager, err = t.DasCore.Daf().ArgsToHex(res.Transaction.Outputs[refOutpoint.Index].Lock.Args)
+				owner, manager, err = t.an.FromArgs(res.Transaction.Outputs[refOutpoint.Index].Lock.Args)
 				if err != nil {
-					return fmt.Errorf("ArgsToHex err: %s", err.Error())
+					return fmt.Errorf("FromArgs err: %s", err.Error())
 				}
-				tmp.Owner = owner.AddressHex
-				tmp.OwnerAlgorithmId = owner.DasAlgorithmId
-				tmp.Manager = manager.AddressHex
-				tmp.ManagerAlgorithmId = manager.DasAlgorithmId
+				tmp.Owner = owner.Hex
+				tmp.OwnerAlgorithmId = owner.AlgorithmId
+				tmp.Manager = manager.Hex
+				tmp.ManagerAlgorithmId = manager.AlgorithmId
 			}
 		}
 
@@ -125,16 +120,12 @@ func (t *ToolSnapshot) addAccountPermissions(info dao.TableSnapshotTxInfo, tx *t
 			tmp.ManagerAlgorithmId = common.DasAlgorithmIdAnyLock
 			tmp.Status = dao.AccountStatusOnUpgrade
 			for k, c := range res.Outputs {
-				mode := address.Mainnet
-				if config.Cfg.Server.Net != common.DasNetTypeMainNet {
-					mode = address.Testnet
-				}
-				addr, err := address.ConvertScriptToAddress(mode, c.Lock)
+				addr, err := t.an.AnyLock(c.Lock)
 				if err != nil {
-					return fmt.Errorf("address.ConvertScriptToAddress err: %s[%s]", err.Error(), k)
+					return fmt.Errorf("AnyLock err: %s[%s]", err.Error(), k)
 				}
-				tmp.Owner = addr
-				tmp.Manager = addr
+				tmp.Owner = addr.Hex
+				tmp.Manager = addr.Hex
 			}
 		}
 		list = append(list, tmp)
@@ -168,9 +159,9 @@ func (t *ToolSnapshot) addSubAccountPermissionsByDasActionCreateSubAccount(info
 	}
 	var list []dao.TableSnapshotPermissionsInfo
 	for k, v := range mapSubAcc {
-		owner, manager, err := t.DasCore.Daf().ArgsToHex(v.CurrentSubAccountData.Lock.Args)
+		owner, manager, err := t.an.FromArgs(v.CurrentSubAccountData.Lock.Args)
 		if err != nil {
-			return fmt.Errorf("ArgsToHex err: %s", err.Error())
+			return fmt.Errorf("FromArgs err: %s", err.Error())
 		}
 		tmp := dao.TableSnapshotPermissionsInfo{
 			BlockNumber:        info.BlockNumber,
@@ -179,10 +170,10 @@ func (t *ToolSnapshot) addSubAccountPermissionsByDasActionCreateSubAccount(info
 			Hash:               info.Hash,
 			Account:            v.Account,
 			BlockTimestamp:     info.BlockTimestamp,
-			Owner:              owner.AddressHex,
-			Manager:            manager.AddressHex,
-			OwnerAlgorithmId:   owner.DasAlgorithmId,
-			ManagerAlgorithmId: manager.DasAlgorithmId,
+			Owner:              owner.Hex,
+			Manager:            manager.Hex,
+			OwnerAlgorithmId:   owner.AlgorithmId,
+			ManagerAlgorithmId: manager.AlgorithmId,
 			ExpiredAt:          v.CurrentSubAccountData.ExpiredAt,
 		}
 		list = append(list, tmp)
@@ -221,9 +212,9 @@ func (t *ToolSnapshot) addSubAccountPermissionsByDasActionEditSubAccount(info da
 			continue
 		}
 
-		owner, manager, err := t.DasCore.Daf().ArgsToHex(v.CurrentSubAccountData.Lock.Args)
+		owner, manager, err := t.an.FromArgs(v.CurrentSubAccountData.Lock.Args)
 		if err != nil {
-			return fmt.Errorf("ArgsToHex err: %s", err.Error())
+			return fmt.Errorf("FromArgs err: %s", err.Error())
 		}
 		tmp := dao.TableSnapshotPermissionsInfo{
 			BlockNumber:        info.BlockNumber,
@@ -232,10 +223,10 @@ func (t *ToolSnapshot) addSubAccountPermissionsByDasActionEditSubAccount(info da
 			Hash:               info.Hash,
 			Account:            v.Account,
 			BlockTimestamp:     info.BlockTimestamp,
-			Owner:              owner.AddressHex,
-			Manager:            manager.AddressHex,
-			OwnerAlgorithmId:   owner.DasAlgorithmId,
-			ManagerAlgorithmId: manager.DasAlgorithmId,
+			Owner:              owner.Hex,
+			Manager:            manager.Hex,
+			OwnerAlgorithmId:   owner.AlgorithmId,
+			ManagerAlgorithmId: manager.AlgorithmId,
 			ExpiredAt:          v.CurrentSubAccountData.ExpiredAt,
 		}
 		list = append(list, tmp)
@@ -273,16 +264,16 @@ func (t *ToolSnapshot) addSubAccountPermissions(info dao.TableSnapshotTxInfo, tx
 		if info.Action == common.DasActionUpdateSubAccount && v.Action == common.SubActionEdit && v.EditKey != common.EditKeyOwner && v.EditKey != common.EditKeyManager {
 			continue
 		}
-		var owner, manager core.DasAddressHex
+		var owner, manager normalize.Address
 		if v.Action == common.SubActionRecycle {
-			owner, manager, err = t.DasCore.Daf().ArgsToHex(v.SubAccountData.Lock.Args)
+			owner, manager, err = t.an.FromArgs(v.SubAccountData.Lock.Args)
 			if err != nil {
-				return fmt.Errorf("ArgsToHex err: %s", err.Error())
+				return fmt.Errorf("FromArgs err: %s", err.Error())
 			}
 		} else {
-			owner, manager, err = t.DasCore.Daf().ArgsToHex(v.CurrentSubAccountData.Lock.Args)
+			owner, manager, err = t.an.FromArgs(v.CurrentSubAccountData.Lock.Args)
 			if err != nil {
-				return fmt.Errorf("ArgsToHex err: %s", err.Error())
+				return fmt.Errorf("FromArgs err: %s", err.Error())
 			}
 		}
 		tmp := dao.TableSnapshotPermissionsInfo{
@@ -292,14 +283,28 @@ func (t *ToolSnapshot) addSubAccountPermissions(info dao.TableSnapshotTxInfo, tx
 			Hash:               info.Hash,
 			Account:            v.Account,
 			BlockTimestamp:     info.BlockTimestamp,
-			Owner:              owner.AddressHex,
-			Manager:            manager.AddressHex,
-			OwnerAlgorithmId:   owner.DasAlgorithmId,
-			ManagerAlgorithmId: manager.DasAlgorithmId,
+			Owner:              owner.Hex,
+			Manager:            manager.Hex,
+			OwnerAlgorithmId:   owner.AlgorithmId,
+			ManagerAlgorithmId: manager.AlgorithmId,
 			ExpiredAt:          v.CurrentSubAccountData.ExpiredAt,
 		}
-		if v.Action == common.SubActionRecycle {
+		switch {
+		case v.Action == common.SubActionRecycle:
 			tmp.Status = dao.AccountStatusRecycle
+		case info.Action == common.DasActionLockSubAccountForCrossChain:
+			tmp.Status = dao.AccountStatusOnLock
+			// locked to the black hole address, the permissions stay with the owner before the lock
+			if owner.Hex == "0x0000000000000000000000000000000000000000" {
+				owner, manager, err = t.an.FromArgs(v.SubAccountData.Lock.Args)
+				if err != nil {
+					return fmt.Errorf("FromArgs err: %s", err.Error())
+				}
+				tmp.Owner = owner.Hex
+				tmp.OwnerAlgorithmId = owner.AlgorithmId
+				tmp.Manager = manager.Hex
+				tmp.ManagerAlgorithmId = manager.AlgorithmId
+			}
 		}
 		list = append(list, tmp)
 	}
@@ -330,9 +335,9 @@ func (t *ToolSnapshot) addAccountPermissionsByDasActionConfirmProposal(info dao.
 			continue
 		}
 
-		owner, manager, err := t.DasCore.Daf().ArgsToHex(tx.Outputs[v.Index].Lock.Args)
+		owner, manager, err := t.an.FromArgs(tx.Outputs[v.Index].Lock.Args)
 		if err != nil {
-			return fmt.Errorf("ArgsToHex err: %s", err.Error())
+			return fmt.Errorf("FromArgs err: %s", err.Error())
 		}
 		tmp := dao.TableSnapshotPermissionsInfo{
 			BlockNumber:        info.BlockNumber,
@@ -340,10 +345,10 @@ func (t *ToolSnapshot) addAccountPermissionsByDasActionConfirmProposal(info dao.
 			Hash:               info.Hash,
 			Account:            v.Account,
 			BlockTimestamp:     info.BlockTimestamp,
-			Owner:              owner.AddressHex,
-			Manager:            manager.AddressHex,
-			OwnerAlgorithmId:   owner.DasAlgorithmId,
-			ManagerAlgorithmId: manager.DasAlgorithmId,
+			Owner:              owner.Hex,
+			Manager:            manager.Hex,
+			OwnerAlgorithmId:   owner.AlgorithmId,
+			ManagerAlgorithmId: manager.AlgorithmId,
 			Status:             dao.AccountStatusNormal,
 			ExpiredAt:          v.ExpiredAt,
 		}
@@ -380,9 +385,9 @@ func (t *ToolSnapshot) addAccountPermissionsByDasActionRecycleExpiredAccount(inf
 		if err != nil {
 			return fmt.Errorf("GetTransaction err: %s", err.Error())
 		}
-		owner, manager, err := t.DasCore.Daf().ArgsToHex(res.Transaction.Outputs[refOutpoint.Index].Lock.Args)
+		owner, manager, err := t.an.FromArgs(res.Transaction.Outputs[refOutpoint.Index].Lock.Args)
 		if err != nil {
-			return fmt.Errorf("ArgsToHex err: %s", err.Error())
+			return fmt.Errorf("FromArgs err: %s", err.Error())
 		}
 
 		tmp := dao.TableSnapshotPermissionsInfo{
@@ -391,10 +396,10 @@ func (t *ToolSnapshot) addAccountPermissionsByDasActionRecycleExpiredAccount(inf
 			Hash:               info.Hash,
 			Account:            v.Account,
 			BlockTimestamp:     info.BlockTimestamp,
-			Owner:              owner.AddressHex,
-			Manager:            manager.AddressHex,
-			OwnerAlgorithmId:   owner.DasAlgorithmId,
-			ManagerAlgorithmId: manager.DasAlgorithmId,
+			Owner:              owner.Hex,
+			Manager:            manager.Hex,
+			OwnerAlgorithmId:   owner.AlgorithmId,
+			ManagerAlgorithmId: manager.AlgorithmId,
 			Status:             dao.AccountStatusRecycle,
 			ExpiredAt:          v.ExpiredAt,
 		}
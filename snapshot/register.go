@@ -28,9 +28,9 @@ func (t *ToolSnapshot) addAccountRegisterByDasActionConfirmProposal(info dao.Tab
 			continue
 		}
 
-		owner, _, err := t.DasCore.Daf().ArgsToHex(tx.Outputs[v.Index].Lock.Args)
+		owner, _, err := t.an.FromArgs(tx.Outputs[v.Index].Lock.Args)
 		if err != nil {
-			return fmt.Errorf("ArgsToHex err: %s", err.Error())
+			return fmt.Errorf("FromArgs err: %s", err.Error())
 		}
 		tmp := dao.TableSnapshotRegisterInfo{
 			BlockNumber:      info.BlockNumber,
@@ -39,8 +39,8 @@ func (t *ToolSnapshot) addAccountRegisterByDasActionConfirmProposal(info dao.Tab
 			Hash:             info.Hash,
 			Account:          v.Account,
 			BlockTimestamp:   info.BlockTimestamp,
-			Owner:            owner.AddressHex,
-			OwnerAlgorithmId: owner.DasAlgorithmId,
+			Owner:            owner.Hex,
+			OwnerAlgorithmId: owner.AlgorithmId,
 			RegisteredAt:     v.RegisteredAt,
 			ExpiredAt:        v.ExpiredAt,
 		}
@@ -76,9 +76,9 @@ func (t *ToolSnapshot) addSubAccountRegisterByDasActionCreateSubAccount(info dao
 	}
 	var list []dao.TableSnapshotRegisterInfo
 	for k, v := range mapSubAcc {
-		owner, _, err := t.DasCore.Daf().ArgsToHex(v.CurrentSubAccountData.Lock.Args)
+		owner, _, err := t.an.FromArgs(v.CurrentSubAccountData.Lock.Args)
 		if err != nil {
-			return fmt.Errorf("ArgsToHex err: %s", err.Error())
+			return fmt.Errorf("FromArgs err: %s", err.Error())
 		}
 		tmp := dao.TableSnapshotRegisterInfo{
 			BlockNumber:      info.BlockNumber,
@@ -87,9 +87,9 @@ func (t *ToolSnapshot) addSubAccountRegisterByDasActionCreateSubAccount(info dao
 			Hash:             info.Hash,
 			Account:          v.CurrentSubAccountData.Account(),
 			BlockTimestamp:   info.BlockTimestamp,
-			Owner:            owner.AddressHex,
+			Owner:            owner.Hex,
 			RegisteredAt:     v.CurrentSubAccountData.RegisteredAt,
-			OwnerAlgorithmId: owner.DasAlgorithmId,
+			OwnerAlgorithmId: owner.AlgorithmId,
 			ExpiredAt:        v.CurrentSubAccountData.ExpiredAt,
 		}
 		list = append(list, tmp)
@@ -126,9 +126,9 @@ func (t *ToolSnapshot) addSubAccountRegister(info dao.TableSnapshotTxInfo, tx *t
 		if info.Action == common.DasActionUpdateSubAccount && v.Action != common.SubActionCreate {
 			continue
 		}
-		owner, _, err := t.DasCore.Daf().ArgsToHex(v.CurrentSubAccountData.Lock.Args)
+		owner, _, err := t.an.FromArgs(v.CurrentSubAccountData.Lock.Args)
 		if err != nil {
-			return fmt.Errorf("ArgsToHex err: %s", err.Error())
+			return fmt.Errorf("FromArgs err: %s", err.Error())
 		}
 		tmp := dao.TableSnapshotRegisterInfo{
 			BlockNumber:      info.BlockNumber,
@@ -137,9 +137,9 @@ func (t *ToolSnapshot) addSubAccountRegister(info dao.TableSnapshotTxInfo, tx *t
 			Hash:             info.Hash,
 			Account:          v.CurrentSubAccountData.Account(),
 			BlockTimestamp:   info.BlockTimestamp,
-			Owner:            owner.AddressHex,
+			Owner:            owner.Hex,
 			RegisteredAt:     v.CurrentSubAccountData.RegisteredAt,
-			OwnerAlgorithmId: owner.DasAlgorithmId,
+			OwnerAlgorithmId: owner.AlgorithmId,
 			ExpiredAt:        v.CurrentSubAccountData.ExpiredAt,
 		}
 		list = append(list, tmp)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"das_database/config"
 	"das_database/dao"
+	"das_database/normalize"
 	"das_database/notify"
 	"fmt"
 	"github.com/dotbitHQ/das-lib/common"
@@ -26,6 +27,7 @@ type ToolSnapshot struct {
 	ConcurrencyNum uint64
 	ConfirmNum     uint64
 
+	an                   *normalize.AddressNormalizer
 	currentBlockNumber   uint64
 	parserType           dao.ParserType
 	mapTransactionHandle map[common.DasAction][]FuncTransactionHandle
@@ -37,6 +39,7 @@ type FuncTransactionHandle func(info dao.TableSnapshotTxInfo, tx *types.Transact
 
 func (t *ToolSnapshot) init() error {
 	t.parserType = dao.ParserTypeSnapshot
+	t.an = normalize.NewAddressNormalizer(t.DasCore.NetType())
 	t.registerTransactionHandle()
 	if err := t.initCurrentBlockNumber(); err != nil {
 		return fmt.Errorf("initCurrentBlockNumber err: %s", err.Error())
@@ -77,6 +80,8 @@ func (t *ToolSnapshot) registerTransactionHandle() {
 	t.mapTransactionHandle[common.DasActionCreateSubAccount] = []FuncTransactionHandle{t.addSubAccountPermissionsByDasActionCreateSubAccount, t.addSubAccountRegisterByDasActionCreateSubAccount}
 	t.mapTransactionHandle[common.DasActionEditSubAccount] = []FuncTransactionHandle{t.addSubAccountPermissionsByDasActionEditSubAccount}
 	t.mapTransactionHandle[common.DasActionUpdateSubAccount] = []FuncTransactionHandle{t.addSubAccountPermissions, t.addSubAccountRegister}
+	t.mapTransactionHandle[common.DasActionLockSubAccountForCrossChain] = []FuncTransactionHandle{t.addSubAccountPermissions}
+	t.mapTransactionHandle[common.DasActionUnlockSubAccountForCrossChain] = []FuncTransactionHandle{t.addSubAccountPermissions}
 
 	t.mapTransactionHandle[common.DasActionBidExpiredAccountAuction] = []FuncTransactionHandle{t.addAccountPermissions}
 
@@ -0,0 +1,51 @@
+package timer
+
+import (
+	"time"
+)
+
+// RunFixAccountSearch fills account_length and reverse_account of the accounts stored before the columns existed,
+// and account_length of the snapshot permissions rows
+func (p *ParserTimer) RunFixAccountSearch() {
+	tickerSearch := time.NewTicker(time.Second * 10)
+	p.Wg.Add(1)
+	go func() {
+		defer tickerSearch.Stop()
+		for {
+			select {
+			case <-tickerSearch.C:
+				if p.doFixAccountSearch() {
+					p.Wg.Done()
+					return
+				}
+			case <-p.Ctx.Done():
+				p.Wg.Done()
+				return
+			}
+		}
+	}()
+}
+
+func (p *ParserTimer) doFixAccountSearch() bool {
+	list, err := p.DbDao.GetNeedFixSearchAccountList()
+	if err != nil {
+		log.Error("GetNeedFixSearchAccountList err: ", err.Error())
+		return false
+	}
+	snapshotList, err := p.DbDao.GetNeedFixSnapshotLengthList()
+	if err != nil {
+		log.Error("GetNeedFixSnapshotLengthList err: ", err.Error())
+		return false
+	}
+	if len(list) == 0 && len(snapshotList) == 0 {
+		log.Info("doFixAccountSearch ok")
+		return true
+	}
+	if err := p.DbDao.UpdateAccountSearchFields(list); err != nil {
+		log.Error("UpdateAccountSearchFields err: ", err.Error())
+	}
+	if err := p.DbDao.UpdateSnapshotAccountLength(snapshotList); err != nil {
+		log.Error("UpdateSnapshotAccountLength err: ", err.Error())
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package timer
+
+import (
+	"das_database/config"
+	"das_database/dao"
+	"das_database/notify"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"strings"
+	"time"
+)
+
+type ApprovalNotice struct {
+	Event          dao.ApprovalEvent `json:"event"`
+	Account        string            `json:"account"`
+	Platform       string            `json:"platform"`
+	Owner          string            `json:"owner"`
+	To             string            `json:"to"`
+	ProtectedUntil uint64            `json:"protected_until"`
+	SealedUntil    uint64            `json:"sealed_until"`
+	EventAt        uint64            `json:"event_at"`
+}
+
+// RunApprovalNotice emits an event once a pending approval leaves its protected window and once it reaches sealed_until,
+// events are kept in t_approval_event so each of them is sent once, a delayed approval is sent again with the new sealed_until
+func (p *ParserTimer) RunApprovalNotice() {
+	if !config.Cfg.ApprovalNotice.Open {
+		return
+	}
+	tickerApproval := time.NewTicker(time.Minute)
+	p.Wg.Add(1)
+	go func() {
+		defer http_api.RecoverPanic()
+		for {
+			select {
+			case <-tickerApproval.C:
+				if err := p.doApprovalNotice(); err != nil {
+					log.Error("doApprovalNotice err:", err.Error())
+					notify.SendLarkErrNotify("doApprovalNotice", err.Error())
+				}
+			case <-p.Ctx.Done():
+				p.Wg.Done()
+				return
+			}
+		}
+	}()
+}
+
+func (p *ParserTimer) doApprovalNotice() error {
+	now := uint64(time.Now().Unix())
+	limit := 500
+	for _, event := range []dao.ApprovalEvent{dao.ApprovalEventUnprotected, dao.ApprovalEventFulfillable} {
+		for {
+			list, err := p.DbDao.GetApprovalDueList(event, now, limit)
+			if err != nil {
+				return fmt.Errorf("GetApprovalDueList err: %s", err.Error())
+			}
+			if len(list) == 0 {
+				break
+			}
+			notices, events := approvalNotices(event, list)
+			// sent before the events are saved, a failed save sends them again rather than losing them
+			if err := notify.SendWebhook(config.Cfg.ApprovalNotice.Webhook, notices); err != nil {
+				return fmt.Errorf("SendWebhook err: %s", err.Error())
+			}
+			if err := p.DbDao.CreateApprovalEvents(events); err != nil {
+				return fmt.Errorf("CreateApprovalEvents err: %s", err.Error())
+			}
+			log.Info("doApprovalNotice:", event, len(list))
+
+			var sb strings.Builder
+			for i, v := range notices {
+				if i >= 50 {
+					sb.WriteString(fmt.Sprintf("... %d approvals in total\n", len(notices)))
+					break
+				}
+				sb.WriteString(fmt.Sprintf("%s is %s since %s\n", v.Account, v.Event, time.Unix(int64(v.EventAt), 0).UTC().Format(time.RFC3339)))
+			}
+			if err := notify.SendLarkTextNotify(config.Cfg.ApprovalNotice.LarkWebhook, "Account Approval", sb.String()); err != nil {
+				log.Error("SendLarkTextNotify err:", err.Error())
+			}
+			if len(list) < limit {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func approvalNotices(event dao.ApprovalEvent, list []dao.ApprovalInfo) (notices []ApprovalNotice, events []dao.TableApprovalEvent) {
+	for _, v := range list {
+		eventAt := v.ProtectedUntil
+		if event == dao.ApprovalEventFulfillable {
+			eventAt = v.SealedUntil
+		}
+		notices = append(notices, ApprovalNotice{
+			Event:          event,
+			Account:        v.Account,
+			Platform:       v.Platform,
+			Owner:          v.Owner,
+			To:             v.To,
+			ProtectedUntil: v.ProtectedUntil,
+			SealedUntil:    v.SealedUntil,
+			EventAt:        eventAt,
+		})
+		events = append(events, dao.TableApprovalEvent{
+			ApprovalId: v.ID,
+			AccountId:  v.AccountID,
+			Account:    v.Account,
+			Event:      event,
+			EventAt:    eventAt,
+		})
+	}
+	return
+}
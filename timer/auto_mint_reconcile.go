@@ -0,0 +1,202 @@
+package timer
+
+import (
+	"das_database/dao"
+	"das_database/notify"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/nervosnetwork/ckb-sdk-go/types"
+	"github.com/shopspring/decimal"
+	"strings"
+	"time"
+)
+
+// RunAutoMintReconcile checks every collected sub-account profit against the capacity withdrawn on chain,
+// and the capacity withdrawn against the income of the provider so far. Discrepancies are kept and sent to lark,
+// one message a run. The first run goes over all withdrawals again, so the rows of an older check are corrected
+func (p *ParserTimer) RunAutoMintReconcile() {
+	tickerReconcile := time.NewTicker(time.Hour)
+	p.Wg.Add(1)
+	go func() {
+		defer http_api.RecoverPanic()
+		full := true
+		for {
+			select {
+			case <-tickerReconcile.C:
+				if err := p.doAutoMintReconcile(full); err != nil {
+					log.Error("doAutoMintReconcile err:", err.Error())
+					notify.SendLarkErrNotify("doAutoMintReconcile", err.Error())
+				} else {
+					full = false
+				}
+			case <-p.Ctx.Done():
+				p.Wg.Done()
+				return
+			}
+		}
+	}()
+}
+
+type autoMintTx struct {
+	tx          *types.Transaction
+	blockNumber uint64
+}
+
+func (p *ParserTimer) getAutoMintTx(txHash string) (res autoMintTx, err error) {
+	txRes, err := p.DasCore.Client().GetTransaction(p.Ctx, types.HexToHash(txHash))
+	if err != nil {
+		return res, fmt.Errorf("GetTransaction err: %s", err.Error())
+	}
+	if txRes == nil || txRes.TxStatus == nil || txRes.TxStatus.BlockHash == nil {
+		return res, nil
+	}
+	header, err := p.DasCore.Client().GetHeader(p.Ctx, *txRes.TxStatus.BlockHash)
+	if err != nil {
+		return res, fmt.Errorf("GetHeader err: %s", err.Error())
+	}
+	res.tx, res.blockNumber = txRes.Transaction, header.Number
+	return res, nil
+}
+
+// doAutoMintReconcile the withdrawals are reconciled in statement id order, which is the order they were parsed in,
+// so the withdrawals of a provider before a statement are the reconciled ones with a smaller statement id.
+// The block number of the legacy statements is the block of the last income collected, the block of the tx is used instead
+func (p *ParserTimer) doAutoMintReconcile(full bool) error {
+	var afterId uint64
+	if !full {
+		last, err := p.DbDao.GetLastAutoMintReconcile()
+		if err != nil {
+			return fmt.Errorf("GetLastAutoMintReconcile err: %s", err.Error())
+		}
+		afterId = last.StatementId
+	}
+	limit := 100
+	var discrepancies []dao.TableSubAccountAutoMintReconcile
+	defer func() {
+		sendAutoMintDiscrepancies(discrepancies)
+	}()
+	for {
+		list, err := p.DbDao.GetAutoMintExpenditureList(afterId, limit)
+		if err != nil {
+			return fmt.Errorf("GetAutoMintExpenditureList err: %s", err.Error())
+		}
+		var res []dao.TableSubAccountAutoMintReconcile
+		txMap := make(map[string]autoMintTx)
+		// the withdrawals of this page are not stored yet
+		pendingMap := make(map[string]decimal.Decimal)
+		for _, v := range list {
+			tx, ok := txMap[v.TxHash]
+			if !ok {
+				if tx, err = p.getAutoMintTx(v.TxHash); err != nil {
+					return err
+				}
+				txMap[v.TxHash] = tx
+			}
+			withdrawn, found := autoMintWithdrawn(tx.tx, v.WitnessIndex, v.ServiceProviderId, v.Price)
+			blockNumber := tx.blockNumber
+			if !found {
+				blockNumber = v.BlockNumber
+			}
+
+			income, err := p.DbDao.GetAutoMintIncomeAt(v.ServiceProviderId, v.ParentAccountId, blockNumber)
+			if err != nil {
+				return fmt.Errorf("GetAutoMintIncomeAt err: %s", err.Error())
+			}
+			withdrawnBefore, err := p.DbDao.GetAutoMintWithdrawnBefore(v.ServiceProviderId, v.ParentAccountId, v.Id)
+			if err != nil {
+				return fmt.Errorf("GetAutoMintWithdrawnBefore err: %s", err.Error())
+			}
+			key := v.ServiceProviderId + v.ParentAccountId
+			balance := income.Sub(withdrawnBefore).Sub(pendingMap[key]).Sub(withdrawn)
+			pendingMap[key] = pendingMap[key].Add(withdrawn)
+
+			reconcile := dao.TableSubAccountAutoMintReconcile{
+				StatementId:       v.Id,
+				BlockNumber:       blockNumber,
+				TxHash:            v.TxHash,
+				WitnessIndex:      v.WitnessIndex,
+				ParentAccountId:   v.ParentAccountId,
+				ServiceProviderId: v.ServiceProviderId,
+				Recorded:          v.Price,
+				Withdrawn:         withdrawn,
+				Balance:           balance,
+				Status:            autoMintReconcileStatus(found, v.Price, withdrawn, balance),
+			}
+			res = append(res, reconcile)
+			if reconcile.Status != dao.AutoMintReconcileStatusOk {
+				discrepancies = append(discrepancies, reconcile)
+			}
+		}
+		if err := p.DbDao.CreateAutoMintReconcileList(res); err != nil {
+			return fmt.Errorf("CreateAutoMintReconcileList err: %s", err.Error())
+		}
+		if len(list) < limit {
+			return nil
+		}
+		afterId = list[len(list)-1].Id
+	}
+}
+
+// sendAutoMintDiscrepancies one lark message with the count of each status and the first rows
+func sendAutoMintDiscrepancies(list []dao.TableSubAccountAutoMintReconcile) {
+	if len(list) == 0 {
+		return
+	}
+	countMap := make(map[dao.AutoMintReconcileStatus]int)
+	for _, v := range list {
+		countMap[v.Status]++
+	}
+	msg := fmt.Sprintf("discrepancies: %d\nmismatch: %d\noverdrawn: %d\nnot found: %d",
+		len(list), countMap[dao.AutoMintReconcileStatusMismatch], countMap[dao.AutoMintReconcileStatusOverdrawn], countMap[dao.AutoMintReconcileStatusNotFound])
+	for i, v := range list {
+		if i == 10 {
+			msg += fmt.Sprintf("\n... %d more", len(list)-i)
+			break
+		}
+		msg += fmt.Sprintf("\n%d %s[%d] provider: %s parent: %s withdrawn: %s balance: %s",
+			v.Status, v.TxHash, v.WitnessIndex, v.ServiceProviderId, v.ParentAccountId, v.Withdrawn, v.Balance)
+	}
+	notify.SendLarkErrNotify("AutoMintReconcile", msg)
+}
+
+// autoMintWithdrawn the capacity paid to the provider, by the output index of the statement,
+// or the output with the same capacity for the statements of the old collect txs which are not indexed
+func autoMintWithdrawn(tx *types.Transaction, index int, serviceProviderId string, recorded decimal.Decimal) (decimal.Decimal, bool) {
+	if tx == nil {
+		return decimal.Zero, false
+	}
+	isProvider := func(i int) bool {
+		return strings.EqualFold(common.Bytes2Hex(tx.Outputs[i].Lock.Args), serviceProviderId)
+	}
+	if index > 0 && index < len(tx.Outputs) && isProvider(index) {
+		return decimal.NewFromInt(int64(tx.Outputs[index].Capacity)), true
+	}
+	found := -1
+	for i := range tx.Outputs {
+		if !isProvider(i) {
+			continue
+		}
+		found = i
+		if decimal.NewFromInt(int64(tx.Outputs[i].Capacity)).Equal(recorded) {
+			break
+		}
+	}
+	if found < 0 {
+		return decimal.Zero, false
+	}
+	return decimal.NewFromInt(int64(tx.Outputs[found].Capacity)), true
+}
+
+func autoMintReconcileStatus(found bool, recorded, withdrawn, balance decimal.Decimal) dao.AutoMintReconcileStatus {
+	switch {
+	case !found:
+		return dao.AutoMintReconcileStatusNotFound
+	case !recorded.Equal(withdrawn):
+		return dao.AutoMintReconcileStatusMismatch
+	case balance.IsNegative():
+		return dao.AutoMintReconcileStatusOverdrawn
+	default:
+		return dao.AutoMintReconcileStatusOk
+	}
+}
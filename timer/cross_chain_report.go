@@ -0,0 +1,93 @@
+package timer
+
+import (
+	"das_database/config"
+	"das_database/dao"
+	"das_database/notify"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"strings"
+	"time"
+)
+
+// RunCrossChainReport sends the consistency report of the cross-chain bridges to the report webhook once a day when it finds anything:
+// bridges locked longer than stuck_days, unlocks without a lock, accounts locked twice, and locks disagreeing with t_account_info
+func (p *ParserTimer) RunCrossChainReport() {
+	if !config.Cfg.CrossChain.Report {
+		return
+	}
+	tickerReport := time.NewTicker(time.Hour * 24)
+	p.Wg.Add(1)
+	go func() {
+		defer http_api.RecoverPanic()
+		for {
+			select {
+			case <-tickerReport.C:
+				if err := p.doCrossChainReport(); err != nil {
+					log.Error("doCrossChainReport err:", err.Error())
+					notify.SendLarkErrNotify("doCrossChainReport", err.Error())
+				}
+			case <-p.Ctx.Done():
+				p.Wg.Done()
+				return
+			}
+		}
+	}()
+}
+
+func (p *ParserTimer) doCrossChainReport() error {
+	stuckDays := config.Cfg.CrossChain.StuckDays
+	if stuckDays == 0 {
+		stuckDays = 30
+	}
+	stuckBefore := uint64(time.Now().Add(-time.Duration(stuckDays) * 24 * time.Hour).UnixMilli())
+	report, err := p.DbDao.GetCrossChainReport(stuckBefore, 20)
+	if err != nil {
+		return fmt.Errorf("GetCrossChainReport err: %s", err.Error())
+	}
+	if report.IsEmpty() {
+		return nil
+	}
+	if err := notify.SendLarkTextNotify(config.Cfg.CrossChain.LarkWebhook, "Cross Chain Report", crossChainReportText(report, stuckDays)); err != nil {
+		return fmt.Errorf("SendLarkTextNotify err: %s", err.Error())
+	}
+	return nil
+}
+
+func crossChainReportText(report dao.CrossChainReport, stuckDays uint64) string {
+	var sb strings.Builder
+	lockedAt := func(v dao.TableCrossChainInfo) string {
+		return time.UnixMilli(int64(v.LockedAt)).UTC().Format(time.RFC3339)
+	}
+	if report.StuckCount > 0 {
+		sb.WriteString(fmt.Sprintf("locked over %d days: %d\n", stuckDays, report.StuckCount))
+		for _, v := range report.Stuck {
+			sb.WriteString(fmt.Sprintf("  %s since %s\n", v.Account, lockedAt(v)))
+		}
+	}
+	if len(report.OrphanUnlocks) > 0 {
+		sb.WriteString(fmt.Sprintf("unlocked without a lock: %d\n", len(report.OrphanUnlocks)))
+		for _, v := range report.OrphanUnlocks {
+			sb.WriteString(fmt.Sprintf("  %s in %s\n", v.Account, v.UnlockTxHash))
+		}
+	}
+	if len(report.DoubleLocks) > 0 {
+		sb.WriteString(fmt.Sprintf("locked twice: %d\n", len(report.DoubleLocks)))
+		for _, v := range report.DoubleLocks {
+			sb.WriteString(fmt.Sprintf("  %s in %s\n", v.Account, v.LockTxHash))
+		}
+	}
+	if len(report.StatusMismatch) > 0 {
+		sb.WriteString(fmt.Sprintf("locked but not on lock in account info: %d\n", len(report.StatusMismatch)))
+		for _, v := range report.StatusMismatch {
+			sb.WriteString(fmt.Sprintf("  %s since %s\n", v.Account, lockedAt(v)))
+		}
+	}
+	if len(report.Unregistered) > 0 {
+		sb.WriteString(fmt.Sprintf("on lock in account info without a lock: %d\n", len(report.Unregistered)))
+		for _, v := range report.Unregistered {
+			sb.WriteString(fmt.Sprintf("  %s at block %d\n", v.Account, v.BlockNumber))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,144 @@
+package timer
+
+import (
+	"das_database/config"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"sort"
+	"time"
+)
+
+const (
+	PriceSourceBinance   = "binance"
+	PriceSourceCoinGecko = "coingecko"
+	PriceSourceOkx       = "okx"
+	PriceSourceStatic    = "static"
+
+	PriceAggregatePriority = "priority"
+	PriceAggregateMedian   = "median"
+)
+
+// PriceSource returns the USD price of each requested symbol, symbols it can't price are left out
+type PriceSource interface {
+	Name() string
+	GetPrices(symbols []string) (map[string]decimal.Decimal, error)
+}
+
+func NewPriceSource(name string, cfg config.PriceSourceCfg, tokens map[string]config.PriceTokenCfg) (PriceSource, error) {
+	timeout := time.Second * 30
+	if cfg.Timeout > 0 {
+		timeout = time.Second * time.Duration(cfg.Timeout)
+	}
+	switch name {
+	case PriceSourceBinance:
+		return &BinancePriceSource{Url: cfg.Url, Timeout: timeout}, nil
+	case PriceSourceCoinGecko:
+		return &CoinGeckoPriceSource{Url: cfg.Url, ApiKey: cfg.ApiKey, Timeout: timeout}, nil
+	case PriceSourceOkx:
+		return &OkxPriceSource{Url: cfg.Url, Timeout: timeout}, nil
+	case PriceSourceStatic:
+		return NewStaticPriceSource(tokens)
+	}
+	return nil, fmt.Errorf("unknown price source [%s]", name)
+}
+
+// defaultPriceTokens keeps the former behaviour of pricing everything from binance when no tokens are configured,
+// the tokens of the registry with a price which binance doesn't serve keep that price
+func defaultPriceTokens() map[string]config.PriceTokenCfg {
+	tokens := make(map[string]config.PriceTokenCfg)
+	for symbol, tokenIds := range TokenIdMap {
+		for _, tokenId := range tokenIds {
+			tokens[tokenId] = config.PriceTokenCfg{
+				Aggregate: PriceAggregatePriority,
+				Sources:   []string{PriceSourceBinance},
+				Symbols:   map[string]string{PriceSourceBinance: symbol},
+			}
+		}
+	}
+	for _, v := range config.Cfg.TokenList {
+		if _, ok := tokens[v.TokenId]; !ok && v.Price != "" {
+			tokens[v.TokenId] = config.PriceTokenCfg{
+				Sources:     []string{PriceSourceStatic},
+				StaticPrice: v.Price,
+			}
+		}
+	}
+	return tokens
+}
+
+func tokenSymbol(tokenId, source string, token config.PriceTokenCfg) string {
+	if symbol, ok := token.Symbols[source]; ok {
+		return symbol
+	}
+	if source == PriceSourceStatic {
+		return tokenId
+	}
+	return ""
+}
+
+// aggregatePrice prices is in the token's source priority order
+func aggregatePrice(mode string, prices []decimal.Decimal) (decimal.Decimal, bool) {
+	if len(prices) == 0 {
+		return decimal.Zero, false
+	}
+	if mode != PriceAggregateMedian {
+		return prices[0], true
+	}
+	return medianPrice(prices), true
+}
+
+func medianPrice(prices []decimal.Decimal) decimal.Decimal {
+	sorted := make([]decimal.Decimal, len(prices))
+	copy(sorted, prices)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].LessThan(sorted[j])
+	})
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return sorted[mid-1].Add(sorted[mid]).Div(decimal.NewFromInt(2))
+}
+
+// fetchTokenPrices queries every source once for all of its symbols and aggregates the answers per token
+func fetchTokenPrices(sources map[string]PriceSource, tokens map[string]config.PriceTokenCfg) (map[string]decimal.Decimal, []error) {
+	var errs []error
+	symbolMap := make(map[string][]string)
+	for tokenId, token := range tokens {
+		for _, name := range token.Sources {
+			if symbol := tokenSymbol(tokenId, name, token); symbol != "" {
+				symbolMap[name] = append(symbolMap[name], symbol)
+			}
+		}
+	}
+
+	sourcePrices := make(map[string]map[string]decimal.Decimal)
+	for name, symbols := range symbolMap {
+		source, ok := sources[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("price source [%s] not configured", name))
+			continue
+		}
+		prices, err := source.GetPrices(symbols)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s GetPrices err: %s", name, err.Error()))
+			continue
+		}
+		sourcePrices[name] = prices
+	}
+
+	res := make(map[string]decimal.Decimal)
+	for tokenId, token := range tokens {
+		var prices []decimal.Decimal
+		for _, name := range token.Sources {
+			price, ok := sourcePrices[name][tokenSymbol(tokenId, name, token)]
+			if ok && price.IsPositive() {
+				prices = append(prices, price)
+			}
+		}
+		if price, ok := aggregatePrice(token.Aggregate, prices); ok {
+			res[tokenId] = price
+		}
+	}
+	return res, errs
+}
@@ -0,0 +1,89 @@
+package timer
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/parnurzeal/gorequest"
+	"github.com/shopspring/decimal"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// BinancePriceSource https://binance-docs.github.io/apidocs/spot/cn/#8ff46b58de
+type BinancePriceSource struct {
+	Url     string
+	Timeout time.Duration
+}
+
+type TokenPriceNew struct {
+	Symbol string          `json:"symbol"`
+	Price  decimal.Decimal `json:"price"`
+}
+
+// TokenIdMap default binance symbols of each token when no price tokens are configured
+var TokenIdMap = map[string][]string{
+	"CKBUSDT":  {"ckb_ckb", "ckb_ccc"},
+	"BTCUSDT":  {"btc_btc"},
+	"ETHUSDT":  {"eth_eth"},
+	"BNBUSDT":  {"bsc_bnb"},
+	"TRXUSDT":  {"tron_trx"},
+	"DOGEUSDT": {"doge_doge"},
+	"POLUSDT":  {"polygon_pol"},
+}
+
+func (b *BinancePriceSource) Name() string {
+	return PriceSourceBinance
+}
+
+// GetPrices binance rejects the whole batch when one symbol is invalid, the symbols are then queried one by one
+func (b *BinancePriceSource) GetPrices(symbols []string) (map[string]decimal.Decimal, error) {
+	symbolStr := ""
+	for _, v := range symbols {
+		symbolStr += "%22" + v + "%22,"
+	}
+	symbolStr = strings.Trim(symbolStr, ",")
+
+	var res []TokenPriceNew
+	statusCode, err := b.get(fmt.Sprintf("symbols=[%s]", symbolStr), &res)
+	if err != nil && statusCode != http.StatusBadRequest {
+		return nil, err
+	} else if err != nil {
+		log.Warn("BinancePriceSource batch err:", err.Error())
+		res = nil
+		for _, symbol := range symbols {
+			var price TokenPriceNew
+			if _, err := b.get("symbol="+symbol, &price); err != nil {
+				log.Warn("BinancePriceSource skip:", symbol, err.Error())
+				continue
+			}
+			res = append(res, price)
+		}
+	}
+
+	prices := make(map[string]decimal.Decimal)
+	for _, v := range res {
+		prices[v.Symbol] = v.Price
+	}
+	return prices, nil
+}
+
+func (b *BinancePriceSource) get(query string, res interface{}) (int, error) {
+	baseUrl := b.Url
+	if baseUrl == "" {
+		baseUrl = "https://api1.binance.com"
+	}
+	url := fmt.Sprintf("%s/api/v3/ticker/price?%s", baseUrl, query)
+	log.Info("BinancePriceSource:", url)
+
+	resp, body, errs := gorequest.New().Timeout(b.Timeout).Get(url).Retry(3, time.Second*2).End()
+	if len(errs) > 0 {
+		return 0, fmt.Errorf("binance api err:%v", errs)
+	} else if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, fmt.Errorf("binance api status code:%d", resp.StatusCode)
+	}
+	if err := json.Unmarshal([]byte(body), res); err != nil {
+		return resp.StatusCode, err
+	}
+	return resp.StatusCode, nil
+}
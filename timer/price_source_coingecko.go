@@ -0,0 +1,53 @@
+package timer
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/parnurzeal/gorequest"
+	"github.com/shopspring/decimal"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CoinGeckoPriceSource https://docs.coingecko.com/reference/simple-price, symbols are coingecko ids
+type CoinGeckoPriceSource struct {
+	Url     string
+	ApiKey  string
+	Timeout time.Duration
+}
+
+func (c *CoinGeckoPriceSource) Name() string {
+	return PriceSourceCoinGecko
+}
+
+func (c *CoinGeckoPriceSource) GetPrices(symbols []string) (map[string]decimal.Decimal, error) {
+	baseUrl := c.Url
+	if baseUrl == "" {
+		baseUrl = "https://api.coingecko.com"
+	}
+	url := fmt.Sprintf("%s/api/v3/simple/price?ids=%s&vs_currencies=usd", baseUrl, strings.Join(symbols, ","))
+
+	req := gorequest.New().Timeout(c.Timeout).Get(url)
+	if c.ApiKey != "" {
+		req = req.Set("x-cg-demo-api-key", c.ApiKey)
+	}
+	var res map[string]struct {
+		Usd decimal.Decimal `json:"usd"`
+	}
+	resp, body, errs := req.Retry(3, time.Second*2).End()
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("coingecko api err:%v", errs)
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("coingecko api status code:%d", resp.StatusCode)
+	}
+	if err := json.Unmarshal([]byte(body), &res); err != nil {
+		return nil, err
+	}
+
+	prices := make(map[string]decimal.Decimal)
+	for id, v := range res {
+		prices[id] = v.Usd
+	}
+	return prices, nil
+}
@@ -0,0 +1,63 @@
+package timer
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/parnurzeal/gorequest"
+	"github.com/shopspring/decimal"
+	"net/http"
+	"time"
+)
+
+// OkxPriceSource https://www.okx.com/docs-v5/en/#public-data-rest-api-get-tickers
+type OkxPriceSource struct {
+	Url     string
+	Timeout time.Duration
+}
+
+type OkxTickersResp struct {
+	Code string `json:"code"`
+	Msg  string `json:"msg"`
+	Data []struct {
+		InstId string          `json:"instId"`
+		Last   decimal.Decimal `json:"last"`
+	} `json:"data"`
+}
+
+func (o *OkxPriceSource) Name() string {
+	return PriceSourceOkx
+}
+
+func (o *OkxPriceSource) GetPrices(symbols []string) (map[string]decimal.Decimal, error) {
+	baseUrl := o.Url
+	if baseUrl == "" {
+		baseUrl = "https://www.okx.com"
+	}
+	url := fmt.Sprintf("%s/api/v5/market/tickers?instType=SPOT", baseUrl)
+
+	var res OkxTickersResp
+	resp, body, errs := gorequest.New().Timeout(o.Timeout).Get(url).Retry(3, time.Second*2).End()
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("okx api err:%v", errs)
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("okx api status code:%d", resp.StatusCode)
+	}
+	if err := json.Unmarshal([]byte(body), &res); err != nil {
+		return nil, err
+	}
+	if res.Code != "0" {
+		return nil, fmt.Errorf("okx api code: %s %s", res.Code, res.Msg)
+	}
+
+	wanted := make(map[string]struct{})
+	for _, v := range symbols {
+		wanted[v] = struct{}{}
+	}
+	prices := make(map[string]decimal.Decimal)
+	for _, v := range res.Data {
+		if _, ok := wanted[v.InstId]; ok {
+			prices[v.InstId] = v.Last
+		}
+	}
+	return prices, nil
+}
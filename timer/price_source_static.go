@@ -0,0 +1,41 @@
+package timer
+
+import (
+	"das_database/config"
+	"fmt"
+	"github.com/shopspring/decimal"
+)
+
+// StaticPriceSource serves the manually configured static_price of each token, symbols are token ids
+type StaticPriceSource struct {
+	Prices map[string]decimal.Decimal
+}
+
+func NewStaticPriceSource(tokens map[string]config.PriceTokenCfg) (*StaticPriceSource, error) {
+	s := StaticPriceSource{Prices: make(map[string]decimal.Decimal)}
+	for tokenId, token := range tokens {
+		if token.StaticPrice == "" {
+			continue
+		}
+		price, err := decimal.NewFromString(token.StaticPrice)
+		if err != nil {
+			return nil, fmt.Errorf("static_price of [%s] invalid: %s", tokenId, err.Error())
+		}
+		s.Prices[tokenId] = price
+	}
+	return &s, nil
+}
+
+func (s *StaticPriceSource) Name() string {
+	return PriceSourceStatic
+}
+
+func (s *StaticPriceSource) GetPrices(symbols []string) (map[string]decimal.Decimal, error) {
+	prices := make(map[string]decimal.Decimal)
+	for _, v := range symbols {
+		if price, ok := s.Prices[v]; ok {
+			prices[v] = price
+		}
+	}
+	return prices, nil
+}
@@ -0,0 +1,147 @@
+package timer
+
+import (
+	"das_database/config"
+	"encoding/json"
+	"github.com/shopspring/decimal"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newFakePriceServer serves binance, okx and coingecko style responses from the given symbol prices
+func newFakePriceServer(prices map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/ticker/price":
+			// like binance, a batch with an unknown symbol is rejected as a whole
+			if symbol := r.URL.Query().Get("symbol"); symbol != "" {
+				if price, ok := prices[symbol]; ok {
+					_ = json.NewEncoder(w).Encode(map[string]string{"symbol": symbol, "price": price})
+				} else {
+					w.WriteHeader(http.StatusBadRequest)
+				}
+				return
+			}
+			var symbols []string
+			_ = json.Unmarshal([]byte(r.URL.Query().Get("symbols")), &symbols)
+			var res []map[string]string
+			for _, symbol := range symbols {
+				price, ok := prices[symbol]
+				if !ok {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				res = append(res, map[string]string{"symbol": symbol, "price": price})
+			}
+			_ = json.NewEncoder(w).Encode(res)
+		case "/api/v5/market/tickers":
+			var data []map[string]string
+			for symbol, price := range prices {
+				data = append(data, map[string]string{"instId": symbol, "last": price})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": "0", "msg": "", "data": data})
+		case "/api/v3/simple/price":
+			res := make(map[string]map[string]json.RawMessage)
+			for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+				if price, ok := prices[id]; ok {
+					res[id] = map[string]json.RawMessage{"usd": json.RawMessage(price)}
+				}
+			}
+			_ = json.NewEncoder(w).Encode(res)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestPriceSources(t *testing.T) {
+	srv := newFakePriceServer(map[string]string{
+		"CKBUSDT":        "0.0150",
+		"CKB-USDT":       "0.0160",
+		"nervos-network": "0.0200",
+	})
+	defer srv.Close()
+
+	sources := map[string]PriceSource{
+		PriceSourceBinance:   &BinancePriceSource{Url: srv.URL, Timeout: time.Second},
+		PriceSourceOkx:       &OkxPriceSource{Url: srv.URL, Timeout: time.Second},
+		PriceSourceCoinGecko: &CoinGeckoPriceSource{Url: srv.URL, Timeout: time.Second},
+	}
+	tokens := map[string]config.PriceTokenCfg{
+		"ckb_ckb": {
+			Aggregate: PriceAggregateMedian,
+			Sources:   []string{PriceSourceBinance, PriceSourceOkx, PriceSourceCoinGecko},
+			Symbols:   map[string]string{PriceSourceBinance: "CKBUSDT", PriceSourceOkx: "CKB-USDT", PriceSourceCoinGecko: "nervos-network"},
+		},
+		"ckb_ccc": {
+			Aggregate: PriceAggregatePriority,
+			Sources:   []string{PriceSourceCoinGecko, PriceSourceBinance},
+			Symbols:   map[string]string{PriceSourceBinance: "CKBUSDT", PriceSourceCoinGecko: "nervos-network"},
+		},
+		"eth_eth": {
+			Sources: []string{PriceSourceBinance},
+			Symbols: map[string]string{PriceSourceBinance: "ETHUSDT"},
+		},
+	}
+
+	prices, errs := fetchTokenPrices(sources, tokens)
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if !prices["ckb_ckb"].Equal(decimal.RequireFromString("0.016")) {
+		t.Fatal("median of ckb_ckb:", prices["ckb_ckb"])
+	}
+	if !prices["ckb_ccc"].Equal(decimal.RequireFromString("0.02")) {
+		t.Fatal("priority of ckb_ccc:", prices["ckb_ccc"])
+	}
+	// the batch with the unknown ETHUSDT is retried symbol by symbol
+	if _, ok := prices["eth_eth"]; ok {
+		t.Fatal("eth_eth should have no price")
+	}
+}
+
+func TestPriceSourceFallback(t *testing.T) {
+	srv := newFakePriceServer(map[string]string{"CKB-USDT": "0.0160"})
+	defer srv.Close()
+
+	static, err := NewStaticPriceSource(map[string]config.PriceTokenCfg{"wx_cny": {StaticPrice: "0.14"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources := map[string]PriceSource{
+		PriceSourceBinance: &BinancePriceSource{Url: srv.URL + "/down", Timeout: time.Second},
+		PriceSourceOkx:     &OkxPriceSource{Url: srv.URL, Timeout: time.Second},
+		PriceSourceStatic:  static,
+	}
+	tokens := map[string]config.PriceTokenCfg{
+		"ckb_ckb": {
+			Sources: []string{PriceSourceBinance, PriceSourceOkx},
+			Symbols: map[string]string{PriceSourceBinance: "CKBUSDT", PriceSourceOkx: "CKB-USDT"},
+		},
+		"wx_cny": {Sources: []string{PriceSourceStatic}},
+	}
+
+	prices, errs := fetchTokenPrices(sources, tokens)
+	if len(errs) != 1 {
+		t.Fatal("binance should fail:", errs)
+	}
+	if !prices["ckb_ckb"].Equal(decimal.RequireFromString("0.016")) {
+		t.Fatal("fallback of ckb_ckb:", prices["ckb_ckb"])
+	}
+	if !prices["wx_cny"].Equal(decimal.RequireFromString("0.14")) {
+		t.Fatal("static of wx_cny:", prices["wx_cny"])
+	}
+}
+
+func TestMedianPrice(t *testing.T) {
+	list := []decimal.Decimal{decimal.NewFromInt(3), decimal.NewFromInt(1), decimal.NewFromInt(2), decimal.NewFromInt(10)}
+	if !medianPrice(list).Equal(decimal.RequireFromString("2.5")) {
+		t.Fatal(medianPrice(list))
+	}
+	if !medianPrice(list[:3]).Equal(decimal.NewFromInt(2)) {
+		t.Fatal(medianPrice(list[:3]))
+	}
+}
@@ -0,0 +1,211 @@
+package timer
+
+import (
+	"das_database/config"
+	"das_database/dao"
+	"das_database/notify"
+	"encoding/csv"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"github.com/shopspring/decimal"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// RunRebateStatement writes the statements of the last month once the parser is past its end, months are in UTC.
+// A statement is written again when the rebates of its month change, e.g. after a rollback
+func (p *ParserTimer) RunRebateStatement() {
+	if !config.Cfg.RebateStatement.Open {
+		return
+	}
+	doStatement := func() {
+		now := time.Now().UTC()
+		lastMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+		latestTs, err := p.DbDao.GetLatestTxTimestamp()
+		if err != nil {
+			log.Error("GetLatestTxTimestamp err:", err.Error())
+			return
+		}
+		if latestTs < uint64(lastMonth.AddDate(0, 1, 0).UnixMilli()) {
+			log.Info("RunRebateStatement: parser is not past the month end yet:", lastMonth.Format("2006-01"), latestTs)
+			return
+		}
+		stale, err := RebateStatementStale(p.DbDao, lastMonth, config.Cfg.RebateStatement.Dir)
+		if err != nil {
+			log.Error("RebateStatementStale err:", err.Error())
+			return
+		} else if !stale {
+			return
+		}
+		if _, err := WriteRebateStatement(p.DbDao, lastMonth, config.Cfg.RebateStatement.Dir); err != nil {
+			log.Error("WriteRebateStatement err:", err.Error())
+			notify.SendLarkErrNotify("WriteRebateStatement", err.Error())
+		}
+	}
+
+	tickerStatement := time.NewTicker(time.Hour * 6)
+	p.Wg.Add(1)
+	go func() {
+		defer http_api.RecoverPanic()
+		doStatement()
+		for {
+			select {
+			case <-tickerStatement.C:
+				doStatement()
+			case <-p.Ctx.Done():
+				p.Wg.Done()
+				return
+			}
+		}
+	}()
+}
+
+const rebateStateFile = "state"
+
+func rebateMonthFilter(month time.Time) dao.RebateFilter {
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return dao.RebateFilter{
+		RewardType: -1,
+		StartAt:    uint64(start.UnixMilli()),
+		EndAt:      uint64(start.AddDate(0, 1, 0).UnixMilli()),
+	}
+}
+
+// rebateState the count and the reward of all rebates in the month, a statement is stale once they change
+func rebateState(dbDao *dao.DbDao, filter dao.RebateFilter) (string, error) {
+	list, err := dbDao.GetRebateSum(filter)
+	if err != nil {
+		return "", fmt.Errorf("GetRebateSum err: %s", err.Error())
+	}
+	var count int64
+	var reward uint64
+	for _, v := range list {
+		count += v.Count
+		reward += v.Reward
+	}
+	return fmt.Sprintf("%d,%d", count, reward), nil
+}
+
+// RebateStatementStale is true when the statement of the month is missing or was written from other rebates
+func RebateStatementStale(dbDao *dao.DbDao, month time.Time, dir string) (bool, error) {
+	state, err := rebateState(dbDao, rebateMonthFilter(month))
+	if err != nil {
+		return false, err
+	}
+	bys, err := os.ReadFile(filepath.Join(dir, month.Format("2006-01"), rebateStateFile))
+	if os.IsNotExist(err) {
+		return true, nil
+	} else if err != nil {
+		return false, fmt.Errorf("ReadFile err: %s", err.Error())
+	}
+	return string(bys) != state, nil
+}
+
+// WriteRebateStatement writes dir/YYYY-MM/summary.csv with the reward of each inviter in the month,
+// and dir/YYYY-MM/<chain_type>_<address>.csv with the rebates of each inviter. Rewards are in CKB.
+// The month dir only shows up once all files are written, its state file records which rebates it was written from
+func WriteRebateStatement(dbDao *dao.DbDao, month time.Time, dir string) (string, error) {
+	filter := rebateMonthFilter(month)
+	start := time.UnixMilli(int64(filter.StartAt)).UTC()
+	state, err := rebateState(dbDao, filter)
+	if err != nil {
+		return "", err
+	}
+
+	monthDir := filepath.Join(dir, start.Format("2006-01"))
+	tmpDir := monthDir + ".tmp"
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return "", fmt.Errorf("RemoveAll err: %s", err.Error())
+	}
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		return "", fmt.Errorf("MkdirAll err: %s", err.Error())
+	}
+
+	inviters, err := dbDao.GetRebateInviterSum(filter, 0)
+	if err != nil {
+		return "", fmt.Errorf("GetRebateInviterSum err: %s", err.Error())
+	}
+	summary := [][]string{{"inviter_chain_type", "inviter_address", "count", "reward_ckb"}}
+	for _, v := range inviters {
+		summary = append(summary, []string{
+			strconv.Itoa(int(v.InviterChainType)), v.InviterAddress, strconv.FormatInt(v.Count, 10), rebateCkb(v.Reward),
+		})
+
+		f := filter
+		f.InviterChainType, f.InviterAddress = v.InviterChainType, v.InviterAddress
+		list, err := getAllRebate(dbDao, f)
+		if err != nil {
+			return "", fmt.Errorf("getAllRebate err: %s", err.Error())
+		}
+		detail := [][]string{{"block_number", "block_timestamp", "outpoint", "action", "service_type", "reward_type", "invitee_account", "reward_ckb"}}
+		// oldest first
+		for i := len(list) - 1; i >= 0; i-- {
+			r := list[i]
+			rewardType := "inviter"
+			if r.RewardType == dao.RewardTypeChannel {
+				rewardType = "channel"
+			}
+			detail = append(detail, []string{
+				strconv.FormatUint(r.BlockNumber, 10),
+				time.UnixMilli(int64(r.BlockTimestamp)).UTC().Format(time.RFC3339),
+				r.Outpoint, r.Action, strconv.Itoa(r.ServiceType), rewardType, r.InviteeAccount, rebateCkb(r.Reward),
+			})
+		}
+		fileName := fmt.Sprintf("%d_%s.csv", v.InviterChainType, v.InviterAddress)
+		if err := writeCsv(filepath.Join(tmpDir, fileName), detail); err != nil {
+			return "", fmt.Errorf("writeCsv err: %s", err.Error())
+		}
+	}
+	if err := writeCsv(filepath.Join(tmpDir, "summary.csv"), summary); err != nil {
+		return "", fmt.Errorf("writeCsv err: %s", err.Error())
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, rebateStateFile), []byte(state), 0644); err != nil {
+		return "", fmt.Errorf("WriteFile err: %s", err.Error())
+	}
+
+	if err := os.RemoveAll(monthDir); err != nil {
+		return "", fmt.Errorf("RemoveAll err: %s", err.Error())
+	}
+	if err := os.Rename(tmpDir, monthDir); err != nil {
+		return "", fmt.Errorf("Rename err: %s", err.Error())
+	}
+	log.Info("WriteRebateStatement:", monthDir, len(inviters))
+	return monthDir, nil
+}
+
+func getAllRebate(dbDao *dao.DbDao, f dao.RebateFilter) (res []dao.TableRebateInfo, err error) {
+	limit := 1000
+	var cursor *dao.Cursor
+	for {
+		list, err := dbDao.GetRebateList(f, cursor, limit, 0)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, list...)
+		if len(list) < limit {
+			return res, nil
+		}
+		last := list[len(list)-1]
+		cursor = &dao.Cursor{Key: last.BlockNumber, Id: last.Id}
+	}
+}
+
+func rebateCkb(shannon uint64) string {
+	return decimal.NewFromBigInt(new(big.Int).SetUint64(shannon), -8).StringFixed(8)
+}
+
+func writeCsv(fileName string, records [][]string) error {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := csv.NewWriter(f)
+	if err := w.WriteAll(records); err != nil {
+		return err
+	}
+	return f.Sync()
+}
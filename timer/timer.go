@@ -3,6 +3,7 @@ package timer
 import (
 	"context"
 	"das_database/dao"
+	"fmt"
 	"github.com/dotbitHQ/das-lib/core"
 	"github.com/dotbitHQ/das-lib/http_api"
 	"github.com/dotbitHQ/das-lib/http_api/logger"
@@ -19,7 +20,15 @@ type ParserTimer struct {
 	DasCore *core.DasCore
 }
 
-func (p *ParserTimer) RunUpdateTokenPrice() {
+func (p *ParserTimer) RunUpdateTokenPrice() error {
+	if err := p.syncTokenList(); err != nil {
+		return fmt.Errorf("syncTokenList err: %s", err.Error())
+	}
+	if rows, err := p.DbDao.SeedTokenPriceHistory(); err != nil {
+		return fmt.Errorf("SeedTokenPriceHistory err: %s", err.Error())
+	} else if rows > 0 {
+		log.Info("SeedTokenPriceHistory:", rows)
+	}
 	p.updateTokenMap()
 
 	tickerToken := time.NewTicker(time.Second * 180)
@@ -34,6 +43,7 @@ func (p *ParserTimer) RunUpdateTokenPrice() {
 				log.Debug("RunUpdateTokenPriceList start ...", time.Now().Format("2006-01-02 15:04:05"))
 				p.updateTokenPriceInfoList()
 				p.updateTokenMap()
+				p.checkTokenPriceStale()
 				log.Debug("RunUpdateTokenPriceList end ...", time.Now().Format("2006-01-02 15:04:05"))
 			//case <-tickerUSD.C:
 			//	log.Info("RunUpdateUSDRate start ...", time.Now().Format("2006-01-02 15:04:05"))
@@ -45,4 +55,5 @@ func (p *ParserTimer) RunUpdateTokenPrice() {
 			}
 		}
 	}()
+	return nil
 }
@@ -0,0 +1,119 @@
+package timer
+
+import (
+	"das_database/config"
+	"das_database/dao"
+	"das_database/normalize"
+	"das_database/notify"
+	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/dotbitHQ/das-lib/http_api"
+	"strings"
+	"time"
+)
+
+type ExpiryReminder struct {
+	Account        string           `json:"account"`
+	OwnerChainType common.ChainType `json:"owner_chain_type"`
+	Owner          string           `json:"owner"`
+	ExpiredAt      uint64           `json:"expired_at"`
+	Days           uint64           `json:"days"`
+}
+
+// RunExpiryReminder once a day, reminds the accounts expiring in [days, days+1) for each configured days,
+// so every account is reminded once per threshold
+func (p *ParserTimer) RunExpiryReminder() {
+	if !config.Cfg.ExpiryReminder.Open {
+		return
+	}
+	tickerExpiry := time.NewTicker(time.Hour * 24)
+	p.Wg.Add(1)
+	go func() {
+		defer http_api.RecoverPanic()
+		for {
+			select {
+			case <-tickerExpiry.C:
+				if err := p.doExpiryReminder(); err != nil {
+					log.Error("doExpiryReminder err:", err.Error())
+					notify.SendLarkErrNotify("doExpiryReminder", err.Error())
+				}
+			case <-p.Ctx.Done():
+				p.Wg.Done()
+				return
+			}
+		}
+	}()
+}
+
+func (p *ParserTimer) doExpiryReminder() error {
+	now := uint64(time.Now().Unix())
+	page := 1000
+	an := normalize.NewAddressNormalizer(p.DasCore.NetType())
+	var reminders []ExpiryReminder
+	for _, days := range config.Cfg.ExpiryReminder.Days {
+		filter := dao.ExpiryFilter{
+			ExpiredFrom: now + days*86400,
+			ExpiredTo:   now + (days+1)*86400,
+		}
+		var cursor *dao.Cursor
+		for {
+			list, err := p.DbDao.GetAccountListByExpiry(filter, cursor, page, 0)
+			if err != nil {
+				return fmt.Errorf("GetAccountListByExpiry err: %s", err.Error())
+			}
+			// the holder of an upgraded account is the lock of its did cell, not the owner of the account cell
+			didCells, err := p.DbDao.GetUpgradedDidCellList(list)
+			if err != nil {
+				return fmt.Errorf("GetUpgradedDidCellList err: %s", err.Error())
+			}
+			didCellOwners := make(map[string]string)
+			for _, v := range didCells {
+				addr, err := an.DidCellLock(v.LockCodeHash, v.Args)
+				if err != nil {
+					log.Warn("DidCellLock err:", err.Error(), v.Account)
+					continue
+				}
+				didCellOwners[v.AccountId] = addr.Hex
+			}
+			for _, v := range list {
+				reminder := ExpiryReminder{
+					Account:        v.Account,
+					OwnerChainType: v.OwnerChainType,
+					Owner:          v.Owner,
+					ExpiredAt:      v.ExpiredAt,
+					Days:           days,
+				}
+				if owner, ok := didCellOwners[v.AccountId]; ok {
+					reminder.OwnerChainType, reminder.Owner = common.ChainTypeAnyLock, owner
+				}
+				reminders = append(reminders, reminder)
+			}
+			if len(list) < page {
+				break
+			}
+			last := list[len(list)-1]
+			cursor = &dao.Cursor{Key: last.ExpiredAt, Id: last.Id}
+		}
+	}
+	log.Info("doExpiryReminder:", len(reminders))
+	if len(reminders) == 0 {
+		return nil
+	}
+
+	if err := notify.SendWebhook(config.Cfg.ExpiryReminder.Webhook, reminders); err != nil {
+		return fmt.Errorf("SendWebhook err: %s", err.Error())
+	}
+
+	var sb strings.Builder
+	for i, v := range reminders {
+		if i >= 50 {
+			sb.WriteString(fmt.Sprintf("... %d accounts in total\n", len(reminders)))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("%s expires in %d days\n", v.Account, v.Days))
+	}
+	if err := notify.SendLarkTextNotify(config.Cfg.ExpiryReminder.LarkWebhook, "Account Expiry Reminder", sb.String()); err != nil {
+		return fmt.Errorf("SendLarkTextNotify err: %s", err.Error())
+	}
+	return nil
+}
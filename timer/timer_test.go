@@ -5,7 +5,11 @@ import (
 	"das_database/config"
 	"das_database/dao"
 	"fmt"
+	"github.com/dotbitHQ/das-lib/common"
+	"github.com/nervosnetwork/ckb-sdk-go/types"
 	"github.com/scorpiotzh/toolib"
+	"github.com/shopspring/decimal"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -43,7 +47,8 @@ func TestGetTokenInfo(t *testing.T) {
 }
 
 func TestGetTokenPriceBinance(t *testing.T) {
-	fmt.Println(GetTokenPriceNew(nil))
+	source := BinancePriceSource{Timeout: time.Second * 30}
+	fmt.Println(source.GetPrices([]string{"CKBUSDT", "ETHUSDT"}))
 }
 
 func TestGetCnyRate(t *testing.T) {
@@ -64,3 +69,74 @@ func TestDailyRegister(t *testing.T) {
 		origin = origin.Add(time.Hour * 24)
 	}
 }
+
+func TestRebateCkb(t *testing.T) {
+	for shannon, ckb := range map[uint64]string{
+		0:                    "0.00000000",
+		1:                    "0.00000001",
+		2000000000:           "20.00000000",
+		18446744073709551615: "184467440737.09551615",
+	} {
+		if res := rebateCkb(shannon); res != ckb {
+			t.Fatal(shannon, res, ckb)
+		}
+	}
+}
+
+func TestAutoMintReconcileStatus(t *testing.T) {
+	providerArgs := []byte{1, 2, 3}
+	tx := &types.Transaction{Outputs: []*types.CellOutput{
+		{Capacity: 100, Lock: &types.Script{Args: []byte{9}}},
+		{Capacity: 200, Lock: &types.Script{Args: providerArgs}},
+		{Capacity: 300, Lock: &types.Script{Args: []byte{9}}},
+	}}
+	providerId := common.Bytes2Hex(providerArgs)
+	for _, v := range []struct {
+		tx       *types.Transaction
+		index    int
+		recorded int64
+		balance  int64
+		status   dao.AutoMintReconcileStatus
+	}{
+		{tx, 1, 200, 0, dao.AutoMintReconcileStatusOk},
+		{tx, 0, 200, 10, dao.AutoMintReconcileStatusOk}, // statements of the old collect txs are not indexed
+		{tx, 1, 150, 0, dao.AutoMintReconcileStatusMismatch},
+		{tx, 1, 200, -1, dao.AutoMintReconcileStatusOverdrawn},
+		{tx, 2, 300, 0, dao.AutoMintReconcileStatusMismatch},
+		{nil, 1, 200, 0, dao.AutoMintReconcileStatusNotFound},
+	} {
+		recorded := decimal.NewFromInt(v.recorded)
+		withdrawn, found := autoMintWithdrawn(v.tx, v.index, providerId, recorded)
+		if status := autoMintReconcileStatus(found, recorded, withdrawn, decimal.NewFromInt(v.balance)); status != v.status {
+			t.Fatal(v.index, v.recorded, v.balance, status, v.status)
+		}
+	}
+}
+
+func TestApprovalNotices(t *testing.T) {
+	list := []dao.ApprovalInfo{{ID: 1, Account: "a.bit", ProtectedUntil: 100, SealedUntil: 200}}
+	notices, events := approvalNotices(dao.ApprovalEventUnprotected, list)
+	if len(notices) != 1 || notices[0].EventAt != 100 || events[0].EventAt != 100 || events[0].ApprovalId != 1 {
+		t.Fatal(notices, events)
+	}
+	notices, events = approvalNotices(dao.ApprovalEventFulfillable, list)
+	if notices[0].EventAt != 200 || events[0].Event != dao.ApprovalEventFulfillable {
+		t.Fatal(notices, events)
+	}
+}
+
+func TestCrossChainReportText(t *testing.T) {
+	report := dao.CrossChainReport{
+		StuckCount:    1,
+		Stuck:         []dao.TableCrossChainInfo{{Account: "a.bit", LockedAt: 1700000000000}},
+		OrphanUnlocks: []dao.TableCrossChainInfo{{Account: "b.bit", UnlockTxHash: "0x01"}},
+	}
+	text := crossChainReportText(report, 30)
+	if !strings.Contains(text, "locked over 30 days: 1\n  a.bit since 2023-11-14T22:13:20Z") ||
+		!strings.Contains(text, "unlocked without a lock: 1\n  b.bit in 0x01") || strings.Contains(text, "twice") {
+		t.Fatal(text)
+	}
+	if !(dao.CrossChainReport{}).IsEmpty() || report.IsEmpty() {
+		t.Fatal("IsEmpty")
+	}
+}
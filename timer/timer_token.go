@@ -1,11 +1,14 @@
 package timer
 
 import (
+	"das_database/config"
 	"das_database/dao"
 	"das_database/notify"
+	"fmt"
 	"github.com/scorpiotzh/toolib"
 	"github.com/shopspring/decimal"
 	"sync"
+	"time"
 )
 
 const (
@@ -17,8 +20,43 @@ const (
 var (
 	tokenLock sync.RWMutex
 	mapToken  map[string]dao.TableTokenPriceInfo
+	staleLock sync.RWMutex
+	mapStale  = make(map[string]bool)
 )
 
+// syncTokenList seeds t_token_price_info with the tokens of the config which don't exist yet,
+// the existing ones are managed by the internal api
+func (p *ParserTimer) syncTokenList() error {
+	var tokenList []dao.TableTokenPriceInfo
+	for _, v := range config.Cfg.TokenList {
+		if !HasPriceSource(v.TokenId) {
+			log.Warn("token has no price source:", v.TokenId)
+		}
+		price := decimal.Zero
+		if v.Price != "" {
+			var err error
+			if price, err = decimal.NewFromString(v.Price); err != nil {
+				return fmt.Errorf("price of [%s] invalid: %s", v.TokenId, err.Error())
+			}
+		}
+		tokenList = append(tokenList, dao.TableTokenPriceInfo{
+			TokenId:       v.TokenId,
+			GeckoId:       v.GeckoId,
+			ChainType:     v.ChainType,
+			CoinType:      v.CoinType,
+			Name:          v.Name,
+			Symbol:        v.Symbol,
+			Decimals:      v.Decimals,
+			Price:         price,
+			Logo:          v.Logo,
+			LastUpdatedAt: time.Now().Unix(),
+			DisplayName:   v.DisplayName,
+			Icon:          v.Icon,
+		})
+	}
+	return p.DbDao.InsertNewTokenList(tokenList)
+}
+
 func (p *ParserTimer) updateTokenMap() {
 	list, err := p.DbDao.SearchTokenPriceInfoList()
 	if err != nil {
@@ -46,34 +84,112 @@ func GetTokenPriceInfoList() map[string]dao.TableTokenPriceInfo {
 	return mapToken
 }
 
+// priceTokens the price sources of the tokens, the banned tokens are not priced
+func priceTokens() map[string]config.PriceTokenCfg {
+	tokens := config.Cfg.Price.Tokens
+	if len(tokens) == 0 {
+		tokens = defaultPriceTokens()
+	}
+	res := make(map[string]config.PriceTokenCfg, len(tokens))
+	for tokenId, v := range tokens {
+		if GetTokenPriceInfo(tokenId).Status == dao.TokenStatusBanned {
+			continue
+		}
+		res[tokenId] = v
+	}
+	return res
+}
+
+// HasPriceSource whether the price of the token is kept up to date by a price source
+func HasPriceSource(tokenId string) bool {
+	tokens := config.Cfg.Price.Tokens
+	if len(tokens) == 0 {
+		tokens = defaultPriceTokens()
+	}
+	_, ok := tokens[tokenId]
+	return ok
+}
+
 func (p *ParserTimer) updateTokenPriceInfoList() {
-	var geckoIds []string
-	if list, err := p.DbDao.SearchTokenPriceInfoList(); err != nil {
-		log.Error("SearchTokenPriceInfoList err:", err.Error())
-	} else {
-		for _, v := range list {
-			geckoIds = append(geckoIds, v.GeckoId)
+	tokens := priceTokens()
+	if len(tokens) == 0 {
+		return
+	}
+	sources, err := newPriceSources(tokens)
+	if err != nil {
+		log.Error("newPriceSources err:", err.Error())
+		notify.SendLarkErrNotify("newPriceSources", err.Error())
+		return
+	}
+
+	prices, errs := fetchTokenPrices(sources, tokens)
+	for _, e := range errs {
+		log.Error("fetchTokenPrices err:", e.Error())
+		notify.SendLarkErrNotify("fetchTokenPrices", e.Error())
+	}
+
+	now := time.Now().Unix()
+	var tokenList []dao.TableTokenPriceInfo
+	for tokenId, price := range prices {
+		tokenList = append(tokenList, dao.TableTokenPriceInfo{
+			TokenId:       tokenId,
+			Price:         price,
+			LastUpdatedAt: now,
+		})
+	}
+	if err := p.DbDao.UpdateTokenPriceInfoList(tokenList); err != nil {
+		log.Error("UpdateTokenPriceInfoList err:", err.Error())
+	}
+}
+
+func newPriceSources(tokens map[string]config.PriceTokenCfg) (map[string]PriceSource, error) {
+	sources := make(map[string]PriceSource)
+	for _, token := range tokens {
+		for _, name := range token.Sources {
+			if _, ok := sources[name]; ok {
+				continue
+			}
+			source, err := NewPriceSource(name, config.Cfg.Price.Sources[name], tokens)
+			if err != nil {
+				return nil, err
+			}
+			sources[name] = source
 		}
 	}
+	return sources, nil
+}
+
+// checkTokenPriceStale marks the tokens whose price is older than stale_seconds and alerts once per token
+func (p *ParserTimer) checkTokenPriceStale() {
+	staleSeconds := config.Cfg.Price.StaleSeconds
+	if staleSeconds <= 0 {
+		return
+	}
+	now := time.Now().Unix()
+	list := GetTokenPriceInfoList()
 
-	if list, err := GetTokenPriceNew(geckoIds); err != nil {
-		log.Error("GetTokenPriceNew err:", err.Error())
-		notify.SendLarkErrNotify("GetTokenPriceNew", err.Error())
-	} else {
-		var tokenList []dao.TableTokenPriceInfo
-		for _, v := range list {
-			tokenList = append(tokenList, dao.TableTokenPriceInfo{
-				TokenId:       v.Id,
-				Price:         v.Price,
-				LastUpdatedAt: v.LastUpdatedAt,
-			})
+	staleLock.Lock()
+	defer staleLock.Unlock()
+	for tokenId, v := range list {
+		if v.LastUpdatedAt == 0 {
+			continue
 		}
-		if err := p.DbDao.UpdateTokenPriceInfoList(tokenList); err != nil {
-			log.Error("UpdateTokenPriceInfoList err:", err.Error())
+		stale := now-v.LastUpdatedAt > staleSeconds
+		if stale && !mapStale[tokenId] {
+			msg := fmt.Sprintf("token: %s\nlast_updated_at: %s", tokenId, time.Unix(v.LastUpdatedAt, 0).Format("2006-01-02 15:04:05"))
+			log.Warn("token price stale:", msg)
+			notify.SendLarkErrNotify("token price stale", msg)
 		}
+		mapStale[tokenId] = stale
 	}
 }
 
+func IsTokenPriceStale(tokenId string) bool {
+	staleLock.RLock()
+	defer staleLock.RUnlock()
+	return mapStale[tokenId]
+}
+
 func (p *ParserTimer) updateUSDRate() {
 	rate, err := GetCnyRate()
 	if err != nil {
@@ -88,3 +204,25 @@ func (p *ParserTimer) updateUSDRate() {
 		}
 	}
 }
+
+// GetTokenPriceInfoAt returns the token info with the price that was in effect at blockTimestamp (ms),
+// so that re-parsing a block always yields the same USD values.
+// ok is false when no price was recorded before that time, or when the last one was older than stale_seconds,
+// the price is then zero.
+func GetTokenPriceInfoAt(dbDao *dao.DbDao, tokenId string, blockTimestamp uint64) (tokenInfo dao.TableTokenPriceInfo, ok bool, err error) {
+	tokenInfo = GetTokenPriceInfo(tokenId)
+	tokenInfo.Price = decimal.Zero
+	history, err := dbDao.GetTokenPriceAt(tokenId, int64(blockTimestamp/1e3))
+	if err != nil {
+		return tokenInfo, false, fmt.Errorf("GetTokenPriceAt err: %s", err.Error())
+	}
+	if history.Id == 0 {
+		return tokenInfo, false, nil
+	}
+	if staleSeconds := config.Cfg.Price.StaleSeconds; staleSeconds > 0 && int64(blockTimestamp/1e3)-history.PriceAt > staleSeconds {
+		log.Warn("token price stale at block time:", tokenId, blockTimestamp, history.PriceAt)
+		return tokenInfo, false, nil
+	}
+	tokenInfo.Price = history.Price
+	return tokenInfo, true, nil
+}